@@ -18,6 +18,7 @@ import (
 	"github.com/yayasan/erp-backend/internal/repository"
 	"github.com/yayasan/erp-backend/internal/routes"
 	"github.com/yayasan/erp-backend/internal/service"
+	"github.com/yayasan/erp-backend/internal/storage"
 )
 
 func main() {
@@ -37,6 +38,16 @@ func main() {
 		return
 	}
 
+	// Check if rebuild-balances command
+	if len(os.Args) > 1 && os.Args[1] == "rebuild-balances" {
+		log.Println("Rebuilding account balances from journal lines...")
+		if err := repository.NewAccountBalanceRepository(database.GetDB()).Rebuild(); err != nil {
+			log.Fatal("Failed to rebuild account balances:", err)
+		}
+		log.Println("✅ Account balances rebuilt successfully!")
+		return
+	}
+
 	// Initialize Gin
 	if config.GlobalConfig.App.Env == "production" {
 		gin.SetMode(gin.ReleaseMode)
@@ -51,6 +62,12 @@ func main() {
 	// Get database instance
 	db := database.GetDB()
 
+	// Initialize file storage for uploads
+	fileStorage, err := storage.New(config.GlobalConfig.Upload)
+	if err != nil {
+		log.Fatal("Failed to initialize file storage:", err)
+	}
+
 	// Initialize repositories
 	userRepo := repository.NewUserRepository(db)
 	branchRepo := repository.NewBranchRepository(db)
@@ -67,6 +84,19 @@ func main() {
 	payrollRepo := repository.NewPayrollRepository(db)
 	assetRepo := repository.NewAssetRepository(db)
 	inventoryRepo := repository.NewInventoryRepository(db)
+	mappingRepo := repository.NewAccountMappingRepository(db)
+	periodRepo := repository.NewAccountingPeriodRepository(db)
+	fundRepo := repository.NewFundRepository(db)
+	programRepo := repository.NewProgramRepository(db)
+	donorRepo := repository.NewDonorRepository(db)
+	bankStatementRepo := repository.NewBankStatementRepository(db)
+	reportTemplateRepo := repository.NewReportTemplateRepository(db)
+	recurringJournalRepo := repository.NewRecurringJournalRepository(db)
+	approvalRepo := repository.NewApprovalRepository(db)
+	attachmentRepo := repository.NewAttachmentRepository(db)
+	exchangeRateRepo := repository.NewExchangeRateRepository(db)
+	documentNumberRepo := repository.NewDocumentNumberRepository(db)
+	openingBalanceRepo := repository.NewOpeningBalanceRepository(db)
 
 	// Initialize services
 	authService := service.NewAuthService(userRepo, roleRepo)
@@ -74,16 +104,30 @@ func main() {
 	branchService := service.NewBranchService(branchRepo)
 	roleService := service.NewRoleService(roleRepo)
 	accountService := service.NewAccountService(accountRepo)
-	journalService := service.NewJournalService(journalRepo, accountRepo, branchRepo)
+	approvalService := service.NewApprovalService(approvalRepo, userRepo, roleRepo, branchRepo)
+	journalService := service.NewJournalService(journalRepo, accountRepo, branchRepo, periodRepo, fundRepo, programRepo, donorRepo, mappingRepo, exchangeRateRepo, approvalService)
 	budgetService := service.NewBudgetService(db, budgetRepo, accountRepo, fiscalYearRepo)
 	reportService := service.NewReportService(db, accountRepo, journalRepo)
 	studentService := service.NewStudentService(studentRepo, parentRepo, branchRepo)
-	paymentService := service.NewPaymentService(paymentRepo, invoiceRepo, branchRepo, studentRepo)
+	paymentService := service.NewPaymentService(paymentRepo, invoiceRepo, branchRepo, studentRepo, journalRepo, mappingRepo, periodRepo)
 	invoiceService := service.NewInvoiceService(invoiceRepo, studentRepo, branchRepo)
 	employeeService := service.NewEmployeeService(employeeRepo, branchRepo)
-	payrollService := service.NewPayrollService(payrollRepo, employeeRepo, branchRepo)
-	assetService := service.NewAssetService(assetRepo, branchRepo)
+	payrollService := service.NewPayrollService(payrollRepo, employeeRepo, branchRepo, journalRepo, mappingRepo, periodRepo)
+	assetService := service.NewAssetService(assetRepo, branchRepo, periodRepo)
 	inventoryService := service.NewInventoryService(inventoryRepo, branchRepo)
+	mappingService := service.NewAccountMappingService(mappingRepo, accountRepo, branchRepo)
+	periodService := service.NewPeriodService(periodRepo, branchRepo)
+	fiscalYearService := service.NewFiscalYearService(fiscalYearRepo, journalRepo, accountRepo, branchRepo)
+	fundService := service.NewFundService(fundRepo)
+	programService := service.NewProgramService(programRepo)
+	bankReconciliationService := service.NewBankReconciliationService(bankStatementRepo, accountRepo, branchRepo)
+	reportExportService := service.NewReportExportService(db, userRepo, branchRepo, accountRepo, fundRepo, programRepo)
+	reportTemplateService := service.NewReportTemplateService(reportTemplateRepo)
+	recurringJournalService := service.NewRecurringJournalService(recurringJournalRepo, accountRepo, branchRepo, journalService)
+	attachmentService := service.NewAttachmentService(attachmentRepo, journalRepo, fileStorage)
+	exchangeRateService := service.NewExchangeRateService(exchangeRateRepo, accountRepo, branchRepo, mappingRepo, periodRepo)
+	documentNumberService := service.NewDocumentNumberService(documentNumberRepo, branchRepo)
+	openingBalanceService := service.NewOpeningBalanceService(openingBalanceRepo, branchRepo, accountRepo, fundRepo, programRepo, donorRepo, studentRepo, periodRepo)
 
 	// Initialize handlers
 	authHandler := handler.NewAuthHandler(authService)
@@ -91,14 +135,26 @@ func main() {
 	branchHandler := handler.NewBranchHandler(branchService)
 	roleHandler := handler.NewRoleHandler(roleService)
 	accountHandler := handler.NewAccountHandler(accountService)
-	journalHandler := handler.NewJournalHandler(journalService)
-	budgetHandler := handler.NewBudgetHandler(budgetService)
-	reportHandler := handler.NewReportHandler(reportService)
+	journalHandler := handler.NewJournalHandler(journalService, reportExportService)
+	budgetHandler := handler.NewBudgetHandler(budgetService, reportExportService)
+	reportHandler := handler.NewReportHandler(reportService, reportExportService)
 	studentHandler := handler.NewStudentHandler(studentService)
 	paymentHandler := handler.NewPaymentHandler(paymentService, invoiceService)
 	employeeHandler := handler.NewEmployeeHandler(employeeService, payrollService)
 	assetHandler := handler.NewAssetHandler(assetService)
 	inventoryHandler := handler.NewInventoryHandler(inventoryService)
+	mappingHandler := handler.NewAccountMappingHandler(mappingService)
+	periodHandler := handler.NewPeriodHandler(periodService)
+	fiscalYearHandler := handler.NewFiscalYearHandler(fiscalYearService)
+	fundHandler := handler.NewFundHandler(fundService, programService)
+	bankReconciliationHandler := handler.NewBankReconciliationHandler(bankReconciliationService)
+	reportTemplateHandler := handler.NewReportTemplateHandler(reportTemplateService, reportService)
+	recurringJournalHandler := handler.NewRecurringJournalHandler(recurringJournalService)
+	approvalHandler := handler.NewApprovalHandler(approvalService)
+	attachmentHandler := handler.NewAttachmentHandler(attachmentService)
+	exchangeRateHandler := handler.NewExchangeRateHandler(exchangeRateService)
+	documentNumberHandler := handler.NewDocumentNumberHandler(documentNumberService)
+	openingBalanceHandler := handler.NewOpeningBalanceHandler(openingBalanceService)
 
 	// Setup routes
 	appRouter := routes.NewRouter(
@@ -115,6 +171,18 @@ func main() {
 		employeeHandler,
 		assetHandler,
 		inventoryHandler,
+		mappingHandler,
+		periodHandler,
+		fiscalYearHandler,
+		fundHandler,
+		bankReconciliationHandler,
+		reportTemplateHandler,
+		recurringJournalHandler,
+		approvalHandler,
+		attachmentHandler,
+		exchangeRateHandler,
+		documentNumberHandler,
+		openingBalanceHandler,
 	)
 	appRouter.Setup(router)
 
@@ -140,12 +208,25 @@ func main() {
 		}
 	}()
 
+	// Generate recurring journals in the background
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	defer stopScheduler()
+	if config.GlobalConfig.App.RecurringJournalsEnabled {
+		go service.RunRecurringJournalScheduler(schedulerCtx, recurringJournalService, config.GlobalConfig.App.RecurringJournalInterval)
+	}
+
+	// Revalue foreign currency accounts after each month end
+	if config.GlobalConfig.App.EnableMultiCurrency && config.GlobalConfig.App.FXRevaluationEnabled {
+		go service.RunFXRevaluationScheduler(schedulerCtx, exchangeRateService, config.GlobalConfig.App.FXRevaluationInterval)
+	}
+
 	// Wait for interrupt signal to gracefully shutdown the server
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
 	log.Println("🛑 Shutting down server...")
+	stopScheduler()
 
 	// Graceful shutdown with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -56,6 +56,14 @@ type UploadConfig struct {
 	MaxSize        int64
 	Path           string
 	AllowedTypes   []string
+	Storage        string // local or s3
+	S3Endpoint     string
+	S3Region       string
+	S3Bucket       string
+	S3Prefix       string
+	S3AccessKey    string
+	S3SecretKey    string
+	S3UseSSL       bool
 }
 
 type EmailConfig struct {
@@ -79,6 +87,10 @@ type AppConfig struct {
 	RateLimitPerMin      int
 	DefaultPageSize      int
 	MaxPageSize          int
+	RecurringJournalsEnabled bool
+	RecurringJournalInterval time.Duration
+	FXRevaluationEnabled     bool
+	FXRevaluationInterval    time.Duration
 }
 
 var GlobalConfig *Config
@@ -100,6 +112,16 @@ func Load() (*Config, error) {
 		jwtRefreshExpiry = 168 * time.Hour
 	}
 
+	recurringJournalInterval, err := time.ParseDuration(getEnv("RECURRING_JOURNAL_INTERVAL", "1h"))
+	if err != nil || recurringJournalInterval <= 0 {
+		recurringJournalInterval = time.Hour
+	}
+
+	fxRevaluationInterval, err := time.ParseDuration(getEnv("FX_REVALUATION_INTERVAL", "1h"))
+	if err != nil || fxRevaluationInterval <= 0 {
+		fxRevaluationInterval = time.Hour
+	}
+
 	config := &Config{
 		Server: ServerConfig{
 			Host:         getEnv("SERVER_HOST", "localhost"),
@@ -132,6 +154,14 @@ func Load() (*Config, error) {
 			MaxSize:      getEnvAsInt64("MAX_UPLOAD_SIZE", 10485760), // 10MB default
 			Path:         getEnv("UPLOAD_PATH", "./uploads"),
 			AllowedTypes: strings.Split(getEnv("ALLOWED_FILE_TYPES", "image/jpeg,image/png,application/pdf"), ","),
+			Storage:      getEnv("UPLOAD_STORAGE", "local"),
+			S3Endpoint:   getEnv("S3_ENDPOINT", ""),
+			S3Region:     getEnv("S3_REGION", ""),
+			S3Bucket:     getEnv("S3_BUCKET", ""),
+			S3Prefix:     getEnv("S3_PREFIX", ""),
+			S3AccessKey:  getEnv("S3_ACCESS_KEY", ""),
+			S3SecretKey:  getEnv("S3_SECRET_KEY", ""),
+			S3UseSSL:     getEnvAsBool("S3_USE_SSL", true),
 		},
 		Email: EmailConfig{
 			SMTPHost:     getEnv("SMTP_HOST", ""),
@@ -153,6 +183,10 @@ func Load() (*Config, error) {
 			RateLimitPerMin:     getEnvAsInt("RATE_LIMIT_REQUESTS_PER_MINUTE", 60),
 			DefaultPageSize:     getEnvAsInt("DEFAULT_PAGE_SIZE", 20),
 			MaxPageSize:         getEnvAsInt("MAX_PAGE_SIZE", 100),
+			RecurringJournalsEnabled: getEnvAsBool("ENABLE_RECURRING_JOURNALS", true),
+			RecurringJournalInterval: recurringJournalInterval,
+			FXRevaluationEnabled:     getEnvAsBool("ENABLE_FX_REVALUATION", true),
+			FXRevaluationInterval:    fxRevaluationInterval,
 		},
 	}
 
@@ -107,7 +107,11 @@ func CloseDB() error {
 
 // AutoMigrate runs database migrations
 func AutoMigrate(db *gorm.DB) error {
-	return db.AutoMigrate(
+	if err := dedupeAccountingPeriods(db); err != nil {
+		return err
+	}
+
+	if err := db.AutoMigrate(
 		&models.User{},
 		&models.Branch{},
 		&models.Role{},
@@ -117,8 +121,27 @@ func AutoMigrate(db *gorm.DB) error {
 		&models.Account{},
 		&models.Journal{},
 		&models.JournalLine{},
+		&models.Fund{},
+		&models.Program{},
+		&models.BankStatement{},
+		&models.BankStatementLine{},
+		&models.RecurringJournal{},
+		&models.RecurringJournalLine{},
+		&models.RecurringJournalRun{},
+		&models.ApprovalChain{},
+		&models.ApprovalChainStep{},
+		&models.ApprovalRequest{},
+		&models.ApprovalHistory{},
+		&models.Attachment{},
+		&models.ExchangeRate{},
+		&models.FXRevaluation{},
+		&models.DocumentNumberFormat{},
+		&models.DocumentSequence{},
+		&models.AccountMapping{},
 		&models.Budget{},
 		&models.FiscalYear{},
+		&models.AccountBalance{},
+		&models.AccountingPeriod{},
 		&models.Student{},
 		&models.Parent{},
 		&models.StudentParent{},
@@ -137,7 +160,163 @@ func AutoMigrate(db *gorm.DB) error {
 		&models.PurchaseOrder{},
 		&models.PurchaseOrderItem{},
 		&models.Setting{},
+		&models.ReportTemplate{},
 		&models.AuditLog{},
 		&models.Donor{},
-	)
+	); err != nil {
+		return err
+	}
+
+	if err := migrateAccountBalanceKey(db); err != nil {
+		return err
+	}
+	if err := migrateCashAccounts(db); err != nil {
+		return err
+	}
+	return migrateFiscalYearJournals(db)
+}
+
+// migrateFiscalYearJournals links the closing and opening journals of fiscal years closed before
+// journals had a fiscal year, matching them by the reference and date the close gave them
+func migrateFiscalYearJournals(db *gorm.DB) error {
+	return db.Exec(`
+		UPDATE journals SET fiscal_year_id = fy.id
+		FROM fiscal_years fy
+		WHERE journals.fiscal_year_id IS NULL
+			AND journals.reversal_of_id IS NULL
+			AND fy.deleted_at IS NULL
+			AND (
+				(journals.reference_no = 'CLOSE/' || fy.name AND journals.journal_date = fy.end_date)
+				OR (journals.reference_no = 'OPEN/' || fy.name AND journals.journal_date = fy.end_date + INTERVAL '1 day')
+			)
+	`).Error
+}
+
+// migrateCashAccounts marks the accounts that receive and pay out money as cash and cash
+// equivalents for the cash flow statement, on databases where no account is marked yet: the
+// accounts mapped to cash and transfer payments and payroll, and the accounts with bank statements
+func migrateCashAccounts(db *gorm.DB) error {
+	var count int64
+	if err := db.Model(&models.Account{}).
+		Where("cash_flow_activity = ?", models.CashFlowActivityCash).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	mapped := db.Model(&models.AccountMapping{}).
+		Select("account_id").
+		Where("mapping_key IN ?", []string{
+			models.AccountMappingPaymentCash,
+			models.AccountMappingPaymentTransfer,
+			models.AccountMappingPayrollCash,
+			models.AccountMappingPayrollTransfer,
+		})
+	banks := db.Model(&models.BankStatement{}).Select("account_id")
+
+	return db.Model(&models.Account{}).
+		Where("id IN (?) OR id IN (?)", mapped, banks).
+		Update("cash_flow_activity", models.CashFlowActivityCash).Error
+}
+
+// dedupeAccountingPeriods keeps only the latest record of a branch and period before the unique
+// idx_accounting_period_key index is created; concurrent lock requests could create several
+func dedupeAccountingPeriods(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if !migrator.HasTable(&models.AccountingPeriod{}) || migrator.HasIndex(&models.AccountingPeriod{}, "idx_accounting_period_key") {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			DELETE FROM accounting_periods
+			WHERE id IN (
+				SELECT id FROM (
+					SELECT id, ROW_NUMBER() OVER (
+						PARTITION BY branch_id, period ORDER BY updated_at DESC, created_at DESC
+					) AS position
+					FROM accounting_periods
+				) ranked
+				WHERE position > 1
+			)
+		`).Error; err != nil {
+			return err
+		}
+		return tx.Exec("DROP INDEX IF EXISTS idx_accounting_period_branch").Error
+	})
+}
+
+// migrateAccountBalanceKey makes the account, branch, fund, program and period of an account
+// balance unique. Fund and program are nullable, so the index covers them through COALESCE.
+// Rows split by concurrent posts before the index existed are merged first.
+func migrateAccountBalanceKey(db *gorm.DB) error {
+	if db.Migrator().HasIndex(&models.AccountBalance{}, "idx_account_balance_key") {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		// Every posted journal added its debits and credits to exactly one of the split rows,
+		// so summing them keeps the movement; the first row of each key is kept
+		if err := tx.Exec(`
+			WITH merged AS (
+				SELECT MIN(id::text)::uuid AS id, SUM(debit) AS debit, SUM(credit) AS credit
+				FROM account_balances
+				WHERE deleted_at IS NULL
+				GROUP BY account_id, branch_id, fund_id, program_id, period
+				HAVING COUNT(*) > 1
+			)
+			UPDATE account_balances SET debit = merged.debit, credit = merged.credit
+			FROM merged WHERE account_balances.id = merged.id
+		`).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec(`
+			DELETE FROM account_balances duplicate
+			USING account_balances kept
+			WHERE duplicate.deleted_at IS NULL AND kept.deleted_at IS NULL
+				AND duplicate.account_id = kept.account_id
+				AND duplicate.branch_id IS NOT DISTINCT FROM kept.branch_id
+				AND duplicate.fund_id IS NOT DISTINCT FROM kept.fund_id
+				AND duplicate.program_id IS NOT DISTINCT FROM kept.program_id
+				AND duplicate.period = kept.period
+				AND duplicate.id::text > kept.id::text
+		`).Error; err != nil {
+			return err
+		}
+
+		// Carry the merged movement through the opening and closing balances of each key
+		if err := tx.Exec(`
+			UPDATE account_balances SET
+				opening_balance = running.closing - running.movement,
+				closing_balance = running.closing
+			FROM (
+				SELECT b.id, m.movement,
+					SUM(m.movement) OVER (PARTITION BY b.account_id, b.branch_id, b.fund_id, b.program_id ORDER BY b.period) AS closing
+				FROM account_balances b
+				JOIN accounts a ON a.id = b.account_id
+				CROSS JOIN LATERAL (SELECT CASE
+					WHEN a.normal_balance = 'credit'
+						OR (COALESCE(a.normal_balance, '') = '' AND a.category IN ('KEWAJIBAN', 'MODAL', 'PENDAPATAN'))
+					THEN b.credit - b.debit
+					ELSE b.debit - b.credit
+				END AS movement) m
+				WHERE b.deleted_at IS NULL
+			) running
+			WHERE account_balances.id = running.id
+		`).Error; err != nil {
+			return err
+		}
+
+		return tx.Exec(`
+			CREATE UNIQUE INDEX idx_account_balance_key ON account_balances (
+				account_id,
+				(COALESCE(branch_id, '00000000-0000-0000-0000-000000000000')),
+				(COALESCE(fund_id, '00000000-0000-0000-0000-000000000000')),
+				(COALESCE(program_id, '00000000-0000-0000-0000-000000000000')),
+				period
+			)
+		`).Error
+	})
 }
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/yayasan/erp-backend/internal/models"
+	"github.com/yayasan/erp-backend/internal/service"
+	"github.com/yayasan/erp-backend/internal/utils"
+)
+
+type AccountMappingHandler struct {
+	mappingService service.AccountMappingService
+}
+
+func NewAccountMappingHandler(mappingService service.AccountMappingService) *AccountMappingHandler {
+	return &AccountMappingHandler{mappingService: mappingService}
+}
+
+func (h *AccountMappingHandler) GetAll(c *gin.Context) {
+	var branchID *uuid.UUID
+	if branchStr := c.Query("branch_id"); branchStr != "" {
+		id, err := uuid.Parse(branchStr)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID")
+			return
+		}
+		branchID = &id
+	}
+
+	mappings, err := h.mappingService.GetAll(branchID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	responses := make([]models.AccountMappingResponse, len(mappings))
+	for i, mapping := range mappings {
+		responses[i] = *mapping.ToAccountMappingResponse()
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Account mappings retrieved successfully", responses)
+}
+
+func (h *AccountMappingHandler) GetByID(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid account mapping ID")
+		return
+	}
+
+	mapping, err := h.mappingService.GetByID(id)
+	if err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Account mapping retrieved successfully", mapping.ToAccountMappingResponse())
+}
+
+func (h *AccountMappingHandler) Save(c *gin.Context) {
+	var req models.SaveAccountMappingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	mapping, err := h.mappingService.Save(&req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Account mapping saved successfully", mapping.ToAccountMappingResponse())
+}
+
+func (h *AccountMappingHandler) Delete(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid account mapping ID")
+		return
+	}
+
+	if err := h.mappingService.Delete(id); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Account mapping deleted successfully", nil)
+}
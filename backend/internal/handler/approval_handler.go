@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/yayasan/erp-backend/internal/models"
+	"github.com/yayasan/erp-backend/internal/service"
+	"github.com/yayasan/erp-backend/internal/utils"
+)
+
+type ApprovalHandler struct {
+	approvalService service.ApprovalService
+}
+
+func NewApprovalHandler(approvalService service.ApprovalService) *ApprovalHandler {
+	return &ApprovalHandler{
+		approvalService: approvalService,
+	}
+}
+
+func (h *ApprovalHandler) GetChains(c *gin.Context) {
+	chains, err := h.approvalService.GetChains(c.Query("document_type"))
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Approval chains retrieved successfully", chains)
+}
+
+func (h *ApprovalHandler) GetChainByID(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid approval chain ID")
+		return
+	}
+
+	chain, err := h.approvalService.GetChainByID(id)
+	if err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Approval chain retrieved successfully", chain)
+}
+
+func (h *ApprovalHandler) CreateChain(c *gin.Context) {
+	var req models.CreateApprovalChainRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	chain, err := h.approvalService.CreateChain(&req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Approval chain created successfully", chain)
+}
+
+func (h *ApprovalHandler) UpdateChain(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid approval chain ID")
+		return
+	}
+
+	var req models.UpdateApprovalChainRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	chain, err := h.approvalService.UpdateChain(id, &req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Approval chain updated successfully", chain)
+}
+
+func (h *ApprovalHandler) DeleteChain(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid approval chain ID")
+		return
+	}
+
+	if err := h.approvalService.DeleteChain(id); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Approval chain deleted successfully", nil)
+}
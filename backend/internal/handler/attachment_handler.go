@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/yayasan/erp-backend/internal/config"
+	"github.com/yayasan/erp-backend/internal/service"
+	"github.com/yayasan/erp-backend/internal/utils"
+)
+
+type AttachmentHandler struct {
+	attachmentService service.AttachmentService
+}
+
+func NewAttachmentHandler(attachmentService service.AttachmentService) *AttachmentHandler {
+	return &AttachmentHandler{attachmentService: attachmentService}
+}
+
+func (h *AttachmentHandler) GetJournalAttachments(c *gin.Context) {
+	journalID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid journal ID")
+		return
+	}
+
+	attachments, err := h.attachmentService.GetJournalAttachments(journalID)
+	if err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Attachments retrieved successfully", attachments)
+}
+
+func (h *AttachmentHandler) UploadJournalAttachment(c *gin.Context) {
+	journalID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid journal ID")
+		return
+	}
+
+	maxSize := config.GlobalConfig.Upload.MaxSize
+
+	// Leave room for the multipart framing around the file
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize+1<<20)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "File is required and must not exceed "+strconv.FormatInt(maxSize, 10)+" bytes")
+		return
+	}
+
+	if fileHeader.Size > maxSize {
+		utils.ErrorResponse(c, http.StatusBadRequest, "File must not exceed "+strconv.FormatInt(maxSize, 10)+" bytes")
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Failed to read file")
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxSize+1))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Failed to read file")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	attachment, err := h.attachmentService.UploadJournalAttachment(journalID, fileHeader.Filename, fileHeader.Header.Get("Content-Type"), data, userID.(uuid.UUID))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Attachment uploaded successfully", attachment)
+}
+
+func (h *AttachmentHandler) DownloadJournalAttachment(c *gin.Context) {
+	journalID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid journal ID")
+		return
+	}
+
+	attachmentID, err := uuid.Parse(c.Param("attachmentId"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid attachment ID")
+		return
+	}
+
+	attachment, content, err := h.attachmentService.OpenJournalAttachment(journalID, attachmentID)
+	if err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+	defer content.Close()
+
+	disposition := mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName})
+	c.DataFromReader(http.StatusOK, attachment.FileSize, attachment.MimeType, content, map[string]string{
+		"Content-Disposition":    disposition,
+		"X-Content-Type-Options": "nosniff",
+	})
+}
+
+func (h *AttachmentHandler) DeleteJournalAttachment(c *gin.Context) {
+	journalID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid journal ID")
+		return
+	}
+
+	attachmentID, err := uuid.Parse(c.Param("attachmentId"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid attachment ID")
+		return
+	}
+
+	if err := h.attachmentService.DeleteJournalAttachment(journalID, attachmentID); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Attachment deleted successfully", nil)
+}
@@ -0,0 +1,237 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/yayasan/erp-backend/internal/models"
+	"github.com/yayasan/erp-backend/internal/service"
+	"github.com/yayasan/erp-backend/internal/utils"
+)
+
+// maxStatementFileSize limits the size of an uploaded bank statement
+const maxStatementFileSize = 10 << 20
+
+type BankReconciliationHandler struct {
+	reconciliationService service.BankReconciliationService
+}
+
+func NewBankReconciliationHandler(reconciliationService service.BankReconciliationService) *BankReconciliationHandler {
+	return &BankReconciliationHandler{reconciliationService: reconciliationService}
+}
+
+func (h *BankReconciliationHandler) GetAll(c *gin.Context) {
+	var accountID *uuid.UUID
+	if accountStr := c.Query("account_id"); accountStr != "" {
+		id, err := uuid.Parse(accountStr)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid account ID")
+			return
+		}
+		accountID = &id
+	}
+
+	statements, err := h.reconciliationService.GetAll(accountID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Bank statements retrieved successfully", statements)
+}
+
+func (h *BankReconciliationHandler) GetByID(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid bank statement ID")
+		return
+	}
+
+	statement, err := h.reconciliationService.GetByID(id)
+	if err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Bank statement retrieved successfully", statement)
+}
+
+func (h *BankReconciliationHandler) Import(c *gin.Context) {
+	var req models.ImportBankStatementRequest
+	if err := c.ShouldBind(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Statement file is required")
+		return
+	}
+
+	if fileHeader.Size > maxStatementFileSize {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Statement file is too large")
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Failed to read statement file")
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxStatementFileSize))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Failed to read statement file")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	statement, err := h.reconciliationService.Import(&req, fileHeader.Filename, data, userID.(uuid.UUID))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Bank statement imported successfully", statement)
+}
+
+func (h *BankReconciliationHandler) AutoMatch(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid bank statement ID")
+		return
+	}
+
+	var dateWindowDays int
+	if windowStr := c.Query("date_window_days"); windowStr != "" {
+		dateWindowDays, err = strconv.Atoi(windowStr)
+		if err != nil || dateWindowDays < 0 || dateWindowDays > 31 {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid date window")
+			return
+		}
+	}
+
+	statement, err := h.reconciliationService.AutoMatch(id, dateWindowDays)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Bank statement matched successfully", statement)
+}
+
+func (h *BankReconciliationHandler) GetUnmatched(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid bank statement ID")
+		return
+	}
+
+	items, err := h.reconciliationService.GetUnmatched(id)
+	if err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Unmatched items retrieved successfully", items)
+}
+
+func (h *BankReconciliationHandler) MatchLine(c *gin.Context) {
+	lineIDStr := c.Param("line_id")
+	lineID, err := uuid.Parse(lineIDStr)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid bank statement line ID")
+		return
+	}
+
+	var req models.MatchStatementLineRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	line, err := h.reconciliationService.MatchLine(lineID, &req, userID.(uuid.UUID))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Bank statement line matched successfully", line)
+}
+
+func (h *BankReconciliationHandler) UnmatchLine(c *gin.Context) {
+	lineIDStr := c.Param("line_id")
+	lineID, err := uuid.Parse(lineIDStr)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid bank statement line ID")
+		return
+	}
+
+	line, err := h.reconciliationService.UnmatchLine(lineID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Bank statement line unmatched successfully", line)
+}
+
+func (h *BankReconciliationHandler) Reconcile(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid bank statement ID")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	statement, err := h.reconciliationService.Reconcile(id, userID.(uuid.UUID))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Bank statement reconciled successfully", statement)
+}
+
+func (h *BankReconciliationHandler) GetReport(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid bank statement ID")
+		return
+	}
+
+	report, err := h.reconciliationService.GetReport(id)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Bank reconciliation report generated successfully", report)
+}
+
+func (h *BankReconciliationHandler) Delete(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid bank statement ID")
+		return
+	}
+
+	if err := h.reconciliationService.Delete(id); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Bank statement deleted successfully", nil)
+}
@@ -12,10 +12,14 @@ import (
 
 type BudgetHandler struct {
 	budgetService service.BudgetService
+	exportService service.ReportExportService
 }
 
-func NewBudgetHandler(budgetService service.BudgetService) *BudgetHandler {
-	return &BudgetHandler{budgetService: budgetService}
+func NewBudgetHandler(budgetService service.BudgetService, exportService service.ReportExportService) *BudgetHandler {
+	return &BudgetHandler{
+		budgetService: budgetService,
+		exportService: exportService,
+	}
 }
 
 func (h *BudgetHandler) GetAll(c *gin.Context) {
@@ -107,6 +111,11 @@ func (h *BudgetHandler) Delete(c *gin.Context) {
 }
 
 func (h *BudgetHandler) GetBudgetVsActual(c *gin.Context) {
+	format, ok := exportFormat(c)
+	if !ok {
+		return
+	}
+
 	var req models.BudgetVsActualRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		utils.ValidationErrorResponse(c, err)
@@ -119,5 +128,16 @@ func (h *BudgetHandler) GetBudgetVsActual(c *gin.Context) {
 		return
 	}
 
+	if format != models.ExportFormatJSON {
+		userID, _ := c.Get("user_id")
+		exported, err := h.exportService.ExportBudgetVsActual(&req, result, format, userID.(uuid.UUID))
+		if err != nil {
+			utils.InternalServerErrorResponse(c, err.Error())
+			return
+		}
+		sendExport(c, exported)
+		return
+	}
+
 	utils.SuccessResponse(c, http.StatusOK, "Budget vs actual report generated successfully", result)
 }
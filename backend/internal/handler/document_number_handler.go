@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/yayasan/erp-backend/internal/models"
+	"github.com/yayasan/erp-backend/internal/service"
+	"github.com/yayasan/erp-backend/internal/utils"
+)
+
+type DocumentNumberHandler struct {
+	documentNumberService service.DocumentNumberService
+}
+
+func NewDocumentNumberHandler(documentNumberService service.DocumentNumberService) *DocumentNumberHandler {
+	return &DocumentNumberHandler{documentNumberService: documentNumberService}
+}
+
+func (h *DocumentNumberHandler) GetFormats(c *gin.Context) {
+	var branchID *uuid.UUID
+	if branchStr := c.Query("branch_id"); branchStr != "" {
+		id, err := uuid.Parse(branchStr)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID")
+			return
+		}
+		branchID = &id
+	}
+
+	formats, err := h.documentNumberService.GetFormats(branchID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Number formats retrieved successfully", formats)
+}
+
+func (h *DocumentNumberHandler) GetFormatByID(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid number format ID")
+		return
+	}
+
+	format, err := h.documentNumberService.GetFormatByID(id)
+	if err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Number format retrieved successfully", format)
+}
+
+func (h *DocumentNumberHandler) SaveFormat(c *gin.Context) {
+	var req models.SaveDocumentNumberFormatRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	format, err := h.documentNumberService.SaveFormat(&req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Number format saved successfully", format)
+}
+
+func (h *DocumentNumberHandler) DeleteFormat(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid number format ID")
+		return
+	}
+
+	if err := h.documentNumberService.DeleteFormat(id); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Number format deleted successfully", nil)
+}
+
+// Preview shows the next number of a document type in a branch without taking it
+func (h *DocumentNumberHandler) Preview(c *gin.Context) {
+	branchID, err := uuid.Parse(c.Query("branch_id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID")
+		return
+	}
+
+	documentType := c.Query("document_type")
+	if documentType == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "document_type is required")
+		return
+	}
+
+	var date time.Time
+	if dateStr := c.Query("date"); dateStr != "" {
+		date, err = time.ParseInLocation("2006-01-02", dateStr, time.Local)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid date, expected YYYY-MM-DD")
+			return
+		}
+	}
+
+	preview, err := h.documentNumberService.Preview(documentType, c.Query("transaction_type"), branchID, date)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Next number retrieved successfully", preview)
+}
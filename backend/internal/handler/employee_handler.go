@@ -238,7 +238,8 @@ func (h *EmployeeHandler) ProcessPayroll(c *gin.Context) {
 		return
 	}
 
-	payroll, err := h.payrollService.ProcessPayroll(id)
+	userID, _ := c.Get("user_id")
+	payroll, err := h.payrollService.ProcessPayroll(id, userID.(uuid.UUID))
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
@@ -294,4 +295,30 @@ func (h *EmployeeHandler) GenerateBulkPayroll(c *gin.Context) {
 		"total":    len(payrolls),
 		"payrolls": payrolls,
 	})
+}
+
+// Bulk payroll processing
+type ProcessBulkPayrollRequest struct {
+	BranchID uuid.UUID `json:"branch_id" binding:"required"`
+	Period   string    `json:"period" binding:"required"`
+}
+
+func (h *EmployeeHandler) ProcessBulkPayroll(c *gin.Context) {
+	var req ProcessBulkPayrollRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	payrolls, err := h.payrollService.ProcessBulkPayroll(req.BranchID, req.Period, userID.(uuid.UUID))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Bulk payroll processed successfully", map[string]interface{}{
+		"total":    len(payrolls),
+		"payrolls": payrolls,
+	})
 }
\ No newline at end of file
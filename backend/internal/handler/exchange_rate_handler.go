@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/yayasan/erp-backend/internal/models"
+	"github.com/yayasan/erp-backend/internal/service"
+	"github.com/yayasan/erp-backend/internal/utils"
+)
+
+type ExchangeRateHandler struct {
+	exchangeRateService service.ExchangeRateService
+}
+
+func NewExchangeRateHandler(exchangeRateService service.ExchangeRateService) *ExchangeRateHandler {
+	return &ExchangeRateHandler{
+		exchangeRateService: exchangeRateService,
+	}
+}
+
+func (h *ExchangeRateHandler) GetAll(c *gin.Context) {
+	rates, err := h.exchangeRateService.GetAll(c.Query("currency"))
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Exchange rates retrieved successfully", rates)
+}
+
+func (h *ExchangeRateHandler) Save(c *gin.Context) {
+	var req models.SaveExchangeRateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	rate, err := h.exchangeRateService.Save(&req, userID.(uuid.UUID))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Exchange rate saved successfully", rate)
+}
+
+func (h *ExchangeRateHandler) Delete(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid exchange rate ID")
+		return
+	}
+
+	if err := h.exchangeRateService.Delete(id); err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Exchange rate deleted successfully", nil)
+}
+
+func (h *ExchangeRateHandler) GetRevaluations(c *gin.Context) {
+	var branchID *uuid.UUID
+	if branchIDStr := c.Query("branch_id"); branchIDStr != "" {
+		id, err := uuid.Parse(branchIDStr)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID")
+			return
+		}
+		branchID = &id
+	}
+
+	revaluations, err := h.exchangeRateService.GetRevaluations(branchID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "FX revaluations retrieved successfully", revaluations)
+}
+
+// Revalue revalues foreign currency accounts at a month end, the same way the scheduler does
+func (h *ExchangeRateHandler) Revalue(c *gin.Context) {
+	var req models.RunFXRevaluationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	processedBy := userID.(uuid.UUID)
+	result, err := h.exchangeRateService.Revalue(&req, &processedBy)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "FX revaluation completed", result)
+}
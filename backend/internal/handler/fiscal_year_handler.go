@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/yayasan/erp-backend/internal/models"
+	"github.com/yayasan/erp-backend/internal/service"
+	"github.com/yayasan/erp-backend/internal/utils"
+)
+
+type FiscalYearHandler struct {
+	fiscalYearService service.FiscalYearService
+}
+
+func NewFiscalYearHandler(fiscalYearService service.FiscalYearService) *FiscalYearHandler {
+	return &FiscalYearHandler{fiscalYearService: fiscalYearService}
+}
+
+func (h *FiscalYearHandler) GetAll(c *gin.Context) {
+	fiscalYears, err := h.fiscalYearService.GetAll()
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Fiscal years retrieved successfully", fiscalYears)
+}
+
+func (h *FiscalYearHandler) GetCurrent(c *gin.Context) {
+	fiscalYear, err := h.fiscalYearService.GetCurrent()
+	if err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Current fiscal year retrieved successfully", fiscalYear)
+}
+
+func (h *FiscalYearHandler) GetByID(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid fiscal year ID")
+		return
+	}
+
+	fiscalYear, err := h.fiscalYearService.GetByID(id)
+	if err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Fiscal year retrieved successfully", fiscalYear)
+}
+
+func (h *FiscalYearHandler) Create(c *gin.Context) {
+	var req models.CreateFiscalYearRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	fiscalYear, err := h.fiscalYearService.Create(&req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Fiscal year created successfully", fiscalYear)
+}
+
+func (h *FiscalYearHandler) SetCurrent(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid fiscal year ID")
+		return
+	}
+
+	fiscalYear, err := h.fiscalYearService.SetCurrent(id)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Current fiscal year updated successfully", fiscalYear)
+}
+
+func (h *FiscalYearHandler) Close(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid fiscal year ID")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	fiscalYear, err := h.fiscalYearService.Close(id, userID.(uuid.UUID))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Fiscal year closed successfully", fiscalYear)
+}
+
+func (h *FiscalYearHandler) Reopen(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid fiscal year ID")
+		return
+	}
+
+	var req models.ReopenFiscalYearRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	fiscalYear, err := h.fiscalYearService.Reopen(id, &req, userID.(uuid.UUID))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Fiscal year reopened successfully", fiscalYear)
+}
@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/yayasan/erp-backend/internal/models"
+	"github.com/yayasan/erp-backend/internal/service"
+	"github.com/yayasan/erp-backend/internal/utils"
+)
+
+type FundHandler struct {
+	fundService    service.FundService
+	programService service.ProgramService
+}
+
+func NewFundHandler(fundService service.FundService, programService service.ProgramService) *FundHandler {
+	return &FundHandler{
+		fundService:    fundService,
+		programService: programService,
+	}
+}
+
+func (h *FundHandler) GetAll(c *gin.Context) {
+	funds, err := h.fundService.GetAll()
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Funds retrieved successfully", funds)
+}
+
+func (h *FundHandler) GetAllActive(c *gin.Context) {
+	funds, err := h.fundService.GetAllActive()
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Active funds retrieved", funds)
+}
+
+func (h *FundHandler) GetByID(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid fund ID")
+		return
+	}
+
+	fund, err := h.fundService.GetByID(id)
+	if err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Fund retrieved successfully", fund)
+}
+
+func (h *FundHandler) Create(c *gin.Context) {
+	var req models.CreateFundRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	fund, err := h.fundService.Create(&req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Fund created successfully", fund)
+}
+
+func (h *FundHandler) Update(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid fund ID")
+		return
+	}
+
+	var req models.UpdateFundRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	fund, err := h.fundService.Update(id, &req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Fund updated successfully", fund)
+}
+
+func (h *FundHandler) Delete(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid fund ID")
+		return
+	}
+
+	if err := h.fundService.Delete(id); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Fund deleted successfully", nil)
+}
+
+func (h *FundHandler) GetAllPrograms(c *gin.Context) {
+	programs, err := h.programService.GetAll()
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Programs retrieved successfully", programs)
+}
+
+func (h *FundHandler) GetAllActivePrograms(c *gin.Context) {
+	programs, err := h.programService.GetAllActive()
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Active programs retrieved", programs)
+}
+
+func (h *FundHandler) GetProgramByID(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid program ID")
+		return
+	}
+
+	program, err := h.programService.GetByID(id)
+	if err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Program retrieved successfully", program)
+}
+
+func (h *FundHandler) CreateProgram(c *gin.Context) {
+	var req models.CreateProgramRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	program, err := h.programService.Create(&req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Program created successfully", program)
+}
+
+func (h *FundHandler) UpdateProgram(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid program ID")
+		return
+	}
+
+	var req models.UpdateProgramRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	program, err := h.programService.Update(id, &req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Program updated successfully", program)
+}
+
+func (h *FundHandler) DeleteProgram(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid program ID")
+		return
+	}
+
+	if err := h.programService.Delete(id); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Program deleted successfully", nil)
+}
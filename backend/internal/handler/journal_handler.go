@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"io"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -10,30 +11,153 @@ import (
 	"github.com/yayasan/erp-backend/internal/utils"
 )
 
+// maxJournalImportFileSize limits the size of an uploaded journal import file
+const maxJournalImportFileSize = 10 << 20
+
 type JournalHandler struct {
 	journalService service.JournalService
+	exportService  service.ReportExportService
 }
 
-func NewJournalHandler(journalService service.JournalService) *JournalHandler {
-	return &JournalHandler{journalService: journalService}
+func NewJournalHandler(journalService service.JournalService, exportService service.ReportExportService) *JournalHandler {
+	return &JournalHandler{
+		journalService: journalService,
+		exportService:  exportService,
+	}
 }
 
-func (h *JournalHandler) GetAll(c *gin.Context) {
-	var params models.PaginationParams
-	if err := c.ShouldBindQuery(&params); err != nil {
-		utils.ValidationErrorResponse(c, err)
+// Search finds journals, as JSON pages or as a CSV, XLSX or PDF file of all results
+func (h *JournalHandler) Search(c *gin.Context) {
+	format, ok := exportFormat(c)
+	if !ok {
+		return
+	}
+
+	params, ok := bindJournalSearch(c)
+	if !ok {
+		return
+	}
+
+	if format != models.ExportFormatJSON {
+		journals, err := h.journalService.SearchAll(params)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		userID, _ := c.Get("user_id")
+		exported, err := h.exportService.ExportJournals(params, journals, format, userID.(uuid.UUID))
+		if err != nil {
+			utils.InternalServerErrorResponse(c, err.Error())
+			return
+		}
+		sendExport(c, exported)
 		return
 	}
 
-	result, err := h.journalService.GetAll(&params)
+	result, err := h.journalService.Search(params)
 	if err != nil {
-		utils.InternalServerErrorResponse(c, err.Error())
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	utils.SuccessResponse(c, http.StatusOK, "Journals retrieved successfully", result)
 }
 
+// SearchLines finds journal lines with the same filters as Search
+func (h *JournalHandler) SearchLines(c *gin.Context) {
+	format, ok := exportFormat(c)
+	if !ok {
+		return
+	}
+
+	params, ok := bindJournalSearch(c)
+	if !ok {
+		return
+	}
+
+	if format != models.ExportFormatJSON {
+		lines, err := h.journalService.SearchAllLines(params)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		userID, _ := c.Get("user_id")
+		exported, err := h.exportService.ExportJournalLines(params, lines, format, userID.(uuid.UUID))
+		if err != nil {
+			utils.InternalServerErrorResponse(c, err.Error())
+			return
+		}
+		sendExport(c, exported)
+		return
+	}
+
+	result, err := h.journalService.SearchLines(params)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Journal lines retrieved successfully", result)
+}
+
+// bindJournalSearch reads the search filters from the query string
+func bindJournalSearch(c *gin.Context) (*models.JournalSearchParams, bool) {
+	var params models.JournalSearchParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return nil, false
+	}
+
+	ids := []struct {
+		name  string
+		label string
+		field **uuid.UUID
+	}{
+		{"branch_id", "branch", &params.BranchID},
+		{"account_id", "account", &params.AccountID},
+		{"fund_id", "fund", &params.FundID},
+		{"program_id", "program", &params.ProgramID},
+		{"donor_id", "donor", &params.DonorID},
+		{"created_by", "creator", &params.CreatedBy},
+	}
+	for _, id := range ids {
+		value := c.Query(id.name)
+		if value == "" {
+			continue
+		}
+		parsed, err := uuid.Parse(value)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid "+id.label+" ID")
+			return nil, false
+		}
+		*id.field = &parsed
+	}
+
+	amounts := []struct {
+		name  string
+		field **models.Money
+	}{
+		{"min_amount", &params.MinAmount},
+		{"max_amount", &params.MaxAmount},
+	}
+	for _, amount := range amounts {
+		value := c.Query(amount.name)
+		if value == "" {
+			continue
+		}
+		parsed, err := models.ParseMoney(value)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid "+amount.name)
+			return nil, false
+		}
+		*amount.field = &parsed
+	}
+
+	return &params, true
+}
+
 func (h *JournalHandler) GetByID(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
@@ -86,6 +210,68 @@ func (h *JournalHandler) Create(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusCreated, "Journal created successfully", journal.ToJournalResponse())
 }
 
+// Import validates a CSV/XLSX file of journal lines and creates the valid journals as drafts.
+// The validation report is returned whether or not anything was imported.
+func (h *JournalHandler) Import(c *gin.Context) {
+	var req models.ImportJournalsRequest
+	if err := c.ShouldBind(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	if branchIDStr := c.PostForm("branch_id"); branchIDStr != "" {
+		branchID, err := uuid.Parse(branchIDStr)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID")
+			return
+		}
+		req.BranchID = &branchID
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Import file is required")
+		return
+	}
+
+	if fileHeader.Size > maxJournalImportFileSize {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Import file is too large")
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Failed to read import file")
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxJournalImportFileSize))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Failed to read import file")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	result, err := h.journalService.Import(&req, fileHeader.Filename, data, userID.(uuid.UUID))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	message := "Journals imported successfully"
+	switch {
+	case result.DryRun:
+		message = "Import file validated, nothing was imported"
+	case result.ImportedJournals == 0:
+		message = "No journals were imported, see the errors"
+	case len(result.Errors) > 0:
+		message = "Valid journals imported, see the errors for the rest"
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, message, result)
+}
+
 func (h *JournalHandler) Update(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
@@ -169,6 +355,23 @@ func (h *JournalHandler) Review(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "Journal reviewed successfully", journal.ToJournalResponse())
 }
 
+func (h *JournalHandler) GetApprovals(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid journal ID")
+		return
+	}
+
+	approvals, err := h.journalService.GetApprovals(id)
+	if err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Journal approvals retrieved successfully", approvals)
+}
+
 func (h *JournalHandler) Post(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
@@ -193,7 +396,7 @@ func (h *JournalHandler) Post(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "Journal posted successfully", journal.ToJournalResponse())
 }
 
-func (h *JournalHandler) Unpost(c *gin.Context) {
+func (h *JournalHandler) Reverse(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
@@ -201,12 +404,18 @@ func (h *JournalHandler) Unpost(c *gin.Context) {
 		return
 	}
 
+	var req models.ReverseJournalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
 	userID, _ := c.Get("user_id")
-	journal, err := h.journalService.Unpost(id, userID.(uuid.UUID))
+	journal, err := h.journalService.Reverse(id, &req, userID.(uuid.UUID))
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	utils.SuccessResponse(c, http.StatusOK, "Journal unposted successfully", journal.ToJournalResponse())
+	utils.SuccessResponse(c, http.StatusCreated, "Journal reversed successfully", journal.ToJournalResponse())
 }
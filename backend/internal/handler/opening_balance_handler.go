@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/yayasan/erp-backend/internal/models"
+	"github.com/yayasan/erp-backend/internal/service"
+	"github.com/yayasan/erp-backend/internal/utils"
+)
+
+type OpeningBalanceHandler struct {
+	openingBalanceService service.OpeningBalanceService
+}
+
+func NewOpeningBalanceHandler(openingBalanceService service.OpeningBalanceService) *OpeningBalanceHandler {
+	return &OpeningBalanceHandler{openingBalanceService: openingBalanceService}
+}
+
+// Import loads opening balances from an uploaded CSV or XLSX file
+func (h *OpeningBalanceHandler) Import(c *gin.Context) {
+	var req models.ImportOpeningBalancesRequest
+	if err := c.ShouldBind(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	if branchIDStr := c.PostForm("branch_id"); branchIDStr != "" {
+		branchID, err := uuid.Parse(branchIDStr)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID")
+			return
+		}
+		req.BranchID = &branchID
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Import file is required")
+		return
+	}
+
+	if fileHeader.Size > maxJournalImportFileSize {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Import file is too large")
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Failed to read import file")
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxJournalImportFileSize))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Failed to read import file")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	result, err := h.openingBalanceService.Import(&req, fileHeader.Filename, data, userID.(uuid.UUID))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	message := "Opening balances imported successfully"
+	switch {
+	case len(result.Errors) > 0:
+		message = "No opening balances were imported, see the errors"
+	case result.DryRun:
+		message = "Opening balances validated, nothing was imported"
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, message, result)
+}
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/yayasan/erp-backend/internal/models"
+	"github.com/yayasan/erp-backend/internal/service"
+	"github.com/yayasan/erp-backend/internal/utils"
+)
+
+type PeriodHandler struct {
+	periodService service.PeriodService
+}
+
+func NewPeriodHandler(periodService service.PeriodService) *PeriodHandler {
+	return &PeriodHandler{periodService: periodService}
+}
+
+func (h *PeriodHandler) GetByBranch(c *gin.Context) {
+	branchID, err := uuid.Parse(c.Query("branch_id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID")
+		return
+	}
+
+	periods, err := h.periodService.GetByBranch(branchID, c.Query("year"))
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Accounting periods retrieved successfully", periods)
+}
+
+func (h *PeriodHandler) SetStatus(c *gin.Context) {
+	var req models.SetPeriodStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	period, err := h.periodService.SetStatus(&req, userID.(uuid.UUID))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Accounting period updated successfully", period)
+}
@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/yayasan/erp-backend/internal/models"
+	"github.com/yayasan/erp-backend/internal/service"
+	"github.com/yayasan/erp-backend/internal/utils"
+)
+
+type RecurringJournalHandler struct {
+	recurringService service.RecurringJournalService
+}
+
+func NewRecurringJournalHandler(recurringService service.RecurringJournalService) *RecurringJournalHandler {
+	return &RecurringJournalHandler{
+		recurringService: recurringService,
+	}
+}
+
+func (h *RecurringJournalHandler) GetAll(c *gin.Context) {
+	recurring, err := h.recurringService.GetAll(c.Query("status"))
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Recurring journals retrieved successfully", recurring)
+}
+
+func (h *RecurringJournalHandler) GetByID(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid recurring journal ID")
+		return
+	}
+
+	recurring, err := h.recurringService.GetByID(id)
+	if err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Recurring journal retrieved successfully", recurring)
+}
+
+func (h *RecurringJournalHandler) GetRuns(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid recurring journal ID")
+		return
+	}
+
+	runs, err := h.recurringService.GetRuns(id)
+	if err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Recurring journal history retrieved successfully", runs)
+}
+
+func (h *RecurringJournalHandler) Create(c *gin.Context) {
+	var req models.CreateRecurringJournalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	recurring, err := h.recurringService.Create(&req, userID.(uuid.UUID))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Recurring journal created successfully", recurring)
+}
+
+func (h *RecurringJournalHandler) Update(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid recurring journal ID")
+		return
+	}
+
+	var req models.UpdateRecurringJournalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	recurring, err := h.recurringService.Update(id, &req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Recurring journal updated successfully", recurring)
+}
+
+func (h *RecurringJournalHandler) Delete(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid recurring journal ID")
+		return
+	}
+
+	if err := h.recurringService.Delete(id); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Recurring journal deleted successfully", nil)
+}
+
+func (h *RecurringJournalHandler) Pause(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid recurring journal ID")
+		return
+	}
+
+	recurring, err := h.recurringService.Pause(id)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Recurring journal paused successfully", recurring)
+}
+
+func (h *RecurringJournalHandler) Resume(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid recurring journal ID")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	recurring, err := h.recurringService.Resume(id, userID.(uuid.UUID))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Recurring journal resumed successfully", recurring)
+}
+
+func (h *RecurringJournalHandler) Skip(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid recurring journal ID")
+		return
+	}
+
+	var req models.SkipRecurringJournalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	run, err := h.recurringService.Skip(id, &req, userID.(uuid.UUID))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Recurring journal due date skipped successfully", run)
+}
+
+// Generate creates the journals that are due, the same way the scheduler does
+func (h *RecurringJournalHandler) Generate(c *gin.Context) {
+	asOfDate := time.Now()
+	if dateStr := c.Query("as_of_date"); dateStr != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", dateStr, time.Local)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid as_of_date, expected YYYY-MM-DD")
+			return
+		}
+		if parsed.After(asOfDate) {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Cannot generate journals for future dates")
+			return
+		}
+		asOfDate = parsed
+	}
+
+	userID, _ := c.Get("user_id")
+	processedBy := userID.(uuid.UUID)
+	result, err := h.recurringService.GenerateDue(asOfDate, &processedBy)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Recurring journals generated successfully", result)
+}
@@ -1,9 +1,12 @@
 package handler
 
 import (
+	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/yayasan/erp-backend/internal/models"
 	"github.com/yayasan/erp-backend/internal/service"
 	"github.com/yayasan/erp-backend/internal/utils"
@@ -11,13 +14,22 @@ import (
 
 type ReportHandler struct {
 	reportService service.ReportService
+	exportService service.ReportExportService
 }
 
-func NewReportHandler(reportService service.ReportService) *ReportHandler {
-	return &ReportHandler{reportService: reportService}
+func NewReportHandler(reportService service.ReportService, exportService service.ReportExportService) *ReportHandler {
+	return &ReportHandler{
+		reportService: reportService,
+		exportService: exportService,
+	}
 }
 
 func (h *ReportHandler) GetTrialBalance(c *gin.Context) {
+	format, ok := exportFormat(c)
+	if !ok {
+		return
+	}
+
 	var req models.TrialBalanceRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		utils.ValidationErrorResponse(c, err)
@@ -30,10 +42,26 @@ func (h *ReportHandler) GetTrialBalance(c *gin.Context) {
 		return
 	}
 
+	if format != models.ExportFormatJSON {
+		userID, _ := c.Get("user_id")
+		exported, err := h.exportService.ExportTrialBalance(&req, result, format, userID.(uuid.UUID))
+		if err != nil {
+			utils.InternalServerErrorResponse(c, err.Error())
+			return
+		}
+		sendExport(c, exported)
+		return
+	}
+
 	utils.SuccessResponse(c, http.StatusOK, "Trial balance generated successfully", result)
 }
 
 func (h *ReportHandler) GetBalanceSheet(c *gin.Context) {
+	format, ok := exportFormat(c)
+	if !ok {
+		return
+	}
+
 	var req models.BalanceSheetRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		utils.ValidationErrorResponse(c, err)
@@ -46,10 +74,26 @@ func (h *ReportHandler) GetBalanceSheet(c *gin.Context) {
 		return
 	}
 
+	if format != models.ExportFormatJSON {
+		userID, _ := c.Get("user_id")
+		exported, err := h.exportService.ExportBalanceSheet(&req, result, format, userID.(uuid.UUID))
+		if err != nil {
+			utils.InternalServerErrorResponse(c, err.Error())
+			return
+		}
+		sendExport(c, exported)
+		return
+	}
+
 	utils.SuccessResponse(c, http.StatusOK, "Balance sheet generated successfully", result)
 }
 
 func (h *ReportHandler) GetIncomeStatement(c *gin.Context) {
+	format, ok := exportFormat(c)
+	if !ok {
+		return
+	}
+
 	var req models.IncomeStatementRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		utils.ValidationErrorResponse(c, err)
@@ -62,10 +106,26 @@ func (h *ReportHandler) GetIncomeStatement(c *gin.Context) {
 		return
 	}
 
+	if format != models.ExportFormatJSON {
+		userID, _ := c.Get("user_id")
+		exported, err := h.exportService.ExportIncomeStatement(&req, result, format, userID.(uuid.UUID))
+		if err != nil {
+			utils.InternalServerErrorResponse(c, err.Error())
+			return
+		}
+		sendExport(c, exported)
+		return
+	}
+
 	utils.SuccessResponse(c, http.StatusOK, "Income statement generated successfully", result)
 }
 
 func (h *ReportHandler) GetGeneralLedger(c *gin.Context) {
+	format, ok := exportFormat(c)
+	if !ok {
+		return
+	}
+
 	var req models.GeneralLedgerRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		utils.ValidationErrorResponse(c, err)
@@ -78,5 +138,224 @@ func (h *ReportHandler) GetGeneralLedger(c *gin.Context) {
 		return
 	}
 
+	if format != models.ExportFormatJSON {
+		userID, _ := c.Get("user_id")
+		exported, err := h.exportService.ExportGeneralLedger(&req, result, format, userID.(uuid.UUID))
+		if err != nil {
+			utils.InternalServerErrorResponse(c, err.Error())
+			return
+		}
+		sendExport(c, exported)
+		return
+	}
+
 	utils.SuccessResponse(c, http.StatusOK, "General ledger generated successfully", result)
 }
+
+func (h *ReportHandler) GetCashFlow(c *gin.Context) {
+	var req models.CashFlowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	result, err := h.reportService.GetCashFlow(&req)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Cash flow statement generated successfully", result)
+}
+
+func (h *ReportHandler) GetFinancialPosition(c *gin.Context) {
+	var req models.FinancialPositionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	result, err := h.reportService.GetFinancialPosition(&req)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Statement of financial position generated successfully", result)
+}
+
+func (h *ReportHandler) GetComprehensiveIncome(c *gin.Context) {
+	var req models.NetAssetsPeriodRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	result, err := h.reportService.GetComprehensiveIncome(&req)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Statement of comprehensive income generated successfully", result)
+}
+
+func (h *ReportHandler) GetChangesInNetAssets(c *gin.Context) {
+	var req models.NetAssetsPeriodRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	result, err := h.reportService.GetChangesInNetAssets(&req)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Statement of changes in net assets generated successfully", result)
+}
+
+func (h *ReportHandler) GetFundBalance(c *gin.Context) {
+	var req models.FundBalanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	result, err := h.reportService.GetFundBalance(&req)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Fund balance report generated successfully", result)
+}
+
+func (h *ReportHandler) GetProgramExpenses(c *gin.Context) {
+	var req models.ProgramExpenseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	result, err := h.reportService.GetProgramExpenses(&req)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Program expense report generated successfully", result)
+}
+
+func (h *ReportHandler) GetInterBranchBalances(c *gin.Context) {
+	var req models.InterBranchBalanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	result, err := h.reportService.GetInterBranchBalances(&req)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Inter-branch balance report generated successfully", result)
+}
+
+func (h *ReportHandler) GetConsolidatedBalanceSheet(c *gin.Context) {
+	var req models.ConsolidatedBalanceSheetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	result, err := h.reportService.GetConsolidatedBalanceSheet(&req)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Consolidated balance sheet generated successfully", result)
+}
+
+func (h *ReportHandler) GetConsolidatedIncomeStatement(c *gin.Context) {
+	var req models.ConsolidatedIncomeStatementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	result, err := h.reportService.GetConsolidatedIncomeStatement(&req)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Consolidated income statement generated successfully", result)
+}
+
+func (h *ReportHandler) GetComparativeIncomeStatement(c *gin.Context) {
+	var req models.ComparativeIncomeStatementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	result, err := h.reportService.GetComparativeIncomeStatement(&req)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Comparative income statement generated successfully", result)
+}
+
+func (h *ReportHandler) GetComparativeBalanceSheet(c *gin.Context) {
+	var req models.ComparativeBalanceSheetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	result, err := h.reportService.GetComparativeBalanceSheet(&req)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Comparative balance sheet generated successfully", result)
+}
+
+func (h *ReportHandler) GetComparativeTrialBalance(c *gin.Context) {
+	var req models.ComparativeTrialBalanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	result, err := h.reportService.GetComparativeTrialBalance(&req)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Comparative trial balance generated successfully", result)
+}
+
+// exportFormat reads the optional format query parameter, defaulting to JSON
+func exportFormat(c *gin.Context) (string, bool) {
+	format := strings.ToLower(c.DefaultQuery("format", models.ExportFormatJSON))
+	if format != models.ExportFormatJSON && !models.IsExportFormat(format) {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid export format")
+		return "", false
+	}
+	return format, true
+}
+
+// sendExport streams an exported report as a file download
+func sendExport(c *gin.Context, exported *models.ExportedReport) {
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", exported.FileName))
+	c.Data(http.StatusOK, exported.ContentType, exported.Data)
+}
@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/yayasan/erp-backend/internal/models"
+	"github.com/yayasan/erp-backend/internal/service"
+	"github.com/yayasan/erp-backend/internal/utils"
+)
+
+type ReportTemplateHandler struct {
+	templateService service.ReportTemplateService
+	reportService   service.ReportService
+}
+
+func NewReportTemplateHandler(templateService service.ReportTemplateService, reportService service.ReportService) *ReportTemplateHandler {
+	return &ReportTemplateHandler{
+		templateService: templateService,
+		reportService:   reportService,
+	}
+}
+
+func (h *ReportTemplateHandler) GetAll(c *gin.Context) {
+	activeOnly := c.Query("active") == "true"
+
+	templates, err := h.templateService.GetAll(activeOnly)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Report templates retrieved successfully", templates)
+}
+
+func (h *ReportTemplateHandler) GetByID(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid report template ID")
+		return
+	}
+
+	template, err := h.templateService.GetByID(id)
+	if err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Report template retrieved successfully", template)
+}
+
+func (h *ReportTemplateHandler) Create(c *gin.Context) {
+	var req models.CreateReportTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	template, err := h.templateService.Create(&req, userID.(uuid.UUID))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Report template created successfully", template)
+}
+
+func (h *ReportTemplateHandler) Update(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid report template ID")
+		return
+	}
+
+	var req models.UpdateReportTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	template, err := h.templateService.Update(id, &req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Report template updated successfully", template)
+}
+
+func (h *ReportTemplateHandler) Delete(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid report template ID")
+		return
+	}
+
+	if err := h.templateService.Delete(id); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Report template deleted successfully", nil)
+}
+
+func (h *ReportTemplateHandler) Run(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid report template ID")
+		return
+	}
+
+	var req models.RunReportTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	template, err := h.templateService.GetByID(id)
+	if err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	result, err := h.reportService.RunTemplate(template, &req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Report generated successfully", result)
+}
@@ -20,6 +20,8 @@ type Account struct {
 	IsDetail    bool       `gorm:"default:false" json:"is_detail"` // Can post transactions
 	Level       int        `gorm:"default:0" json:"level"`
 	Description string     `gorm:"type:text" json:"description"`
+	CashFlowActivity string `gorm:"size:20" json:"cash_flow_activity"` // cash, operating, investing, financing
+	Currency    string     `gorm:"size:3" json:"currency,omitempty"` // Foreign currency of the account; empty for the default currency
 	
 	// Relationships
 	Parent   *Account   `gorm:"foreignKey:ParentID" json:"parent,omitempty"`
@@ -50,6 +52,14 @@ const (
 	AccountCategoryExpense    = "BIAYA"
 )
 
+// CashFlowActivity constants
+const (
+	CashFlowActivityCash      = "cash" // Cash and cash equivalents
+	CashFlowActivityOperating = "operating"
+	CashFlowActivityInvesting = "investing"
+	CashFlowActivityFinancing = "financing"
+)
+
 // NormalBalance constants
 const (
 	NormalBalanceDebit  = "debit"
@@ -66,6 +76,18 @@ func (a *Account) CanPostTransaction() bool {
 	return a.IsDetail && a.IsActive && !a.IsHeader()
 }
 
+// GetCashFlowActivity returns the cash flow activity, defaulting equity to financing and everything else to operating
+func (a *Account) GetCashFlowActivity() string {
+	if a.CashFlowActivity != "" {
+		return a.CashFlowActivity
+	}
+
+	if a.Category == AccountCategoryEquity {
+		return CashFlowActivityFinancing
+	}
+	return CashFlowActivityOperating
+}
+
 // GetNormalBalance returns normal balance based on category
 func (a *Account) GetNormalBalance() string {
 	if a.NormalBalance != "" {
@@ -98,6 +120,8 @@ type AccountResponse struct {
 	IsDetail      bool             `json:"is_detail"`
 	Level         int              `json:"level"`
 	Description   string           `json:"description,omitempty"`
+	CashFlowActivity string        `json:"cash_flow_activity"`
+	Currency      string           `json:"currency,omitempty"`
 	Parent        *AccountResponse `json:"parent,omitempty"`
 	Children      []AccountResponse `json:"children,omitempty"`
 	CreatedAt     time.Time        `json:"created_at"`
@@ -120,6 +144,8 @@ func (a *Account) ToAccountResponse() *AccountResponse {
 		IsDetail:      a.IsDetail,
 		Level:         a.Level,
 		Description:   a.Description,
+		CashFlowActivity: a.GetCashFlowActivity(),
+		Currency:      a.Currency,
 		CreatedAt:     a.CreatedAt,
 		UpdatedAt:     a.UpdatedAt,
 	}
@@ -161,6 +187,8 @@ type CreateAccountRequest struct {
 	Category      string     `json:"category" binding:"required,oneof=ASET KEWAJIBAN MODAL PENDAPATAN BIAYA"`
 	NormalBalance string     `json:"normal_balance" binding:"omitempty,oneof=debit credit"`
 	Description   string     `json:"description"`
+	CashFlowActivity string  `json:"cash_flow_activity" binding:"omitempty,oneof=cash operating investing financing"`
+	Currency      string     `json:"currency" binding:"omitempty,len=3,alpha"` // Foreign currency accounts only
 }
 
 // UpdateAccountRequest for updating account
@@ -169,6 +197,8 @@ type UpdateAccountRequest struct {
 	NameEn        string  `json:"name_en" binding:"max=200"`
 	IsActive      *bool   `json:"is_active"`
 	Description   string  `json:"description"`
+	CashFlowActivity *string `json:"cash_flow_activity" binding:"omitempty,oneof=cash operating investing financing"`
+	Currency      *string `json:"currency" binding:"omitempty,max=3"` // Only while the account has no journal lines
 }
 
 // AccountListResponse for paginated account list
@@ -0,0 +1,119 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AccountMapping maps a posting purpose to a COA account for automatic journals
+type AccountMapping struct {
+	BaseModel
+	BranchID    *uuid.UUID `gorm:"type:uuid;index" json:"branch_id,omitempty"` // NULL = default for all branches
+	MappingKey  string     `gorm:"size:100;not null;index" json:"mapping_key"`
+	AccountID   uuid.UUID  `gorm:"type:uuid;not null" json:"account_id"`
+	Description string     `gorm:"type:text" json:"description,omitempty"`
+
+	// Relationships
+	Branch  *Branch `gorm:"foreignKey:BranchID" json:"branch,omitempty"`
+	Account Account `gorm:"foreignKey:AccountID" json:"account"`
+}
+
+// TableName specifies table name
+func (AccountMapping) TableName() string {
+	return "account_mappings"
+}
+
+// Account Mapping Key constants
+const (
+	AccountMappingPaymentCash           = "payment.cash"
+	AccountMappingPaymentTransfer       = "payment.transfer"
+	AccountMappingPaymentCard           = "payment.card"
+	AccountMappingPaymentVirtualAccount = "payment.virtual_account"
+	AccountMappingStudentReceivable     = "receivable.student"
+
+	AccountMappingPayrollSalaryExpense = "payroll.salary_expense"
+	AccountMappingPayrollCash          = "payroll.cash"
+	AccountMappingPayrollTransfer      = "payroll.transfer"
+	AccountMappingPayrollPPh21Payable  = "payroll.pph21_payable"
+	AccountMappingPayrollBPJSPayable   = "payroll.bpjs_payable"
+	AccountMappingPayrollLoan          = "payroll.loan"
+	AccountMappingPayrollOtherPayable  = "payroll.other_payable"
+
+	AccountMappingInterBranchDueFrom     = "interbranch.due_from"
+	AccountMappingInterBranchDueTo       = "interbranch.due_to"
+	AccountMappingInterBranchTransferIn  = "interbranch.transfer_in"
+	AccountMappingInterBranchTransferOut = "interbranch.transfer_out"
+
+	AccountMappingFXUnrealizedGain = "fx.unrealized_gain"
+	AccountMappingFXUnrealizedLoss = "fx.unrealized_loss"
+)
+
+// InterBranchMappingKeys lists the mappings of inter-branch accounts, eliminated on consolidation
+func InterBranchMappingKeys() []string {
+	return []string{
+		AccountMappingInterBranchDueFrom,
+		AccountMappingInterBranchDueTo,
+		AccountMappingInterBranchTransferIn,
+		AccountMappingInterBranchTransferOut,
+	}
+}
+
+// PaymentMethodMappingKey returns the account mapping key for a payment method
+func PaymentMethodMappingKey(method string) string {
+	return "payment." + method
+}
+
+// PayrollPaymentMappingKey returns the account mapping key used to pay net salary
+func PayrollPaymentMappingKey(method string) string {
+	return "payroll." + method
+}
+
+// DepartmentSalaryExpenseMappingKey returns the salary expense mapping key for a department
+func DepartmentSalaryExpenseMappingKey(department string) string {
+	return AccountMappingPayrollSalaryExpense + "." + strings.ToLower(strings.TrimSpace(department))
+}
+
+// AccountMappingResponse for API responses
+type AccountMappingResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	BranchID    *uuid.UUID `json:"branch_id,omitempty"`
+	BranchName  string     `json:"branch_name,omitempty"`
+	MappingKey  string     `json:"mapping_key"`
+	AccountID   uuid.UUID  `json:"account_id"`
+	AccountCode string     `json:"account_code"`
+	AccountName string     `json:"account_name"`
+	Description string     `json:"description,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// ToAccountMappingResponse converts AccountMapping to AccountMappingResponse
+func (m *AccountMapping) ToAccountMappingResponse() *AccountMappingResponse {
+	resp := &AccountMappingResponse{
+		ID:          m.ID,
+		BranchID:    m.BranchID,
+		MappingKey:  m.MappingKey,
+		AccountID:   m.AccountID,
+		AccountCode: m.Account.Code,
+		AccountName: m.Account.Name,
+		Description: m.Description,
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
+	}
+
+	if m.Branch != nil {
+		resp.BranchName = m.Branch.Name
+	}
+
+	return resp
+}
+
+// SaveAccountMappingRequest for creating or replacing a mapping
+type SaveAccountMappingRequest struct {
+	BranchID    *uuid.UUID `json:"branch_id"`
+	MappingKey  string     `json:"mapping_key" binding:"required,max=100"`
+	AccountID   uuid.UUID  `json:"account_id" binding:"required"`
+	Description string     `json:"description"`
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AccountingPeriod represents the lock state of a monthly period for a branch.
+// A period without a record is open; there is at most one record per branch and period.
+type AccountingPeriod struct {
+	BaseModel
+	BranchID uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_accounting_period_key" json:"branch_id"`
+	Period   string     `gorm:"size:7;not null;uniqueIndex:idx_accounting_period_key" json:"period"` // YYYY-MM
+	Status   string     `gorm:"size:20;not null;default:'open'" json:"status"`
+	ClosedAt *time.Time `json:"closed_at,omitempty"`
+	ClosedBy *uuid.UUID `gorm:"type:uuid" json:"closed_by,omitempty"`
+	Notes    string     `gorm:"type:text" json:"notes,omitempty"`
+
+	// Relationships
+	Branch Branch `gorm:"foreignKey:BranchID" json:"branch,omitempty"`
+}
+
+// TableName specifies table name
+func (AccountingPeriod) TableName() string {
+	return "accounting_periods"
+}
+
+// Period Status constants
+const (
+	PeriodStatusOpen       = "open"
+	PeriodStatusSoftClosed = "soft_closed" // Only ledger adjustments (journals) allowed
+	PeriodStatusClosed     = "closed"      // No postings allowed
+)
+
+// AccountMovement is the sum of posted journal lines for an account dimension
+type AccountMovement struct {
+	BranchID  uuid.UUID  `json:"branch_id"`
+	AccountID uuid.UUID  `json:"account_id"`
+	FundID    *uuid.UUID `json:"fund_id,omitempty"`
+	ProgramID *uuid.UUID `json:"program_id,omitempty"`
+	Debit     Money      `json:"debit"`
+	Credit    Money      `json:"credit"`
+}
+
+// SetPeriodStatusRequest for opening, soft-closing or closing a period
+type SetPeriodStatusRequest struct {
+	BranchID uuid.UUID `json:"branch_id" binding:"required"`
+	Period   string    `json:"period" binding:"required,len=7"` // YYYY-MM
+	Status   string    `json:"status" binding:"required,oneof=open soft_closed closed"`
+	Notes    string    `json:"notes"`
+}
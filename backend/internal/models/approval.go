@@ -0,0 +1,175 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Approval document type constants
+const (
+	ApprovalDocumentJournal = "journal"
+)
+
+// Approval step scope constants, deciding at which branch the approver must hold the role
+const (
+	ApproverScopeDocumentBranch = "document_branch" // Branch of the document being approved
+	ApproverScopeBranch         = "branch"          // Branch set on the step, e.g. head office
+	ApproverScopeAnyBranch      = "any_branch"
+)
+
+// Approval request status constants
+const (
+	ApprovalStatusPending   = "pending"
+	ApprovalStatusApproved  = "approved"
+	ApprovalStatusRejected  = "rejected"
+	ApprovalStatusCancelled = "cancelled" // Replaced by a newer submission
+)
+
+// Approval history action constants
+const (
+	ApprovalActionSubmitted = "submitted"
+	ApprovalActionApproved  = "approved"
+	ApprovalActionRejected  = "rejected"
+)
+
+// ApprovalChain defines the approval steps for a document type, branch and amount band
+type ApprovalChain struct {
+	BaseModel
+	Code         string     `gorm:"size:50;uniqueIndex;not null" json:"code"`
+	Name         string     `gorm:"size:200;not null" json:"name"`
+	DocumentType string     `gorm:"size:50;not null;index" json:"document_type"`
+	BranchID     *uuid.UUID `gorm:"type:uuid;index" json:"branch_id,omitempty"` // Empty applies to every branch
+	MinAmount    Money      `gorm:"type:decimal(15,2);not null;default:0" json:"min_amount"`
+	MaxAmount    *Money     `gorm:"type:decimal(15,2)" json:"max_amount,omitempty"` // Exclusive; empty has no upper limit
+	Description  string     `gorm:"type:text" json:"description"`
+	IsActive     bool       `gorm:"default:true" json:"is_active"`
+
+	// Relationships
+	Branch *Branch             `gorm:"foreignKey:BranchID" json:"branch,omitempty"`
+	Steps  []ApprovalChainStep `gorm:"foreignKey:ChainID" json:"steps,omitempty"`
+}
+
+// TableName specifies table name
+func (ApprovalChain) TableName() string {
+	return "approval_chains"
+}
+
+// Matches checks whether an amount falls in the chain's band
+func (a *ApprovalChain) Matches(amount Money) bool {
+	return amount >= a.MinAmount && (a.MaxAmount == nil || amount < *a.MaxAmount)
+}
+
+// ApprovalChainStep is one approval level of a chain. The approver is either a specific user
+// or anyone holding the role at the branch given by the scope.
+type ApprovalChainStep struct {
+	BaseModel
+	ChainID  uuid.UUID  `gorm:"type:uuid;not null;index" json:"chain_id"`
+	StepNo   int        `gorm:"not null" json:"step_no"`
+	Name     string     `gorm:"size:100;not null" json:"name"` // e.g. Branch Head
+	RoleID   *uuid.UUID `gorm:"type:uuid" json:"role_id,omitempty"`
+	UserID   *uuid.UUID `gorm:"type:uuid" json:"user_id,omitempty"`
+	Scope    string     `gorm:"size:20;not null;default:'document_branch'" json:"scope"`
+	BranchID *uuid.UUID `gorm:"type:uuid" json:"branch_id,omitempty"` // Branch for the branch scope
+
+	// Relationships
+	Role *Role `gorm:"foreignKey:RoleID" json:"role,omitempty"`
+	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+// TableName specifies table name
+func (ApprovalChainStep) TableName() string {
+	return "approval_chain_steps"
+}
+
+// ApprovalRequest tracks one submission of a document through its approval chain. Documents
+// without a matching chain get a single review step that anyone but the requester can approve.
+type ApprovalRequest struct {
+	BaseModel
+	DocumentType string     `gorm:"size:50;not null;index:idx_approval_request_document" json:"document_type"`
+	DocumentID   uuid.UUID  `gorm:"type:uuid;not null;index:idx_approval_request_document" json:"document_id"`
+	ChainID      *uuid.UUID `gorm:"type:uuid;index" json:"chain_id,omitempty"`
+	BranchID     uuid.UUID  `gorm:"type:uuid;not null" json:"branch_id"`
+	Amount       Money      `gorm:"type:decimal(15,2);not null;default:0" json:"amount"`
+	CurrentStep  int        `gorm:"not null;default:1" json:"current_step"`
+	TotalSteps   int        `gorm:"not null;default:1" json:"total_steps"`
+	Status       string     `gorm:"size:20;not null;default:'pending'" json:"status"`
+	RequestedBy  uuid.UUID  `gorm:"type:uuid;not null" json:"requested_by"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty"`
+
+	// Relationships
+	Chain   *ApprovalChain    `gorm:"foreignKey:ChainID" json:"chain,omitempty"`
+	History []ApprovalHistory `gorm:"foreignKey:RequestID" json:"history,omitempty"`
+}
+
+// TableName specifies table name
+func (ApprovalRequest) TableName() string {
+	return "approval_requests"
+}
+
+// Step returns the chain step with the given number, or nil for the default review step
+func (a *ApprovalRequest) Step(stepNo int) *ApprovalChainStep {
+	if a.Chain == nil {
+		return nil
+	}
+	for i := range a.Chain.Steps {
+		if a.Chain.Steps[i].StepNo == stepNo {
+			return &a.Chain.Steps[i]
+		}
+	}
+	return nil
+}
+
+// ApprovalHistory records an action taken on an approval request
+type ApprovalHistory struct {
+	BaseModel
+	RequestID    uuid.UUID `gorm:"type:uuid;not null;index" json:"request_id"`
+	DocumentType string    `gorm:"size:50;not null;index:idx_approval_history_document" json:"document_type"`
+	DocumentID   uuid.UUID `gorm:"type:uuid;not null;index:idx_approval_history_document" json:"document_id"`
+	StepNo       int       `gorm:"not null;default:0" json:"step_no"` // Zero for the submission
+	StepName     string    `gorm:"size:100" json:"step_name"`
+	Action       string    `gorm:"size:20;not null" json:"action"`
+	ActedBy      uuid.UUID `gorm:"type:uuid;not null" json:"acted_by"`
+	ActedAt      time.Time `gorm:"not null" json:"acted_at"`
+	Notes        string    `gorm:"type:text" json:"notes,omitempty"`
+
+	// Relationships
+	Actor *User `gorm:"foreignKey:ActedBy" json:"actor,omitempty"`
+}
+
+// TableName specifies table name
+func (ApprovalHistory) TableName() string {
+	return "approval_history"
+}
+
+// ApprovalChainStepReq for an approval chain step
+type ApprovalChainStepReq struct {
+	Name     string     `json:"name" binding:"required,max=100"`
+	RoleID   *uuid.UUID `json:"role_id"`
+	UserID   *uuid.UUID `json:"user_id"`
+	Scope    string     `json:"scope" binding:"omitempty,oneof=document_branch branch any_branch"` // Defaults to document_branch
+	BranchID *uuid.UUID `json:"branch_id"`
+}
+
+// CreateApprovalChainRequest for creating an approval chain
+type CreateApprovalChainRequest struct {
+	Code         string                 `json:"code" binding:"required,max=50"`
+	Name         string                 `json:"name" binding:"required,max=200"`
+	DocumentType string                 `json:"document_type" binding:"required,oneof=journal"`
+	BranchID     *uuid.UUID             `json:"branch_id"`
+	MinAmount    Money                  `json:"min_amount" binding:"min=0"`
+	MaxAmount    *Money                 `json:"max_amount"`
+	Description  string                 `json:"description"`
+	Steps        []ApprovalChainStepReq `json:"steps" binding:"required,min=1,dive"`
+}
+
+// UpdateApprovalChainRequest for updating an approval chain
+type UpdateApprovalChainRequest struct {
+	Name        string                 `json:"name" binding:"required,max=200"`
+	BranchID    *uuid.UUID             `json:"branch_id"`
+	MinAmount   Money                  `json:"min_amount" binding:"min=0"`
+	MaxAmount   *Money                 `json:"max_amount"`
+	Description string                 `json:"description"`
+	IsActive    *bool                  `json:"is_active"`
+	Steps       []ApprovalChainStepReq `json:"steps" binding:"required,min=1,dive"`
+}
@@ -22,16 +22,16 @@ type Asset struct {
 	
 	// Financial
 	PurchaseDate     time.Time  `gorm:"not null" json:"purchase_date"`
-	PurchasePrice    float64    `gorm:"type:decimal(15,2);not null" json:"purchase_price"`
+	PurchasePrice    Money      `gorm:"type:decimal(15,2);not null" json:"purchase_price"`
 	SupplierName     string     `gorm:"size:200" json:"supplier_name,omitempty"`
 	InvoiceNumber    string     `gorm:"size:100" json:"invoice_number,omitempty"`
 	
 	// Depreciation
 	DepreciationMethod string   `gorm:"size:50" json:"depreciation_method"` // straight_line, declining_balance
 	UsefulLife       int        `gorm:"default:0" json:"useful_life"` // in years
-	SalvageValue     float64    `gorm:"type:decimal(15,2);default:0" json:"salvage_value"`
-	AccumulatedDepreciation float64 `gorm:"type:decimal(15,2);default:0" json:"accumulated_depreciation"`
-	BookValue        float64    `gorm:"type:decimal(15,2)" json:"book_value"`
+	SalvageValue     Money      `gorm:"type:decimal(15,2);default:0" json:"salvage_value"`
+	AccumulatedDepreciation Money   `gorm:"type:decimal(15,2);default:0" json:"accumulated_depreciation"`
+	BookValue        Money      `gorm:"type:decimal(15,2)" json:"book_value"`
 	
 	// Location
 	Location         string     `gorm:"size:200" json:"location,omitempty"`
@@ -50,7 +50,7 @@ type Asset struct {
 	
 	// Disposal
 	DisposalDate     *time.Time `json:"disposal_date,omitempty"`
-	DisposalValue    float64    `gorm:"type:decimal(15,2);default:0" json:"disposal_value"`
+	DisposalValue    Money      `gorm:"type:decimal(15,2);default:0" json:"disposal_value"`
 	DisposalReason   string     `gorm:"type:text" json:"disposal_reason,omitempty"`
 	
 	// Documents
@@ -94,9 +94,9 @@ type AssetDepreciation struct {
 	BaseModel
 	AssetID          uuid.UUID `gorm:"type:uuid;not null;index" json:"asset_id"`
 	Period           string    `gorm:"size:7;not null;index" json:"period"` // YYYY-MM
-	DepreciationAmount float64 `gorm:"type:decimal(15,2);not null" json:"depreciation_amount"`
-	AccumulatedAmount float64  `gorm:"type:decimal(15,2);not null" json:"accumulated_amount"`
-	BookValue        float64   `gorm:"type:decimal(15,2);not null" json:"book_value"`
+	DepreciationAmount Money   `gorm:"type:decimal(15,2);not null" json:"depreciation_amount"`
+	AccumulatedAmount Money    `gorm:"type:decimal(15,2);not null" json:"accumulated_amount"`
+	BookValue        Money     `gorm:"type:decimal(15,2);not null" json:"book_value"`
 	
 	// Journal Integration
 	IsPosted         bool      `gorm:"default:false" json:"is_posted"`
@@ -120,7 +120,7 @@ type AssetMaintenance struct {
 	MaintenanceType  string     `gorm:"size:50;not null" json:"maintenance_type"` // routine, repair, emergency
 	MaintenanceDate  time.Time  `gorm:"not null;index" json:"maintenance_date"`
 	Description      string     `gorm:"type:text;not null" json:"description"`
-	Cost             float64    `gorm:"type:decimal(15,2);default:0" json:"cost"`
+	Cost             Money      `gorm:"type:decimal(15,2);default:0" json:"cost"`
 	Vendor           string     `gorm:"size:200" json:"vendor,omitempty"`
 	PerformedBy      string     `gorm:"size:200" json:"performed_by,omitempty"`
 	NextScheduled    *time.Time `json:"next_scheduled,omitempty"`
@@ -216,10 +216,10 @@ type CreateAssetRequest struct {
 	Brand              string    `json:"brand"`
 	SerialNumber       string    `json:"serial_number"`
 	PurchaseDate       time.Time `json:"purchase_date" binding:"required"`
-	PurchasePrice      float64   `json:"purchase_price" binding:"required,gt=0"`
+	PurchasePrice      Money     `json:"purchase_price" binding:"required,gt=0"`
 	DepreciationMethod string    `json:"depreciation_method"`
 	UsefulLife         int       `json:"useful_life"`
-	SalvageValue       float64   `json:"salvage_value"`
+	SalvageValue       Money     `json:"salvage_value"`
 	Location           string    `json:"location"`
 	ResponsibleUser    *uuid.UUID `json:"responsible_user_id"`
 }
@@ -230,7 +230,7 @@ type CreateMaintenanceRequest struct {
 	MaintenanceType string    `json:"maintenance_type" binding:"required"`
 	MaintenanceDate time.Time `json:"maintenance_date" binding:"required"`
 	Description     string    `json:"description" binding:"required"`
-	Cost            float64   `json:"cost"`
+	Cost            Money     `json:"cost"`
 	Vendor          string    `json:"vendor"`
 	NextScheduled   *time.Time `json:"next_scheduled"`
 }
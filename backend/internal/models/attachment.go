@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Attachment entity type constants
+const (
+	AttachmentEntityJournal = "journal"
+)
+
+// Attachment is a supporting document stored for a record, such as a receipt for a journal
+type Attachment struct {
+	BaseModel
+	EntityType string    `gorm:"size:100;not null;index:idx_attachments_entity" json:"entity_type"`
+	EntityID   uuid.UUID `gorm:"type:uuid;not null;index:idx_attachments_entity" json:"entity_id"`
+	FileName   string    `gorm:"size:255;not null" json:"file_name"` // Original name of the uploaded file
+	FilePath   string    `gorm:"size:500;not null" json:"-"`         // Storage key
+	Storage    string    `gorm:"size:20;not null;default:'local'" json:"storage"`
+	FileSize   int64     `json:"file_size"`
+	MimeType   string    `gorm:"size:100" json:"mime_type"`
+	Checksum   string    `gorm:"size:64" json:"checksum"` // SHA-256 of the content
+	UploadedBy uuid.UUID `gorm:"type:uuid;not null" json:"uploaded_by"`
+	UploadedAt time.Time `gorm:"not null" json:"uploaded_at"`
+
+	// Relationships
+	Uploader *User `gorm:"foreignKey:UploadedBy" json:"uploader,omitempty"`
+}
+
+// TableName specifies table name
+func (Attachment) TableName() string {
+	return "attachments"
+}
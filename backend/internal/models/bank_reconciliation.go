@@ -0,0 +1,135 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BankStatement represents an imported bank statement for a cash/bank account
+type BankStatement struct {
+	BaseModel
+	AccountID      uuid.UUID  `gorm:"type:uuid;not null;index" json:"account_id"`
+	BranchID       uuid.UUID  `gorm:"type:uuid;not null;index" json:"branch_id"`
+	Format         string     `gorm:"size:20;not null" json:"format"`
+	FileName       string     `gorm:"size:255" json:"file_name"`
+	PeriodStart    time.Time  `gorm:"not null" json:"period_start"`
+	PeriodEnd      time.Time  `gorm:"not null" json:"period_end"`
+	OpeningBalance Money      `gorm:"type:decimal(15,2);default:0" json:"opening_balance"`
+	ClosingBalance Money      `gorm:"type:decimal(15,2);default:0" json:"closing_balance"`
+	Status         string     `gorm:"size:20;not null;default:'open'" json:"status"`
+	ImportedBy     uuid.UUID  `gorm:"type:uuid;not null" json:"imported_by"`
+	ReconciledAt   *time.Time `json:"reconciled_at,omitempty"`
+	ReconciledBy   *uuid.UUID `gorm:"type:uuid" json:"reconciled_by,omitempty"`
+
+	// Relationships
+	Account Account             `gorm:"foreignKey:AccountID" json:"account"`
+	Branch  Branch              `gorm:"foreignKey:BranchID" json:"branch"`
+	Lines   []BankStatementLine `gorm:"foreignKey:StatementID" json:"lines,omitempty"`
+}
+
+// TableName specifies table name
+func (BankStatement) TableName() string {
+	return "bank_statements"
+}
+
+// BankStatementLine represents one transaction on a bank statement
+type BankStatementLine struct {
+	BaseModel
+	StatementID     uuid.UUID  `gorm:"type:uuid;not null;index" json:"statement_id"`
+	LineNo          int        `gorm:"not null" json:"line_no"`
+	TransactionDate time.Time  `gorm:"not null;index" json:"transaction_date"`
+	Description     string     `gorm:"type:text" json:"description"`
+	ReferenceNo     string     `gorm:"size:100" json:"reference_no"`
+	Amount          Money      `gorm:"type:decimal(15,2);not null" json:"amount"` // Positive for deposits, negative for withdrawals
+	JournalLineID   *uuid.UUID `gorm:"type:uuid;uniqueIndex" json:"journal_line_id,omitempty"`
+	MatchStatus     string     `gorm:"size:20;not null;default:'unmatched'" json:"match_status"`
+	MatchedAt       *time.Time `json:"matched_at,omitempty"`
+	MatchedBy       *uuid.UUID `gorm:"type:uuid" json:"matched_by,omitempty"`
+	IsReconciled    bool       `gorm:"default:false" json:"is_reconciled"`
+
+	// Relationships
+	JournalLine *JournalLine `gorm:"foreignKey:JournalLineID" json:"journal_line,omitempty"`
+}
+
+// TableName specifies table name
+func (BankStatementLine) TableName() string {
+	return "bank_statement_lines"
+}
+
+// Bank statement format constants
+const (
+	BankStatementFormatBCA     = "csv_bca"
+	BankStatementFormatMandiri = "csv_mandiri"
+	BankStatementFormatBNI     = "csv_bni"
+	BankStatementFormatBRI     = "csv_bri"
+	BankStatementFormatMT940   = "mt940"
+)
+
+// Bank statement status constants
+const (
+	BankStatementStatusOpen       = "open"
+	BankStatementStatusReconciled = "reconciled"
+)
+
+// Match status constants
+const (
+	MatchStatusUnmatched = "unmatched"
+	MatchStatusAuto      = "auto"
+	MatchStatusManual    = "manual"
+)
+
+// IsMatched checks if the statement line is matched to a journal line
+func (l *BankStatementLine) IsMatched() bool {
+	return l.JournalLineID != nil
+}
+
+// ImportBankStatementRequest for importing a bank statement file (multipart form)
+type ImportBankStatementRequest struct {
+	AccountID      uuid.UUID `form:"account_id" binding:"required"`
+	BranchID       uuid.UUID `form:"branch_id" binding:"required"`
+	Format         string    `form:"format" binding:"required,oneof=csv_bca csv_mandiri csv_bni csv_bri mt940"`
+	DateWindowDays int       `form:"date_window_days" binding:"min=0,max=31"` // Defaults to 3 days
+}
+
+// MatchStatementLineRequest for manually matching a statement line
+type MatchStatementLineRequest struct {
+	JournalLineID uuid.UUID `json:"journal_line_id" binding:"required"`
+}
+
+// UnmatchedItemsResponse lists statement and ledger items still to be matched
+type UnmatchedItemsResponse struct {
+	StatementLines []BankStatementLine `json:"statement_lines"`
+	JournalLines   []LedgerItem        `json:"journal_lines"`
+}
+
+// LedgerItem represents a posted journal line on a bank account
+type LedgerItem struct {
+	JournalLineID uuid.UUID `json:"journal_line_id"`
+	JournalID     uuid.UUID `json:"journal_id"`
+	JournalNumber string    `json:"journal_number"`
+	JournalDate   time.Time `json:"journal_date"`
+	ReferenceNo   string    `json:"reference_no"`
+	Description   string    `json:"description"`
+	PaymentRefNo  string    `json:"payment_reference_no,omitempty"`
+	Amount        Money     `json:"amount"` // Positive for deposits, negative for payments
+}
+
+// BankReconciliationReport compares a bank statement with the ledger
+type BankReconciliationReport struct {
+	StatementID           uuid.UUID           `json:"statement_id"`
+	AccountCode           string              `json:"account_code"`
+	AccountName           string              `json:"account_name"`
+	PeriodEnd             time.Time           `json:"period_end"`
+	BankBalance           Money               `json:"bank_balance"`
+	OutstandingDeposits   []LedgerItem        `json:"outstanding_deposits"` // In the ledger, not yet on a statement
+	OutstandingCheques    []LedgerItem        `json:"outstanding_cheques"`
+	TotalDeposits         Money               `json:"total_deposits"`
+	TotalCheques          Money               `json:"total_cheques"`
+	UnmatchedBankItems    []BankStatementLine `json:"unmatched_bank_items"` // On the statement, not in the ledger
+	AdjustedBankBalance   Money               `json:"adjusted_bank_balance"`
+	LedgerBalance         Money               `json:"ledger_balance"`
+	AdjustedLedgerBalance Money               `json:"adjusted_ledger_balance"` // Ledger plus bank items still to be booked
+	Difference            Money               `json:"difference"`
+	IsReconciled          bool                `json:"is_reconciled"`
+}
@@ -15,7 +15,7 @@ type Budget struct {
 	FundID       *uuid.UUID `gorm:"type:uuid;index" json:"fund_id,omitempty"`
 	ProgramID    *uuid.UUID `gorm:"type:uuid;index" json:"program_id,omitempty"`
 	Period       string     `gorm:"size:7;not null" json:"period"` // YYYY-MM format
-	Amount       float64    `gorm:"type:decimal(15,2);not null;default:0" json:"amount"`
+	Amount       Money      `gorm:"type:decimal(15,2);not null;default:0" json:"amount"`
 	Description  string     `gorm:"type:text" json:"description"`
 	IsActive     bool       `gorm:"default:true" json:"is_active"`
 	
@@ -49,7 +49,21 @@ func (FiscalYear) TableName() string {
 	return "fiscal_years"
 }
 
-// AccountBalance represents account balance at a point in time
+// CreateFiscalYearRequest for creating a fiscal year
+type CreateFiscalYearRequest struct {
+	Name      string     `json:"name" binding:"required,max=100"`
+	StartDate time.Time  `json:"start_date" binding:"required"`
+	EndDate   *time.Time `json:"end_date"` // Defaults to one year after start date
+	IsCurrent bool       `json:"is_current"`
+}
+
+// ReopenFiscalYearRequest for reopening a closed fiscal year
+type ReopenFiscalYearRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// AccountBalance represents account balance at a point in time. There is one row per account,
+// branch, fund, program and period, enforced by the idx_account_balance_key index.
 type AccountBalance struct {
 	BaseModel
 	AccountID     uuid.UUID  `gorm:"type:uuid;not null;index:idx_account_balance" json:"account_id"`
@@ -57,10 +71,10 @@ type AccountBalance struct {
 	FundID        *uuid.UUID `gorm:"type:uuid;index" json:"fund_id,omitempty"`
 	ProgramID     *uuid.UUID `gorm:"type:uuid;index" json:"program_id,omitempty"`
 	Period        string     `gorm:"size:7;not null;index:idx_account_balance" json:"period"` // YYYY-MM
-	OpeningBalance float64   `gorm:"type:decimal(15,2);not null;default:0" json:"opening_balance"`
-	Debit         float64    `gorm:"type:decimal(15,2);not null;default:0" json:"debit"`
-	Credit        float64    `gorm:"type:decimal(15,2);not null;default:0" json:"credit"`
-	ClosingBalance float64   `gorm:"type:decimal(15,2);not null;default:0" json:"closing_balance"`
+	OpeningBalance Money     `gorm:"type:decimal(15,2);not null;default:0" json:"opening_balance"`
+	Debit         Money      `gorm:"type:decimal(15,2);not null;default:0" json:"debit"`
+	Credit        Money      `gorm:"type:decimal(15,2);not null;default:0" json:"credit"`
+	ClosingBalance Money     `gorm:"type:decimal(15,2);not null;default:0" json:"closing_balance"`
 	
 	// Relationships
 	Account Account  `gorm:"foreignKey:AccountID" json:"account"`
@@ -89,9 +103,9 @@ type BudgetResponse struct {
 	ProgramID    *uuid.UUID `json:"program_id,omitempty"`
 	ProgramName  string     `json:"program_name,omitempty"`
 	Period       string     `json:"period"`
-	Amount       float64    `json:"amount"`
-	Actual       float64    `json:"actual"`
-	Variance     float64    `json:"variance"`
+	Amount       Money      `json:"amount"`
+	Actual       Money      `json:"actual"`
+	Variance     Money      `json:"variance"`
 	VariancePct  float64    `json:"variance_pct"`
 	Description  string     `json:"description,omitempty"`
 	IsActive     bool       `json:"is_active"`
@@ -106,13 +120,13 @@ type CreateBudgetRequest struct {
 	FundID       *uuid.UUID `json:"fund_id"`
 	ProgramID    *uuid.UUID `json:"program_id"`
 	Period       string     `json:"period" binding:"required"` // YYYY-MM
-	Amount       float64    `json:"amount" binding:"required,min=0"`
+	Amount       Money      `json:"amount" binding:"required,min=0"`
 	Description  string     `json:"description"`
 }
 
 // UpdateBudgetRequest for updating budget
 type UpdateBudgetRequest struct {
-	Amount      float64 `json:"amount" binding:"required,min=0"`
+	Amount      Money   `json:"amount" binding:"required,min=0"`
 	Description string  `json:"description"`
 	IsActive    *bool   `json:"is_active"`
 }
@@ -137,23 +151,24 @@ type TrialBalanceLine struct {
 	AccountCode string  `json:"account_code"`
 	AccountName string  `json:"account_name"`
 	Category    string  `json:"category"`
-	Debit       float64 `json:"debit"`
-	Credit      float64 `json:"credit"`
+	Debit       Money   `json:"debit"`
+	Credit      Money   `json:"credit"`
 }
 
 // TrialBalanceSummary represents trial balance summary
 type TrialBalanceSummary struct {
-	TotalDebit  float64 `json:"total_debit"`
-	TotalCredit float64 `json:"total_credit"`
-	Difference  float64 `json:"difference"`
+	TotalDebit  Money   `json:"total_debit"`
+	TotalCredit Money   `json:"total_credit"`
+	Difference  Money   `json:"difference"`
 	IsBalanced  bool    `json:"is_balanced"`
 }
 
 // BalanceSheetRequest for balance sheet report
 type BalanceSheetRequest struct {
-	AsOfDate  time.Time  `json:"as_of_date" binding:"required"`
-	BranchID  *uuid.UUID `json:"branch_id"`
-	FundID    *uuid.UUID `json:"fund_id"`
+	AsOfDate       time.Time  `json:"as_of_date" binding:"required"`
+	BranchID       *uuid.UUID `json:"branch_id"`
+	FundID         *uuid.UUID `json:"fund_id"`
+	IncludeHeaders bool       `json:"include_headers"` // Add header accounts with rolled-up amounts
 }
 
 // BalanceSheetResponse for balance sheet report
@@ -162,33 +177,34 @@ type BalanceSheetResponse struct {
 	Assets           BalanceSheetSection   `json:"assets"`
 	Liabilities      BalanceSheetSection   `json:"liabilities"`
 	Equity           BalanceSheetSection   `json:"equity"`
-	TotalAssets      float64               `json:"total_assets"`
-	TotalLiabilities float64               `json:"total_liabilities"`
-	TotalEquity      float64               `json:"total_equity"`
+	TotalAssets      Money                 `json:"total_assets"`
+	TotalLiabilities Money                 `json:"total_liabilities"`
+	TotalEquity      Money                 `json:"total_equity"`
 	IsBalanced       bool                  `json:"is_balanced"`
 }
 
 // BalanceSheetSection represents a section in balance sheet
 type BalanceSheetSection struct {
 	Lines []BalanceSheetLine `json:"lines"`
-	Total float64            `json:"total"`
+	Total Money              `json:"total"`
 }
 
 // BalanceSheetLine represents a line in balance sheet
 type BalanceSheetLine struct {
 	AccountCode string  `json:"account_code"`
 	AccountName string  `json:"account_name"`
-	Amount      float64 `json:"amount"`
+	Amount      Money   `json:"amount"`
 	Level       int     `json:"level"`
 	IsHeader    bool    `json:"is_header"`
 }
 
 // IncomeStatementRequest for income statement report
 type IncomeStatementRequest struct {
-	StartDate time.Time  `json:"start_date" binding:"required"`
-	EndDate   time.Time  `json:"end_date" binding:"required"`
-	BranchID  *uuid.UUID `json:"branch_id"`
-	FundID    *uuid.UUID `json:"fund_id"`
+	StartDate      time.Time  `json:"start_date" binding:"required"`
+	EndDate        time.Time  `json:"end_date" binding:"required"`
+	BranchID       *uuid.UUID `json:"branch_id"`
+	FundID         *uuid.UUID `json:"fund_id"`
+	IncludeHeaders bool       `json:"include_headers"` // Add header accounts with rolled-up amounts
 }
 
 // IncomeStatementResponse for income statement report
@@ -197,22 +213,22 @@ type IncomeStatementResponse struct {
 	EndDate        time.Time              `json:"end_date"`
 	Revenue        IncomeStatementSection `json:"revenue"`
 	Expenses       IncomeStatementSection `json:"expenses"`
-	TotalRevenue   float64                `json:"total_revenue"`
-	TotalExpenses  float64                `json:"total_expenses"`
-	NetIncome      float64                `json:"net_income"`
+	TotalRevenue   Money                  `json:"total_revenue"`
+	TotalExpenses  Money                  `json:"total_expenses"`
+	NetIncome      Money                  `json:"net_income"`
 }
 
 // IncomeStatementSection represents a section in income statement
 type IncomeStatementSection struct {
 	Lines []IncomeStatementLine `json:"lines"`
-	Total float64               `json:"total"`
+	Total Money                 `json:"total"`
 }
 
 // IncomeStatementLine represents a line in income statement
 type IncomeStatementLine struct {
 	AccountCode string  `json:"account_code"`
 	AccountName string  `json:"account_name"`
-	Amount      float64 `json:"amount"`
+	Amount      Money   `json:"amount"`
 	Level       int     `json:"level"`
 	IsHeader    bool    `json:"is_header"`
 }
@@ -230,11 +246,11 @@ type GeneralLedgerResponse struct {
 	Account         AccountResponse      `json:"account"`
 	StartDate       time.Time            `json:"start_date"`
 	EndDate         time.Time            `json:"end_date"`
-	OpeningBalance  float64              `json:"opening_balance"`
+	OpeningBalance  Money                `json:"opening_balance"`
 	Transactions    []GeneralLedgerLine  `json:"transactions"`
-	TotalDebit      float64              `json:"total_debit"`
-	TotalCredit     float64              `json:"total_credit"`
-	ClosingBalance  float64              `json:"closing_balance"`
+	TotalDebit      Money                `json:"total_debit"`
+	TotalCredit     Money                `json:"total_credit"`
+	ClosingBalance  Money                `json:"closing_balance"`
 }
 
 // GeneralLedgerLine represents a transaction in general ledger
@@ -242,11 +258,53 @@ type GeneralLedgerLine struct {
 	Date          time.Time `json:"date"`
 	JournalNumber string    `json:"journal_number"`
 	Description   string    `json:"description"`
-	Debit         float64   `json:"debit"`
-	Credit        float64   `json:"credit"`
-	Balance       float64   `json:"balance"`
+	Debit         Money     `json:"debit"`
+	Credit        Money     `json:"credit"`
+	Balance       Money     `json:"balance"`
+}
+
+// CashFlowRequest for cash flow statement
+type CashFlowRequest struct {
+	StartDate time.Time  `json:"start_date" binding:"required"`
+	EndDate   time.Time  `json:"end_date" binding:"required"`
+	BranchID  *uuid.UUID `json:"branch_id"`
+	FundID    *uuid.UUID `json:"fund_id"`
+	Method    string     `json:"method" binding:"omitempty,oneof=indirect direct"` // Defaults to indirect
+}
+
+// CashFlowResponse for cash flow statement
+type CashFlowResponse struct {
+	StartDate     time.Time       `json:"start_date"`
+	EndDate       time.Time       `json:"end_date"`
+	Method        string          `json:"method"`
+	Operating     CashFlowSection `json:"operating"`
+	Investing     CashFlowSection `json:"investing"`
+	Financing     CashFlowSection `json:"financing"`
+	NetChange     Money           `json:"net_change"`
+	OpeningCash   Money           `json:"opening_cash"`
+	ClosingCash   Money           `json:"closing_cash"`
+	IsReconciled  bool            `json:"is_reconciled"` // Opening cash plus net change equals closing cash
+}
+
+// CashFlowSection represents an activity section in cash flow statement
+type CashFlowSection struct {
+	Lines []CashFlowLine `json:"lines"`
+	Total Money          `json:"total"`
 }
 
+// CashFlowLine represents a line in cash flow statement
+type CashFlowLine struct {
+	AccountCode string  `json:"account_code"`
+	AccountName string  `json:"account_name"`
+	Amount      Money   `json:"amount"`
+}
+
+// Cash flow method constants
+const (
+	CashFlowMethodIndirect = "indirect"
+	CashFlowMethodDirect   = "direct"
+)
+
 // BudgetVsActualRequest for budget vs actual report
 type BudgetVsActualRequest struct {
 	FiscalYearID uuid.UUID  `json:"fiscal_year_id" binding:"required"`
@@ -267,16 +325,16 @@ type BudgetVsActualResponse struct {
 type BudgetVsActualLine struct {
 	AccountCode string  `json:"account_code"`
 	AccountName string  `json:"account_name"`
-	Budget      float64 `json:"budget"`
-	Actual      float64 `json:"actual"`
-	Variance    float64 `json:"variance"`
+	Budget      Money   `json:"budget"`
+	Actual      Money   `json:"actual"`
+	Variance    Money   `json:"variance"`
 	VariancePct float64 `json:"variance_pct"`
 }
 
 // BudgetVsActualSummary represents summary of budget vs actual
 type BudgetVsActualSummary struct {
-	TotalBudget      float64 `json:"total_budget"`
-	TotalActual      float64 `json:"total_actual"`
-	TotalVariance    float64 `json:"total_variance"`
+	TotalBudget      Money   `json:"total_budget"`
+	TotalActual      Money   `json:"total_actual"`
+	TotalVariance    Money   `json:"total_variance"`
 	TotalVariancePct float64 `json:"total_variance_pct"`
 }
@@ -0,0 +1,119 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Comparison mode constants
+const (
+	ComparisonPriorPeriod = "prior_period" // Against the period right before
+	ComparisonPriorYear   = "prior_year"   // Against the same period last year
+	ComparisonMonthly     = "monthly"      // Twelve monthly columns ending with the requested month
+)
+
+// ComparativeIncomeStatementRequest for comparative income statement report
+type ComparativeIncomeStatementRequest struct {
+	StartDate      time.Time  `json:"start_date" binding:"required"`
+	EndDate        time.Time  `json:"end_date" binding:"required"`
+	Mode           string     `json:"mode" binding:"required,oneof=prior_period prior_year monthly"`
+	BranchID       *uuid.UUID `json:"branch_id"`
+	FundID         *uuid.UUID `json:"fund_id"`
+	IncludeHeaders bool       `json:"include_headers"`
+	IncludeBudget  bool       `json:"include_budget"` // Add budget and budget variance per period
+}
+
+// ComparativeBalanceSheetRequest for comparative balance sheet report
+type ComparativeBalanceSheetRequest struct {
+	AsOfDate       time.Time  `json:"as_of_date" binding:"required"`
+	Mode           string     `json:"mode" binding:"required,oneof=prior_period prior_year monthly"`
+	BranchID       *uuid.UUID `json:"branch_id"`
+	FundID         *uuid.UUID `json:"fund_id"`
+	IncludeHeaders bool       `json:"include_headers"`
+}
+
+// ComparativeTrialBalanceRequest for comparative trial balance report
+type ComparativeTrialBalanceRequest struct {
+	AsOfDate  time.Time  `json:"as_of_date" binding:"required"`
+	Mode      string     `json:"mode" binding:"required,oneof=prior_period prior_year monthly"`
+	BranchID  *uuid.UUID `json:"branch_id"`
+	FundID    *uuid.UUID `json:"fund_id"`
+	ProgramID *uuid.UUID `json:"program_id"`
+}
+
+// ReportPeriod is one column of a comparative report
+type ReportPeriod struct {
+	Label     string    `json:"label"`
+	StartDate time.Time `json:"start_date"`
+	EndDate   time.Time `json:"end_date"`
+}
+
+// Variance compares an amount with a base amount
+type Variance struct {
+	Amount  Money    `json:"amount"`
+	Percent *float64 `json:"percent"` // Empty when the base amount is zero
+}
+
+// ComparativeAmounts holds the amounts of a line per report period, oldest first
+type ComparativeAmounts struct {
+	Amounts         []Money    `json:"amounts"`
+	Variances       []Variance `json:"variances"`                  // Each period against the period before it
+	Budgets         []Money    `json:"budgets,omitempty"`          // Budget per period
+	BudgetVariances []Variance `json:"budget_variances,omitempty"` // Actual against budget per period
+}
+
+// ComparativeLine represents an account line of a comparative report
+type ComparativeLine struct {
+	AccountCode string `json:"account_code"`
+	AccountName string `json:"account_name"`
+	Category    string `json:"category,omitempty"`
+	Level       int    `json:"level"`
+	IsHeader    bool   `json:"is_header"`
+	ComparativeAmounts
+}
+
+// ComparativeSection represents a section of a comparative report
+type ComparativeSection struct {
+	Lines []ComparativeLine  `json:"lines"`
+	Total ComparativeAmounts `json:"total"`
+}
+
+// ComparativeIncomeStatementResponse for comparative income statement report
+type ComparativeIncomeStatementResponse struct {
+	Mode          string             `json:"mode"`
+	Periods       []ReportPeriod     `json:"periods"`
+	Revenue       ComparativeSection `json:"revenue"`
+	Expenses      ComparativeSection `json:"expenses"`
+	TotalRevenue  ComparativeAmounts `json:"total_revenue"`
+	TotalExpenses ComparativeAmounts `json:"total_expenses"`
+	NetIncome     ComparativeAmounts `json:"net_income"`
+}
+
+// ComparativeBalanceSheetResponse for comparative balance sheet report
+type ComparativeBalanceSheetResponse struct {
+	Mode             string             `json:"mode"`
+	Periods          []ReportPeriod     `json:"periods"`
+	Assets           ComparativeSection `json:"assets"`
+	Liabilities      ComparativeSection `json:"liabilities"`
+	Equity           ComparativeSection `json:"equity"`
+	TotalAssets      ComparativeAmounts `json:"total_assets"`
+	TotalLiabilities ComparativeAmounts `json:"total_liabilities"`
+	TotalEquity      ComparativeAmounts `json:"total_equity"`
+}
+
+// ComparativeTrialBalanceLine represents an account of a comparative trial balance. Amounts
+// hold the balance following the account's normal balance.
+type ComparativeTrialBalanceLine struct {
+	ComparativeLine
+	Debits  []Money `json:"debits"`
+	Credits []Money `json:"credits"`
+}
+
+// ComparativeTrialBalanceResponse for comparative trial balance report
+type ComparativeTrialBalanceResponse struct {
+	Mode      string                        `json:"mode"`
+	Periods   []ReportPeriod                `json:"periods"`
+	Lines     []ComparativeTrialBalanceLine `json:"lines"`
+	Summaries []TrialBalanceSummary         `json:"summaries"` // Per period
+}
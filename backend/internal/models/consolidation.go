@@ -0,0 +1,81 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ConsolidatedBalanceSheetRequest for consolidated balance sheet report
+type ConsolidatedBalanceSheetRequest struct {
+	AsOfDate       time.Time   `json:"as_of_date" binding:"required"`
+	BranchIDs      []uuid.UUID `json:"branch_ids"` // Defaults to all active branches
+	IncludeHeaders bool        `json:"include_headers"`
+}
+
+// ConsolidatedIncomeStatementRequest for consolidated income statement report
+type ConsolidatedIncomeStatementRequest struct {
+	StartDate      time.Time   `json:"start_date" binding:"required"`
+	EndDate        time.Time   `json:"end_date" binding:"required"`
+	BranchIDs      []uuid.UUID `json:"branch_ids"` // Defaults to all active branches
+	IncludeHeaders bool        `json:"include_headers"`
+}
+
+// ConsolidatedBranch is one branch column of a consolidated report
+type ConsolidatedBranch struct {
+	BranchID   uuid.UUID `json:"branch_id"`
+	BranchCode string    `json:"branch_code"`
+	BranchName string    `json:"branch_name"`
+}
+
+// ConsolidatedAmounts holds the branch columns, the eliminations and the consolidated total.
+// Consolidated is the sum of the branch amounts plus the eliminations.
+type ConsolidatedAmounts struct {
+	BranchAmounts []Money `json:"branch_amounts"` // In the order of the report branches
+	Eliminations  Money   `json:"eliminations"`
+	Consolidated  Money   `json:"consolidated"`
+}
+
+// ConsolidatedLine represents an account line of a consolidated report
+type ConsolidatedLine struct {
+	AccountCode string `json:"account_code"`
+	AccountName string `json:"account_name"`
+	Level       int    `json:"level"`
+	IsHeader    bool   `json:"is_header"`
+	ConsolidatedAmounts
+}
+
+// ConsolidatedSection represents a section of a consolidated report
+type ConsolidatedSection struct {
+	Lines []ConsolidatedLine  `json:"lines"`
+	Total ConsolidatedAmounts `json:"total"`
+}
+
+// ConsolidatedBalanceSheetResponse for consolidated balance sheet report
+type ConsolidatedBalanceSheetResponse struct {
+	AsOfDate         time.Time            `json:"as_of_date"`
+	Branches         []ConsolidatedBranch `json:"branches"`
+	Assets           ConsolidatedSection  `json:"assets"`
+	Liabilities      ConsolidatedSection  `json:"liabilities"`
+	Equity           ConsolidatedSection  `json:"equity"`
+	TotalAssets      ConsolidatedAmounts  `json:"total_assets"`
+	TotalLiabilities ConsolidatedAmounts  `json:"total_liabilities"`
+	TotalEquity      ConsolidatedAmounts  `json:"total_equity"`
+	IsBalanced       bool                 `json:"is_balanced"`
+	// Consolidated balance left on inter-branch accounts, e.g. entries booked without a counterpart branch
+	UneliminatedBalance Money `json:"uneliminated_balance"`
+}
+
+// ConsolidatedIncomeStatementResponse for consolidated income statement report
+type ConsolidatedIncomeStatementResponse struct {
+	StartDate     time.Time            `json:"start_date"`
+	EndDate       time.Time            `json:"end_date"`
+	Branches      []ConsolidatedBranch `json:"branches"`
+	Revenue       ConsolidatedSection  `json:"revenue"`
+	Expenses      ConsolidatedSection  `json:"expenses"`
+	TotalRevenue  ConsolidatedAmounts  `json:"total_revenue"`
+	TotalExpenses ConsolidatedAmounts  `json:"total_expenses"`
+	NetIncome     ConsolidatedAmounts  `json:"net_income"`
+	// Consolidated amount left on inter-branch transfer accounts
+	UneliminatedBalance Money `json:"uneliminated_balance"`
+}
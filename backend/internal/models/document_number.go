@@ -0,0 +1,327 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Document types with numbers issued by the numbering service
+const (
+	DocumentTypeJournal          = "journal"
+	DocumentTypeInvoice          = "invoice"
+	DocumentTypePayment          = "payment"
+	DocumentTypePayroll          = "payroll"
+	DocumentTypeStockTransaction = "stock_transaction"
+	DocumentTypeStockOpname      = "stock_opname"
+	DocumentTypePurchaseOrder    = "purchase_order"
+)
+
+// Number reset periods
+const (
+	NumberResetNever   = "never"
+	NumberResetYearly  = "yearly"
+	NumberResetMonthly = "monthly"
+	NumberResetDaily   = "daily"
+)
+
+// Number pattern tokens
+const (
+	NumberTokenPrefix   = "{PREFIX}"
+	NumberTokenBranch   = "{BRANCH}" // Branch code
+	NumberTokenType     = "{TYPE}"   // Stock transaction type: IN, OUT, ADJ or OPN
+	NumberTokenYear     = "{YYYY}"
+	NumberTokenYearTwo  = "{YY}"
+	NumberTokenMonth    = "{MM}"
+	NumberTokenDay      = "{DD}"
+	NumberTokenSequence = "{SEQ}" // Sequence number, zero padded
+)
+
+// DefaultNumberPattern is the pattern of the built-in formats, such as JE/YAY/202411/0001
+const DefaultNumberPattern = "{PREFIX}/{BRANCH}/{YYYY}{MM}/{SEQ}"
+
+// DocumentNumberFormat is how numbers of a document type are built for a branch
+type DocumentNumberFormat struct {
+	BaseModel
+	DocumentType string     `gorm:"size:30;not null;index" json:"document_type"`
+	BranchID     *uuid.UUID `gorm:"type:uuid;index" json:"branch_id,omitempty"` // NULL = default for all branches
+	Prefix       string     `gorm:"size:20" json:"prefix"`
+	Pattern      string     `gorm:"size:100;not null" json:"pattern"`
+	Padding      int        `gorm:"not null;default:4" json:"padding"`
+	ResetPeriod  string     `gorm:"size:10;not null;default:'monthly'" json:"reset_period"`
+
+	// Relationships
+	Branch *Branch `gorm:"foreignKey:BranchID" json:"branch,omitempty"`
+}
+
+// TableName specifies table name
+func (DocumentNumberFormat) TableName() string {
+	return "document_number_formats"
+}
+
+// DocumentSequence holds the last number issued in one numbering sequence. Numbers are taken
+// by locking the row inside the transaction that saves the document, so a rolled back document
+// gives its number back.
+type DocumentSequence struct {
+	BaseModel
+	DocumentType string `gorm:"size:30;not null;uniqueIndex:idx_document_sequence" json:"document_type"`
+	Scope        string `gorm:"size:150;not null;uniqueIndex:idx_document_sequence" json:"scope"` // Pattern with the prefix, branch and type filled in
+	Period       string `gorm:"size:10;not null;uniqueIndex:idx_document_sequence" json:"period"` // Empty when never reset
+	LastNumber   int64  `gorm:"not null;default:0" json:"last_number"`
+}
+
+// TableName specifies table name
+func (DocumentSequence) TableName() string {
+	return "document_sequences"
+}
+
+// DefaultDocumentNumberFormat returns the built-in format of a document type, matching the
+// numbers issued before formats could be configured
+func DefaultDocumentNumberFormat(documentType string) (*DocumentNumberFormat, error) {
+	format := &DocumentNumberFormat{
+		DocumentType: documentType,
+		Pattern:      DefaultNumberPattern,
+		Padding:      4,
+		ResetPeriod:  NumberResetMonthly,
+	}
+
+	switch documentType {
+	case DocumentTypeJournal:
+		format.Prefix = "JE"
+	case DocumentTypeInvoice:
+		format.Prefix = "INV"
+	case DocumentTypePayment, DocumentTypePayroll:
+		format.Prefix = "PAY"
+	case DocumentTypeStockTransaction:
+		format.Prefix = "STK"
+		format.Pattern = "{PREFIX}/{TYPE}/{BRANCH}/{YYYY}{MM}{DD}/{SEQ}"
+		format.ResetPeriod = NumberResetDaily
+	case DocumentTypeStockOpname:
+		format.Prefix = "OPN"
+	case DocumentTypePurchaseOrder:
+		format.Prefix = "PO"
+	default:
+		return nil, errors.New("unknown document type " + documentType)
+	}
+
+	return format, nil
+}
+
+// numberTokenPattern matches the tokens of a number pattern
+var numberTokenPattern = regexp.MustCompile(`\{[A-Z]+\}`)
+
+// numberPart is a literal piece of a number pattern, or one of its tokens
+type numberPart struct {
+	text  string
+	token string
+}
+
+func (f *DocumentNumberFormat) parts() []numberPart {
+	var parts []numberPart
+	last := 0
+	for _, match := range numberTokenPattern.FindAllStringIndex(f.Pattern, -1) {
+		if match[0] > last {
+			parts = append(parts, numberPart{text: f.Pattern[last:match[0]]})
+		}
+		parts = append(parts, numberPart{token: f.Pattern[match[0]:match[1]]})
+		last = match[1]
+	}
+	if last < len(f.Pattern) {
+		parts = append(parts, numberPart{text: f.Pattern[last:]})
+	}
+	return parts
+}
+
+// Validate checks that the pattern has one sequence and the date parts its reset period needs,
+// so that numbers of different periods cannot clash
+func (f *DocumentNumberFormat) Validate() error {
+	if _, err := DefaultDocumentNumberFormat(f.DocumentType); err != nil {
+		return err
+	}
+	if f.Padding < 1 || f.Padding > 10 {
+		return errors.New("padding must be between 1 and 10")
+	}
+
+	counts := make(map[string]int)
+	for _, part := range f.parts() {
+		if part.token == "" {
+			continue
+		}
+		switch part.token {
+		case NumberTokenPrefix, NumberTokenBranch, NumberTokenType, NumberTokenYear,
+			NumberTokenYearTwo, NumberTokenMonth, NumberTokenDay, NumberTokenSequence:
+			counts[part.token]++
+		default:
+			return errors.New("unknown token " + part.token + " in number pattern")
+		}
+	}
+
+	if counts[NumberTokenSequence] != 1 {
+		return errors.New("number pattern must contain {SEQ} once")
+	}
+
+	hasYear := counts[NumberTokenYear] > 0 || counts[NumberTokenYearTwo] > 0
+	switch f.ResetPeriod {
+	case NumberResetNever:
+	case NumberResetYearly:
+		if !hasYear {
+			return errors.New("a yearly reset needs {YYYY} or {YY} in the number pattern")
+		}
+	case NumberResetMonthly:
+		if !hasYear || counts[NumberTokenMonth] == 0 {
+			return errors.New("a monthly reset needs the year and {MM} in the number pattern")
+		}
+	case NumberResetDaily:
+		if !hasYear || counts[NumberTokenMonth] == 0 || counts[NumberTokenDay] == 0 {
+			return errors.New("a daily reset needs the year, {MM} and {DD} in the number pattern")
+		}
+	default:
+		return errors.New("reset period must be never, yearly, monthly or daily")
+	}
+
+	return nil
+}
+
+// fixedValue returns the value of a token that does not depend on the date
+func (f *DocumentNumberFormat) fixedValue(token, branchCode, typeCode string) (string, bool) {
+	switch token {
+	case NumberTokenPrefix:
+		return f.Prefix, true
+	case NumberTokenBranch:
+		return branchCode, true
+	case NumberTokenType:
+		return typeCode, true
+	}
+	return "", false
+}
+
+// dateValue returns the value of a date token, and whether the token is fixed within a period
+// of the reset
+func (f *DocumentNumberFormat) dateValue(token string, date time.Time) (string, bool) {
+	var layout string
+	var minReset int
+	switch token {
+	case NumberTokenYear:
+		layout, minReset = "2006", 1
+	case NumberTokenYearTwo:
+		layout, minReset = "06", 1
+	case NumberTokenMonth:
+		layout, minReset = "01", 2
+	case NumberTokenDay:
+		layout, minReset = "02", 3
+	}
+
+	reset := map[string]int{NumberResetYearly: 1, NumberResetMonthly: 2, NumberResetDaily: 3}[f.ResetPeriod]
+	return date.Format(layout), reset >= minReset
+}
+
+// Format builds the number of a document
+func (f *DocumentNumberFormat) Format(branchCode, typeCode string, date time.Time, sequence int64) string {
+	var number strings.Builder
+	for _, part := range f.parts() {
+		if part.token == "" {
+			number.WriteString(part.text)
+			continue
+		}
+		if value, ok := f.fixedValue(part.token, branchCode, typeCode); ok {
+			number.WriteString(value)
+			continue
+		}
+		if part.token == NumberTokenSequence {
+			number.WriteString(fmt.Sprintf("%0*d", f.Padding, sequence))
+			continue
+		}
+		value, _ := f.dateValue(part.token, date)
+		number.WriteString(value)
+	}
+	return number.String()
+}
+
+// Scope returns the pattern with everything but the date and sequence filled in. Numbers with
+// the same scope and period share one sequence.
+func (f *DocumentNumberFormat) Scope(branchCode, typeCode string) string {
+	var scope strings.Builder
+	for _, part := range f.parts() {
+		if value, ok := f.fixedValue(part.token, branchCode, typeCode); ok {
+			scope.WriteString(value)
+			continue
+		}
+		scope.WriteString(part.text + part.token)
+	}
+	return scope.String()
+}
+
+// Period returns the reset period a date falls in, empty when numbers never reset
+func (f *DocumentNumberFormat) Period(date time.Time) string {
+	switch f.ResetPeriod {
+	case NumberResetYearly:
+		return date.Format("2006")
+	case NumberResetMonthly:
+		return date.Format("2006-01")
+	case NumberResetDaily:
+		return date.Format("2006-01-02")
+	}
+	return ""
+}
+
+// Matcher returns a SQL LIKE pattern and a regular expression matching the numbers of the
+// sequence a date falls in. The expression captures the sequence number. It is used to
+// continue from numbers issued before the sequence existed.
+func (f *DocumentNumberFormat) Matcher(branchCode, typeCode string, date time.Time) (string, *regexp.Regexp) {
+	likeEscaper := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+	var like, expression strings.Builder
+	expression.WriteString("^")
+	for _, part := range f.parts() {
+		if part.token == "" {
+			like.WriteString(likeEscaper.Replace(part.text))
+			expression.WriteString(regexp.QuoteMeta(part.text))
+			continue
+		}
+		if value, ok := f.fixedValue(part.token, branchCode, typeCode); ok {
+			like.WriteString(likeEscaper.Replace(value))
+			expression.WriteString(regexp.QuoteMeta(value))
+			continue
+		}
+		if part.token == NumberTokenSequence {
+			like.WriteString("%")
+			expression.WriteString(`(\d+)`)
+			continue
+		}
+
+		value, fixed := f.dateValue(part.token, date)
+		if fixed {
+			like.WriteString(value)
+			expression.WriteString(value)
+		} else {
+			like.WriteString(strings.Repeat("_", len(value)))
+			expression.WriteString(fmt.Sprintf(`\d{%d}`, len(value)))
+		}
+	}
+	expression.WriteString("$")
+
+	return like.String(), regexp.MustCompile(expression.String())
+}
+
+// SaveDocumentNumberFormatRequest for creating or replacing the number format of a document type
+type SaveDocumentNumberFormatRequest struct {
+	DocumentType string     `json:"document_type" binding:"required,max=30"`
+	BranchID     *uuid.UUID `json:"branch_id"`
+	Prefix       string     `json:"prefix" binding:"max=20"`
+	Pattern      string     `json:"pattern" binding:"required,max=100"`
+	Padding      int        `json:"padding" binding:"required,min=1,max=10"`
+	ResetPeriod  string     `json:"reset_period" binding:"required,oneof=never yearly monthly daily"`
+}
+
+// DocumentNumberPreview shows the next number of a document type without taking it
+type DocumentNumberPreview struct {
+	DocumentType string                `json:"document_type"`
+	BranchID     uuid.UUID             `json:"branch_id"`
+	Date         time.Time             `json:"date"`
+	Format       *DocumentNumberFormat `json:"format"`
+	NextNumber   string                `json:"next_number"`
+}
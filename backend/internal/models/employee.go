@@ -87,7 +87,7 @@ type EmployeeContract struct {
 	StartDate      time.Time  `gorm:"not null" json:"start_date"`
 	EndDate        *time.Time `json:"end_date,omitempty"`
 	Position       string     `gorm:"size:100;not null" json:"position"`
-	BaseSalary     float64    `gorm:"type:decimal(15,2);not null" json:"base_salary"`
+	BaseSalary     Money      `gorm:"type:decimal(15,2);not null" json:"base_salary"`
 	
 	// Contract Terms
 	WorkingHours   int        `gorm:"default:40" json:"working_hours"` // per week
@@ -121,21 +121,21 @@ type Payroll struct {
 	PaymentDate     time.Time  `gorm:"not null" json:"payment_date"`
 	
 	// Salary Components
-	BaseSalary      float64    `gorm:"type:decimal(15,2);not null" json:"base_salary"`
-	Allowances      float64    `gorm:"type:decimal(15,2);default:0" json:"allowances"`
-	Overtime        float64    `gorm:"type:decimal(15,2);default:0" json:"overtime"`
-	Bonus           float64    `gorm:"type:decimal(15,2);default:0" json:"bonus"`
+	BaseSalary      Money      `gorm:"type:decimal(15,2);not null" json:"base_salary"`
+	Allowances      Money      `gorm:"type:decimal(15,2);default:0" json:"allowances"`
+	Overtime        Money      `gorm:"type:decimal(15,2);default:0" json:"overtime"`
+	Bonus           Money      `gorm:"type:decimal(15,2);default:0" json:"bonus"`
 	
 	// Deductions
-	TaxPPh21        float64    `gorm:"type:decimal(15,2);default:0" json:"tax_pph21"`
-	BPJS            float64    `gorm:"type:decimal(15,2);default:0" json:"bpjs"`
-	Loan            float64    `gorm:"type:decimal(15,2);default:0" json:"loan"`
-	OtherDeductions float64    `gorm:"type:decimal(15,2);default:0" json:"other_deductions"`
+	TaxPPh21        Money      `gorm:"type:decimal(15,2);default:0" json:"tax_pph21"`
+	BPJS            Money      `gorm:"type:decimal(15,2);default:0" json:"bpjs"`
+	Loan            Money      `gorm:"type:decimal(15,2);default:0" json:"loan"`
+	OtherDeductions Money      `gorm:"type:decimal(15,2);default:0" json:"other_deductions"`
 	
 	// Totals
-	GrossSalary     float64    `gorm:"type:decimal(15,2);not null" json:"gross_salary"`
-	TotalDeductions float64    `gorm:"type:decimal(15,2);default:0" json:"total_deductions"`
-	NetSalary       float64    `gorm:"type:decimal(15,2);not null" json:"net_salary"`
+	GrossSalary     Money      `gorm:"type:decimal(15,2);not null" json:"gross_salary"`
+	TotalDeductions Money      `gorm:"type:decimal(15,2);default:0" json:"total_deductions"`
+	NetSalary       Money      `gorm:"type:decimal(15,2);not null" json:"net_salary"`
 	
 	// Payment
 	PaymentMethod   string     `gorm:"size:50" json:"payment_method"` // transfer, cash
@@ -166,7 +166,7 @@ type PayrollComponent struct {
 	ComponentID uuid.UUID `gorm:"type:uuid;not null" json:"component_id"`
 	Name        string    `gorm:"size:100;not null" json:"name"`
 	Type        string    `gorm:"size:20;not null" json:"type"` // earning, deduction
-	Amount      float64   `gorm:"type:decimal(15,2);not null" json:"amount"`
+	Amount      Money     `gorm:"type:decimal(15,2);not null" json:"amount"`
 	IsTaxable   bool      `gorm:"default:true" json:"is_taxable"`
 	
 	// Relationships
@@ -188,7 +188,7 @@ type SalaryComponent struct {
 	Category    string  `gorm:"size:50;not null" json:"category"` // basic, allowance, overtime, tax, insurance, loan
 	IsTaxable   bool    `gorm:"default:true" json:"is_taxable"`
 	IsFixed     bool    `gorm:"default:false" json:"is_fixed"`
-	Amount      float64 `gorm:"type:decimal(15,2)" json:"amount,omitempty"` // For fixed components
+	Amount      Money   `gorm:"type:decimal(15,2)" json:"amount,omitempty"` // For fixed components
 	Percentage  float64 `gorm:"type:decimal(5,2)" json:"percentage,omitempty"` // For percentage-based
 	AccountID   *uuid.UUID `gorm:"type:uuid" json:"account_id,omitempty"` // For journal posting
 	IsActive    bool    `gorm:"default:true" json:"is_active"`
@@ -294,12 +294,12 @@ type CreatePayrollRequest struct {
 	EmployeeID    uuid.UUID              `json:"employee_id" binding:"required"`
 	Period        string                 `json:"period" binding:"required"` // YYYY-MM
 	PaymentDate   time.Time              `json:"payment_date" binding:"required"`
-	BaseSalary    float64                `json:"base_salary" binding:"required,gt=0"`
+	BaseSalary    Money                  `json:"base_salary" binding:"required,gt=0"`
 	Components    []PayrollComponentReq  `json:"components"`
 }
 
 // PayrollComponentReq for payroll component
 type PayrollComponentReq struct {
 	ComponentID uuid.UUID `json:"component_id" binding:"required"`
-	Amount      float64   `json:"amount" binding:"required"`
+	Amount      Money     `json:"amount" binding:"required"`
 }
@@ -0,0 +1,98 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ExchangeRate is the rate of a foreign currency in the default currency, used from its rate
+// date until the next rate of the same currency
+type ExchangeRate struct {
+	BaseModel
+	Currency  string    `gorm:"size:3;not null;uniqueIndex:idx_exchange_rate_date" json:"currency"`
+	RateDate  time.Time `gorm:"not null;uniqueIndex:idx_exchange_rate_date" json:"rate_date"`
+	Rate      float64   `gorm:"type:decimal(18,6);not null" json:"rate"` // Default currency per unit of the currency
+	CreatedBy uuid.UUID `gorm:"type:uuid;not null" json:"created_by"`
+}
+
+// TableName specifies table name
+func (ExchangeRate) TableName() string {
+	return "exchange_rates"
+}
+
+// FXRevaluation records the revaluation of a branch's foreign currency accounts at a month end
+type FXRevaluation struct {
+	BaseModel
+	BranchID        uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_fx_revaluation_date" json:"branch_id"`
+	RevaluationDate time.Time  `gorm:"not null;uniqueIndex:idx_fx_revaluation_date" json:"revaluation_date"`
+	Status          string     `gorm:"size:20;not null" json:"status"`
+	JournalID       *uuid.UUID `gorm:"type:uuid" json:"journal_id,omitempty"`
+	NetGain         Money      `gorm:"type:decimal(15,2);not null;default:0" json:"net_gain"` // Negative for a net loss
+	Message         string     `gorm:"type:text" json:"message,omitempty"`                    // Failure reason
+	ProcessedBy     *uuid.UUID `gorm:"type:uuid" json:"processed_by,omitempty"`               // Empty when run by the scheduler
+
+	// Relationships
+	Branch  Branch   `gorm:"foreignKey:BranchID" json:"branch"`
+	Journal *Journal `gorm:"foreignKey:JournalID" json:"journal,omitempty"`
+}
+
+// TableName specifies table name
+func (FXRevaluation) TableName() string {
+	return "fx_revaluations"
+}
+
+// FX revaluation status constants
+const (
+	FXRevaluationProcessing = "processing" // Claimed by a revaluation run
+	FXRevaluationPosted     = "posted"
+	FXRevaluationUnchanged  = "unchanged" // Balances already at the month-end rates
+	FXRevaluationFailed     = "failed"    // Retried on the next run
+)
+
+// ForeignCurrencyBalance is the balance of a foreign currency account in a branch
+type ForeignCurrencyBalance struct {
+	BranchID       uuid.UUID `json:"branch_id"`
+	AccountID      uuid.UUID `json:"account_id"`
+	Currency       string    `json:"currency"`
+	ForeignBalance Money     `json:"foreign_balance"` // Debit minus credit in the account currency
+	BookBalance    Money     `json:"book_balance"`    // Debit minus credit in the default currency
+}
+
+// SaveExchangeRateRequest for creating or replacing the rate of a currency on a date
+type SaveExchangeRateRequest struct {
+	Currency string    `json:"currency" binding:"required,len=3,alpha,uppercase"`
+	RateDate time.Time `json:"rate_date" binding:"required"`
+	Rate     float64   `json:"rate" binding:"required,gt=0"`
+}
+
+// RunFXRevaluationRequest for revaluing foreign currency accounts at a month end
+type RunFXRevaluationRequest struct {
+	RevaluationDate time.Time  `json:"revaluation_date" binding:"required"` // Last day of a month
+	BranchID        *uuid.UUID `json:"branch_id"`                           // Defaults to all branches
+	DryRun          bool       `json:"dry_run"`                             // Calculate only, post nothing
+}
+
+// FXRevaluationLine is the revaluation of one account in one branch
+type FXRevaluationLine struct {
+	BranchID        uuid.UUID `json:"branch_id"`
+	AccountID       uuid.UUID `json:"account_id"`
+	AccountCode     string    `json:"account_code"`
+	AccountName     string    `json:"account_name"`
+	Currency        string    `json:"currency"`
+	ForeignBalance  Money     `json:"foreign_balance"`
+	Rate            float64   `json:"rate"`
+	BookBalance     Money     `json:"book_balance"`
+	RevaluedBalance Money     `json:"revalued_balance"`
+	Difference      Money     `json:"difference"` // Unrealized gain, negative for a loss
+}
+
+// FXRevaluationResult summarizes a revaluation run
+type FXRevaluationResult struct {
+	RevaluationDate time.Time           `json:"revaluation_date"`
+	DryRun          bool                `json:"dry_run"`
+	Posted          int                 `json:"posted"`
+	Failed          int                 `json:"failed"`
+	Lines           []FXRevaluationLine `json:"lines"`
+	Revaluations    []FXRevaluation     `json:"revaluations"`
+}
@@ -0,0 +1,102 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreateFundRequest for creating a fund
+type CreateFundRequest struct {
+	Code        string `json:"code" binding:"required,max=20"`
+	Name        string `json:"name" binding:"required,max=200"`
+	Type        string `json:"type" binding:"required,oneof=restricted unrestricted"`
+	Description string `json:"description"`
+}
+
+// UpdateFundRequest for updating a fund. The type is fixed once created because it
+// determines the net asset class of every line already tagged with the fund.
+type UpdateFundRequest struct {
+	Name        string `json:"name" binding:"required,max=200"`
+	Description string `json:"description"`
+	IsActive    *bool  `json:"is_active"`
+}
+
+// CreateProgramRequest for creating a program
+type CreateProgramRequest struct {
+	Code        string     `json:"code" binding:"required,max=20"`
+	Name        string     `json:"name" binding:"required,max=200"`
+	Description string     `json:"description"`
+	StartDate   *time.Time `json:"start_date"`
+	EndDate     *time.Time `json:"end_date"`
+}
+
+// UpdateProgramRequest for updating a program
+type UpdateProgramRequest struct {
+	Name        string     `json:"name" binding:"required,max=200"`
+	Description string     `json:"description"`
+	StartDate   *time.Time `json:"start_date"`
+	EndDate     *time.Time `json:"end_date"`
+	IsActive    *bool      `json:"is_active"`
+}
+
+// FundBalanceRequest for fund balance report
+type FundBalanceRequest struct {
+	StartDate time.Time  `json:"start_date" binding:"required"`
+	EndDate   time.Time  `json:"end_date" binding:"required"`
+	BranchID  *uuid.UUID `json:"branch_id"`
+}
+
+// FundBalanceResponse for fund balance report
+type FundBalanceResponse struct {
+	StartDate time.Time         `json:"start_date"`
+	EndDate   time.Time         `json:"end_date"`
+	Lines     []FundBalanceLine `json:"lines"`
+	Total     FundBalanceLine   `json:"total"`
+}
+
+// FundBalanceLine represents the net asset activity of one fund
+type FundBalanceLine struct {
+	FundID         *uuid.UUID `json:"fund_id,omitempty"` // Empty for lines without a fund
+	FundCode       string     `json:"fund_code"`
+	FundName       string     `json:"fund_name"`
+	FundType       string     `json:"fund_type"`
+	OpeningBalance Money      `json:"opening_balance"`
+	Revenue        Money      `json:"revenue"`
+	Expenses       Money      `json:"expenses"`
+	OtherChanges   Money      `json:"other_changes"` // Direct movements of equity accounts
+	ClosingBalance Money      `json:"closing_balance"`
+}
+
+// ProgramExpenseRequest for program expense report
+type ProgramExpenseRequest struct {
+	StartDate time.Time  `json:"start_date" binding:"required"`
+	EndDate   time.Time  `json:"end_date" binding:"required"`
+	BranchID  *uuid.UUID `json:"branch_id"`
+	FundID    *uuid.UUID `json:"fund_id"`
+	ProgramID *uuid.UUID `json:"program_id"`
+}
+
+// ProgramExpenseResponse for program expense report
+type ProgramExpenseResponse struct {
+	StartDate     time.Time               `json:"start_date"`
+	EndDate       time.Time               `json:"end_date"`
+	Programs      []ProgramExpenseSection `json:"programs"`
+	TotalExpenses Money                   `json:"total_expenses"`
+}
+
+// ProgramExpenseSection represents the expenses of one program
+type ProgramExpenseSection struct {
+	ProgramID   *uuid.UUID           `json:"program_id,omitempty"` // Empty for expenses without a program
+	ProgramCode string               `json:"program_code"`
+	ProgramName string               `json:"program_name"`
+	Lines       []ProgramExpenseLine `json:"lines"`
+	Total       Money                `json:"total"`
+}
+
+// ProgramExpenseLine represents an expense account within a program
+type ProgramExpenseLine struct {
+	AccountCode string `json:"account_code"`
+	AccountName string `json:"account_name"`
+	Amount      Money  `json:"amount"`
+}
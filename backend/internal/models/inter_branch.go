@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// InterBranchBalanceRequest for inter-branch balance report
+type InterBranchBalanceRequest struct {
+	AsOfDate         time.Time  `json:"as_of_date" binding:"required"`
+	BranchID         *uuid.UUID `json:"branch_id"`         // Only pairs involving this branch
+	UnreconciledOnly bool       `json:"unreconciled_only"` // Only pairs whose positions do not offset
+}
+
+// InterBranchBalanceResponse for inter-branch balance report
+type InterBranchBalanceResponse struct {
+	AsOfDate        time.Time                `json:"as_of_date"`
+	Lines           []InterBranchBalanceLine `json:"lines"`
+	TotalDifference Money                    `json:"total_difference"`
+	IsReconciled    bool                     `json:"is_reconciled"`
+}
+
+// InterBranchBalanceLine compares the positions two branches hold against each other.
+// A position is the debit balance of due to/due from lines booked against the other branch:
+// positive is a receivable, negative a payable.
+type InterBranchBalanceLine struct {
+	BranchID              uuid.UUID `json:"branch_id"`
+	BranchCode            string    `json:"branch_code"`
+	BranchName            string    `json:"branch_name"`
+	CounterpartBranchID   uuid.UUID `json:"counterpart_branch_id"`
+	CounterpartBranchCode string    `json:"counterpart_branch_code"`
+	CounterpartBranchName string    `json:"counterpart_branch_name"`
+	BranchPosition        Money     `json:"branch_position"`      // In the books of the branch
+	CounterpartPosition   Money     `json:"counterpart_position"` // In the books of the counterpart branch
+	Difference            Money     `json:"difference"`           // Zero when both positions offset
+	IsReconciled          bool      `json:"is_reconciled"`
+}
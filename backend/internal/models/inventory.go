@@ -24,8 +24,8 @@ type InventoryItem struct {
 	MaximumStock     float64    `gorm:"type:decimal(15,3);default:0" json:"maximum_stock"`
 	
 	// Pricing
-	PurchasePrice    float64    `gorm:"type:decimal(15,2);default:0" json:"purchase_price"`
-	SellingPrice     float64    `gorm:"type:decimal(15,2);default:0" json:"selling_price"`
+	PurchasePrice    Money      `gorm:"type:decimal(15,2);default:0" json:"purchase_price"`
+	SellingPrice     Money      `gorm:"type:decimal(15,2);default:0" json:"selling_price"`
 	
 	// Accounting
 	InventoryAccountID *uuid.UUID `gorm:"type:uuid" json:"inventory_account_id,omitempty"`
@@ -77,8 +77,8 @@ type StockTransaction struct {
 	
 	// Quantity
 	Quantity         float64    `gorm:"type:decimal(15,3);not null" json:"quantity"`
-	UnitPrice        float64    `gorm:"type:decimal(15,2);default:0" json:"unit_price"`
-	TotalValue       float64    `gorm:"type:decimal(15,2);default:0" json:"total_value"`
+	UnitPrice        Money      `gorm:"type:decimal(15,2);default:0" json:"unit_price"`
+	TotalValue       Money      `gorm:"type:decimal(15,2);default:0" json:"total_value"`
 	
 	// Stock Balance
 	StockBefore      float64    `gorm:"type:decimal(15,3);not null" json:"stock_before"`
@@ -153,8 +153,8 @@ type StockOpnameItem struct {
 	Difference       float64    `gorm:"type:decimal(15,3);not null" json:"difference"`
 	
 	// Value
-	UnitPrice        float64    `gorm:"type:decimal(15,2);default:0" json:"unit_price"`
-	DifferenceValue  float64    `gorm:"type:decimal(15,2);default:0" json:"difference_value"`
+	UnitPrice        Money      `gorm:"type:decimal(15,2);default:0" json:"unit_price"`
+	DifferenceValue  Money      `gorm:"type:decimal(15,2);default:0" json:"difference_value"`
 	
 	Remarks          string     `gorm:"type:text" json:"remarks,omitempty"`
 	
@@ -179,9 +179,9 @@ type PurchaseOrder struct {
 	ExpectedDate     *time.Time `json:"expected_date,omitempty"`
 	
 	// Totals
-	Subtotal         float64    `gorm:"type:decimal(15,2);default:0" json:"subtotal"`
-	Tax              float64    `gorm:"type:decimal(15,2);default:0" json:"tax"`
-	TotalAmount      float64    `gorm:"type:decimal(15,2);default:0" json:"total_amount"`
+	Subtotal         Money      `gorm:"type:decimal(15,2);default:0" json:"subtotal"`
+	Tax              Money      `gorm:"type:decimal(15,2);default:0" json:"tax"`
+	TotalAmount      Money      `gorm:"type:decimal(15,2);default:0" json:"total_amount"`
 	
 	// Status
 	Status           string     `gorm:"size:20;not null;default:'draft'" json:"status"` // draft, submitted, received, cancelled
@@ -209,8 +209,8 @@ type PurchaseOrderItem struct {
 	POID             uuid.UUID `gorm:"type:uuid;not null;index" json:"po_id"`
 	ItemID           uuid.UUID `gorm:"type:uuid;not null" json:"item_id"`
 	Quantity         float64   `gorm:"type:decimal(15,3);not null" json:"quantity"`
-	UnitPrice        float64   `gorm:"type:decimal(15,2);not null" json:"unit_price"`
-	Amount           float64   `gorm:"type:decimal(15,2);not null" json:"amount"`
+	UnitPrice        Money     `gorm:"type:decimal(15,2);not null" json:"unit_price"`
+	Amount           Money     `gorm:"type:decimal(15,2);not null" json:"amount"`
 	ReceivedQty      float64   `gorm:"type:decimal(15,3);default:0" json:"received_qty"`
 	
 	Notes            string    `gorm:"type:text" json:"notes,omitempty"`
@@ -256,8 +256,8 @@ type CreateInventoryItemRequest struct {
 	Description   string    `json:"description"`
 	Unit          string    `json:"unit" binding:"required"`
 	MinimumStock  float64   `json:"minimum_stock"`
-	PurchasePrice float64   `json:"purchase_price"`
-	SellingPrice  float64   `json:"selling_price"`
+	PurchasePrice Money     `json:"purchase_price"`
+	SellingPrice  Money     `json:"selling_price"`
 	IsSaleable    bool      `json:"is_saleable"`
 }
 
@@ -267,7 +267,7 @@ type CreateStockTransactionRequest struct {
 	TransactionType string    `json:"transaction_type" binding:"required"`
 	TransactionDate time.Time `json:"transaction_date" binding:"required"`
 	Quantity        float64   `json:"quantity" binding:"required,gt=0"`
-	UnitPrice       float64   `json:"unit_price"`
+	UnitPrice       Money     `json:"unit_price"`
 	Supplier        string    `json:"supplier"`
 	Customer        string    `json:"customer"`
 	Reason          string    `json:"reason"`
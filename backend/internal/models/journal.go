@@ -14,9 +14,10 @@ type Journal struct {
 	JournalDate   time.Time  `gorm:"not null;index" json:"journal_date"`
 	Description   string     `gorm:"type:text;not null" json:"description"`
 	ReferenceNo   string     `gorm:"size:100" json:"reference_no"`
+	Type          string     `gorm:"size:20;not null;default:'general'" json:"type"` // general, inter_branch, fx_revaluation, opening
 	Status        string     `gorm:"size:20;not null;default:'draft'" json:"status"`
-	TotalDebit    float64    `gorm:"type:decimal(15,2);not null;default:0" json:"total_debit"`
-	TotalCredit   float64    `gorm:"type:decimal(15,2);not null;default:0" json:"total_credit"`
+	TotalDebit    Money      `gorm:"type:decimal(15,2);not null;default:0" json:"total_debit"`
+	TotalCredit   Money      `gorm:"type:decimal(15,2);not null;default:0" json:"total_credit"`
 	IsPosted      bool       `gorm:"default:false" json:"is_posted"`
 	PostedAt      *time.Time `gorm:"index" json:"posted_at,omitempty"`
 	PostedBy      *uuid.UUID `gorm:"type:uuid" json:"posted_by,omitempty"`
@@ -31,12 +32,27 @@ type Journal struct {
 	RejectedAt  *time.Time `json:"rejected_at,omitempty"`
 	RejectReason string    `gorm:"type:text" json:"reject_reason,omitempty"`
 	
+	// Reversal link
+	ReversalOfID        *uuid.UUID `gorm:"type:uuid;index" json:"reversal_of_id,omitempty"`          // Set on the reversing journal
+	ReversedByJournalID *uuid.UUID `gorm:"type:uuid;index" json:"reversed_by_journal_id,omitempty"` // Set on the reversed journal
+	ReverseReason       string     `gorm:"type:text" json:"reverse_reason,omitempty"`
+	
+	// Inter-branch link, set on the journals generated for the other branches
+	SourceJournalID *uuid.UUID `gorm:"type:uuid;index" json:"source_journal_id,omitempty"`
+
+	// Fiscal year link, set on the closing and opening journals posted when a fiscal year is closed
+	FiscalYearID *uuid.UUID `gorm:"type:uuid;index" json:"fiscal_year_id,omitempty"`
+	
 	// Relationships
 	Branch       Branch        `gorm:"foreignKey:BranchID" json:"branch"`
 	JournalLines []JournalLine `gorm:"foreignKey:JournalID" json:"journal_lines,omitempty"`
 	Creator      User          `gorm:"foreignKey:CreatedBy" json:"creator,omitempty"`
 	Reviewer     *User         `gorm:"foreignKey:ReviewedBy" json:"reviewer,omitempty"`
 	Approver     *User         `gorm:"foreignKey:ApprovedBy" json:"approver,omitempty"`
+	ReversalOf   *Journal      `gorm:"foreignKey:ReversalOfID" json:"reversal_of,omitempty"`
+	ReversedBy   *Journal      `gorm:"foreignKey:ReversedByJournalID" json:"reversed_by,omitempty"`
+	SourceJournal  *Journal    `gorm:"foreignKey:SourceJournalID" json:"source_journal,omitempty"`
+	BranchJournals []Journal   `gorm:"foreignKey:SourceJournalID" json:"branch_journals,omitempty"`
 }
 
 // TableName specifies table name
@@ -50,8 +66,8 @@ type JournalLine struct {
 	JournalID   uuid.UUID  `gorm:"type:uuid;not null;index" json:"journal_id"`
 	AccountID   uuid.UUID  `gorm:"type:uuid;not null;index" json:"account_id"`
 	Description string     `gorm:"type:text" json:"description"`
-	Debit       float64    `gorm:"type:decimal(15,2);not null;default:0" json:"debit"`
-	Credit      float64    `gorm:"type:decimal(15,2);not null;default:0" json:"credit"`
+	Debit       Money      `gorm:"type:decimal(15,2);not null;default:0" json:"debit"`
+	Credit      Money      `gorm:"type:decimal(15,2);not null;default:0" json:"credit"`
 	
 	// Multi-dimensional accounting
 	FundID      *uuid.UUID `gorm:"type:uuid;index" json:"fund_id,omitempty"`
@@ -59,9 +75,23 @@ type JournalLine struct {
 	DonorID     *uuid.UUID `gorm:"type:uuid;index" json:"donor_id,omitempty"`
 	ProjectID   *uuid.UUID `gorm:"type:uuid;index" json:"project_id,omitempty"`
 	
+	// Foreign currency; Debit and Credit are always in the default currency
+	Currency      string  `gorm:"size:3" json:"currency,omitempty"`
+	ForeignAmount Money   `gorm:"type:decimal(15,2);not null;default:0" json:"foreign_amount,omitempty"` // Positive for a debit, negative for a credit
+	ExchangeRate  float64 `gorm:"type:decimal(18,6);not null;default:0" json:"exchange_rate,omitempty"`
+	
+	// Bank reconciliation
+	IsReconciled bool      `gorm:"default:false" json:"is_reconciled"`
+	
+	// Inter-branch
+	BranchID            *uuid.UUID `gorm:"type:uuid;index" json:"branch_id,omitempty"`             // Branch the line is booked in; empty means the journal branch
+	CounterpartBranchID *uuid.UUID `gorm:"type:uuid;index" json:"counterpart_branch_id,omitempty"` // Other branch of a due to/due from line
+	
 	// Relationships
 	Journal Journal  `gorm:"foreignKey:JournalID" json:"journal,omitempty"`
 	Account Account  `gorm:"foreignKey:AccountID" json:"account"`
+	Branch            *Branch `gorm:"foreignKey:BranchID" json:"branch,omitempty"`
+	CounterpartBranch *Branch `gorm:"foreignKey:CounterpartBranchID" json:"counterpart_branch,omitempty"`
 	Fund    *Fund    `gorm:"foreignKey:FundID" json:"fund,omitempty"`
 	Program *Program `gorm:"foreignKey:ProgramID" json:"program,omitempty"`
 	Donor   *Donor   `gorm:"foreignKey:DonorID" json:"donor,omitempty"`
@@ -112,6 +142,14 @@ const (
 	JournalStatusPosted   = "posted"
 )
 
+// Journal Type constants
+const (
+	JournalTypeGeneral     = "general"
+	JournalTypeInterBranch = "inter_branch"
+	JournalTypeFXRevaluation = "fx_revaluation"
+	JournalTypeOpening     = "opening" // Opening balances; reports count them in the opening balance, not the period movement
+)
+
 // Fund Type constants
 const (
 	FundTypeRestricted   = "restricted"
@@ -131,6 +169,19 @@ func (j *Journal) CanPost() bool {
 		   len(j.JournalLines) > 0
 }
 
+// IsInterBranch checks if journal books lines in other branches
+func (j *Journal) IsInterBranch() bool {
+	return j.Type == JournalTypeInterBranch
+}
+
+// LineBranchID returns the branch a journal line is booked in
+func (j *Journal) LineBranchID(line *JournalLine) uuid.UUID {
+	if line.BranchID != nil {
+		return *line.BranchID
+	}
+	return j.BranchID
+}
+
 // CanApprove checks if journal can be approved
 func (j *Journal) CanApprove() bool {
 	return j.Status == JournalStatusReview && j.IsBalanced()
@@ -144,10 +195,11 @@ type JournalResponse struct {
 	JournalDate   time.Time            `json:"journal_date"`
 	Description   string               `json:"description"`
 	ReferenceNo   string               `json:"reference_no,omitempty"`
+	Type          string               `json:"type"`
 	Status        string               `json:"status"`
 	StatusName    string               `json:"status_name"`
-	TotalDebit    float64              `json:"total_debit"`
-	TotalCredit   float64              `json:"total_credit"`
+	TotalDebit    Money                `json:"total_debit"`
+	TotalCredit   Money                `json:"total_credit"`
 	IsPosted      bool                 `json:"is_posted"`
 	IsBalanced    bool                 `json:"is_balanced"`
 	PostedAt      *time.Time           `json:"posted_at,omitempty"`
@@ -160,6 +212,14 @@ type JournalResponse struct {
 	RejectedBy    *uuid.UUID           `json:"rejected_by,omitempty"`
 	RejectedAt    *time.Time           `json:"rejected_at,omitempty"`
 	RejectReason  string               `json:"reject_reason,omitempty"`
+	ReversalOfID        *uuid.UUID     `json:"reversal_of_id,omitempty"`
+	ReversalOfNumber    string         `json:"reversal_of_number,omitempty"`
+	ReversedByJournalID *uuid.UUID     `json:"reversed_by_journal_id,omitempty"`
+	ReversedByNumber    string         `json:"reversed_by_number,omitempty"`
+	ReverseReason       string         `json:"reverse_reason,omitempty"`
+	SourceJournalID     *uuid.UUID     `json:"source_journal_id,omitempty"`
+	SourceJournalNumber string         `json:"source_journal_number,omitempty"`
+	BranchJournals      []JournalResponse `json:"branch_journals,omitempty"`
 	JournalLines  []JournalLineResponse `json:"journal_lines,omitempty"`
 	CreatedAt     time.Time            `json:"created_at"`
 	UpdatedAt     time.Time            `json:"updated_at"`
@@ -172,14 +232,21 @@ type JournalLineResponse struct {
 	AccountCode string     `json:"account_code"`
 	AccountName string     `json:"account_name"`
 	Description string     `json:"description,omitempty"`
-	Debit       float64    `json:"debit"`
-	Credit      float64    `json:"credit"`
+	Debit       Money      `json:"debit"`
+	Credit      Money      `json:"credit"`
 	FundID      *uuid.UUID `json:"fund_id,omitempty"`
 	FundName    string     `json:"fund_name,omitempty"`
 	ProgramID   *uuid.UUID `json:"program_id,omitempty"`
 	ProgramName string     `json:"program_name,omitempty"`
 	DonorID     *uuid.UUID `json:"donor_id,omitempty"`
 	DonorName   string     `json:"donor_name,omitempty"`
+	BranchID    *uuid.UUID `json:"branch_id,omitempty"`
+	BranchName  string     `json:"branch_name,omitempty"`
+	CounterpartBranchID   *uuid.UUID `json:"counterpart_branch_id,omitempty"`
+	CounterpartBranchName string     `json:"counterpart_branch_name,omitempty"`
+	Currency      string  `json:"currency,omitempty"`
+	ForeignAmount Money   `json:"foreign_amount,omitempty"`
+	ExchangeRate  float64 `json:"exchange_rate,omitempty"`
 }
 
 // ToJournalResponse converts Journal to JournalResponse
@@ -191,6 +258,7 @@ func (j *Journal) ToJournalResponse() *JournalResponse {
 		JournalDate:   j.JournalDate,
 		Description:   j.Description,
 		ReferenceNo:   j.ReferenceNo,
+		Type:          j.Type,
 		Status:        j.Status,
 		StatusName:    GetJournalStatusName(j.Status),
 		TotalDebit:    j.TotalDebit,
@@ -206,6 +274,10 @@ func (j *Journal) ToJournalResponse() *JournalResponse {
 		RejectedBy:    j.RejectedBy,
 		RejectedAt:    j.RejectedAt,
 		RejectReason:  j.RejectReason,
+		ReversalOfID:        j.ReversalOfID,
+		ReversedByJournalID: j.ReversedByJournalID,
+		ReverseReason:       j.ReverseReason,
+		SourceJournalID:     j.SourceJournalID,
 		CreatedAt:     j.CreatedAt,
 		UpdatedAt:     j.UpdatedAt,
 	}
@@ -214,37 +286,72 @@ func (j *Journal) ToJournalResponse() *JournalResponse {
 		resp.CreatorName = j.Creator.FullName
 	}
 
+	if j.ReversalOf != nil {
+		resp.ReversalOfNumber = j.ReversalOf.JournalNumber
+	}
+	if j.ReversedBy != nil {
+		resp.ReversedByNumber = j.ReversedBy.JournalNumber
+	}
+	if j.SourceJournal != nil {
+		resp.SourceJournalNumber = j.SourceJournal.JournalNumber
+	}
+
+	if len(j.BranchJournals) > 0 {
+		resp.BranchJournals = make([]JournalResponse, len(j.BranchJournals))
+		for i := range j.BranchJournals {
+			resp.BranchJournals[i] = *j.BranchJournals[i].ToJournalResponse()
+		}
+	}
+
 	if len(j.JournalLines) > 0 {
 		resp.JournalLines = make([]JournalLineResponse, len(j.JournalLines))
-		for i, line := range j.JournalLines {
-			resp.JournalLines[i] = JournalLineResponse{
-				ID:          line.ID,
-				AccountID:   line.AccountID,
-				AccountCode: line.Account.Code,
-				AccountName: line.Account.Name,
-				Description: line.Description,
-				Debit:       line.Debit,
-				Credit:      line.Credit,
-				FundID:      line.FundID,
-				ProgramID:   line.ProgramID,
-				DonorID:     line.DonorID,
-			}
-			
-			if line.Fund != nil {
-				resp.JournalLines[i].FundName = line.Fund.Name
-			}
-			if line.Program != nil {
-				resp.JournalLines[i].ProgramName = line.Program.Name
-			}
-			if line.Donor != nil {
-				resp.JournalLines[i].DonorName = line.Donor.Name
-			}
+		for i := range j.JournalLines {
+			resp.JournalLines[i] = *j.JournalLines[i].ToJournalLineResponse()
 		}
 	}
 
 	return resp
 }
 
+// ToJournalLineResponse converts JournalLine to JournalLineResponse
+func (line *JournalLine) ToJournalLineResponse() *JournalLineResponse {
+	resp := &JournalLineResponse{
+		ID:                  line.ID,
+		AccountID:           line.AccountID,
+		AccountCode:         line.Account.Code,
+		AccountName:         line.Account.Name,
+		Description:         line.Description,
+		Debit:               line.Debit,
+		Credit:              line.Credit,
+		FundID:              line.FundID,
+		ProgramID:           line.ProgramID,
+		DonorID:             line.DonorID,
+		BranchID:            line.BranchID,
+		CounterpartBranchID: line.CounterpartBranchID,
+		Currency:            line.Currency,
+		ForeignAmount:       line.ForeignAmount,
+		ExchangeRate:        line.ExchangeRate,
+	}
+
+	if line.Fund != nil {
+		resp.FundName = line.Fund.Name
+	}
+	if line.Program != nil {
+		resp.ProgramName = line.Program.Name
+	}
+	if line.Donor != nil {
+		resp.DonorName = line.Donor.Name
+	}
+	if line.Branch != nil {
+		resp.BranchName = line.Branch.Name
+	}
+	if line.CounterpartBranch != nil {
+		resp.CounterpartBranchName = line.CounterpartBranch.Name
+	}
+
+	return resp
+}
+
 // GetJournalStatusName returns human-readable status name
 func GetJournalStatusName(status string) string {
 	names := map[string]string{
@@ -263,6 +370,7 @@ type CreateJournalRequest struct {
 	JournalDate   time.Time              `json:"journal_date" binding:"required"`
 	Description   string                 `json:"description" binding:"required"`
 	ReferenceNo   string                 `json:"reference_no"`
+	Type          string                 `json:"type" binding:"omitempty,oneof=general inter_branch"` // Defaults to general
 	JournalLines  []CreateJournalLineReq `json:"journal_lines" binding:"required,min=2,dive"`
 }
 
@@ -270,11 +378,16 @@ type CreateJournalRequest struct {
 type CreateJournalLineReq struct {
 	AccountID   uuid.UUID  `json:"account_id" binding:"required"`
 	Description string     `json:"description"`
-	Debit       float64    `json:"debit" binding:"min=0"`
-	Credit      float64    `json:"credit" binding:"min=0"`
+	Debit       Money      `json:"debit" binding:"min=0"`
+	Credit      Money      `json:"credit" binding:"min=0"`
 	FundID      *uuid.UUID `json:"fund_id"`
 	ProgramID   *uuid.UUID `json:"program_id"`
 	DonorID     *uuid.UUID `json:"donor_id"`
+	BranchID    *uuid.UUID `json:"branch_id"`             // Inter-branch journals only
+	CounterpartBranchID *uuid.UUID `json:"counterpart_branch_id"` // Other branch when booking due to/due from directly
+	Currency      string  `json:"currency" binding:"omitempty,len=3,alpha"` // Empty for the default currency
+	ForeignAmount Money   `json:"foreign_amount"`                           // Positive for a debit, negative for a credit
+	ExchangeRate  float64 `json:"exchange_rate" binding:"min=0"`            // Defaults to the rate on the journal date
 }
 
 // UpdateJournalRequest for updating journal
@@ -301,6 +414,12 @@ type PostJournalRequest struct {
 	PostDate time.Time `json:"post_date" binding:"required"`
 }
 
+// ReverseJournalRequest for reversing a posted journal
+type ReverseJournalRequest struct {
+	ReversalDate time.Time `json:"reversal_date"` // Defaults to today
+	Reason       string    `json:"reason" binding:"required"`
+}
+
 // JournalListResponse for paginated journal list
 type JournalListResponse struct {
 	Journals   []JournalResponse `json:"journals"`
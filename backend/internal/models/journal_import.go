@@ -0,0 +1,62 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Journal import mode constants
+const (
+	JournalImportAllOrNothing = "all_or_nothing" // Nothing is imported when any row has an error
+	JournalImportValidOnly    = "valid_only"     // Valid journals are imported, invalid ones are reported
+)
+
+// Journal import status constants
+const (
+	JournalImportStatusValid    = "valid"
+	JournalImportStatusInvalid  = "invalid"
+	JournalImportStatusImported = "imported"
+)
+
+// ImportJournalsRequest holds the form fields sent with a journal import file
+type ImportJournalsRequest struct {
+	BranchID *uuid.UUID `form:"-"`                                                        // From the branch_id field; used for rows without a branch_code
+	Mode     string     `form:"mode" binding:"omitempty,oneof=all_or_nothing valid_only"` // Defaults to all_or_nothing
+	DryRun   bool       `form:"dry_run"`                                                  // Validate only, import nothing
+}
+
+// JournalImportError is a problem found in the import file. Row is the line number in the
+// file, counting the header.
+type JournalImportError struct {
+	Row        int    `json:"row"`
+	JournalKey string `json:"journal_key,omitempty"`
+	Column     string `json:"column,omitempty"`
+	Message    string `json:"message"`
+}
+
+// JournalImportJournal is the outcome for the rows sharing one journal key
+type JournalImportJournal struct {
+	JournalKey    string     `json:"journal_key"`
+	Rows          []int      `json:"rows"`
+	BranchID      *uuid.UUID `json:"branch_id,omitempty"`
+	JournalDate   *time.Time `json:"journal_date,omitempty"`
+	Description   string     `json:"description"`
+	TotalDebit    Money      `json:"total_debit"`
+	TotalCredit   Money      `json:"total_credit"`
+	Status        string     `json:"status"`
+	JournalID     *uuid.UUID `json:"journal_id,omitempty"`
+	JournalNumber string     `json:"journal_number,omitempty"`
+}
+
+// JournalImportResult is the validation report of a journal import
+type JournalImportResult struct {
+	Mode             string                 `json:"mode"`
+	DryRun           bool                   `json:"dry_run"`
+	TotalRows        int                    `json:"total_rows"`
+	TotalJournals    int                    `json:"total_journals"`
+	ValidJournals    int                    `json:"valid_journals"`
+	ImportedJournals int                    `json:"imported_journals"`
+	Errors           []JournalImportError   `json:"errors"`
+	Journals         []JournalImportJournal `json:"journals"`
+}
@@ -0,0 +1,99 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Journal search sort fields
+const (
+	JournalSortDate        = "journal_date"
+	JournalSortNumber      = "journal_number"
+	JournalSortReference   = "reference_no"
+	JournalSortStatus      = "status"
+	JournalSortAmount      = "amount" // Total debit of a journal, or the debit or credit of a line
+	JournalSortCreatedAt   = "created_at"
+	JournalSortAccountCode = "account_code" // Journal lines only
+)
+
+// JournalSearchParams filters journals and journal lines. The ID and amount filters are read
+// by the handler, since they cannot be bound from a query string.
+type JournalSearchParams struct {
+	Page      int        `form:"page"`
+	PageSize  int        `form:"page_size"`
+	Search    string     `form:"search"` // Free text over the journal number, description and reference, and the line description when searching lines
+	StartDate *time.Time `form:"start_date" time_format:"2006-01-02"`
+	EndDate   *time.Time `form:"end_date" time_format:"2006-01-02"`
+	Status    string     `form:"status"` // Comma separated
+	Type      string     `form:"type"`   // Comma separated
+
+	BranchID           *uuid.UUID `form:"-"`
+	AccountID          *uuid.UUID `form:"-"`
+	IncludeSubAccounts bool       `form:"include_sub_accounts"` // Match the account and all accounts below it
+	FundID             *uuid.UUID `form:"-"`
+	ProgramID          *uuid.UUID `form:"-"`
+	DonorID            *uuid.UUID `form:"-"`
+	CreatedBy          *uuid.UUID `form:"-"`
+	MinAmount          *Money     `form:"-"`
+	MaxAmount          *Money     `form:"-"`
+
+	SortBy    string `form:"sort_by" binding:"omitempty,oneof=journal_date journal_number reference_no status amount created_at account_code"`
+	SortOrder string `form:"sort_order" binding:"omitempty,oneof=asc desc"` // Defaults to desc
+}
+
+// Validate checks the ranges of the search
+func (p *JournalSearchParams) Validate() error {
+	if p.StartDate != nil && p.EndDate != nil && p.EndDate.Before(*p.StartDate) {
+		return errors.New("end_date must not be before start_date")
+	}
+	if p.MinAmount != nil && p.MaxAmount != nil && *p.MaxAmount < *p.MinAmount {
+		return errors.New("max_amount must not be less than min_amount")
+	}
+	if p.IncludeSubAccounts && p.AccountID == nil {
+		return errors.New("include_sub_accounts needs an account_id")
+	}
+	return nil
+}
+
+// JournalLineSearchResult is a journal line found by a search, with its journal
+type JournalLineSearchResult struct {
+	JournalID          uuid.UUID `json:"journal_id"`
+	JournalNumber      string    `json:"journal_number"`
+	JournalDate        time.Time `json:"journal_date"`
+	JournalDescription string    `json:"journal_description"`
+	ReferenceNo        string    `json:"reference_no,omitempty"`
+	JournalBranchID    uuid.UUID `json:"journal_branch_id"`
+	JournalBranchName  string    `json:"journal_branch_name,omitempty"`
+	Status             string    `json:"status"`
+	CreatedBy          uuid.UUID `json:"created_by"`
+	CreatorName        string    `json:"creator_name,omitempty"`
+	JournalLineResponse
+}
+
+// ToJournalLineSearchResult converts a journal line with its journal loaded
+func (line *JournalLine) ToJournalLineSearchResult() *JournalLineSearchResult {
+	return &JournalLineSearchResult{
+		JournalID:           line.JournalID,
+		JournalNumber:       line.Journal.JournalNumber,
+		JournalDate:         line.Journal.JournalDate,
+		JournalDescription:  line.Journal.Description,
+		ReferenceNo:         line.Journal.ReferenceNo,
+		JournalBranchID:     line.Journal.BranchID,
+		JournalBranchName:   line.Journal.Branch.Name,
+		Status:              line.Journal.Status,
+		CreatedBy:           line.Journal.CreatedBy,
+		CreatorName:         line.Journal.Creator.FullName,
+		JournalLineResponse: *line.ToJournalLineResponse(),
+	}
+}
+
+// JournalLineListResponse for paginated journal line search results
+type JournalLineListResponse struct {
+	Lines      []JournalLineSearchResult `json:"lines"`
+	Total      int64                     `json:"total"`
+	Page       int                       `json:"page"`
+	PageSize   int                       `json:"page_size"`
+	TotalPages int                       `json:"total_pages"`
+}
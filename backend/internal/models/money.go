@@ -0,0 +1,260 @@
+package models
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Money is an exact amount of money in sen, hundredths of a rupiah. Sums and comparisons are
+// exact, so a balanced journal always has equal debit and credit totals. It is stored in
+// decimal(15,2) columns and written to JSON as a plain number, the same as a float64 amount.
+//
+// Multiplying or dividing an amount rounds half away from zero to the nearest sen. Use Mul,
+// Percent and Div for that rather than the * and / operators, which work on sen.
+type Money int64
+
+// Money units
+const (
+	Sen    Money = 1
+	Rupiah Money = 100
+)
+
+// decimalPattern matches plain decimal numbers, optionally with an exponent as JSON allows
+var decimalPattern = regexp.MustCompile(`^[+-]?(\d+\.?\d*|\.\d+)([eE][+-]?\d+)?$`)
+
+// NewMoney converts a float amount, rounding half away from zero to the nearest sen. The float
+// is read in its shortest decimal form, so 1.005 is rounded as 1.005 and becomes 1.01. NaN,
+// infinities and amounts too large for Money are errors.
+func NewMoney(amount float64) (Money, error) {
+	value, err := ratFromFloat(amount)
+	if err != nil {
+		return 0, err
+	}
+	return moneyFromRat(value)
+}
+
+// ParseMoney parses a decimal amount such as "-1500.25". Digits beyond the sen are rounded half
+// away from zero.
+func ParseMoney(text string) (Money, error) {
+	text = strings.TrimSpace(text)
+	if !decimalPattern.MatchString(text) {
+		return 0, fmt.Errorf("invalid amount %q", text)
+	}
+	value, ok := new(big.Rat).SetString(text)
+	if !ok {
+		return 0, fmt.Errorf("invalid amount %q", text)
+	}
+	return moneyFromRat(value)
+}
+
+// Float64 returns the amount in rupiah. Use it only for display or ratios, never for sums.
+func (m Money) Float64() float64 {
+	return float64(m) / float64(Rupiah)
+}
+
+// String formats the amount with two decimals, such as "1500.00"
+func (m Money) String() string {
+	sign := ""
+	value := int64(m)
+	if value < 0 {
+		sign = "-"
+	}
+	units, sen := value/int64(Rupiah), value%int64(Rupiah)
+	return fmt.Sprintf("%s%d.%02d", sign, abs64(units), abs64(sen))
+}
+
+// Abs returns the amount without its sign
+func (m Money) Abs() Money {
+	if m < 0 {
+		return -m
+	}
+	return m
+}
+
+// Mul multiplies the amount by a factor such as a quantity or an exchange rate. A factor that
+// is NaN or infinite, or a result too large for Money, is an error.
+func (m Money) Mul(factor float64) (Money, error) {
+	rate, err := ratFromFloat(factor)
+	if err != nil {
+		return 0, err
+	}
+	value := m.Rat()
+	return moneyFromRat(value.Mul(value, rate))
+}
+
+// Percent returns percent percent of the amount, as for tax rates
+func (m Money) Percent(percent float64) (Money, error) {
+	return m.Mul(percent / 100)
+}
+
+// Div divides the amount into n parts
+func (m Money) Div(n int64) Money {
+	if n == 0 {
+		return 0
+	}
+	quotient, remainder := int64(m)/n, int64(m)%n
+	if 2*abs64(remainder) >= abs64(n) {
+		if (remainder < 0) != (n < 0) {
+			quotient--
+		} else {
+			quotient++
+		}
+	}
+	return Money(quotient)
+}
+
+// Prorate returns the share part/total of the amount, as when spreading a payment over
+// invoice items
+func (m Money) Prorate(part, total Money) Money {
+	if total == 0 {
+		return 0
+	}
+	value := new(big.Rat).SetFrac(new(big.Int).Mul(big.NewInt(int64(m)), big.NewInt(int64(part))), big.NewInt(int64(total)))
+	result, err := moneyFromRat(value.Quo(value, new(big.Rat).SetInt64(int64(Rupiah))))
+	if err != nil {
+		return 0
+	}
+	return result
+}
+
+// DivRound divides the amount into n parts, rounded half away from zero to a multiple of unit
+func (m Money) DivRound(n int64, unit Money) Money {
+	return m.Div(n*int64(unit)) * unit
+}
+
+// Round rounds the amount half away from zero to a multiple of unit, such as Rupiah
+func (m Money) Round(unit Money) Money {
+	return m.DivRound(1, unit)
+}
+
+// Floor rounds the amount down to a multiple of unit
+func (m Money) Floor(unit Money) Money {
+	floored := m / unit * unit
+	if floored > m {
+		floored -= unit
+	}
+	return floored
+}
+
+// Ratio returns the amount divided by base, such as for percentages in reports
+func (m Money) Ratio(base Money) float64 {
+	if base == 0 {
+		return 0
+	}
+	return float64(m) / float64(base)
+}
+
+// Rat returns the amount in rupiah as an exact fraction
+func (m Money) Rat() *big.Rat {
+	return new(big.Rat).SetFrac64(int64(m), int64(Rupiah))
+}
+
+// MoneyFromRat rounds an exact rupiah amount half away from zero to the nearest sen
+func MoneyFromRat(value *big.Rat) (Money, error) {
+	return moneyFromRat(value)
+}
+
+// MarshalJSON writes the amount as a number, without trailing zeros
+func (m Money) MarshalJSON() ([]byte, error) {
+	text := m.String()
+	text = strings.TrimRight(text, "0")
+	text = strings.TrimSuffix(text, ".")
+	return []byte(text), nil
+}
+
+// UnmarshalJSON reads a JSON number
+func (m *Money) UnmarshalJSON(data []byte) error {
+	text := string(data)
+	if text == "null" {
+		return nil
+	}
+	if strings.HasPrefix(text, "\"") {
+		return errors.New("amount must be a number")
+	}
+	money, err := ParseMoney(text)
+	if err != nil {
+		return err
+	}
+	*m = money
+	return nil
+}
+
+// Scan reads a decimal column
+func (m *Money) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*m = 0
+		return nil
+	case []byte:
+		money, err := ParseMoney(string(v))
+		*m = money
+		return err
+	case string:
+		money, err := ParseMoney(v)
+		*m = money
+		return err
+	case int64:
+		*m = Money(v) * Rupiah
+		return nil
+	case float64:
+		money, err := NewMoney(v)
+		*m = money
+		return err
+	}
+	return fmt.Errorf("cannot scan %T into Money", value)
+}
+
+// Value writes the amount as a decimal
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+// GormDataType is the column type used when a field has no type tag
+func (Money) GormDataType() string {
+	return "decimal(15,2)"
+}
+
+// moneyFromRat rounds a rupiah amount half away from zero to the nearest sen
+func moneyFromRat(value *big.Rat) (Money, error) {
+	scaled := new(big.Rat).Mul(value, new(big.Rat).SetInt64(int64(Rupiah)))
+	numerator, denominator := scaled.Num(), scaled.Denom()
+
+	quotient, remainder := new(big.Int).QuoRem(numerator, denominator, new(big.Int))
+	if new(big.Int).Mul(new(big.Int).Abs(remainder), big.NewInt(2)).Cmp(denominator) >= 0 {
+		if numerator.Sign() < 0 {
+			quotient.Sub(quotient, big.NewInt(1))
+		} else {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+	}
+
+	if !quotient.IsInt64() {
+		return 0, errors.New("amount is out of range")
+	}
+	return Money(quotient.Int64()), nil
+}
+
+// ratFromFloat reads a float in its shortest decimal form
+func ratFromFloat(value float64) (*big.Rat, error) {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return nil, fmt.Errorf("%v is not a number", value)
+	}
+	rat, ok := new(big.Rat).SetString(strconv.FormatFloat(value, 'f', -1, 64))
+	if !ok {
+		return nil, fmt.Errorf("%v is not a number", value)
+	}
+	return rat, nil
+}
+
+func abs64(value int64) int64 {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
@@ -0,0 +1,285 @@
+package models
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		text    string
+		want    Money
+		wantErr bool
+	}{
+		{text: "1500.25", want: 150025},
+		{text: "-1500.25", want: -150025},
+		{text: " 1500 ", want: 150000},
+		{text: ".5", want: 50},
+		{text: "1e3", want: 100000},
+		{text: "0.005", want: 1},
+		{text: "-0.005", want: -1},
+		{text: "0.0049", want: 0},
+		{text: "1.015", want: 102},
+		{text: "-1.015", want: -102},
+		{text: "", wantErr: true},
+		{text: "abc", wantErr: true},
+		{text: "1,000", wantErr: true},
+		{text: "99999999999999999999", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseMoney(tt.text)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseMoney(%q) error = %v, wantErr %v", tt.text, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseMoney(%q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestNewMoney(t *testing.T) {
+	tests := []struct {
+		amount  float64
+		want    Money
+		wantErr bool
+	}{
+		{amount: 1500.25, want: 150025},
+		{amount: 1.005, want: 101},
+		{amount: -1.005, want: -101},
+		{amount: 0.004, want: 0},
+		{amount: math.NaN(), wantErr: true},
+		{amount: math.Inf(1), wantErr: true},
+		{amount: math.Inf(-1), wantErr: true},
+		{amount: 1e30, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := NewMoney(tt.amount)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("NewMoney(%v) error = %v, wantErr %v", tt.amount, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("NewMoney(%v) = %d, want %d", tt.amount, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyString(t *testing.T) {
+	tests := []struct {
+		money Money
+		want  string
+		json  string
+	}{
+		{money: 0, want: "0.00", json: "0"},
+		{money: 150025, want: "1500.25", json: "1500.25"},
+		{money: 150050, want: "1500.50", json: "1500.5"},
+		{money: 150000, want: "1500.00", json: "1500"},
+		{money: -5, want: "-0.05", json: "-0.05"},
+		{money: -150025, want: "-1500.25", json: "-1500.25"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.money.String(); got != tt.want {
+			t.Errorf("Money(%d).String() = %q, want %q", tt.money, got, tt.want)
+		}
+		data, err := tt.money.MarshalJSON()
+		if err != nil {
+			t.Errorf("Money(%d).MarshalJSON() error = %v", tt.money, err)
+			continue
+		}
+		if string(data) != tt.json {
+			t.Errorf("Money(%d).MarshalJSON() = %s, want %s", tt.money, data, tt.json)
+		}
+
+		var parsed Money
+		if err := parsed.UnmarshalJSON(data); err != nil || parsed != tt.money {
+			t.Errorf("UnmarshalJSON(%s) = %d, %v, want %d", data, parsed, err, tt.money)
+		}
+	}
+}
+
+func TestMoneyUnmarshalJSONRejectsStrings(t *testing.T) {
+	var money Money
+	if err := money.UnmarshalJSON([]byte(`"1500"`)); err == nil {
+		t.Error("UnmarshalJSON accepted a quoted amount")
+	}
+}
+
+func TestMoneyScan(t *testing.T) {
+	tests := []struct {
+		value   interface{}
+		want    Money
+		wantErr bool
+	}{
+		{value: nil, want: 0},
+		{value: []byte("1500.25"), want: 150025},
+		{value: "-0.05", want: -5},
+		{value: int64(3), want: 300},
+		{value: 1.5, want: 150},
+		{value: math.NaN(), wantErr: true},
+		{value: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		var got Money
+		err := got.Scan(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Scan(%v) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("Scan(%v) = %d, want %d", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyMul(t *testing.T) {
+	tests := []struct {
+		money   Money
+		factor  float64
+		want    Money
+		wantErr bool
+	}{
+		{money: 1000 * Rupiah, factor: 1.5, want: 1500 * Rupiah},
+		{money: 1000 * Rupiah, factor: -2, want: -2000 * Rupiah},
+		{money: 1 * Sen, factor: 0.5, want: 1},
+		{money: -1 * Sen, factor: 0.5, want: -1},
+		{money: 3 * Sen, factor: 0.5, want: 2},
+		{money: 3 * Sen, factor: 0.49, want: 1},
+		{money: 15500 * Rupiah, factor: 0.00006452, want: 100},
+		{money: Rupiah, factor: math.NaN(), wantErr: true},
+		{money: Rupiah, factor: math.Inf(1), wantErr: true},
+		{money: Money(math.MaxInt64), factor: 2, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := tt.money.Mul(tt.factor)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Money(%d).Mul(%v) error = %v, wantErr %v", tt.money, tt.factor, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Money(%d).Mul(%v) = %d, want %d", tt.money, tt.factor, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyPercent(t *testing.T) {
+	got, err := (1000 * Rupiah).Percent(4)
+	if err != nil || got != 40*Rupiah {
+		t.Errorf("Percent(4) = %d, %v, want %d", got, err, 40*Rupiah)
+	}
+	if _, err := Rupiah.Percent(math.NaN()); err == nil {
+		t.Error("Percent(NaN) did not return an error")
+	}
+}
+
+func TestMoneyDiv(t *testing.T) {
+	tests := []struct {
+		money Money
+		n     int64
+		want  Money
+	}{
+		{money: 5, n: 2, want: 3},
+		{money: -5, n: 2, want: -3},
+		{money: 5, n: -2, want: -3},
+		{money: -5, n: -2, want: 3},
+		{money: 4, n: 3, want: 1},
+		{money: -4, n: 3, want: -1},
+		{money: 5, n: 0, want: 0},
+	}
+
+	for _, tt := range tests {
+		if got := tt.money.Div(tt.n); got != tt.want {
+			t.Errorf("Money(%d).Div(%d) = %d, want %d", tt.money, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyProrate(t *testing.T) {
+	tests := []struct {
+		money       Money
+		part, total Money
+		want        Money
+	}{
+		{money: 10000, part: 1, total: 3, want: 3333},
+		{money: 10000, part: 2, total: 3, want: 6667},
+		{money: -10000, part: 2, total: 3, want: -6667},
+		{money: 1, part: 1, total: 2, want: 1},
+		{money: -1, part: 1, total: 2, want: -1},
+		{money: 3, part: 1, total: 2, want: 2},
+		{money: 10000, part: 3, total: 3, want: 10000},
+		{money: 10000, part: 1, total: 0, want: 0},
+	}
+
+	for _, tt := range tests {
+		if got := tt.money.Prorate(tt.part, tt.total); got != tt.want {
+			t.Errorf("Money(%d).Prorate(%d, %d) = %d, want %d", tt.money, tt.part, tt.total, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyDivRound(t *testing.T) {
+	tests := []struct {
+		money Money
+		n     int64
+		unit  Money
+		want  Money
+	}{
+		{money: 150, n: 1, unit: Rupiah, want: 200},
+		{money: -150, n: 1, unit: Rupiah, want: -200},
+		{money: 149, n: 1, unit: Rupiah, want: 100},
+		{money: -149, n: 1, unit: Rupiah, want: -100},
+		{money: 12000 * Rupiah, n: 12, unit: Rupiah, want: 1000 * Rupiah},
+		{money: 18 * Rupiah, n: 12, unit: Rupiah, want: 2 * Rupiah},
+		{money: -18 * Rupiah, n: 12, unit: Rupiah, want: -2 * Rupiah},
+		{money: 100, n: 3, unit: Sen, want: 33},
+	}
+
+	for _, tt := range tests {
+		if got := tt.money.DivRound(tt.n, tt.unit); got != tt.want {
+			t.Errorf("Money(%d).DivRound(%d, %d) = %d, want %d", tt.money, tt.n, tt.unit, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyRound(t *testing.T) {
+	tests := []struct {
+		money Money
+		want  Money
+	}{
+		{money: 250, want: 300},
+		{money: -250, want: -300},
+		{money: 249, want: 200},
+	}
+
+	for _, tt := range tests {
+		if got := tt.money.Round(Rupiah); got != tt.want {
+			t.Errorf("Money(%d).Round(Rupiah) = %d, want %d", tt.money, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyFloor(t *testing.T) {
+	tests := []struct {
+		money Money
+		unit  Money
+		want  Money
+	}{
+		{money: 1500 * Rupiah, unit: 1000 * Rupiah, want: 1000 * Rupiah},
+		{money: 2000 * Rupiah, unit: 1000 * Rupiah, want: 2000 * Rupiah},
+		{money: -1500 * Rupiah, unit: 1000 * Rupiah, want: -2000 * Rupiah},
+		{money: -2000 * Rupiah, unit: 1000 * Rupiah, want: -2000 * Rupiah},
+		{money: 99, unit: Rupiah, want: 0},
+		{money: -1, unit: Rupiah, want: -Rupiah},
+	}
+
+	for _, tt := range tests {
+		if got := tt.money.Floor(tt.unit); got != tt.want {
+			t.Errorf("Money(%d).Floor(%d) = %d, want %d", tt.money, tt.unit, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Net asset class constants (ISAK 35)
+const (
+	NetAssetsWithoutRestrictions = "without_restrictions"
+	NetAssetsWithRestrictions    = "with_restrictions"
+)
+
+// FinancialPositionRequest for ISAK 35 statement of financial position
+type FinancialPositionRequest struct {
+	AsOfDate time.Time  `json:"as_of_date" binding:"required"`
+	BranchID *uuid.UUID `json:"branch_id"`
+}
+
+// NetAssetsPeriodRequest for ISAK 35 period statements
+type NetAssetsPeriodRequest struct {
+	StartDate time.Time  `json:"start_date" binding:"required"`
+	EndDate   time.Time  `json:"end_date" binding:"required"`
+	BranchID  *uuid.UUID `json:"branch_id"`
+}
+
+// RestrictionAmounts splits an amount by net asset class
+type RestrictionAmounts struct {
+	WithoutRestrictions Money `json:"without_restrictions"`
+	WithRestrictions    Money `json:"with_restrictions"`
+	Total               Money `json:"total"`
+}
+
+// Add adds an amount to a net asset class and the total
+func (r *RestrictionAmounts) Add(restricted bool, amount Money) {
+	if restricted {
+		r.WithRestrictions += amount
+	} else {
+		r.WithoutRestrictions += amount
+	}
+	r.Total += amount
+}
+
+// RestrictionLine represents an account or reclassification line split by net asset class
+type RestrictionLine struct {
+	AccountCode        string `json:"account_code"`
+	AccountName        string `json:"account_name"`
+	IsReclassification bool   `json:"is_reclassification"`
+	RestrictionAmounts
+}
+
+// FinancialPositionResponse for ISAK 35 statement of financial position
+type FinancialPositionResponse struct {
+	AsOfDate         time.Time           `json:"as_of_date"`
+	Assets           BalanceSheetSection `json:"assets"`
+	Liabilities      BalanceSheetSection `json:"liabilities"`
+	NetAssets        RestrictionAmounts  `json:"net_assets"`
+	TotalAssets      Money               `json:"total_assets"`
+	TotalLiabilities Money               `json:"total_liabilities"`
+	IsBalanced       bool                `json:"is_balanced"`
+}
+
+// ComprehensiveIncomeResponse for ISAK 35 statement of comprehensive income by net asset class
+type ComprehensiveIncomeResponse struct {
+	StartDate     time.Time          `json:"start_date"`
+	EndDate       time.Time          `json:"end_date"`
+	Revenue       []RestrictionLine  `json:"revenue"`
+	Releases      []RestrictionLine  `json:"releases"` // Net assets released from restriction
+	Expenses      []RestrictionLine  `json:"expenses"`
+	TotalRevenue  RestrictionAmounts `json:"total_revenue"`
+	TotalReleases RestrictionAmounts `json:"total_releases"`
+	TotalExpenses RestrictionAmounts `json:"total_expenses"`
+	Surplus       RestrictionAmounts `json:"surplus"` // Change in net assets from activities
+}
+
+// ChangesInNetAssetsResponse for ISAK 35 statement of changes in net assets
+type ChangesInNetAssetsResponse struct {
+	StartDate    time.Time          `json:"start_date"`
+	EndDate      time.Time          `json:"end_date"`
+	Opening      RestrictionAmounts `json:"opening"`
+	Surplus      RestrictionAmounts `json:"surplus"` // Before releases
+	Releases     RestrictionAmounts `json:"releases"`
+	OtherChanges RestrictionAmounts `json:"other_changes"` // Direct movements of net asset accounts
+	Closing      RestrictionAmounts `json:"closing"`
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Opening balance import status constants
+const (
+	OpeningBalanceStatusValid    = "valid"
+	OpeningBalanceStatusInvalid  = "invalid"
+	OpeningBalanceStatusImported = "imported"
+)
+
+// ImportOpeningBalancesRequest holds the form fields sent with an opening balance file
+type ImportOpeningBalancesRequest struct {
+	BranchID    *uuid.UUID `form:"-"`                                                        // From the branch_id field; used for rows without a branch_code
+	CutoverDate time.Time  `form:"cutover_date" binding:"required" time_format:"2006-01-02"` // Balances are as of the start of this date
+	DryRun      bool       `form:"dry_run"`                                                  // Validate only, import nothing
+}
+
+// OpeningBalanceBranch is the outcome for the rows of one branch, which become one opening journal
+type OpeningBalanceBranch struct {
+	BranchID           uuid.UUID  `json:"branch_id"`
+	BranchCode         string     `json:"branch_code"`
+	BranchName         string     `json:"branch_name"`
+	Rows               []int      `json:"rows"`
+	TotalDebit         Money      `json:"total_debit"`
+	TotalCredit        Money      `json:"total_credit"`
+	StudentReceivables int        `json:"student_receivables"` // Rows loaded as opening invoices of a student
+	Status             string     `json:"status"`
+	JournalID          *uuid.UUID `json:"journal_id,omitempty"`
+	JournalNumber      string     `json:"journal_number,omitempty"`
+}
+
+// OpeningBalanceImportResult is the validation report of an opening balance import. Rows are
+// only imported when the whole file is valid.
+type OpeningBalanceImportResult struct {
+	CutoverDate time.Time              `json:"cutover_date"`
+	DryRun      bool                   `json:"dry_run"`
+	Imported    bool                   `json:"imported"`
+	TotalRows   int                    `json:"total_rows"`
+	Errors      []JournalImportError   `json:"errors"`
+	Branches    []OpeningBalanceBranch `json:"branches"`
+}
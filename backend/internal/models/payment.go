@@ -14,7 +14,7 @@ type Payment struct {
 	BranchID       uuid.UUID  `gorm:"type:uuid;not null;index" json:"branch_id"`
 	PaymentNumber  string     `gorm:"size:50;uniqueIndex;not null" json:"payment_number"`
 	PaymentDate    time.Time  `gorm:"not null;index" json:"payment_date"`
-	Amount         float64    `gorm:"type:decimal(15,2);not null" json:"amount"`
+	Amount         Money      `gorm:"type:decimal(15,2);not null" json:"amount"`
 	PaymentMethod  string     `gorm:"size:50;not null" json:"payment_method"` // cash, transfer, card
 	ReferenceNo    string     `gorm:"size:100" json:"reference_no,omitempty"`
 	Notes          string     `gorm:"type:text" json:"notes,omitempty"`
@@ -46,9 +46,10 @@ type Invoice struct {
 	InvoiceDate    time.Time  `gorm:"not null;index" json:"invoice_date"`
 	DueDate        time.Time  `gorm:"not null;index" json:"due_date"`
 	Description    string     `gorm:"type:text;not null" json:"description"`
-	TotalAmount    float64    `gorm:"type:decimal(15,2);not null" json:"total_amount"`
-	PaidAmount     float64    `gorm:"type:decimal(15,2);default:0" json:"paid_amount"`
+	TotalAmount    Money      `gorm:"type:decimal(15,2);not null" json:"total_amount"`
+	PaidAmount     Money      `gorm:"type:decimal(15,2);default:0" json:"paid_amount"`
 	Status         string     `gorm:"size:20;not null;default:'unpaid'" json:"status"` // unpaid, partial, paid, overdue
+	OpeningJournalID *uuid.UUID `gorm:"type:uuid;index" json:"opening_journal_id,omitempty"` // Set on opening invoices; reversing the journal removes them
 	
 	// Relationships
 	Student      Student       `gorm:"foreignKey:StudentID" json:"student"`
@@ -70,8 +71,8 @@ type InvoiceItem struct {
 	FeeStructureID  *uuid.UUID `gorm:"type:uuid" json:"fee_structure_id,omitempty"`
 	Description     string     `gorm:"type:text;not null" json:"description"`
 	Quantity        int        `gorm:"default:1" json:"quantity"`
-	UnitPrice       float64    `gorm:"type:decimal(15,2);not null" json:"unit_price"`
-	Amount          float64    `gorm:"type:decimal(15,2);not null" json:"amount"`
+	UnitPrice       Money      `gorm:"type:decimal(15,2);not null" json:"unit_price"`
+	Amount          Money      `gorm:"type:decimal(15,2);not null" json:"amount"`
 	AccountID       *uuid.UUID `gorm:"type:uuid" json:"account_id,omitempty"` // Revenue account
 	
 	// Relationships
@@ -92,7 +93,7 @@ type FeeStructure struct {
 	Code          string    `gorm:"size:20;uniqueIndex;not null" json:"code"`
 	Name          string    `gorm:"size:200;not null" json:"name"`
 	Description   string    `gorm:"type:text" json:"description,omitempty"`
-	Amount        float64   `gorm:"type:decimal(15,2);not null" json:"amount"`
+	Amount        Money     `gorm:"type:decimal(15,2);not null" json:"amount"`
 	FeeType       string    `gorm:"size:50;not null" json:"fee_type"` // monthly, annual, one_time
 	Category      string    `gorm:"size:50;not null" json:"category"` // tuition, registration, uniform, book, etc
 	AccountID     uuid.UUID `gorm:"type:uuid;not null" json:"account_id"` // Revenue account
@@ -0,0 +1,143 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RecurringJournal is a template for journals generated on a schedule
+type RecurringJournal struct {
+	BaseModel
+	BranchID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"branch_id"`
+	Name        string     `gorm:"size:200;not null" json:"name"`
+	Description string     `gorm:"type:text;not null" json:"description"` // Description of generated journals
+	ReferenceNo string     `gorm:"size:100" json:"reference_no"`
+	Type        string     `gorm:"size:20;not null;default:'general'" json:"type"`
+	Frequency   string     `gorm:"size:20;not null" json:"frequency"`
+	Interval    int        `gorm:"not null;default:1" json:"interval"` // Every N frequency units
+	StartDate   time.Time  `gorm:"not null" json:"start_date"`
+	EndDate     *time.Time `json:"end_date,omitempty"`
+	NextRunDate *time.Time `gorm:"index" json:"next_run_date,omitempty"` // Empty once completed
+	Occurrence  int        `gorm:"not null;default:0" json:"occurrence"` // Occurrences generated or skipped so far
+	AutoSubmit  bool       `gorm:"default:false" json:"auto_submit"`     // Submit generated journals for review
+	Status      string     `gorm:"size:20;not null;default:'active'" json:"status"`
+	CreatedBy   uuid.UUID  `gorm:"type:uuid;not null" json:"created_by"` // Generated journals are created as this user
+
+	// Relationships
+	Branch Branch                 `gorm:"foreignKey:BranchID" json:"branch"`
+	Lines  []RecurringJournalLine `gorm:"foreignKey:RecurringJournalID" json:"lines,omitempty"`
+}
+
+// TableName specifies table name
+func (RecurringJournal) TableName() string {
+	return "recurring_journals"
+}
+
+// RecurringJournalLine represents a line of a recurring journal template
+type RecurringJournalLine struct {
+	BaseModel
+	RecurringJournalID  uuid.UUID  `gorm:"type:uuid;not null;index" json:"recurring_journal_id"`
+	LineNo              int        `gorm:"not null" json:"line_no"`
+	AccountID           uuid.UUID  `gorm:"type:uuid;not null" json:"account_id"`
+	Description         string     `gorm:"type:text" json:"description"`
+	Debit               Money      `gorm:"type:decimal(15,2);default:0" json:"debit"`
+	Credit              Money      `gorm:"type:decimal(15,2);default:0" json:"credit"`
+	FundID              *uuid.UUID `gorm:"type:uuid" json:"fund_id,omitempty"`
+	ProgramID           *uuid.UUID `gorm:"type:uuid" json:"program_id,omitempty"`
+	DonorID             *uuid.UUID `gorm:"type:uuid" json:"donor_id,omitempty"`
+	BranchID            *uuid.UUID `gorm:"type:uuid" json:"branch_id,omitempty"`
+	CounterpartBranchID *uuid.UUID `gorm:"type:uuid" json:"counterpart_branch_id,omitempty"`
+
+	// Relationships
+	Account Account `gorm:"foreignKey:AccountID" json:"account"`
+}
+
+// TableName specifies table name
+func (RecurringJournalLine) TableName() string {
+	return "recurring_journal_lines"
+}
+
+// RecurringJournalRun records what happened to one due date of a recurring journal
+type RecurringJournalRun struct {
+	BaseModel
+	RecurringJournalID uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_recurring_run_date" json:"recurring_journal_id"`
+	ScheduledDate      time.Time  `gorm:"not null;uniqueIndex:idx_recurring_run_date" json:"scheduled_date"`
+	Status             string     `gorm:"size:20;not null" json:"status"`
+	JournalID          *uuid.UUID `gorm:"type:uuid" json:"journal_id,omitempty"`
+	Message            string     `gorm:"type:text" json:"message,omitempty"`      // Skip reason or failure
+	ProcessedBy        *uuid.UUID `gorm:"type:uuid" json:"processed_by,omitempty"` // Empty when run by the scheduler
+
+	// Relationships
+	Journal *Journal `gorm:"foreignKey:JournalID" json:"journal,omitempty"`
+}
+
+// TableName specifies table name
+func (RecurringJournalRun) TableName() string {
+	return "recurring_journal_runs"
+}
+
+// Recurring journal frequency constants
+const (
+	RecurringFrequencyDaily     = "daily"
+	RecurringFrequencyWeekly    = "weekly"
+	RecurringFrequencyMonthly   = "monthly"
+	RecurringFrequencyQuarterly = "quarterly"
+	RecurringFrequencyYearly    = "yearly"
+)
+
+// Recurring journal status constants
+const (
+	RecurringStatusActive    = "active"
+	RecurringStatusPaused    = "paused"
+	RecurringStatusCompleted = "completed"
+)
+
+// Recurring journal run status constants
+const (
+	RecurringRunProcessing = "processing" // Claimed by a generator
+	RecurringRunGenerated  = "generated"
+	RecurringRunSkipped    = "skipped"
+	RecurringRunFailed     = "failed" // Retried on the next generation
+)
+
+// CreateRecurringJournalRequest for creating a recurring journal
+type CreateRecurringJournalRequest struct {
+	BranchID     uuid.UUID              `json:"branch_id" binding:"required"`
+	Name         string                 `json:"name" binding:"required,max=200"`
+	Description  string                 `json:"description" binding:"required"`
+	ReferenceNo  string                 `json:"reference_no" binding:"max=100"`
+	Type         string                 `json:"type" binding:"omitempty,oneof=general inter_branch"` // Defaults to general
+	Frequency    string                 `json:"frequency" binding:"required,oneof=daily weekly monthly quarterly yearly"`
+	Interval     int                    `json:"interval" binding:"omitempty,min=1,max=365"` // Defaults to 1
+	StartDate    time.Time              `json:"start_date" binding:"required"`
+	EndDate      *time.Time             `json:"end_date"`
+	AutoSubmit   bool                   `json:"auto_submit"`
+	JournalLines []CreateJournalLineReq `json:"journal_lines" binding:"required,min=2,dive"`
+}
+
+// UpdateRecurringJournalRequest for updating a recurring journal. The schedule start is fixed
+// once created so the generated history stays consistent.
+type UpdateRecurringJournalRequest struct {
+	Name         string                 `json:"name" binding:"required,max=200"`
+	Description  string                 `json:"description" binding:"required"`
+	ReferenceNo  string                 `json:"reference_no" binding:"max=100"`
+	EndDate      *time.Time             `json:"end_date"`
+	AutoSubmit   bool                   `json:"auto_submit"`
+	JournalLines []CreateJournalLineReq `json:"journal_lines" binding:"required,min=2,dive"`
+}
+
+// SkipRecurringJournalRequest for skipping a due date of a recurring journal
+type SkipRecurringJournalRequest struct {
+	ScheduledDate *time.Time `json:"scheduled_date"` // Defaults to the next due date
+	Reason        string     `json:"reason"`
+}
+
+// RecurringGenerationResult summarizes a generation run
+type RecurringGenerationResult struct {
+	AsOfDate  time.Time             `json:"as_of_date"`
+	Generated int                   `json:"generated"`
+	Skipped   int                   `json:"skipped"`
+	Failed    int                   `json:"failed"`
+	Runs      []RecurringJournalRun `json:"runs"`
+}
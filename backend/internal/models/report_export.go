@@ -0,0 +1,67 @@
+package models
+
+import "time"
+
+// Export format constants
+const (
+	ExportFormatJSON = "json"
+	ExportFormatXLSX = "xlsx"
+	ExportFormatPDF  = "pdf"
+	ExportFormatCSV  = "csv"
+)
+
+// Letterhead setting keys used on exported reports
+const (
+	SettingCompanyName    = "company.name"
+	SettingCompanyAddress = "company.address"
+	SettingCompanyPhone   = "company.phone"
+	SettingCompanyEmail   = "company.email"
+)
+
+// IsExportFormat reports whether format is a downloadable export format
+func IsExportFormat(format string) bool {
+	switch format {
+	case ExportFormatXLSX, ExportFormatPDF, ExportFormatCSV:
+		return true
+	}
+	return false
+}
+
+// ReportDocument is a report laid out as a table, ready to be written in any export format
+type ReportDocument struct {
+	Title       string
+	FileName    string // Without extension
+	Parameters  []ReportParameter
+	Columns     []ReportColumn
+	Rows        []ReportRow
+	GeneratedBy string
+	GeneratedAt time.Time
+}
+
+// ReportParameter is a report parameter printed above the table
+type ReportParameter struct {
+	Label string
+	Value string
+}
+
+// ReportColumn is a column of a report document
+type ReportColumn struct {
+	Title   string
+	Numeric bool
+	Width   float64 // Relative width
+}
+
+// ReportRow is a row of a report document. Cells hold a string, a Money amount or a float64
+// percentage per column.
+type ReportRow struct {
+	Cells  []interface{}
+	Indent int  // Indentation level of the first cell
+	Bold   bool // Section titles and totals
+}
+
+// ExportedReport is a report written in an export format
+type ExportedReport struct {
+	FileName    string
+	ContentType string
+	Data        []byte
+}
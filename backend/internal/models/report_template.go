@@ -0,0 +1,141 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Report template row type constants
+const (
+	TemplateRowAccounts = "accounts" // Sum of the accounts matched by code range and categories
+	TemplateRowFormula  = "formula"  // Arithmetic over other rows by code
+	TemplateRowHeading  = "heading"  // Label only
+)
+
+// Report template row basis constants
+const (
+	TemplateBasisMovement = "movement" // Movement within the column period
+	TemplateBasisClosing  = "closing"  // Balance at the end of the column period
+	TemplateBasisOpening  = "opening"  // Balance at the start of the column period
+)
+
+// Report template column type constants
+const (
+	TemplateColumnActual  = "actual"  // Posted journals
+	TemplateColumnBudget  = "budget"  // Active budgets
+	TemplateColumnFormula = "formula" // Arithmetic over other columns by code
+)
+
+// Report template column period constants, relative to the period the template is run for
+const (
+	TemplatePeriodCurrent     = "current"
+	TemplatePeriodPriorPeriod = "prior_period"
+	TemplatePeriodPriorYear   = "prior_year"
+	TemplatePeriodYearToDate  = "year_to_date"
+)
+
+// ReportTemplate is a user defined financial report layout
+type ReportTemplate struct {
+	BaseModel
+	Code           string                 `json:"code" gorm:"type:varchar(50);uniqueIndex;not null"`
+	Name           string                 `json:"name" gorm:"type:varchar(255);not null"`
+	Module         string                 `json:"module" gorm:"type:varchar(50);not null;default:'finance'"`
+	ReportType     string                 `json:"report_type" gorm:"type:varchar(50);default:'financial'"` // financial, operational, analytical
+	Description    string                 `json:"description" gorm:"type:text"`
+	Rows           []ReportTemplateRow    `json:"rows" gorm:"type:jsonb;serializer:json"`
+	Columns        []ReportTemplateColumn `json:"columns" gorm:"type:jsonb;serializer:json"`
+	IsSystemReport bool                   `json:"is_system_report" gorm:"default:false"`
+	IsActive       bool                   `json:"is_active" gorm:"default:true"`
+	CreatedBy      *uuid.UUID             `json:"created_by,omitempty" gorm:"type:uuid"`
+}
+
+// TableName specifies table name
+func (ReportTemplate) TableName() string {
+	return "report_templates"
+}
+
+// ReportTemplateRow defines a row of a report template
+type ReportTemplateRow struct {
+	Code        string   `json:"code,omitempty" binding:"omitempty,max=50"` // Referenced by formulas
+	Label       string   `json:"label" binding:"required,max=255"`
+	Type        string   `json:"type" binding:"required,oneof=accounts formula heading"`
+	AccountFrom string   `json:"account_from,omitempty"` // Inclusive account code range
+	AccountTo   string   `json:"account_to,omitempty"`
+	Categories  []string `json:"categories,omitempty" binding:"omitempty,dive,oneof=ASET KEWAJIBAN MODAL PENDAPATAN BIAYA"`
+	Basis       string   `json:"basis,omitempty" binding:"omitempty,oneof=movement closing opening"` // Defaults to movement for revenue and expense, closing otherwise
+	Formula     string   `json:"formula,omitempty"`                                                  // e.g. "REVENUE - EXPENSES"
+	Negate      bool     `json:"negate,omitempty"`                                                   // Flip the sign of the amounts
+	Bold        bool     `json:"bold,omitempty"`
+	Indent      int      `json:"indent,omitempty" binding:"omitempty,min=0,max=10"`
+	Hidden      bool     `json:"hidden,omitempty"` // Only used by formulas
+}
+
+// ReportTemplateColumn defines a column of a report template
+type ReportTemplateColumn struct {
+	Code     string     `json:"code,omitempty" binding:"omitempty,max=50"` // Referenced by formulas
+	Label    string     `json:"label" binding:"required,max=255"`
+	Type     string     `json:"type" binding:"required,oneof=actual budget formula"`
+	Period   string     `json:"period,omitempty" binding:"omitempty,oneof=current prior_period prior_year year_to_date"` // Defaults to current
+	BranchID *uuid.UUID `json:"branch_id,omitempty"`                                                                     // Overrides the branch the template is run for
+	FundID   *uuid.UUID `json:"fund_id,omitempty"`                                                                       // Overrides the fund the template is run for
+	Formula  string     `json:"formula,omitempty"`                                                                       // e.g. "(ACTUAL - BUDGET) / BUDGET * 100"
+}
+
+// CreateReportTemplateRequest for creating a report template
+type CreateReportTemplateRequest struct {
+	Code        string                 `json:"code" binding:"required,max=50"`
+	Name        string                 `json:"name" binding:"required,max=255"`
+	ReportType  string                 `json:"report_type" binding:"omitempty,oneof=financial operational analytical"`
+	Description string                 `json:"description"`
+	Rows        []ReportTemplateRow    `json:"rows" binding:"required,min=1,dive"`
+	Columns     []ReportTemplateColumn `json:"columns" binding:"required,min=1,dive"`
+}
+
+// UpdateReportTemplateRequest for updating a report template
+type UpdateReportTemplateRequest struct {
+	Name        string                 `json:"name" binding:"required,max=255"`
+	ReportType  string                 `json:"report_type" binding:"omitempty,oneof=financial operational analytical"`
+	Description string                 `json:"description"`
+	Rows        []ReportTemplateRow    `json:"rows" binding:"required,min=1,dive"`
+	Columns     []ReportTemplateColumn `json:"columns" binding:"required,min=1,dive"`
+	IsActive    *bool                  `json:"is_active"`
+}
+
+// RunReportTemplateRequest for running a report template
+type RunReportTemplateRequest struct {
+	StartDate time.Time  `json:"start_date" binding:"required"`
+	EndDate   time.Time  `json:"end_date" binding:"required"`
+	BranchID  *uuid.UUID `json:"branch_id"`
+	FundID    *uuid.UUID `json:"fund_id"`
+}
+
+// ReportTemplateResult is the output of a report template run
+type ReportTemplateResult struct {
+	TemplateID   uuid.UUID                    `json:"template_id"`
+	TemplateCode string                       `json:"template_code"`
+	Name         string                       `json:"name"`
+	StartDate    time.Time                    `json:"start_date"`
+	EndDate      time.Time                    `json:"end_date"`
+	Columns      []ReportTemplateResultColumn `json:"columns"`
+	Rows         []ReportTemplateResultRow    `json:"rows"`
+}
+
+// ReportTemplateResultColumn describes a column of a report template run
+type ReportTemplateResultColumn struct {
+	Code      string     `json:"code,omitempty"`
+	Label     string     `json:"label"`
+	Type      string     `json:"type"`
+	StartDate *time.Time `json:"start_date,omitempty"` // Empty for formula columns
+	EndDate   *time.Time `json:"end_date,omitempty"`
+}
+
+// ReportTemplateResultRow is a row of a report template run. Headings have no amounts.
+type ReportTemplateResultRow struct {
+	Code    string  `json:"code,omitempty"`
+	Label   string  `json:"label"`
+	Type    string  `json:"type"`
+	Bold    bool    `json:"bold"`
+	Indent  int     `json:"indent"`
+	Amounts []Money `json:"amounts,omitempty"` // In the order of the columns
+}
@@ -100,7 +100,7 @@ type Parent struct {
 	// Employment
 	Occupation      string  `gorm:"size:100" json:"occupation"`
 	Company         string  `gorm:"size:200" json:"company,omitempty"`
-	MonthlyIncome   float64 `gorm:"type:decimal(15,2)" json:"monthly_income,omitempty"`
+	MonthlyIncome   Money   `gorm:"type:decimal(15,2)" json:"monthly_income,omitempty"`
 	
 	// Education
 	Education       string  `gorm:"size:50" json:"education"` // SD, SMP, SMA, D3, S1, S2, S3
@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/yayasan/erp-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type AccountBalanceRepository interface {
+	GetByPeriod(period string, branchID *uuid.UUID) ([]models.AccountBalance, error)
+	Rebuild() error
+}
+
+type accountBalanceRepository struct {
+	db *gorm.DB
+}
+
+func NewAccountBalanceRepository(db *gorm.DB) AccountBalanceRepository {
+	return &accountBalanceRepository{db: db}
+}
+
+func (r *accountBalanceRepository) GetByPeriod(period string, branchID *uuid.UUID) ([]models.AccountBalance, error) {
+	var balances []models.AccountBalance
+	query := r.db.Where("period = ?", period)
+
+	if branchID != nil {
+		query = query.Where("branch_id = ?", *branchID)
+	}
+
+	err := query.
+		Preload("Account").
+		Order("account_id ASC").
+		Find(&balances).Error
+	return balances, err
+}
+
+// Rebuild recomputes all account balances from posted journal lines
+func (r *accountBalanceRepository) Rebuild() error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM account_balances").Error; err != nil {
+			return err
+		}
+
+		// Movements are signed by the account's normal balance, then accumulated per key in period order
+		return tx.Exec(`
+			INSERT INTO account_balances
+				(id, account_id, branch_id, fund_id, program_id, period,
+				 opening_balance, debit, credit, closing_balance, created_at, updated_at)
+			SELECT uuid_generate_v4(), account_id, branch_id, fund_id, program_id, period,
+				SUM(movement) OVER w - movement, debit, credit, SUM(movement) OVER w, NOW(), NOW()
+			FROM (
+				SELECT jl.account_id, j.branch_id, jl.fund_id, jl.program_id,
+					TO_CHAR(j.journal_date, 'YYYY-MM') AS period,
+					SUM(jl.debit) AS debit,
+					SUM(jl.credit) AS credit,
+					CASE
+						WHEN a.normal_balance = 'credit'
+							OR (COALESCE(a.normal_balance, '') = '' AND a.category IN ('KEWAJIBAN', 'MODAL', 'PENDAPATAN'))
+						THEN SUM(jl.credit) - SUM(jl.debit)
+						ELSE SUM(jl.debit) - SUM(jl.credit)
+					END AS movement
+				FROM journal_lines jl
+				JOIN journals j ON j.id = jl.journal_id
+				JOIN accounts a ON a.id = jl.account_id
+				WHERE j.is_posted = true
+					AND j.deleted_at IS NULL
+					AND jl.deleted_at IS NULL
+				GROUP BY jl.account_id, j.branch_id, jl.fund_id, jl.program_id,
+					TO_CHAR(j.journal_date, 'YYYY-MM'), a.normal_balance, a.category
+			) movements
+			WINDOW w AS (PARTITION BY account_id, branch_id, fund_id, program_id ORDER BY period)
+		`).Error
+	})
+}
+
+// accountBalanceKey is the conflict target matching the unique idx_account_balance_key index
+const accountBalanceKey = `(account_id,
+	(COALESCE(branch_id, '00000000-0000-0000-0000-000000000000')),
+	(COALESCE(fund_id, '00000000-0000-0000-0000-000000000000')),
+	(COALESCE(program_id, '00000000-0000-0000-0000-000000000000')),
+	period)`
+
+// balanceKey identifies one account balance row within a period
+type balanceKey struct {
+	accountID uuid.UUID
+	fundID    uuid.UUID
+	programID uuid.UUID
+}
+
+// applyJournalBalancesTx adds a posted journal's lines to the account balances of its period
+// inside an existing transaction. Balances follow the account's normal balance, and later
+// periods are shifted by the same movement.
+func applyJournalBalancesTx(tx *gorm.DB, journal *models.Journal) error {
+	period := journal.JournalDate.Format("2006-01")
+
+	// Aggregate lines per account, fund and program
+	var keys []balanceKey
+	debits := make(map[balanceKey]models.Money)
+	credits := make(map[balanceKey]models.Money)
+	accountIDs := make([]uuid.UUID, 0, len(journal.JournalLines))
+
+	for _, line := range journal.JournalLines {
+		key := balanceKey{accountID: line.AccountID}
+		if line.FundID != nil {
+			key.fundID = *line.FundID
+		}
+		if line.ProgramID != nil {
+			key.programID = *line.ProgramID
+		}
+
+		if _, exists := debits[key]; !exists {
+			keys = append(keys, key)
+			accountIDs = append(accountIDs, line.AccountID)
+		}
+		debits[key] += line.Debit
+		credits[key] += line.Credit
+	}
+
+	if len(keys) == 0 {
+		return nil
+	}
+
+	var accounts []models.Account
+	if err := tx.Where("id IN ?", accountIDs).Find(&accounts).Error; err != nil {
+		return err
+	}
+	normalBalances := make(map[uuid.UUID]string, len(accounts))
+	for i := range accounts {
+		normalBalances[accounts[i].ID] = accounts[i].GetNormalBalance()
+	}
+
+	for _, key := range keys {
+		debit := debits[key]
+		credit := credits[key]
+
+		movement := debit - credit
+		if normalBalances[key.accountID] == models.NormalBalanceCredit {
+			movement = credit - debit
+		}
+
+		// Opening balance of a new row carries over from the latest earlier period
+		var previous models.AccountBalance
+		var opening models.Money
+		err := balanceScope(tx, key, journal.BranchID).
+			Where("period < ?", period).
+			Order("period DESC").
+			First(&previous).Error
+		if err == nil {
+			opening = previous.ClosingBalance
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		// Concurrent first posts of a period meet on the unique key and add to the same row
+		if err := tx.Exec(`
+			INSERT INTO account_balances
+				(id, account_id, branch_id, fund_id, program_id, period,
+				 opening_balance, debit, credit, closing_balance, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NOW(), NOW())
+			ON CONFLICT `+accountBalanceKey+` DO UPDATE SET
+				debit = account_balances.debit + EXCLUDED.debit,
+				credit = account_balances.credit + EXCLUDED.credit,
+				closing_balance = account_balances.closing_balance + ?,
+				updated_at = NOW()`,
+			uuid.New(), key.accountID, journal.BranchID, nullableUUID(key.fundID), nullableUUID(key.programID), period,
+			opening, debit, credit, opening+movement, movement,
+		).Error; err != nil {
+			return err
+		}
+
+		// Shift later periods by the same movement
+		if err := balanceScope(tx, key, journal.BranchID).
+			Where("period > ?", period).
+			Updates(map[string]interface{}{
+				"opening_balance": gorm.Expr("opening_balance + ?", movement),
+				"closing_balance": gorm.Expr("closing_balance + ?", movement),
+			}).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// balanceScope filters account balances by key and branch, matching NULL fund/program
+func balanceScope(tx *gorm.DB, key balanceKey, branchID uuid.UUID) *gorm.DB {
+	query := tx.Model(&models.AccountBalance{}).
+		Where("account_id = ? AND branch_id = ?", key.accountID, branchID)
+
+	if key.fundID != uuid.Nil {
+		query = query.Where("fund_id = ?", key.fundID)
+	} else {
+		query = query.Where("fund_id IS NULL")
+	}
+
+	if key.programID != uuid.Nil {
+		query = query.Where("program_id = ?", key.programID)
+	} else {
+		query = query.Where("program_id IS NULL")
+	}
+
+	return query
+}
+
+// nullableUUID returns nil for uuid.Nil
+func nullableUUID(id uuid.UUID) *uuid.UUID {
+	if id == uuid.Nil {
+		return nil
+	}
+	return &id
+}
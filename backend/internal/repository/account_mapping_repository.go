@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/yayasan/erp-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type AccountMappingRepository interface {
+	GetAll(branchID *uuid.UUID) ([]models.AccountMapping, error)
+	GetByID(id uuid.UUID) (*models.AccountMapping, error)
+	GetByBranchKey(branchID *uuid.UUID, key string) (*models.AccountMapping, error)
+	Resolve(key string, branchID uuid.UUID) (*models.AccountMapping, error)
+	Create(mapping *models.AccountMapping) error
+	Update(mapping *models.AccountMapping) error
+	Delete(id uuid.UUID) error
+}
+
+type accountMappingRepository struct {
+	db *gorm.DB
+}
+
+func NewAccountMappingRepository(db *gorm.DB) AccountMappingRepository {
+	return &accountMappingRepository{db: db}
+}
+
+func (r *accountMappingRepository) GetAll(branchID *uuid.UUID) ([]models.AccountMapping, error) {
+	var mappings []models.AccountMapping
+	query := r.db.Model(&models.AccountMapping{})
+
+	if branchID != nil {
+		query = query.Where("branch_id = ? OR branch_id IS NULL", *branchID)
+	}
+
+	err := query.
+		Preload("Branch").
+		Preload("Account").
+		Order("mapping_key ASC, branch_id ASC").
+		Find(&mappings).Error
+	return mappings, err
+}
+
+func (r *accountMappingRepository) GetByID(id uuid.UUID) (*models.AccountMapping, error) {
+	var mapping models.AccountMapping
+	err := r.db.
+		Preload("Branch").
+		Preload("Account").
+		First(&mapping, "id = ?", id).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("account mapping not found")
+		}
+		return nil, err
+	}
+	return &mapping, nil
+}
+
+func (r *accountMappingRepository) GetByBranchKey(branchID *uuid.UUID, key string) (*models.AccountMapping, error) {
+	var mapping models.AccountMapping
+	query := r.db.Where("mapping_key = ?", key)
+
+	if branchID != nil {
+		query = query.Where("branch_id = ?", *branchID)
+	} else {
+		query = query.Where("branch_id IS NULL")
+	}
+
+	err := query.Preload("Account").First(&mapping).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &mapping, nil
+}
+
+// Resolve returns the branch-specific mapping for key, falling back to the default mapping
+func (r *accountMappingRepository) Resolve(key string, branchID uuid.UUID) (*models.AccountMapping, error) {
+	mapping, err := r.GetByBranchKey(&branchID, key)
+	if err != nil {
+		return nil, err
+	}
+	if mapping != nil {
+		return mapping, nil
+	}
+
+	mapping, err = r.GetByBranchKey(nil, key)
+	if err != nil {
+		return nil, err
+	}
+	if mapping == nil {
+		return nil, errors.New("no account mapping configured for " + key)
+	}
+	return mapping, nil
+}
+
+func (r *accountMappingRepository) Create(mapping *models.AccountMapping) error {
+	return r.db.Create(mapping).Error
+}
+
+func (r *accountMappingRepository) Update(mapping *models.AccountMapping) error {
+	return r.db.Save(mapping).Error
+}
+
+func (r *accountMappingRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&models.AccountMapping{}, "id = ?", id).Error
+}
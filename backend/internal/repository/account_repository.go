@@ -13,6 +13,7 @@ type AccountRepository interface {
 	GetByID(id uuid.UUID) (*models.Account, error)
 	GetByCode(code string) (*models.Account, error)
 	GetByCategory(category string) ([]models.Account, error)
+	GetByType(accountType string) ([]models.Account, error)
 	GetActiveAccounts() ([]models.Account, error)
 	GetDetailAccounts() ([]models.Account, error)
 	GetTree() ([]models.Account, error)
@@ -22,6 +23,7 @@ type AccountRepository interface {
 	Delete(id uuid.UUID) error
 	BulkCreate(accounts []models.Account) error
 	CodeExists(code string) (bool, error)
+	HasJournalLines(id uuid.UUID) (bool, error)
 }
 
 type accountRepository struct {
@@ -111,6 +113,15 @@ func (r *accountRepository) GetByCategory(category string) ([]models.Account, er
 	return accounts, err
 }
 
+func (r *accountRepository) GetByType(accountType string) ([]models.Account, error) {
+	var accounts []models.Account
+	err := r.db.
+		Where("type = ? AND is_active = ?", accountType, true).
+		Order("code ASC").
+		Find(&accounts).Error
+	return accounts, err
+}
+
 func (r *accountRepository) GetActiveAccounts() ([]models.Account, error) {
 	var accounts []models.Account
 	err := r.db.
@@ -268,3 +279,10 @@ func (r *accountRepository) CodeExists(code string) (bool, error) {
 	err := r.db.Model(&models.Account{}).Where("code = ?", code).Count(&count).Error
 	return count > 0, err
 }
+
+// HasJournalLines reports whether any journal line is booked on the account
+func (r *accountRepository) HasJournalLines(id uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.JournalLine{}).Where("account_id = ?", id).Count(&count).Error
+	return count > 0, err
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yayasan/erp-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AccountingPeriodRepository interface {
+	GetByBranch(branchID uuid.UUID, year string) ([]models.AccountingPeriod, error)
+	GetByBranchPeriod(branchID uuid.UUID, period string) (*models.AccountingPeriod, error)
+	GetStatus(branchID uuid.UUID, date time.Time) (string, error)
+	IsInClosedFiscalYear(date time.Time) (bool, error)
+	Save(period *models.AccountingPeriod) error
+}
+
+type accountingPeriodRepository struct {
+	db *gorm.DB
+}
+
+func NewAccountingPeriodRepository(db *gorm.DB) AccountingPeriodRepository {
+	return &accountingPeriodRepository{db: db}
+}
+
+func (r *accountingPeriodRepository) GetByBranch(branchID uuid.UUID, year string) ([]models.AccountingPeriod, error) {
+	var periods []models.AccountingPeriod
+	query := r.db.Where("branch_id = ?", branchID)
+
+	if year != "" {
+		query = query.Where("period LIKE ?", year+"-%")
+	}
+
+	err := query.Order("period ASC").Find(&periods).Error
+	return periods, err
+}
+
+func (r *accountingPeriodRepository) GetByBranchPeriod(branchID uuid.UUID, period string) (*models.AccountingPeriod, error) {
+	var accountingPeriod models.AccountingPeriod
+	err := r.db.
+		Where("branch_id = ? AND period = ?", branchID, period).
+		First(&accountingPeriod).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &accountingPeriod, nil
+}
+
+// GetStatus returns the lock status for a branch on a date; dates in a closed fiscal year are closed
+func (r *accountingPeriodRepository) GetStatus(branchID uuid.UUID, date time.Time) (string, error) {
+	closed, err := r.IsInClosedFiscalYear(date)
+	if err != nil {
+		return "", err
+	}
+	if closed {
+		return models.PeriodStatusClosed, nil
+	}
+
+	period, err := r.GetByBranchPeriod(branchID, date.Format("2006-01"))
+	if err != nil {
+		return "", err
+	}
+	if period == nil {
+		return models.PeriodStatusOpen, nil
+	}
+	return period.Status, nil
+}
+
+func (r *accountingPeriodRepository) IsInClosedFiscalYear(date time.Time) (bool, error) {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+
+	var count int64
+	err := r.db.Model(&models.FiscalYear{}).
+		Where("is_closed = ? AND start_date <= ? AND end_date >= ?", true, day, day).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// Save inserts the period of a branch or updates the one already there, so concurrent requests
+// for the same branch and period end up in one record
+func (r *accountingPeriodRepository) Save(period *models.AccountingPeriod) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Omit("Branch").Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "branch_id"}, {Name: "period"}},
+			DoUpdates: clause.AssignmentColumns([]string{"status", "closed_at", "closed_by", "notes", "updated_at"}),
+		}).Create(period).Error
+		if err != nil {
+			return err
+		}
+
+		// The record kept may be an earlier one
+		var saved models.AccountingPeriod
+		if err := tx.Where("branch_id = ? AND period = ?", period.BranchID, period.Period).First(&saved).Error; err != nil {
+			return err
+		}
+		*period = saved
+		return nil
+	})
+}
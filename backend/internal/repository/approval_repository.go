@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/yayasan/erp-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type ApprovalRepository interface {
+	// Chains
+	GetChains(documentType string) ([]models.ApprovalChain, error)
+	GetChainByID(id uuid.UUID) (*models.ApprovalChain, error)
+	GetChainByCode(code string) (*models.ApprovalChain, error)
+	FindChains(documentType string, branchID uuid.UUID) ([]models.ApprovalChain, error)
+	CreateChain(chain *models.ApprovalChain) error
+	UpdateChain(chain *models.ApprovalChain) error
+	DeleteChain(id uuid.UUID) error
+	HasPendingRequests(chainID uuid.UUID) (bool, error)
+
+	// Requests
+	GetPendingRequest(documentType string, documentID uuid.UUID) (*models.ApprovalRequest, error)
+	GetRequests(documentType string, documentID uuid.UUID) ([]models.ApprovalRequest, error)
+	UserHasRole(userID, roleID uuid.UUID, branchID *uuid.UUID) (bool, error)
+}
+
+type approvalRepository struct {
+	db *gorm.DB
+}
+
+func NewApprovalRepository(db *gorm.DB) ApprovalRepository {
+	return &approvalRepository{db: db}
+}
+
+func preloadChainSteps(db *gorm.DB) *gorm.DB {
+	return db.Order("step_no ASC")
+}
+
+func (r *approvalRepository) GetChains(documentType string) ([]models.ApprovalChain, error) {
+	var chains []models.ApprovalChain
+	query := r.db.Preload("Branch").Preload("Steps", preloadChainSteps)
+
+	if documentType != "" {
+		query = query.Where("document_type = ?", documentType)
+	}
+
+	err := query.Order("document_type ASC, min_amount ASC").Find(&chains).Error
+	return chains, err
+}
+
+func (r *approvalRepository) GetChainByID(id uuid.UUID) (*models.ApprovalChain, error) {
+	var chain models.ApprovalChain
+	err := r.db.
+		Preload("Branch").
+		Preload("Steps", preloadChainSteps).
+		Preload("Steps.Role").
+		Preload("Steps.User").
+		First(&chain, "id = ?", id).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("approval chain not found")
+		}
+		return nil, err
+	}
+	return &chain, nil
+}
+
+func (r *approvalRepository) GetChainByCode(code string) (*models.ApprovalChain, error) {
+	var chain models.ApprovalChain
+	err := r.db.First(&chain, "code = ?", code).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("approval chain not found")
+		}
+		return nil, err
+	}
+	return &chain, nil
+}
+
+// FindChains returns the active chains of a document type that apply to a branch
+func (r *approvalRepository) FindChains(documentType string, branchID uuid.UUID) ([]models.ApprovalChain, error) {
+	var chains []models.ApprovalChain
+	err := r.db.
+		Preload("Steps", preloadChainSteps).
+		Where("document_type = ? AND is_active = ?", documentType, true).
+		Where("branch_id IS NULL OR branch_id = ?", branchID).
+		Find(&chains).Error
+	return chains, err
+}
+
+func (r *approvalRepository) CreateChain(chain *models.ApprovalChain) error {
+	return r.db.Create(chain).Error
+}
+
+// UpdateChain saves the chain and replaces its steps
+func (r *approvalRepository) UpdateChain(chain *models.ApprovalChain) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("chain_id = ?", chain.ID).Delete(&models.ApprovalChainStep{}).Error; err != nil {
+			return err
+		}
+
+		for i := range chain.Steps {
+			chain.Steps[i].ID = uuid.Nil
+			chain.Steps[i].ChainID = chain.ID
+		}
+
+		return tx.Omit("Branch").Save(chain).Error
+	})
+}
+
+func (r *approvalRepository) DeleteChain(id uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("chain_id = ?", id).Delete(&models.ApprovalChainStep{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.ApprovalChain{}, "id = ?", id).Error
+	})
+}
+
+func (r *approvalRepository) HasPendingRequests(chainID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.ApprovalRequest{}).
+		Where("chain_id = ? AND status = ?", chainID, models.ApprovalStatusPending).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *approvalRepository) GetPendingRequest(documentType string, documentID uuid.UUID) (*models.ApprovalRequest, error) {
+	var request models.ApprovalRequest
+	err := r.db.
+		Preload("Chain.Steps", preloadChainSteps).
+		Preload("History").
+		Where("document_type = ? AND document_id = ? AND status = ?", documentType, documentID, models.ApprovalStatusPending).
+		Order("created_at DESC").
+		First(&request).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("no pending approval found")
+		}
+		return nil, err
+	}
+	return &request, nil
+}
+
+// GetRequests returns every submission of a document with its history, newest first
+func (r *approvalRepository) GetRequests(documentType string, documentID uuid.UUID) ([]models.ApprovalRequest, error) {
+	var requests []models.ApprovalRequest
+	err := r.db.
+		Preload("Chain.Steps", preloadChainSteps).
+		Preload("Chain.Steps.Role").
+		Preload("History", func(db *gorm.DB) *gorm.DB {
+			return db.Order("acted_at ASC")
+		}).
+		Preload("History.Actor").
+		Where("document_type = ? AND document_id = ?", documentType, documentID).
+		Order("created_at DESC").
+		Find(&requests).Error
+	return requests, err
+}
+
+// createApprovalRequestTx cancels any pending request of the document and starts a new one
+// inside the transaction that also updates the document
+func createApprovalRequestTx(tx *gorm.DB, request *models.ApprovalRequest, history *models.ApprovalHistory) error {
+	err := tx.Model(&models.ApprovalRequest{}).
+		Where("document_type = ? AND document_id = ? AND status = ?", request.DocumentType, request.DocumentID, models.ApprovalStatusPending).
+		Update("status", models.ApprovalStatusCancelled).Error
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Omit("Chain", "History").Create(request).Error; err != nil {
+		return err
+	}
+
+	history.RequestID = request.ID
+	return tx.Create(history).Error
+}
+
+// recordApprovalActionTx saves the outcome of a step together with its history entry, inside
+// the transaction that also updates the document. The update only applies while the request is
+// still pending at the step in history.StepNo, so two approvers acting on the same step at once
+// cannot both succeed.
+func recordApprovalActionTx(tx *gorm.DB, request *models.ApprovalRequest, history *models.ApprovalHistory) error {
+	result := tx.Model(&models.ApprovalRequest{}).
+		Where("id = ? AND status = ? AND current_step = ?", request.ID, models.ApprovalStatusPending, history.StepNo).
+		Updates(map[string]interface{}{
+			"current_step": request.CurrentStep,
+			"status":       request.Status,
+			"completed_at": request.CompletedAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("approval was updated by someone else, please reload")
+	}
+
+	history.RequestID = request.ID
+	return tx.Create(history).Error
+}
+
+// UserHasRole checks whether a user holds a role, at a branch when one is given
+func (r *approvalRepository) UserHasRole(userID, roleID uuid.UUID, branchID *uuid.UUID) (bool, error) {
+	var count int64
+	query := r.db.Model(&models.UserRole{}).Where("user_id = ? AND role_id = ?", userID, roleID)
+	if branchID != nil {
+		query = query.Where("branch_id = ?", *branchID)
+	}
+	err := query.Count(&count).Error
+	return count > 0, err
+}
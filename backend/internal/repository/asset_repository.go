@@ -39,8 +39,8 @@ type AssetRepository interface {
 	
 	// Statistics
 	CountByStatus(status string) (int64, error)
-	GetTotalValue() (float64, error)
-	GetTotalBookValue() (float64, error)
+	GetTotalValue() (models.Money, error)
+	GetTotalBookValue() (models.Money, error)
 }
 
 type assetRepository struct {
@@ -255,8 +255,8 @@ func (r *assetRepository) CountByStatus(status string) (int64, error) {
 	return count, err
 }
 
-func (r *assetRepository) GetTotalValue() (float64, error) {
-	var total float64
+func (r *assetRepository) GetTotalValue() (models.Money, error) {
+	var total models.Money
 	err := r.db.Model(&models.Asset{}).
 		Where("status = ?", models.AssetStatusActive).
 		Select("COALESCE(SUM(purchase_price), 0)").
@@ -264,8 +264,8 @@ func (r *assetRepository) GetTotalValue() (float64, error) {
 	return total, err
 }
 
-func (r *assetRepository) GetTotalBookValue() (float64, error) {
-	var total float64
+func (r *assetRepository) GetTotalBookValue() (models.Money, error) {
+	var total models.Money
 	err := r.db.Model(&models.Asset{}).
 		Where("status = ?", models.AssetStatusActive).
 		Select("COALESCE(SUM(book_value), 0)").
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/yayasan/erp-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type AttachmentRepository interface {
+	GetByEntity(entityType string, entityID uuid.UUID) ([]models.Attachment, error)
+	GetByID(id uuid.UUID) (*models.Attachment, error)
+	Create(attachment *models.Attachment) error
+	Delete(id uuid.UUID) error
+}
+
+type attachmentRepository struct {
+	db *gorm.DB
+}
+
+func NewAttachmentRepository(db *gorm.DB) AttachmentRepository {
+	return &attachmentRepository{db: db}
+}
+
+func (r *attachmentRepository) GetByEntity(entityType string, entityID uuid.UUID) ([]models.Attachment, error) {
+	var attachments []models.Attachment
+	err := r.db.
+		Preload("Uploader").
+		Where("entity_type = ? AND entity_id = ?", entityType, entityID).
+		Order("uploaded_at ASC").
+		Find(&attachments).Error
+	return attachments, err
+}
+
+func (r *attachmentRepository) GetByID(id uuid.UUID) (*models.Attachment, error) {
+	var attachment models.Attachment
+	err := r.db.Preload("Uploader").First(&attachment, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("attachment not found")
+		}
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+func (r *attachmentRepository) Create(attachment *models.Attachment) error {
+	return r.db.Create(attachment).Error
+}
+
+func (r *attachmentRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&models.Attachment{}, "id = ?", id).Error
+}
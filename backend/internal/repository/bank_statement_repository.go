@@ -0,0 +1,261 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yayasan/erp-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// LedgerItemFilter selects posted journal lines on a bank account
+type LedgerItemFilter struct {
+	AccountID     uuid.UUID
+	BranchID      uuid.UUID
+	StartDate     *time.Time
+	EndDate       *time.Time
+	JournalLineID *uuid.UUID
+	UnmatchedOnly bool
+	MatchedBefore *time.Time // With UnmatchedOnly, ignore matches to statement lines after this date
+}
+
+type BankStatementRepository interface {
+	Create(statement *models.BankStatement) error
+	GetAll(accountID *uuid.UUID) ([]models.BankStatement, error)
+	GetByID(id uuid.UUID) (*models.BankStatement, error)
+	GetLineByID(id uuid.UUID) (*models.BankStatementLine, error)
+	UpdateLine(line *models.BankStatementLine) error
+	SaveMatches(lines []models.BankStatementLine) error
+	IsJournalLineMatched(journalLineID uuid.UUID) (bool, error)
+	GetLedgerItems(filter LedgerItemFilter) ([]models.LedgerItem, error)
+	GetLedgerBalance(accountID, branchID uuid.UUID, asOfDate time.Time) (models.Money, error)
+	Reconcile(statement *models.BankStatement, userID uuid.UUID) error
+	Delete(id uuid.UUID) error
+}
+
+type bankStatementRepository struct {
+	db *gorm.DB
+}
+
+func NewBankStatementRepository(db *gorm.DB) BankStatementRepository {
+	return &bankStatementRepository{db: db}
+}
+
+func (r *bankStatementRepository) Create(statement *models.BankStatement) error {
+	return r.db.Create(statement).Error
+}
+
+func (r *bankStatementRepository) GetAll(accountID *uuid.UUID) ([]models.BankStatement, error) {
+	var statements []models.BankStatement
+	query := r.db.Preload("Account").Preload("Branch")
+
+	if accountID != nil {
+		query = query.Where("account_id = ?", *accountID)
+	}
+
+	err := query.Order("period_end DESC").Find(&statements).Error
+	return statements, err
+}
+
+func (r *bankStatementRepository) GetByID(id uuid.UUID) (*models.BankStatement, error) {
+	var statement models.BankStatement
+	err := r.db.
+		Preload("Account").
+		Preload("Branch").
+		Preload("Lines", func(db *gorm.DB) *gorm.DB {
+			return db.Order("line_no ASC")
+		}).
+		Preload("Lines.JournalLine.Journal").
+		First(&statement, "id = ?", id).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("bank statement not found")
+		}
+		return nil, err
+	}
+	return &statement, nil
+}
+
+func (r *bankStatementRepository) GetLineByID(id uuid.UUID) (*models.BankStatementLine, error) {
+	var line models.BankStatementLine
+	err := r.db.First(&line, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("bank statement line not found")
+		}
+		return nil, err
+	}
+	return &line, nil
+}
+
+// UpdateLine saves the match of a statement line unless it is already reconciled. A match is only
+// saved on a line that is not matched yet, and fails when another line took the journal line
+// first.
+func (r *bankStatementRepository) UpdateLine(line *models.BankStatementLine) error {
+	query := r.db.Model(&models.BankStatementLine{}).
+		Where("id = ? AND is_reconciled = ?", line.ID, false)
+	if line.JournalLineID != nil {
+		query = query.Where("journal_line_id IS NULL")
+	}
+
+	result := query.Updates(map[string]interface{}{
+		"journal_line_id": line.JournalLineID,
+		"match_status":    line.MatchStatus,
+		"matched_at":      line.MatchedAt,
+		"matched_by":      line.MatchedBy,
+	})
+	if result.Error != nil {
+		return r.matchError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		if line.JournalLineID != nil {
+			return errors.New("bank statement line is already matched or reconciled")
+		}
+		return errors.New("bank statement line is reconciled")
+	}
+	return nil
+}
+
+// matchError reports a journal line matched by two statement lines at once, caught by the unique
+// index on journal_line_id, as already matched
+func (r *bankStatementRepository) matchError(err error) error {
+	if translator, ok := r.db.Dialector.(gorm.ErrorTranslator); ok && errors.Is(translator.Translate(err), gorm.ErrDuplicatedKey) {
+		return errors.New("journal line is already matched to another statement line")
+	}
+	return err
+}
+
+func (r *bankStatementRepository) SaveMatches(lines []models.BankStatementLine) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for i := range lines {
+			if err := tx.Model(&models.BankStatementLine{}).
+				Where("id = ? AND is_reconciled = ?", lines[i].ID, false).
+				Updates(map[string]interface{}{
+					"journal_line_id": lines[i].JournalLineID,
+					"match_status":    lines[i].MatchStatus,
+					"matched_at":      lines[i].MatchedAt,
+				}).Error; err != nil {
+				return r.matchError(err)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *bankStatementRepository) IsJournalLineMatched(journalLineID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.BankStatementLine{}).
+		Where("journal_line_id = ?", journalLineID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// GetLedgerItems returns posted journal lines on a bank account with the reference of the payment
+// that created them, if any
+func (r *bankStatementRepository) GetLedgerItems(filter LedgerItemFilter) ([]models.LedgerItem, error) {
+	query := r.db.Table("journal_lines").
+		Select(`journal_lines.id as journal_line_id,
+			journals.id as journal_id,
+			journals.journal_number,
+			journals.journal_date,
+			journals.reference_no,
+			COALESCE(NULLIF(journal_lines.description, ''), journals.description) as description,
+			COALESCE((SELECT payments.reference_no FROM payments
+				WHERE payments.journal_id = journals.id AND payments.deleted_at IS NULL LIMIT 1), '') as payment_ref_no,
+			journal_lines.debit - journal_lines.credit as amount`).
+		Joins("JOIN journals ON journals.id = journal_lines.journal_id").
+		Where("journal_lines.account_id = ? AND journals.branch_id = ?", filter.AccountID, filter.BranchID).
+		Where("journals.is_posted = ?", true).
+		Where("journal_lines.deleted_at IS NULL AND journals.deleted_at IS NULL")
+
+	if filter.StartDate != nil {
+		query = query.Where("journals.journal_date >= ?", *filter.StartDate)
+	}
+	if filter.EndDate != nil {
+		query = query.Where("journals.journal_date <= ?", *filter.EndDate)
+	}
+	if filter.JournalLineID != nil {
+		query = query.Where("journal_lines.id = ?", *filter.JournalLineID)
+	}
+	if filter.UnmatchedOnly {
+		matched := r.db.Model(&models.BankStatementLine{}).
+			Select("1").
+			Where("bank_statement_lines.journal_line_id = journal_lines.id")
+		if filter.MatchedBefore != nil {
+			matched = matched.Where("bank_statement_lines.transaction_date <= ?", *filter.MatchedBefore)
+		}
+		query = query.Where("NOT EXISTS (?)", matched)
+	}
+
+	var items []models.LedgerItem
+	err := query.Order("journals.journal_date ASC, journals.journal_number ASC").Scan(&items).Error
+	return items, err
+}
+
+// GetLedgerBalance returns the debit balance of a bank account for a branch as of a date
+func (r *bankStatementRepository) GetLedgerBalance(accountID, branchID uuid.UUID, asOfDate time.Time) (models.Money, error) {
+	var balance models.Money
+	err := r.db.Model(&models.JournalLine{}).
+		Select("COALESCE(SUM(journal_lines.debit - journal_lines.credit), 0)").
+		Joins("JOIN journals ON journals.id = journal_lines.journal_id").
+		Where("journal_lines.account_id = ? AND journals.branch_id = ?", accountID, branchID).
+		Where("journals.is_posted = ? AND journals.journal_date <= ?", true, asOfDate).
+		Row().Scan(&balance)
+	return balance, err
+}
+
+// Reconcile locks a statement, its lines and the journal lines they are matched to
+func (r *bankStatementRepository) Reconcile(statement *models.BankStatement, userID uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&models.BankStatement{}).
+			Where("id = ? AND status = ?", statement.ID, models.BankStatementStatusOpen).
+			Updates(map[string]interface{}{
+				"status":        models.BankStatementStatusReconciled,
+				"reconciled_at": now,
+				"reconciled_by": userID,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("bank statement is already reconciled")
+		}
+
+		if err := tx.Model(&models.BankStatementLine{}).
+			Where("statement_id = ?", statement.ID).
+			Update("is_reconciled", true).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&models.JournalLine{}).
+			Where("id IN (?)", tx.Model(&models.BankStatementLine{}).
+				Select("journal_line_id").
+				Where("statement_id = ? AND journal_line_id IS NOT NULL", statement.ID)).
+			Update("is_reconciled", true).Error
+	})
+}
+
+func (r *bankStatementRepository) Delete(id uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND status = ?", id, models.BankStatementStatusOpen).
+			Delete(&models.BankStatement{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("reconciled bank statements cannot be deleted")
+		}
+
+		// Release the matched journal lines so they can be matched on a new import
+		if err := tx.Model(&models.BankStatementLine{}).
+			Where("statement_id = ?", id).
+			Update("journal_line_id", nil).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("statement_id = ?", id).Delete(&models.BankStatementLine{}).Error
+	})
+}
@@ -0,0 +1,267 @@
+package repository
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yayasan/erp-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DocumentNumberRepository interface {
+	GetFormats(branchID *uuid.UUID) ([]models.DocumentNumberFormat, error)
+	GetFormatByID(id uuid.UUID) (*models.DocumentNumberFormat, error)
+	GetByBranchType(branchID *uuid.UUID, documentType string) (*models.DocumentNumberFormat, error)
+	CreateFormat(format *models.DocumentNumberFormat) error
+	UpdateFormat(format *models.DocumentNumberFormat) error
+	DeleteFormat(id uuid.UUID) error
+	Preview(documentType, transactionType string, branchID uuid.UUID, date time.Time) (string, *models.DocumentNumberFormat, error)
+}
+
+type documentNumberRepository struct {
+	db *gorm.DB
+}
+
+func NewDocumentNumberRepository(db *gorm.DB) DocumentNumberRepository {
+	return &documentNumberRepository{db: db}
+}
+
+// documentNumberColumns lists where each document type keeps its number, to continue from
+// numbers issued before their sequence existed
+var documentNumberColumns = map[string]struct{ table, column string }{
+	models.DocumentTypeJournal:          {"journals", "journal_number"},
+	models.DocumentTypeInvoice:          {"invoices", "invoice_number"},
+	models.DocumentTypePayment:          {"payments", "payment_number"},
+	models.DocumentTypePayroll:          {"payrolls", "payroll_number"},
+	models.DocumentTypeStockTransaction: {"stock_transactions", "transaction_number"},
+	models.DocumentTypeStockOpname:      {"stock_opnames", "opname_number"},
+	models.DocumentTypePurchaseOrder:    {"purchase_orders", "po_number"},
+}
+
+func (r *documentNumberRepository) GetFormats(branchID *uuid.UUID) ([]models.DocumentNumberFormat, error) {
+	var formats []models.DocumentNumberFormat
+	query := r.db.Model(&models.DocumentNumberFormat{})
+
+	if branchID != nil {
+		query = query.Where("branch_id = ? OR branch_id IS NULL", *branchID)
+	}
+
+	err := query.
+		Preload("Branch").
+		Order("document_type ASC, branch_id ASC").
+		Find(&formats).Error
+	return formats, err
+}
+
+func (r *documentNumberRepository) GetFormatByID(id uuid.UUID) (*models.DocumentNumberFormat, error) {
+	var format models.DocumentNumberFormat
+	err := r.db.Preload("Branch").First(&format, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("number format not found")
+		}
+		return nil, err
+	}
+	return &format, nil
+}
+
+func (r *documentNumberRepository) GetByBranchType(branchID *uuid.UUID, documentType string) (*models.DocumentNumberFormat, error) {
+	return documentNumberFormatTx(r.db, branchID, documentType)
+}
+
+func (r *documentNumberRepository) CreateFormat(format *models.DocumentNumberFormat) error {
+	return r.db.Omit("Branch").Create(format).Error
+}
+
+func (r *documentNumberRepository) UpdateFormat(format *models.DocumentNumberFormat) error {
+	return r.db.Omit("Branch").Save(format).Error
+}
+
+func (r *documentNumberRepository) DeleteFormat(id uuid.UUID) error {
+	return r.db.Unscoped().Delete(&models.DocumentNumberFormat{}, "id = ?", id).Error
+}
+
+// Preview returns the number the next document would get, without taking it. transactionType
+// is the type of a stock transaction.
+func (r *documentNumberRepository) Preview(documentType, transactionType string, branchID uuid.UUID, date time.Time) (string, *models.DocumentNumberFormat, error) {
+	typeCode := ""
+	if documentType == models.DocumentTypeStockTransaction {
+		typeCode = stockTransactionTypeCode(transactionType)
+	}
+
+	format, branchCode, err := resolveDocumentNumberFormatTx(r.db, documentType, branchID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var sequence models.DocumentSequence
+	err = r.db.
+		Where("document_type = ? AND scope = ? AND period = ?", documentType, format.Scope(branchCode, typeCode), format.Period(date)).
+		First(&sequence).Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", nil, err
+		}
+		if sequence.LastNumber, err = lastIssuedNumberTx(r.db, format, documentType, branchCode, typeCode, date); err != nil {
+			return "", nil, err
+		}
+	}
+
+	return format.Format(branchCode, typeCode, date, sequence.LastNumber+1), format, nil
+}
+
+// documentNumberFormatTx returns the configured format of a branch, or the default format for
+// all branches when branchID is nil. It returns nil when there is none.
+func documentNumberFormatTx(tx *gorm.DB, branchID *uuid.UUID, documentType string) (*models.DocumentNumberFormat, error) {
+	var format models.DocumentNumberFormat
+	query := tx.Where("document_type = ?", documentType)
+
+	if branchID != nil {
+		query = query.Where("branch_id = ?", *branchID)
+	} else {
+		query = query.Where("branch_id IS NULL")
+	}
+
+	err := query.First(&format).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &format, nil
+}
+
+// resolveDocumentNumberFormatTx returns the format used for a branch, falling back to the
+// format for all branches and then the built-in format, with the branch code
+func resolveDocumentNumberFormatTx(tx *gorm.DB, documentType string, branchID uuid.UUID) (*models.DocumentNumberFormat, string, error) {
+	var branch models.Branch
+	if err := tx.Select("id", "code").First(&branch, "id = ?", branchID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, "", errors.New("branch not found")
+		}
+		return nil, "", err
+	}
+
+	format, err := documentNumberFormatTx(tx, &branchID, documentType)
+	if err != nil {
+		return nil, "", err
+	}
+	if format == nil {
+		format, err = documentNumberFormatTx(tx, nil, documentType)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+	if format == nil {
+		format, err = models.DefaultDocumentNumberFormat(documentType)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	return format, branch.Code, nil
+}
+
+// lastIssuedNumberTx returns the highest sequence number among existing documents matching the
+// sequence of a date, so a new sequence continues after them
+func lastIssuedNumberTx(tx *gorm.DB, format *models.DocumentNumberFormat, documentType, branchCode, typeCode string, date time.Time) (int64, error) {
+	source, exists := documentNumberColumns[documentType]
+	if !exists {
+		return 0, nil
+	}
+
+	like, expression := format.Matcher(branchCode, typeCode, date)
+
+	// Soft deleted documents keep their numbers, so they are included
+	var numbers []string
+	if err := tx.Table(source.table).
+		Where(source.column+" LIKE ?", like).
+		Pluck(source.column, &numbers).Error; err != nil {
+		return 0, err
+	}
+
+	var last int64
+	for _, number := range numbers {
+		match := expression.FindStringSubmatch(number)
+		if match == nil {
+			continue
+		}
+		sequence, err := strconv.ParseInt(match[1], 10, 64)
+		if err == nil && sequence > last {
+			last = sequence
+		}
+	}
+	return last, nil
+}
+
+// nextDocumentNumberTx takes the next number of a document type in a branch. It must be called
+// inside the transaction that saves the document: the sequence row stays locked until that
+// transaction ends, so concurrent documents wait for each other instead of getting the same
+// number, and a rollback returns the number so no gap is left. typeCode fills the {TYPE}
+// token and is empty for documents without subtypes.
+func nextDocumentNumberTx(tx *gorm.DB, documentType, typeCode string, branchID uuid.UUID, date time.Time) (string, error) {
+	format, branchCode, err := resolveDocumentNumberFormatTx(tx, documentType, branchID)
+	if err != nil {
+		return "", err
+	}
+
+	scope := format.Scope(branchCode, typeCode)
+	period := format.Period(date)
+	lockSequence := func(sequence *models.DocumentSequence) error {
+		return tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("document_type = ? AND scope = ? AND period = ?", documentType, scope, period).
+			First(sequence).Error
+	}
+
+	var sequence models.DocumentSequence
+	err = lockSequence(&sequence)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Start the sequence after numbers issued before it existed. When another transaction
+		// creates it first, the insert waits for that transaction and then does nothing.
+		last, err := lastIssuedNumberTx(tx, format, documentType, branchCode, typeCode, date)
+		if err != nil {
+			return "", err
+		}
+
+		sequence = models.DocumentSequence{
+			DocumentType: documentType,
+			Scope:        scope,
+			Period:       period,
+			LastNumber:   last,
+		}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&sequence).Error; err != nil {
+			return "", err
+		}
+
+		sequence = models.DocumentSequence{}
+		err = lockSequence(&sequence)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	sequence.LastNumber++
+	if err := tx.Model(&sequence).Update("last_number", sequence.LastNumber).Error; err != nil {
+		return "", err
+	}
+
+	return format.Format(branchCode, typeCode, date, sequence.LastNumber), nil
+}
+
+// assignDocumentNumberTx numbers a document that has no number yet
+func assignDocumentNumberTx(tx *gorm.DB, number *string, documentType, typeCode string, branchID uuid.UUID, date time.Time) error {
+	if *number != "" {
+		return nil
+	}
+
+	next, err := nextDocumentNumberTx(tx, documentType, typeCode, branchID, date)
+	if err != nil {
+		return err
+	}
+	*number = next
+	return nil
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/yayasan/erp-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type DonorRepository interface {
+	GetByID(id uuid.UUID) (*models.Donor, error)
+	GetByCode(code string) (*models.Donor, error)
+}
+
+type donorRepository struct {
+	db *gorm.DB
+}
+
+func NewDonorRepository(db *gorm.DB) DonorRepository {
+	return &donorRepository{db: db}
+}
+
+func (r *donorRepository) GetByID(id uuid.UUID) (*models.Donor, error) {
+	var donor models.Donor
+	err := r.db.First(&donor, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("donor not found")
+		}
+		return nil, err
+	}
+	return &donor, nil
+}
+
+func (r *donorRepository) GetByCode(code string) (*models.Donor, error) {
+	var donor models.Donor
+	err := r.db.First(&donor, "code = ?", code).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("donor not found")
+		}
+		return nil, err
+	}
+	return &donor, nil
+}
@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yayasan/erp-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ExchangeRateRepository interface {
+	GetAll(currency string) ([]models.ExchangeRate, error)
+	GetByID(id uuid.UUID) (*models.ExchangeRate, error)
+	GetRate(currency string, date time.Time) (*models.ExchangeRate, error)
+	Save(rate *models.ExchangeRate) error
+	Delete(id uuid.UUID) error
+	GetForeignBalances(branchID *uuid.UUID, asOf time.Time, defaultCurrency string) ([]models.ForeignCurrencyBalance, error)
+	GetRevaluations(branchID *uuid.UUID) ([]models.FXRevaluation, error)
+	ClaimRevaluation(revaluation *models.FXRevaluation) (bool, error)
+	SaveRevaluation(revaluation *models.FXRevaluation) error
+	SaveRevaluationJournal(revaluation *models.FXRevaluation, journal *models.Journal) error
+}
+
+// staleRevaluationTimeout is how long a revaluation may stay processing before another run
+// takes it over; posting one branch takes seconds, so such a revaluation was interrupted
+const staleRevaluationTimeout = 30 * time.Minute
+
+type exchangeRateRepository struct {
+	db *gorm.DB
+}
+
+func NewExchangeRateRepository(db *gorm.DB) ExchangeRateRepository {
+	return &exchangeRateRepository{db: db}
+}
+
+func (r *exchangeRateRepository) GetAll(currency string) ([]models.ExchangeRate, error) {
+	var rates []models.ExchangeRate
+	query := r.db.Model(&models.ExchangeRate{})
+
+	if currency != "" {
+		query = query.Where("currency = ?", currency)
+	}
+
+	err := query.Order("rate_date DESC, currency ASC").Find(&rates).Error
+	return rates, err
+}
+
+func (r *exchangeRateRepository) GetByID(id uuid.UUID) (*models.ExchangeRate, error) {
+	var rate models.ExchangeRate
+	err := r.db.First(&rate, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("exchange rate not found")
+		}
+		return nil, err
+	}
+	return &rate, nil
+}
+
+// GetRate returns the latest rate of a currency dated on or before the given date
+func (r *exchangeRateRepository) GetRate(currency string, date time.Time) (*models.ExchangeRate, error) {
+	var rate models.ExchangeRate
+	err := r.db.
+		Where("currency = ? AND rate_date <= ?", currency, date).
+		Order("rate_date DESC").
+		First(&rate).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("no " + currency + " exchange rate on or before " + date.Format("2006-01-02"))
+		}
+		return nil, err
+	}
+	return &rate, nil
+}
+
+// Save creates the rate, or replaces the rate of the same currency and date
+func (r *exchangeRateRepository) Save(rate *models.ExchangeRate) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "currency"}, {Name: "rate_date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rate", "created_by", "updated_at"}),
+	}).Create(rate).Error
+}
+
+// Delete removes the rate for good, so the date can be given a new rate
+func (r *exchangeRateRepository) Delete(id uuid.UUID) error {
+	return r.db.Unscoped().Delete(&models.ExchangeRate{}, "id = ?", id).Error
+}
+
+// GetForeignBalances sums posted journal lines up to a date for asset accounts kept in a
+// foreign currency, per branch and account
+func (r *exchangeRateRepository) GetForeignBalances(branchID *uuid.UUID, asOf time.Time, defaultCurrency string) ([]models.ForeignCurrencyBalance, error) {
+	var balances []models.ForeignCurrencyBalance
+
+	query := r.db.Model(&models.JournalLine{}).
+		Select(`journals.branch_id, journal_lines.account_id, accounts.currency,
+			COALESCE(SUM(CASE WHEN journal_lines.currency = accounts.currency THEN journal_lines.foreign_amount ELSE 0 END), 0) AS foreign_balance,
+			COALESCE(SUM(journal_lines.debit - journal_lines.credit), 0) AS book_balance`).
+		Joins("JOIN journals ON journals.id = journal_lines.journal_id AND journals.deleted_at IS NULL").
+		Joins("JOIN accounts ON accounts.id = journal_lines.account_id").
+		Where("journals.is_posted = ?", true).
+		Where("journals.journal_date <= ?", asOf).
+		Where("accounts.category = ?", models.AccountCategoryAsset).
+		Where("accounts.currency <> '' AND accounts.currency <> ?", defaultCurrency)
+
+	if branchID != nil {
+		query = query.Where("journals.branch_id = ?", *branchID)
+	}
+
+	err := query.
+		Group("journals.branch_id, journal_lines.account_id, accounts.currency, accounts.code").
+		Order("journals.branch_id, accounts.code").
+		Scan(&balances).Error
+	return balances, err
+}
+
+func (r *exchangeRateRepository) GetRevaluations(branchID *uuid.UUID) ([]models.FXRevaluation, error) {
+	var revaluations []models.FXRevaluation
+	query := r.db.Preload("Branch").Preload("Journal")
+
+	if branchID != nil {
+		query = query.Where("branch_id = ?", *branchID)
+	}
+
+	err := query.Order("revaluation_date DESC").Find(&revaluations).Error
+	return revaluations, err
+}
+
+// ClaimRevaluation records a revaluation of a branch at a date unless one already exists. A
+// failed revaluation, or one left processing for longer than staleRevaluationTimeout, is taken
+// over so it can be retried. When the date is already claimed, revaluation is loaded with the
+// existing record and false is returned. The unique index on the branch and date makes this safe
+// when several runs happen at once.
+func (r *exchangeRateRepository) ClaimRevaluation(revaluation *models.FXRevaluation) (bool, error) {
+	result := r.db.Omit("Branch", "Journal").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "branch_id"}, {Name: "revaluation_date"}},
+		DoNothing: true,
+	}).Create(revaluation)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 1 {
+		return true, nil
+	}
+
+	result = r.db.Model(&models.FXRevaluation{}).
+		Where("branch_id = ? AND revaluation_date = ?", revaluation.BranchID, revaluation.RevaluationDate).
+		Where("status = ? OR (status = ? AND updated_at < ?)",
+			models.FXRevaluationFailed, models.FXRevaluationProcessing, time.Now().Add(-staleRevaluationTimeout)).
+		Updates(map[string]interface{}{
+			"status":       revaluation.Status,
+			"message":      revaluation.Message,
+			"processed_by": revaluation.ProcessedBy,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	claimed := result.RowsAffected == 1
+
+	var existing models.FXRevaluation
+	err := r.db.Where("branch_id = ? AND revaluation_date = ?", revaluation.BranchID, revaluation.RevaluationDate).
+		First(&existing).Error
+	if err != nil {
+		return false, err
+	}
+	if claimed {
+		revaluation.ID = existing.ID
+		revaluation.CreatedAt = existing.CreatedAt
+		return true, nil
+	}
+
+	*revaluation = existing
+	return false, nil
+}
+
+func (r *exchangeRateRepository) SaveRevaluation(revaluation *models.FXRevaluation) error {
+	return r.db.Omit("Branch", "Journal").Save(revaluation).Error
+}
+
+// SaveRevaluationJournal creates the journal of a revaluation and saves the revaluation in one
+// transaction, so a revaluation never stays processing once its journal exists
+func (r *exchangeRateRepository) SaveRevaluationJournal(revaluation *models.FXRevaluation, journal *models.Journal) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := createJournalTx(tx, journal); err != nil {
+			return err
+		}
+
+		revaluation.JournalID = &journal.ID
+		return tx.Omit("Branch", "Journal").Save(revaluation).Error
+	})
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/yayasan/erp-backend/internal/models"
@@ -12,8 +13,12 @@ type FiscalYearRepository interface {
 	GetAll() ([]models.FiscalYear, error)
 	GetByID(id uuid.UUID) (*models.FiscalYear, error)
 	GetCurrent() (*models.FiscalYear, error)
+	HasOverlap(start, end time.Time, excludeID *uuid.UUID) (bool, error)
 	Create(fiscalYear *models.FiscalYear) error
 	Update(fiscalYear *models.FiscalYear) error
+	SetCurrent(id uuid.UUID) error
+	Close(fiscalYear *models.FiscalYear, journals []*models.Journal, openingPeriod string) error
+	Reopen(fiscalYear *models.FiscalYear, originals []models.Journal, reversals []*models.Journal) error
 	Delete(id uuid.UUID) error
 }
 
@@ -55,14 +60,136 @@ func (r *fiscalYearRepository) GetCurrent() (*models.FiscalYear, error) {
 	return &fiscalYear, nil
 }
 
+// HasOverlap checks whether a date range overlaps another fiscal year
+func (r *fiscalYearRepository) HasOverlap(start, end time.Time, excludeID *uuid.UUID) (bool, error) {
+	query := r.db.Model(&models.FiscalYear{}).
+		Where("start_date <= ? AND end_date >= ?", end, start)
+
+	if excludeID != nil {
+		query = query.Where("id <> ?", *excludeID)
+	}
+
+	var count int64
+	err := query.Count(&count).Error
+	return count > 0, err
+}
+
+// Create saves a fiscal year. A current fiscal year clears the flag on all others in the same
+// transaction.
 func (r *fiscalYearRepository) Create(fiscalYear *models.FiscalYear) error {
-	return r.db.Create(fiscalYear).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if fiscalYear.IsCurrent {
+			if err := clearCurrentFiscalYearTx(tx); err != nil {
+				return err
+			}
+		}
+		return tx.Create(fiscalYear).Error
+	})
 }
 
 func (r *fiscalYearRepository) Update(fiscalYear *models.FiscalYear) error {
 	return r.db.Save(fiscalYear).Error
 }
 
+// SetCurrent makes one fiscal year current and clears the flag on all others
+func (r *fiscalYearRepository) SetCurrent(id uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := clearCurrentFiscalYearTx(tx); err != nil {
+			return err
+		}
+
+		return tx.Model(&models.FiscalYear{}).
+			Where("id = ?", id).
+			Update("is_current", true).Error
+	})
+}
+
+// clearCurrentFiscalYearTx clears the current flag of every fiscal year
+func clearCurrentFiscalYearTx(tx *gorm.DB) error {
+	return tx.Model(&models.FiscalYear{}).
+		Where("is_current = ?", true).
+		Update("is_current", false).Error
+}
+
+// Close posts the closing and opening journals, carries balances into the opening period
+// and marks the fiscal year closed in one transaction
+func (r *fiscalYearRepository) Close(fiscalYear *models.FiscalYear, journals []*models.Journal, openingPeriod string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, journal := range journals {
+			if err := createJournalTx(tx, journal); err != nil {
+				return err
+			}
+		}
+
+		// Opening balances: carry every balance without a row in the opening period
+		if err := tx.Exec(`
+			INSERT INTO account_balances
+				(id, account_id, branch_id, fund_id, program_id, period,
+				 opening_balance, debit, credit, closing_balance, created_at, updated_at)
+			SELECT uuid_generate_v4(), b.account_id, b.branch_id, b.fund_id, b.program_id, ?,
+				b.closing_balance, 0, 0, b.closing_balance, NOW(), NOW()
+			FROM (
+				SELECT DISTINCT ON (account_id, branch_id, fund_id, program_id) *
+				FROM account_balances
+				WHERE period < ? AND deleted_at IS NULL
+				ORDER BY account_id, branch_id, fund_id, program_id, period DESC
+			) b
+			WHERE b.closing_balance <> 0
+				AND NOT EXISTS (
+					SELECT 1 FROM account_balances o
+					WHERE o.period = ? AND o.deleted_at IS NULL
+						AND o.account_id = b.account_id
+						AND o.branch_id IS NOT DISTINCT FROM b.branch_id
+						AND o.fund_id IS NOT DISTINCT FROM b.fund_id
+						AND o.program_id IS NOT DISTINCT FROM b.program_id
+				)
+		`, openingPeriod, openingPeriod, openingPeriod).Error; err != nil {
+			return err
+		}
+
+		result := tx.Model(&models.FiscalYear{}).
+			Where("id = ? AND is_closed = ?", fiscalYear.ID, false).
+			Updates(map[string]interface{}{
+				"is_closed": true,
+				"closed_at": fiscalYear.ClosedAt,
+				"closed_by": fiscalYear.ClosedBy,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("fiscal year is already closed")
+		}
+		return nil
+	})
+}
+
+// Reopen reverses the closing and opening journals and unlocks the fiscal year in one transaction
+func (r *fiscalYearRepository) Reopen(fiscalYear *models.FiscalYear, originals []models.Journal, reversals []*models.Journal) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for i := range originals {
+			if err := createReversalTx(tx, &originals[i], reversals[i]); err != nil {
+				return err
+			}
+		}
+
+		result := tx.Model(&models.FiscalYear{}).
+			Where("id = ? AND is_closed = ?", fiscalYear.ID, true).
+			Updates(map[string]interface{}{
+				"is_closed": false,
+				"closed_at": nil,
+				"closed_by": nil,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("fiscal year is not closed")
+		}
+		return nil
+	})
+}
+
 func (r *fiscalYearRepository) Delete(id uuid.UUID) error {
 	return r.db.Delete(&models.FiscalYear{}, "id = ?", id).Error
 }
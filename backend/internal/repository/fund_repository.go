@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/yayasan/erp-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type FundRepository interface {
+	GetAll() ([]models.Fund, error)
+	GetAllActive() ([]models.Fund, error)
+	GetByID(id uuid.UUID) (*models.Fund, error)
+	GetByCode(code string) (*models.Fund, error)
+	IsUsed(id uuid.UUID) (bool, error)
+	Create(fund *models.Fund) error
+	Update(fund *models.Fund) error
+	Delete(id uuid.UUID) error
+}
+
+type fundRepository struct {
+	db *gorm.DB
+}
+
+func NewFundRepository(db *gorm.DB) FundRepository {
+	return &fundRepository{db: db}
+}
+
+func (r *fundRepository) GetAll() ([]models.Fund, error) {
+	var funds []models.Fund
+	err := r.db.Order("code ASC").Find(&funds).Error
+	return funds, err
+}
+
+func (r *fundRepository) GetAllActive() ([]models.Fund, error) {
+	var funds []models.Fund
+	err := r.db.Where("is_active = ?", true).Order("code ASC").Find(&funds).Error
+	return funds, err
+}
+
+func (r *fundRepository) GetByID(id uuid.UUID) (*models.Fund, error) {
+	var fund models.Fund
+	err := r.db.First(&fund, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("fund not found")
+		}
+		return nil, err
+	}
+	return &fund, nil
+}
+
+func (r *fundRepository) GetByCode(code string) (*models.Fund, error) {
+	var fund models.Fund
+	err := r.db.First(&fund, "code = ?", code).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("fund not found")
+		}
+		return nil, err
+	}
+	return &fund, nil
+}
+
+// IsUsed checks whether any journal line or budget references the fund
+func (r *fundRepository) IsUsed(id uuid.UUID) (bool, error) {
+	var count int64
+	if err := r.db.Model(&models.JournalLine{}).Where("fund_id = ?", id).Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return true, nil
+	}
+
+	err := r.db.Model(&models.Budget{}).Where("fund_id = ?", id).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *fundRepository) Create(fund *models.Fund) error {
+	return r.db.Create(fund).Error
+}
+
+func (r *fundRepository) Update(fund *models.Fund) error {
+	return r.db.Save(fund).Error
+}
+
+func (r *fundRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&models.Fund{}, "id = ?", id).Error
+}
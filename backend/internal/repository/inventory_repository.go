@@ -29,7 +29,6 @@ type InventoryRepository interface {
 	GetTransactionsByItem(itemID uuid.UUID) ([]models.StockTransaction, error)
 	GetTransactionsByDateRange(startDate, endDate time.Time) ([]models.StockTransaction, error)
 	CreateTransaction(transaction *models.StockTransaction) error
-	GenerateTransactionNumber(txType, branchCode string, date time.Time) (string, error)
 	
 	// Stock Opname
 	GetAllOpnames(params *models.PaginationParams) ([]models.StockOpname, int64, error)
@@ -37,7 +36,6 @@ type InventoryRepository interface {
 	CreateOpname(opname *models.StockOpname) error
 	UpdateOpname(opname *models.StockOpname) error
 	ApproveOpname(id uuid.UUID, approverID uuid.UUID) error
-	GenerateOpnameNumber(branchCode string, date time.Time) (string, error)
 	
 	// Purchase Orders
 	GetAllPOs(params *models.PaginationParams) ([]models.PurchaseOrder, int64, error)
@@ -45,7 +43,6 @@ type InventoryRepository interface {
 	CreatePO(po *models.PurchaseOrder) error
 	UpdatePO(po *models.PurchaseOrder) error
 	ApprovePO(id uuid.UUID, approverID uuid.UUID) error
-	GeneratePONumber(branchCode string, date time.Time) (string, error)
 }
 
 type inventoryRepository struct {
@@ -275,6 +272,11 @@ func (r *inventoryRepository) CreateTransaction(transaction *models.StockTransac
 
 		transaction.StockAfter = newStock
 
+		if err := assignDocumentNumberTx(tx, &transaction.TransactionNumber, models.DocumentTypeStockTransaction,
+			stockTransactionTypeCode(transaction.TransactionType), transaction.BranchID, transaction.TransactionDate); err != nil {
+			return err
+		}
+
 		// Create transaction
 		if err := tx.Create(transaction).Error; err != nil {
 			return err
@@ -288,37 +290,17 @@ func (r *inventoryRepository) CreateTransaction(transaction *models.StockTransac
 	})
 }
 
-func (r *inventoryRepository) GenerateTransactionNumber(txType, branchCode string, date time.Time) (string, error) {
-	// Format: STK/IN|OUT|ADJ/BRANCH/YYYYMMDD/XXXX
-	dateStr := date.Format("20060102")
-	typeCode := "IN"
+// stockTransactionTypeCode returns the {TYPE} of a stock transaction number
+func stockTransactionTypeCode(txType string) string {
 	switch txType {
 	case models.TransactionTypeOut:
-		typeCode = "OUT"
+		return "OUT"
 	case models.TransactionTypeAdjustment:
-		typeCode = "ADJ"
+		return "ADJ"
 	case models.TransactionTypeOpname:
-		typeCode = "OPN"
-	}
-	
-	prefix := fmt.Sprintf("STK/%s/%s/%s/", typeCode, branchCode, dateStr)
-
-	var lastTxn models.StockTransaction
-	err := r.db.
-		Where("transaction_number LIKE ?", prefix+"%").
-		Order("transaction_number DESC").
-		First(&lastTxn).Error
-
-	var sequence int
-	if err == nil {
-		var lastSeq int
-		fmt.Sscanf(lastTxn.TransactionNumber[len(prefix):], "%d", &lastSeq)
-		sequence = lastSeq + 1
-	} else {
-		sequence = 1
+		return "OPN"
 	}
-
-	return fmt.Sprintf("%s%04d", prefix, sequence), nil
+	return "IN"
 }
 
 // Stock Opname
@@ -365,7 +347,12 @@ func (r *inventoryRepository) GetOpnameByID(id uuid.UUID) (*models.StockOpname,
 }
 
 func (r *inventoryRepository) CreateOpname(opname *models.StockOpname) error {
-	return r.db.Create(opname).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := assignDocumentNumberTx(tx, &opname.OpnameNumber, models.DocumentTypeStockOpname, "", opname.BranchID, opname.OpnameDate); err != nil {
+			return err
+		}
+		return tx.Create(opname).Error
+	})
 }
 
 func (r *inventoryRepository) UpdateOpname(opname *models.StockOpname) error {
@@ -383,29 +370,6 @@ func (r *inventoryRepository) ApproveOpname(id uuid.UUID, approverID uuid.UUID)
 		}).Error
 }
 
-func (r *inventoryRepository) GenerateOpnameNumber(branchCode string, date time.Time) (string, error) {
-	// Format: OPN/BRANCH/YYYYMM/XXXX
-	yearMonth := date.Format("200601")
-	prefix := fmt.Sprintf("OPN/%s/%s/", branchCode, yearMonth)
-
-	var lastOpname models.StockOpname
-	err := r.db.
-		Where("opname_number LIKE ?", prefix+"%").
-		Order("opname_number DESC").
-		First(&lastOpname).Error
-
-	var sequence int
-	if err == nil {
-		var lastSeq int
-		fmt.Sscanf(lastOpname.OpnameNumber[len(prefix):], "%d", &lastSeq)
-		sequence = lastSeq + 1
-	} else {
-		sequence = 1
-	}
-
-	return fmt.Sprintf("%s%04d", prefix, sequence), nil
-}
-
 // Purchase Orders
 func (r *inventoryRepository) GetAllPOs(params *models.PaginationParams) ([]models.PurchaseOrder, int64, error) {
 	var pos []models.PurchaseOrder
@@ -448,7 +412,12 @@ func (r *inventoryRepository) GetPOByID(id uuid.UUID) (*models.PurchaseOrder, er
 }
 
 func (r *inventoryRepository) CreatePO(po *models.PurchaseOrder) error {
-	return r.db.Create(po).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := assignDocumentNumberTx(tx, &po.PONumber, models.DocumentTypePurchaseOrder, "", po.BranchID, po.OrderDate); err != nil {
+			return err
+		}
+		return tx.Create(po).Error
+	})
 }
 
 func (r *inventoryRepository) UpdatePO(po *models.PurchaseOrder) error {
@@ -463,26 +432,3 @@ func (r *inventoryRepository) ApprovePO(id uuid.UUID, approverID uuid.UUID) erro
 			"approved_by": approverID,
 		}).Error
 }
-
-func (r *inventoryRepository) GeneratePONumber(branchCode string, date time.Time) (string, error) {
-	// Format: PO/BRANCH/YYYYMM/XXXX
-	yearMonth := date.Format("200601")
-	prefix := fmt.Sprintf("PO/%s/%s/", branchCode, yearMonth)
-
-	var lastPO models.PurchaseOrder
-	err := r.db.
-		Where("po_number LIKE ?", prefix+"%").
-		Order("po_number DESC").
-		First(&lastPO).Error
-
-	var sequence int
-	if err == nil {
-		var lastSeq int
-		fmt.Sscanf(lastPO.PONumber[len(prefix):], "%d", &lastSeq)
-		sequence = lastSeq + 1
-	} else {
-		sequence = 1
-	}
-
-	return fmt.Sprintf("%s%04d", prefix, sequence), nil
-}
@@ -2,7 +2,6 @@ package repository
 
 import (
 	"errors"
-	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -19,7 +18,6 @@ type InvoiceRepository interface {
 	Create(invoice *models.Invoice) error
 	Update(invoice *models.Invoice) error
 	Delete(id uuid.UUID) error
-	GenerateInvoiceNumber(branchCode string, date time.Time) (string, error)
 }
 
 type invoiceRepository struct {
@@ -121,21 +119,30 @@ func (r *invoiceRepository) GetOverdue() ([]models.Invoice, error) {
 
 func (r *invoiceRepository) Create(invoice *models.Invoice) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
-		// Calculate total amount
-		var total float64
-		for _, item := range invoice.Items {
-			item.Amount = item.UnitPrice * float64(item.Quantity)
-			total += item.Amount
-		}
-		invoice.TotalAmount = total
-		invoice.PaidAmount = 0
-		invoice.Status = models.InvoiceStatusUnpaid
-
-		// Create invoice
-		return tx.Create(invoice).Error
+		return createInvoiceTx(tx, invoice)
 	})
 }
 
+// createInvoiceTx numbers and saves an unpaid invoice with its items
+func createInvoiceTx(tx *gorm.DB, invoice *models.Invoice) error {
+	// Calculate total amount
+	var total models.Money
+	for i := range invoice.Items {
+		invoice.Items[i].Amount = invoice.Items[i].UnitPrice * models.Money(invoice.Items[i].Quantity)
+		total += invoice.Items[i].Amount
+	}
+	invoice.TotalAmount = total
+	invoice.PaidAmount = 0
+	invoice.Status = models.InvoiceStatusUnpaid
+
+	if err := assignDocumentNumberTx(tx, &invoice.InvoiceNumber, models.DocumentTypeInvoice, "", invoice.BranchID, invoice.InvoiceDate); err != nil {
+		return err
+	}
+
+	// Create invoice
+	return tx.Create(invoice).Error
+}
+
 func (r *invoiceRepository) Update(invoice *models.Invoice) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
 		// Delete old items
@@ -144,9 +151,9 @@ func (r *invoiceRepository) Update(invoice *models.Invoice) error {
 		}
 
 		// Calculate total amount
-		var total float64
+		var total models.Money
 		for i := range invoice.Items {
-			invoice.Items[i].Amount = invoice.Items[i].UnitPrice * float64(invoice.Items[i].Quantity)
+			invoice.Items[i].Amount = invoice.Items[i].UnitPrice * models.Money(invoice.Items[i].Quantity)
 			total += invoice.Items[i].Amount
 		}
 		invoice.TotalAmount = total
@@ -176,6 +183,16 @@ func (r *invoiceRepository) Delete(id uuid.UUID) error {
 			return errors.New("cannot delete invoice with payments")
 		}
 
+		// Opening invoices go together with their opening journal
+		if err := tx.Model(&models.Invoice{}).
+			Where("id = ? AND opening_journal_id IS NOT NULL", id).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return errors.New("opening invoice is removed by reversing its opening journal")
+		}
+
 		// Delete invoice items
 		if err := tx.Where("invoice_id = ?", id).Delete(&models.InvoiceItem{}).Error; err != nil {
 			return err
@@ -185,26 +202,3 @@ func (r *invoiceRepository) Delete(id uuid.UUID) error {
 		return tx.Delete(&models.Invoice{}, "id = ?", id).Error
 	})
 }
-
-func (r *invoiceRepository) GenerateInvoiceNumber(branchCode string, date time.Time) (string, error) {
-	// Format: INV/BranchCode/YYYYMM/XXXX
-	yearMonth := date.Format("200601")
-	prefix := fmt.Sprintf("INV/%s/%s/", branchCode, yearMonth)
-
-	var lastInvoice models.Invoice
-	err := r.db.
-		Where("invoice_number LIKE ?", prefix+"%").
-		Order("invoice_number DESC").
-		First(&lastInvoice).Error
-
-	var sequence int
-	if err == nil {
-		var lastSeq int
-		fmt.Sscanf(lastInvoice.InvoiceNumber[len(prefix):], "%d", &lastSeq)
-		sequence = lastSeq + 1
-	} else {
-		sequence = 1
-	}
-
-	return fmt.Sprintf("%s%04d", prefix, sequence), nil
-}
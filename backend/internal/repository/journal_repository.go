@@ -1,8 +1,9 @@
 package repository
 
 import (
+	"database/sql"
 	"errors"
-	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,16 +12,27 @@ import (
 )
 
 type JournalRepository interface {
-	GetAll(params *models.PaginationParams) ([]models.Journal, int64, error)
+	Search(params *models.JournalSearchParams) ([]models.Journal, int64, error)
+	SearchLines(params *models.JournalSearchParams) ([]models.JournalLine, int64, error)
+	CountSearch(params *models.JournalSearchParams) (int64, error)
+	CountSearchLines(params *models.JournalSearchParams) (int64, error)
 	GetByID(id uuid.UUID) (*models.Journal, error)
 	GetByJournalNumber(number string) (*models.Journal, error)
+	GetByFiscalYear(fiscalYearID uuid.UUID) ([]models.Journal, error)
 	GetByBranch(branchID uuid.UUID, params *models.PaginationParams) ([]models.Journal, int64, error)
 	GetByStatus(status string, params *models.PaginationParams) ([]models.Journal, int64, error)
 	GetByDateRange(start, end time.Time) ([]models.Journal, error)
+	GetAccountMovements(branchID *uuid.UUID, start, end time.Time, categories []string) ([]models.AccountMovement, error)
 	Create(journal *models.Journal) error
+	CreateBatch(journals []*models.Journal) error
 	Update(journal *models.Journal) error
+	Post(journal *models.Journal) error
+	PostInterBranch(journal *models.Journal, branchJournals []models.Journal) error
+	SubmitForReview(journal *models.Journal, request *models.ApprovalRequest, history *models.ApprovalHistory) error
+	RecordReview(journal *models.Journal, request *models.ApprovalRequest, history *models.ApprovalHistory) error
+	CreateReversal(original *models.Journal, reversal *models.Journal) error
+	CreateReversals(originals []*models.Journal, reversals []*models.Journal) error
 	Delete(id uuid.UUID) error
-	GenerateJournalNumber(branchCode string, date time.Time) (string, error)
 }
 
 type journalRepository struct {
@@ -31,36 +43,21 @@ func NewJournalRepository(db *gorm.DB) JournalRepository {
 	return &journalRepository{db: db}
 }
 
-func (r *journalRepository) GetAll(params *models.PaginationParams) ([]models.Journal, int64, error) {
+func (r *journalRepository) Search(params *models.JournalSearchParams) ([]models.Journal, int64, error) {
 	var journals []models.Journal
 	var total int64
 
-	query := r.db.Model(&models.Journal{})
-
-	// Filter by status if provided
-	if params.Search != "" {
-		query = query.Where("status = ?", params.Search)
-	}
-
-	// Count total
+	query := r.journalSearchQuery(params)
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	// Apply sorting
-	sortOrder := "journal_date DESC, created_at DESC"
-	if params.SortBy != "" {
-		sortOrder = params.SortBy
-		if params.SortOrder == "desc" {
-			sortOrder += " DESC"
-		} else {
-			sortOrder += " ASC"
-		}
+	sortColumn, exists := journalSortColumns[params.SortBy]
+	if !exists {
+		return nil, 0, errors.New("journals cannot be sorted by " + params.SortBy)
 	}
 
-	// Get paginated results with relationships
-	offset := (params.Page - 1) * params.PageSize
-	err := query.
+	query = query.
 		Preload("Branch").
 		Preload("Creator").
 		Preload("JournalLines").
@@ -68,14 +65,218 @@ func (r *journalRepository) GetAll(params *models.PaginationParams) ([]models.Jo
 		Preload("JournalLines.Fund").
 		Preload("JournalLines.Program").
 		Preload("JournalLines.Donor").
-		Order(sortOrder).
-		Limit(params.PageSize).
-		Offset(offset).
-		Find(&journals).Error
+		Order(journalSortOrder(sortColumn, params.SortOrder))
+	if params.PageSize > 0 {
+		query = query.Limit(params.PageSize).Offset((params.Page - 1) * params.PageSize)
+	}
 
+	err := query.Find(&journals).Error
 	return journals, total, err
 }
 
+// CountSearch counts the journals found by a search without loading them
+func (r *journalRepository) CountSearch(params *models.JournalSearchParams) (int64, error) {
+	var total int64
+	err := r.journalSearchQuery(params).Count(&total).Error
+	return total, err
+}
+
+// journalSearchQuery filters journals by a search
+func (r *journalRepository) journalSearchQuery(params *models.JournalSearchParams) *gorm.DB {
+	query := applyJournalSearch(r.db.Model(&models.Journal{}), params, false)
+
+	// Line filters match journals with at least one matching line
+	lines := applyJournalLineSearch(r.db.Table("journal_lines").
+		Select("1").
+		Where("journal_lines.journal_id = journals.id AND journal_lines.deleted_at IS NULL"), params)
+	if lines != nil {
+		query = query.Where("EXISTS (?)", lines)
+	}
+
+	if params.MinAmount != nil {
+		query = query.Where("journals.total_debit >= ?", *params.MinAmount)
+	}
+	if params.MaxAmount != nil {
+		query = query.Where("journals.total_debit <= ?", *params.MaxAmount)
+	}
+	return query
+}
+
+// SearchLines finds journal lines, with their journals
+func (r *journalRepository) SearchLines(params *models.JournalSearchParams) ([]models.JournalLine, int64, error) {
+	var lines []models.JournalLine
+	var total int64
+
+	query := r.journalLineSearchQuery(params)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	sortColumn, exists := journalLineSortColumns[params.SortBy]
+	if !exists {
+		return nil, 0, errors.New("journal lines cannot be sorted by " + params.SortBy)
+	}
+
+	query = query.
+		Preload("Journal").
+		Preload("Journal.Branch").
+		Preload("Journal.Creator").
+		Preload("Account").
+		Preload("Fund").
+		Preload("Program").
+		Preload("Donor").
+		Preload("Branch").
+		Preload("CounterpartBranch").
+		Order(journalSortOrder(sortColumn, params.SortOrder) + ", journal_lines.created_at ASC")
+	if params.PageSize > 0 {
+		query = query.Limit(params.PageSize).Offset((params.Page - 1) * params.PageSize)
+	}
+
+	err := query.Find(&lines).Error
+	return lines, total, err
+}
+
+// CountSearchLines counts the journal lines found by a search without loading them
+func (r *journalRepository) CountSearchLines(params *models.JournalSearchParams) (int64, error) {
+	var total int64
+	err := r.journalLineSearchQuery(params).Count(&total).Error
+	return total, err
+}
+
+// journalLineSearchQuery filters journal lines by a search
+func (r *journalRepository) journalLineSearchQuery(params *models.JournalSearchParams) *gorm.DB {
+	query := r.db.Model(&models.JournalLine{}).
+		Joins("JOIN journals ON journals.id = journal_lines.journal_id AND journals.deleted_at IS NULL").
+		Joins("JOIN accounts ON accounts.id = journal_lines.account_id")
+	query = applyJournalSearch(query, params, true)
+	if lineQuery := applyJournalLineSearch(query, params); lineQuery != nil {
+		query = lineQuery
+	}
+
+	if params.MinAmount != nil {
+		query = query.Where("journal_lines.debit + journal_lines.credit >= ?", *params.MinAmount)
+	}
+	if params.MaxAmount != nil {
+		query = query.Where("journal_lines.debit + journal_lines.credit <= ?", *params.MaxAmount)
+	}
+	return query
+}
+
+// journalSortColumns and journalLineSortColumns map the sort fields of a search to columns
+var journalSortColumns = map[string]string{
+	"":                          "journals.journal_date",
+	models.JournalSortDate:      "journals.journal_date",
+	models.JournalSortNumber:    "journals.journal_number",
+	models.JournalSortReference: "journals.reference_no",
+	models.JournalSortStatus:    "journals.status",
+	models.JournalSortAmount:    "journals.total_debit",
+	models.JournalSortCreatedAt: "journals.created_at",
+}
+
+var journalLineSortColumns = map[string]string{
+	"":                            "journals.journal_date",
+	models.JournalSortDate:        "journals.journal_date",
+	models.JournalSortNumber:      "journals.journal_number",
+	models.JournalSortReference:   "journals.reference_no",
+	models.JournalSortStatus:      "journals.status",
+	models.JournalSortAmount:      "journal_lines.debit + journal_lines.credit",
+	models.JournalSortCreatedAt:   "journals.created_at",
+	models.JournalSortAccountCode: "accounts.code",
+}
+
+// journalSortOrder orders by a column, newest or largest first unless asc is asked for. Ties
+// keep the journal number order so pages are stable.
+func journalSortOrder(column, sortOrder string) string {
+	direction := " DESC"
+	if sortOrder == "asc" {
+		direction = " ASC"
+	}
+	return column + direction + ", journals.journal_number" + direction
+}
+
+// applyJournalSearch applies the filters on the journal columns. The free text also matches the
+// line description when searching lines.
+func applyJournalSearch(query *gorm.DB, params *models.JournalSearchParams, lines bool) *gorm.DB {
+	if params.StartDate != nil {
+		query = query.Where("journals.journal_date >= ?", *params.StartDate)
+	}
+	if params.EndDate != nil {
+		query = query.Where("journals.journal_date < ?", params.EndDate.AddDate(0, 0, 1))
+	}
+	if statuses := splitSearchValues(params.Status); len(statuses) > 0 {
+		query = query.Where("journals.status IN ?", statuses)
+	}
+	if types := splitSearchValues(params.Type); len(types) > 0 {
+		query = query.Where("journals.type IN ?", types)
+	}
+	if params.CreatedBy != nil {
+		query = query.Where("journals.created_by = ?", *params.CreatedBy)
+	}
+	if search := strings.TrimSpace(params.Search); search != "" {
+		pattern := "%" + search + "%"
+		condition := "journals.journal_number ILIKE @pattern OR journals.description ILIKE @pattern OR journals.reference_no ILIKE @pattern"
+		if lines {
+			condition += " OR journal_lines.description ILIKE @pattern"
+		}
+		query = query.Where(condition, sql.Named("pattern", pattern))
+	}
+	return query
+}
+
+// applyJournalLineSearch applies the filters on the line columns, returning nil when there are
+// none. A line without a branch of its own is booked in the journal branch.
+func applyJournalLineSearch(query *gorm.DB, params *models.JournalSearchParams) *gorm.DB {
+	filtered := false
+
+	if params.BranchID != nil {
+		query = query.Where("COALESCE(journal_lines.branch_id, journals.branch_id) = ?", *params.BranchID)
+		filtered = true
+	}
+	if params.AccountID != nil {
+		if params.IncludeSubAccounts {
+			query = query.Where(`journal_lines.account_id IN (
+				WITH RECURSIVE subtree AS (
+					SELECT id FROM accounts WHERE id = ? AND deleted_at IS NULL
+					UNION ALL
+					SELECT a.id FROM accounts a JOIN subtree s ON a.parent_id = s.id WHERE a.deleted_at IS NULL
+				)
+				SELECT id FROM subtree
+			)`, *params.AccountID)
+		} else {
+			query = query.Where("journal_lines.account_id = ?", *params.AccountID)
+		}
+		filtered = true
+	}
+	if params.FundID != nil {
+		query = query.Where("journal_lines.fund_id = ?", *params.FundID)
+		filtered = true
+	}
+	if params.ProgramID != nil {
+		query = query.Where("journal_lines.program_id = ?", *params.ProgramID)
+		filtered = true
+	}
+	if params.DonorID != nil {
+		query = query.Where("journal_lines.donor_id = ?", *params.DonorID)
+		filtered = true
+	}
+
+	if !filtered {
+		return nil
+	}
+	return query
+}
+
+// splitSearchValues splits a comma separated filter
+func splitSearchValues(value string) []string {
+	var values []string
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			values = append(values, part)
+		}
+	}
+	return values
+}
+
 func (r *journalRepository) GetByID(id uuid.UUID) (*models.Journal, error) {
 	var journal models.Journal
 	err := r.db.
@@ -88,6 +289,16 @@ func (r *journalRepository) GetByID(id uuid.UUID) (*models.Journal, error) {
 		Preload("JournalLines.Fund").
 		Preload("JournalLines.Program").
 		Preload("JournalLines.Donor").
+		Preload("JournalLines.Branch").
+		Preload("JournalLines.CounterpartBranch").
+		Preload("ReversalOf").
+		Preload("ReversedBy").
+		Preload("SourceJournal").
+		Preload("BranchJournals").
+		Preload("BranchJournals.Branch").
+		Preload("BranchJournals.JournalLines").
+		Preload("BranchJournals.JournalLines.Account").
+		Preload("BranchJournals.JournalLines.CounterpartBranch").
 		First(&journal, "id = ?", id).Error
 	
 	if err != nil {
@@ -115,6 +326,18 @@ func (r *journalRepository) GetByJournalNumber(number string) (*models.Journal,
 	return &journal, nil
 }
 
+// GetByFiscalYear returns the closing and opening journals of a fiscal year that are not reversed
+func (r *journalRepository) GetByFiscalYear(fiscalYearID uuid.UUID) ([]models.Journal, error) {
+	var journals []models.Journal
+	err := r.db.
+		Where("fiscal_year_id = ? AND is_posted = ? AND reversed_by_journal_id IS NULL", fiscalYearID, true).
+		Preload("Branch").
+		Preload("JournalLines").
+		Order("journal_date ASC, journal_number ASC").
+		Find(&journals).Error
+	return journals, err
+}
+
 func (r *journalRepository) GetByBranch(branchID uuid.UUID, params *models.PaginationParams) ([]models.Journal, int64, error) {
 	var journals []models.Journal
 	var total int64
@@ -172,33 +395,95 @@ func (r *journalRepository) GetByDateRange(start, end time.Time) ([]models.Journ
 	return journals, err
 }
 
+// GetAccountMovements sums posted journal lines per branch, account, fund and program
+func (r *journalRepository) GetAccountMovements(branchID *uuid.UUID, start, end time.Time, categories []string) ([]models.AccountMovement, error) {
+	var movements []models.AccountMovement
+
+	query := r.db.Model(&models.JournalLine{}).
+		Select(`journals.branch_id, journal_lines.account_id, journal_lines.fund_id, journal_lines.program_id,
+			COALESCE(SUM(journal_lines.debit), 0) AS debit,
+			COALESCE(SUM(journal_lines.credit), 0) AS credit`).
+		Joins("JOIN journals ON journals.id = journal_lines.journal_id AND journals.deleted_at IS NULL").
+		Joins("JOIN accounts ON accounts.id = journal_lines.account_id").
+		Where("journals.is_posted = ?", true).
+		Where("journals.journal_date BETWEEN ? AND ?", start, end)
+
+	if branchID != nil {
+		query = query.Where("journals.branch_id = ?", *branchID)
+	}
+	if len(categories) > 0 {
+		query = query.Where("accounts.category IN ?", categories)
+	}
+
+	err := query.
+		Group("journals.branch_id, journal_lines.account_id, journal_lines.fund_id, journal_lines.program_id").
+		Order("journals.branch_id, journal_lines.account_id").
+		Scan(&movements).Error
+	return movements, err
+}
+
 func (r *journalRepository) Create(journal *models.Journal) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
-		// Create journal
-		if err := tx.Create(journal).Error; err != nil {
-			return err
-		}
+		return createJournalTx(tx, journal)
+	})
+}
 
-		// Calculate totals
-		var totalDebit, totalCredit float64
-		for _, line := range journal.JournalLines {
-			totalDebit += line.Debit
-			totalCredit += line.Credit
+// CreateBatch creates several journals in one transaction, so either all or none are saved
+func (r *journalRepository) CreateBatch(journals []*models.Journal) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, journal := range journals {
+			if err := createJournalTx(tx, journal); err != nil {
+				return err
+			}
 		}
+		return nil
+	})
+}
 
-		// Update journal totals
-		journal.TotalDebit = totalDebit
-		journal.TotalCredit = totalCredit
+// createJournalTx creates a journal with its lines inside an existing transaction, numbering
+// it when it has no number yet
+func createJournalTx(tx *gorm.DB, journal *models.Journal) error {
+	if err := assignDocumentNumberTx(tx, &journal.JournalNumber, models.DocumentTypeJournal, "", journal.BranchID, journal.JournalDate); err != nil {
+		return err
+	}
 
-		return tx.Model(journal).Updates(map[string]interface{}{
-			"total_debit":  totalDebit,
-			"total_credit": totalCredit,
-		}).Error
-	})
+	// Create journal
+	if err := tx.Create(journal).Error; err != nil {
+		return err
+	}
+
+	// Calculate totals
+	var totalDebit, totalCredit models.Money
+	for _, line := range journal.JournalLines {
+		totalDebit += line.Debit
+		totalCredit += line.Credit
+	}
+
+	// Update journal totals
+	journal.TotalDebit = totalDebit
+	journal.TotalCredit = totalCredit
+
+	if err := tx.Model(journal).Updates(map[string]interface{}{
+		"total_debit":  totalDebit,
+		"total_credit": totalCredit,
+	}).Error; err != nil {
+		return err
+	}
+
+	// Journals created already posted go straight into the account balances
+	if journal.IsPosted {
+		return applyJournalBalancesTx(tx, journal)
+	}
+	return nil
 }
 
 func (r *journalRepository) Update(journal *models.Journal) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Posted journals are immutable
+		if err := ensureNotPostedTx(tx, journal.ID); err != nil {
+			return err
+		}
+
 		// Delete existing lines
 		if err := tx.Where("journal_id = ?", journal.ID).Delete(&models.JournalLine{}).Error; err != nil {
 			return err
@@ -210,7 +495,7 @@ func (r *journalRepository) Update(journal *models.Journal) error {
 		}
 
 		// Calculate totals
-		var totalDebit, totalCredit float64
+		var totalDebit, totalCredit models.Money
 		for _, line := range journal.JournalLines {
 			totalDebit += line.Debit
 			totalCredit += line.Credit
@@ -227,8 +512,205 @@ func (r *journalRepository) Update(journal *models.Journal) error {
 	})
 }
 
+// SubmitForReview starts the approval of a journal and puts it in review in one transaction.
+// Journals already in review without a pending approval are resubmitted as they are.
+func (r *journalRepository) SubmitForReview(journal *models.Journal, request *models.ApprovalRequest, history *models.ApprovalHistory) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Journal{}).
+			Where("id = ? AND status IN ?", journal.ID, []string{models.JournalStatusDraft, models.JournalStatusReview}).
+			Update("status", models.JournalStatusReview)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("journal was updated by someone else, please reload")
+		}
+
+		return createApprovalRequestTx(tx, request, history)
+	})
+}
+
+// RecordReview records an approval step and, once the approval is complete, the journal's
+// approval or rejection in one transaction
+func (r *journalRepository) RecordReview(journal *models.Journal, request *models.ApprovalRequest, history *models.ApprovalHistory) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := recordApprovalActionTx(tx, request, history); err != nil {
+			return err
+		}
+		if request.Status == models.ApprovalStatusPending {
+			return nil
+		}
+
+		result := tx.Model(&models.Journal{}).
+			Where("id = ? AND status = ?", journal.ID, models.JournalStatusReview).
+			Updates(map[string]interface{}{
+				"status":        journal.Status,
+				"approved_by":   journal.ApprovedBy,
+				"approved_at":   journal.ApprovedAt,
+				"rejected_by":   journal.RejectedBy,
+				"rejected_at":   journal.RejectedAt,
+				"reject_reason": journal.RejectReason,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("journal was updated by someone else, please reload")
+		}
+		return nil
+	})
+}
+
+// Post marks the journal as posted and adds its lines to the account balances
+func (r *journalRepository) Post(journal *models.Journal) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := markJournalPostedTx(tx, journal); err != nil {
+			return err
+		}
+
+		return applyJournalBalancesTx(tx, journal)
+	})
+}
+
+// PostInterBranch posts an inter-branch journal together with the journals generated for the
+// other branches. Lines that already exist are moved to the journal they are listed under,
+// new due to/due from lines are created.
+func (r *journalRepository) PostInterBranch(journal *models.Journal, branchJournals []models.Journal) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := markJournalPostedTx(tx, journal); err != nil {
+			return err
+		}
+
+		for i := range branchJournals {
+			branchJournal := &branchJournals[i]
+			if err := assignDocumentNumberTx(tx, &branchJournal.JournalNumber, models.DocumentTypeJournal, "", branchJournal.BranchID, branchJournal.JournalDate); err != nil {
+				return err
+			}
+			if err := tx.Omit("JournalLines").Create(branchJournal).Error; err != nil {
+				return err
+			}
+			if err := saveJournalLinesTx(tx, branchJournal); err != nil {
+				return err
+			}
+			if err := applyJournalBalancesTx(tx, branchJournal); err != nil {
+				return err
+			}
+		}
+
+		if err := saveJournalLinesTx(tx, journal); err != nil {
+			return err
+		}
+
+		return applyJournalBalancesTx(tx, journal)
+	})
+}
+
+// markJournalPostedTx marks a journal as posted only if it is not posted yet, so concurrent posts
+// of the same journal cannot both apply its balances
+func markJournalPostedTx(tx *gorm.DB, journal *models.Journal) error {
+	result := tx.Model(&models.Journal{}).
+		Where("id = ? AND is_posted = ?", journal.ID, false).
+		Updates(map[string]interface{}{
+			"status":    journal.Status,
+			"is_posted": true,
+			"posted_at": journal.PostedAt,
+			"posted_by": journal.PostedBy,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("journal already posted")
+	}
+	return nil
+}
+
+// saveJournalLinesTx attaches a journal's lines to it, creating new lines and moving existing
+// ones, then updates the journal totals
+func saveJournalLinesTx(tx *gorm.DB, journal *models.Journal) error {
+	var totalDebit, totalCredit models.Money
+	for i := range journal.JournalLines {
+		line := &journal.JournalLines[i]
+		line.JournalID = journal.ID
+		totalDebit += line.Debit
+		totalCredit += line.Credit
+
+		if line.ID == uuid.Nil {
+			if err := tx.Create(line).Error; err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := tx.Model(&models.JournalLine{}).
+			Where("id = ?", line.ID).
+			Updates(map[string]interface{}{
+				"journal_id": journal.ID,
+				"branch_id":  line.BranchID,
+			}).Error; err != nil {
+			return err
+		}
+	}
+
+	journal.TotalDebit = totalDebit
+	journal.TotalCredit = totalCredit
+
+	return tx.Model(journal).Updates(map[string]interface{}{
+		"total_debit":  totalDebit,
+		"total_credit": totalCredit,
+	}).Error
+}
+
+// CreateReversal creates a posted reversing journal and links it to the original in one transaction
+func (r *journalRepository) CreateReversal(original *models.Journal, reversal *models.Journal) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return createReversalTx(tx, original, reversal)
+	})
+}
+
+// CreateReversals reverses several journals in one transaction, pairing originals and reversals by index
+func (r *journalRepository) CreateReversals(originals []*models.Journal, reversals []*models.Journal) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for i := range originals {
+			if err := createReversalTx(tx, originals[i], reversals[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// createReversalTx creates a reversing journal and links the original inside an existing transaction
+func createReversalTx(tx *gorm.DB, original *models.Journal, reversal *models.Journal) error {
+	if original.Type == models.JournalTypeOpening {
+		if err := removeOpeningInvoicesTx(tx, original); err != nil {
+			return err
+		}
+	}
+
+	if err := createJournalTx(tx, reversal); err != nil {
+		return err
+	}
+
+	result := tx.Model(&models.Journal{}).
+		Where("id = ? AND is_posted = ? AND reversed_by_journal_id IS NULL", original.ID, true).
+		Update("reversed_by_journal_id", reversal.ID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("journal " + original.JournalNumber + " is already reversed")
+	}
+	return nil
+}
+
 func (r *journalRepository) Delete(id uuid.UUID) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Posted journals are immutable
+		if err := ensureNotPostedTx(tx, id); err != nil {
+			return err
+		}
+
 		// Delete journal lines first
 		if err := tx.Where("journal_id = ?", id).Delete(&models.JournalLine{}).Error; err != nil {
 			return err
@@ -239,31 +721,16 @@ func (r *journalRepository) Delete(id uuid.UUID) error {
 	})
 }
 
-func (r *journalRepository) GenerateJournalNumber(branchCode string, date time.Time) (string, error) {
-	// Format: JE/BRANCH/YYYYMM/XXXX
-	// Example: JE/YAY/202411/0001
-	
-	yearMonth := date.Format("200601")
-	prefix := "JE/" + branchCode + "/" + yearMonth + "/"
-
-	// Get last number for this month
-	var lastJournal models.Journal
-	err := r.db.
-		Where("journal_number LIKE ?", prefix+"%").
-		Order("journal_number DESC").
-		First(&lastJournal).Error
-
-	var sequence int
-	if err == nil {
-		// Extract sequence from last number
-		// Simple increment for now
-		sequence = 1
-	} else {
-		sequence = 1
+// ensureNotPostedTx rejects changes to a journal that is already posted
+func ensureNotPostedTx(tx *gorm.DB, id uuid.UUID) error {
+	var count int64
+	if err := tx.Model(&models.Journal{}).
+		Where("id = ? AND is_posted = ?", id, true).
+		Count(&count).Error; err != nil {
+		return err
 	}
-
-	// Generate number
-	journalNumber := prefix + fmt.Sprintf("%04d", sequence)
-	
-	return journalNumber, nil
+	if count > 0 {
+		return errors.New("posted journal cannot be modified")
+	}
+	return nil
 }
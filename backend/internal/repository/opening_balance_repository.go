@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/yayasan/erp-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OpeningBalanceRepository interface {
+	GetOpeningJournal(branchID uuid.UUID) (*models.Journal, error)
+	GetCurrentAcademicYear() (*models.AcademicYear, error)
+	Create(journals []*models.Journal, invoices []*models.Invoice) error
+}
+
+type openingBalanceRepository struct {
+	db *gorm.DB
+}
+
+func NewOpeningBalanceRepository(db *gorm.DB) OpeningBalanceRepository {
+	return &openingBalanceRepository{db: db}
+}
+
+// GetOpeningJournal returns the opening journal of a branch that has not been reversed, or nil
+// when there is none
+func (r *openingBalanceRepository) GetOpeningJournal(branchID uuid.UUID) (*models.Journal, error) {
+	return openingJournalTx(r.db, branchID)
+}
+
+func openingJournalTx(tx *gorm.DB, branchID uuid.UUID) (*models.Journal, error) {
+	var journal models.Journal
+	err := tx.
+		Where("branch_id = ? AND type = ? AND is_posted = ?", branchID, models.JournalTypeOpening, true).
+		Where("reversal_of_id IS NULL AND reversed_by_journal_id IS NULL").
+		Order("journal_date DESC").
+		First(&journal).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &journal, nil
+}
+
+// GetCurrentAcademicYear returns the academic year marked as current, or nil when there is none
+func (r *openingBalanceRepository) GetCurrentAcademicYear() (*models.AcademicYear, error) {
+	var academicYear models.AcademicYear
+	err := r.db.Where("is_current = ?", true).First(&academicYear).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &academicYear, nil
+}
+
+// Create posts the opening journals and saves the opening invoices of students in one
+// transaction, so a failure leaves no partial opening balances. Each invoice is linked to the
+// opening journal of its branch.
+func (r *openingBalanceRepository) Create(journals []*models.Journal, invoices []*models.Invoice) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		journalIDs := make(map[uuid.UUID]uuid.UUID, len(journals))
+		for _, journal := range journals {
+			// Lock the branch, so concurrent imports cannot both load its opening balances
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Select("id").
+				First(&models.Branch{}, "id = ?", journal.BranchID).Error; err != nil {
+				return err
+			}
+
+			existing, err := openingJournalTx(tx, journal.BranchID)
+			if err != nil {
+				return err
+			}
+			if existing != nil {
+				return errors.New("branch already has opening balances in journal " + existing.JournalNumber)
+			}
+
+			if err := createJournalTx(tx, journal); err != nil {
+				return err
+			}
+			journalIDs[journal.BranchID] = journal.ID
+		}
+
+		for _, invoice := range invoices {
+			journalID, exists := journalIDs[invoice.BranchID]
+			if !exists {
+				return errors.New("opening invoice " + invoice.Description + " has no opening journal")
+			}
+			invoice.OpeningJournalID = &journalID
+
+			if err := createInvoiceTx(tx, invoice); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// removeOpeningInvoicesTx deletes the opening invoices loaded with an opening journal that is
+// being reversed, so the student receivables keep matching the ledger. Invoices that already
+// have payments block the reversal.
+func removeOpeningInvoicesTx(tx *gorm.DB, journal *models.Journal) error {
+	var paid int64
+	if err := tx.Model(&models.Payment{}).
+		Where("invoice_id IN (?)", tx.Model(&models.Invoice{}).Select("id").Where("opening_journal_id = ?", journal.ID)).
+		Count(&paid).Error; err != nil {
+		return err
+	}
+	if paid > 0 {
+		return errors.New("opening invoices of journal " + journal.JournalNumber + " already have payments")
+	}
+
+	openingInvoices := tx.Model(&models.Invoice{}).Select("id").Where("opening_journal_id = ?", journal.ID)
+	if err := tx.Where("invoice_id IN (?)", openingInvoices).Delete(&models.InvoiceItem{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("opening_journal_id = ?", journal.ID).Delete(&models.Invoice{}).Error
+}
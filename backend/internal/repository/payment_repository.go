@@ -2,7 +2,6 @@ package repository
 
 import (
 	"errors"
-	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -17,8 +16,8 @@ type PaymentRepository interface {
 	GetByDateRange(start, end time.Time) ([]models.Payment, error)
 	Create(payment *models.Payment) error
 	Update(payment *models.Payment) error
+	PostWithJournal(payment *models.Payment, journal *models.Journal) error
 	Delete(id uuid.UUID) error
-	GeneratePaymentNumber(branchCode string, date time.Time) (string, error)
 }
 
 type paymentRepository struct {
@@ -57,8 +56,10 @@ func (r *paymentRepository) GetByID(id uuid.UUID) (*models.Payment, error) {
 	err := r.db.
 		Preload("Invoice").
 		Preload("Invoice.Items").
+		Preload("Invoice.Items.FeeStructure").
 		Preload("Student").
 		Preload("Branch").
+		Preload("Journal").
 		First(&payment, "id = ?", id).Error
 	
 	if err != nil {
@@ -93,6 +94,10 @@ func (r *paymentRepository) GetByDateRange(start, end time.Time) ([]models.Payme
 
 func (r *paymentRepository) Create(payment *models.Payment) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := assignDocumentNumberTx(tx, &payment.PaymentNumber, models.DocumentTypePayment, "", payment.BranchID, payment.PaymentDate); err != nil {
+			return err
+		}
+
 		// Create payment
 		if err := tx.Create(payment).Error; err != nil {
 			return err
@@ -125,6 +130,32 @@ func (r *paymentRepository) Update(payment *models.Payment) error {
 	return r.db.Save(payment).Error
 }
 
+// PostWithJournal creates the payment journal and marks the payment as posted in one transaction
+func (r *paymentRepository) PostWithJournal(payment *models.Payment, journal *models.Journal) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := createJournalTx(tx, journal); err != nil {
+			return err
+		}
+
+		payment.JournalID = &journal.ID
+
+		result := tx.Model(&models.Payment{}).
+			Where("id = ? AND is_posted = ?", payment.ID, false).
+			Updates(map[string]interface{}{
+				"is_posted":  true,
+				"posted_at":  payment.PostedAt,
+				"journal_id": journal.ID,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("payment is already posted")
+		}
+		return nil
+	})
+}
+
 func (r *paymentRepository) Delete(id uuid.UUID) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
 		// Get payment
@@ -163,26 +194,3 @@ func (r *paymentRepository) Delete(id uuid.UUID) error {
 		return tx.Delete(&models.Payment{}, "id = ?", id).Error
 	})
 }
-
-func (r *paymentRepository) GeneratePaymentNumber(branchCode string, date time.Time) (string, error) {
-	// Format: PAY/BranchCode/YYYYMM/XXXX
-	yearMonth := date.Format("200601")
-	prefix := fmt.Sprintf("PAY/%s/%s/", branchCode, yearMonth)
-
-	var lastPayment models.Payment
-	err := r.db.
-		Where("payment_number LIKE ?", prefix+"%").
-		Order("payment_number DESC").
-		First(&lastPayment).Error
-
-	var sequence int
-	if err == nil {
-		var lastSeq int
-		fmt.Sscanf(lastPayment.PaymentNumber[len(prefix):], "%d", &lastSeq)
-		sequence = lastSeq + 1
-	} else {
-		sequence = 1
-	}
-
-	return fmt.Sprintf("%s%04d", prefix, sequence), nil
-}
@@ -2,7 +2,6 @@ package repository
 
 import (
 	"errors"
-	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,10 +14,11 @@ type PayrollRepository interface {
 	GetByID(id uuid.UUID) (*models.Payroll, error)
 	GetByPeriod(period string) ([]models.Payroll, error)
 	GetByEmployee(employeeID uuid.UUID) ([]models.Payroll, error)
+	GetPendingByBranchPeriod(branchID uuid.UUID, period string) ([]models.Payroll, error)
 	Create(payroll *models.Payroll) error
 	Update(payroll *models.Payroll) error
+	ProcessWithJournal(payrollIDs []uuid.UUID, journal *models.Journal, paidAt time.Time) error
 	Delete(id uuid.UUID) error
-	GeneratePayrollNumber(branchCode string, date time.Time) (string, error)
 }
 
 type payrollRepository struct {
@@ -92,14 +92,54 @@ func (r *payrollRepository) GetByEmployee(employeeID uuid.UUID) ([]models.Payrol
 	return payrolls, err
 }
 
+func (r *payrollRepository) GetPendingByBranchPeriod(branchID uuid.UUID, period string) ([]models.Payroll, error) {
+	var payrolls []models.Payroll
+	err := r.db.
+		Where("branch_id = ? AND period = ? AND payment_status = ?", branchID, period, models.PayrollStatusPending).
+		Preload("Employee").
+		Order("payroll_number ASC").
+		Find(&payrolls).Error
+	return payrolls, err
+}
+
 func (r *payrollRepository) Create(payroll *models.Payroll) error {
-	return r.db.Create(payroll).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := assignDocumentNumberTx(tx, &payroll.PayrollNumber, models.DocumentTypePayroll, "", payroll.BranchID, payroll.PaymentDate); err != nil {
+			return err
+		}
+		return tx.Create(payroll).Error
+	})
 }
 
 func (r *payrollRepository) Update(payroll *models.Payroll) error {
 	return r.db.Save(payroll).Error
 }
 
+// ProcessWithJournal creates the payroll journal and marks the payrolls as paid and posted in one transaction
+func (r *payrollRepository) ProcessWithJournal(payrollIDs []uuid.UUID, journal *models.Journal, paidAt time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := createJournalTx(tx, journal); err != nil {
+			return err
+		}
+
+		result := tx.Model(&models.Payroll{}).
+			Where("id IN ? AND payment_status <> ? AND is_posted = ?", payrollIDs, models.PayrollStatusPaid, false).
+			Updates(map[string]interface{}{
+				"payment_status": models.PayrollStatusPaid,
+				"paid_at":        paidAt,
+				"is_posted":      true,
+				"journal_id":     journal.ID,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected != int64(len(payrollIDs)) {
+			return errors.New("payroll already paid or posted")
+		}
+		return nil
+	})
+}
+
 func (r *payrollRepository) Delete(id uuid.UUID) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
 		if err := tx.Where("payroll_id = ?", id).Delete(&models.PayrollComponent{}).Error; err != nil {
@@ -108,26 +148,3 @@ func (r *payrollRepository) Delete(id uuid.UUID) error {
 		return tx.Delete(&models.Payroll{}, "id = ?", id).Error
 	})
 }
-
-func (r *payrollRepository) GeneratePayrollNumber(branchCode string, date time.Time) (string, error) {
-	// Format: PAY/BRANCH/YYYYMM/XXXX
-	yearMonth := date.Format("200601")
-	prefix := fmt.Sprintf("PAY/%s/%s/", branchCode, yearMonth)
-
-	var lastPayroll models.Payroll
-	err := r.db.
-		Where("payroll_number LIKE ?", prefix+"%").
-		Order("payroll_number DESC").
-		First(&lastPayroll).Error
-
-	var sequence int
-	if err == nil {
-		var lastSeq int
-		fmt.Sscanf(lastPayroll.PayrollNumber[len(prefix):], "%d", &lastSeq)
-		sequence = lastSeq + 1
-	} else {
-		sequence = 1
-	}
-
-	return fmt.Sprintf("%s%04d", prefix, sequence), nil
-}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/yayasan/erp-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type ProgramRepository interface {
+	GetAll() ([]models.Program, error)
+	GetAllActive() ([]models.Program, error)
+	GetByID(id uuid.UUID) (*models.Program, error)
+	GetByCode(code string) (*models.Program, error)
+	IsUsed(id uuid.UUID) (bool, error)
+	Create(program *models.Program) error
+	Update(program *models.Program) error
+	Delete(id uuid.UUID) error
+}
+
+type programRepository struct {
+	db *gorm.DB
+}
+
+func NewProgramRepository(db *gorm.DB) ProgramRepository {
+	return &programRepository{db: db}
+}
+
+func (r *programRepository) GetAll() ([]models.Program, error) {
+	var programs []models.Program
+	err := r.db.Order("code ASC").Find(&programs).Error
+	return programs, err
+}
+
+func (r *programRepository) GetAllActive() ([]models.Program, error) {
+	var programs []models.Program
+	err := r.db.Where("is_active = ?", true).Order("code ASC").Find(&programs).Error
+	return programs, err
+}
+
+func (r *programRepository) GetByID(id uuid.UUID) (*models.Program, error) {
+	var program models.Program
+	err := r.db.First(&program, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("program not found")
+		}
+		return nil, err
+	}
+	return &program, nil
+}
+
+func (r *programRepository) GetByCode(code string) (*models.Program, error) {
+	var program models.Program
+	err := r.db.First(&program, "code = ?", code).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("program not found")
+		}
+		return nil, err
+	}
+	return &program, nil
+}
+
+// IsUsed checks whether any journal line or budget references the program
+func (r *programRepository) IsUsed(id uuid.UUID) (bool, error) {
+	var count int64
+	if err := r.db.Model(&models.JournalLine{}).Where("program_id = ?", id).Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return true, nil
+	}
+
+	err := r.db.Model(&models.Budget{}).Where("program_id = ?", id).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *programRepository) Create(program *models.Program) error {
+	return r.db.Create(program).Error
+}
+
+func (r *programRepository) Update(program *models.Program) error {
+	return r.db.Save(program).Error
+}
+
+func (r *programRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&models.Program{}, "id = ?", id).Error
+}
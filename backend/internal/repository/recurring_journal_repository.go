@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yayasan/erp-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RecurringJournalRepository interface {
+	GetAll(status string) ([]models.RecurringJournal, error)
+	GetByID(id uuid.UUID) (*models.RecurringJournal, error)
+	GetDue(before time.Time) ([]models.RecurringJournal, error)
+	Create(recurring *models.RecurringJournal) error
+	Update(recurring *models.RecurringJournal) error
+	UpdateSchedule(recurring *models.RecurringJournal) error
+	Delete(id uuid.UUID) error
+	GetRuns(recurringID uuid.UUID) ([]models.RecurringJournalRun, error)
+	ClaimRun(run *models.RecurringJournalRun) (bool, error)
+	SaveRun(run *models.RecurringJournalRun) error
+	SaveRunJournal(run *models.RecurringJournalRun, journal *models.Journal) error
+}
+
+// staleRunTimeout is how long a run may stay processing before another generator takes it
+// over; generating one journal takes seconds, so such a run was interrupted
+const staleRunTimeout = 30 * time.Minute
+
+type recurringJournalRepository struct {
+	db *gorm.DB
+}
+
+func NewRecurringJournalRepository(db *gorm.DB) RecurringJournalRepository {
+	return &recurringJournalRepository{db: db}
+}
+
+func (r *recurringJournalRepository) GetAll(status string) ([]models.RecurringJournal, error) {
+	var recurring []models.RecurringJournal
+	query := r.db.Preload("Branch")
+
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	err := query.Order("name ASC").Find(&recurring).Error
+	return recurring, err
+}
+
+func (r *recurringJournalRepository) GetByID(id uuid.UUID) (*models.RecurringJournal, error) {
+	var recurring models.RecurringJournal
+	err := r.db.
+		Preload("Branch").
+		Preload("Lines", func(db *gorm.DB) *gorm.DB {
+			return db.Order("line_no ASC")
+		}).
+		Preload("Lines.Account").
+		First(&recurring, "id = ?", id).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("recurring journal not found")
+		}
+		return nil, err
+	}
+	return &recurring, nil
+}
+
+// GetDue returns active recurring journals with a due date before the given time
+func (r *recurringJournalRepository) GetDue(before time.Time) ([]models.RecurringJournal, error) {
+	var recurring []models.RecurringJournal
+	err := r.db.
+		Preload("Lines", func(db *gorm.DB) *gorm.DB {
+			return db.Order("line_no ASC")
+		}).
+		Where("status = ? AND next_run_date < ?", models.RecurringStatusActive, before).
+		Order("next_run_date ASC").
+		Find(&recurring).Error
+	return recurring, err
+}
+
+func (r *recurringJournalRepository) Create(recurring *models.RecurringJournal) error {
+	return r.db.Create(recurring).Error
+}
+
+// Update saves the recurring journal and replaces its lines
+func (r *recurringJournalRepository) Update(recurring *models.RecurringJournal) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("recurring_journal_id = ?", recurring.ID).Delete(&models.RecurringJournalLine{}).Error; err != nil {
+			return err
+		}
+
+		for i := range recurring.Lines {
+			recurring.Lines[i].ID = uuid.Nil
+			recurring.Lines[i].RecurringJournalID = recurring.ID
+		}
+
+		return tx.Omit("Branch").Save(recurring).Error
+	})
+}
+
+// UpdateSchedule saves only the schedule fields, leaving the template untouched
+func (r *recurringJournalRepository) UpdateSchedule(recurring *models.RecurringJournal) error {
+	return r.db.Model(&models.RecurringJournal{}).
+		Where("id = ?", recurring.ID).
+		Updates(map[string]interface{}{
+			"next_run_date": recurring.NextRunDate,
+			"occurrence":    recurring.Occurrence,
+			"status":        recurring.Status,
+		}).Error
+}
+
+func (r *recurringJournalRepository) Delete(id uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("recurring_journal_id = ?", id).Delete(&models.RecurringJournalLine{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.RecurringJournal{}, "id = ?", id).Error
+	})
+}
+
+func (r *recurringJournalRepository) GetRuns(recurringID uuid.UUID) ([]models.RecurringJournalRun, error) {
+	var runs []models.RecurringJournalRun
+	err := r.db.
+		Preload("Journal").
+		Where("recurring_journal_id = ?", recurringID).
+		Order("scheduled_date DESC").
+		Find(&runs).Error
+	return runs, err
+}
+
+// ClaimRun records a run for a due date unless one already exists. A failed run, or one left
+// processing for longer than staleRunTimeout, is taken over so it can be retried. When the date
+// is already claimed, run is loaded with the existing record and false is returned. The unique
+// index on the due date makes this safe when several generators run at once.
+func (r *recurringJournalRepository) ClaimRun(run *models.RecurringJournalRun) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "recurring_journal_id"}, {Name: "scheduled_date"}},
+		DoNothing: true,
+	}).Create(run)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 1 {
+		return true, nil
+	}
+
+	result = r.db.Model(&models.RecurringJournalRun{}).
+		Where("recurring_journal_id = ? AND scheduled_date = ?", run.RecurringJournalID, run.ScheduledDate).
+		Where("status = ? OR (status = ? AND updated_at < ?)",
+			models.RecurringRunFailed, models.RecurringRunProcessing, time.Now().Add(-staleRunTimeout)).
+		Updates(map[string]interface{}{
+			"status":       run.Status,
+			"message":      run.Message,
+			"processed_by": run.ProcessedBy,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	claimed := result.RowsAffected == 1
+
+	var existing models.RecurringJournalRun
+	err := r.db.Where("recurring_journal_id = ? AND scheduled_date = ?", run.RecurringJournalID, run.ScheduledDate).
+		First(&existing).Error
+	if err != nil {
+		return false, err
+	}
+	if claimed {
+		run.ID = existing.ID
+		run.CreatedAt = existing.CreatedAt
+		return true, nil
+	}
+
+	*run = existing
+	return false, nil
+}
+
+func (r *recurringJournalRepository) SaveRun(run *models.RecurringJournalRun) error {
+	return r.db.Omit("Journal").Save(run).Error
+}
+
+// SaveRunJournal creates the journal of a run and saves the run in one transaction, so a run
+// never stays processing once its journal exists
+func (r *recurringJournalRepository) SaveRunJournal(run *models.RecurringJournalRun, journal *models.Journal) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := createJournalTx(tx, journal); err != nil {
+			return err
+		}
+
+		run.JournalID = &journal.ID
+		return tx.Omit("Journal").Save(run).Error
+	})
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/yayasan/erp-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type ReportTemplateRepository interface {
+	GetAll(activeOnly bool) ([]models.ReportTemplate, error)
+	GetByID(id uuid.UUID) (*models.ReportTemplate, error)
+	GetByCode(code string) (*models.ReportTemplate, error)
+	Create(template *models.ReportTemplate) error
+	Update(template *models.ReportTemplate) error
+	Delete(id uuid.UUID) error
+}
+
+type reportTemplateRepository struct {
+	db *gorm.DB
+}
+
+func NewReportTemplateRepository(db *gorm.DB) ReportTemplateRepository {
+	return &reportTemplateRepository{db: db}
+}
+
+func (r *reportTemplateRepository) GetAll(activeOnly bool) ([]models.ReportTemplate, error) {
+	var templates []models.ReportTemplate
+	query := r.db.Order("code ASC")
+	if activeOnly {
+		query = query.Where("is_active = ?", true)
+	}
+	err := query.Find(&templates).Error
+	return templates, err
+}
+
+func (r *reportTemplateRepository) GetByID(id uuid.UUID) (*models.ReportTemplate, error) {
+	var template models.ReportTemplate
+	err := r.db.First(&template, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("report template not found")
+		}
+		return nil, err
+	}
+	return &template, nil
+}
+
+func (r *reportTemplateRepository) GetByCode(code string) (*models.ReportTemplate, error) {
+	var template models.ReportTemplate
+	err := r.db.First(&template, "code = ?", code).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("report template not found")
+		}
+		return nil, err
+	}
+	return &template, nil
+}
+
+func (r *reportTemplateRepository) Create(template *models.ReportTemplate) error {
+	return r.db.Create(template).Error
+}
+
+func (r *reportTemplateRepository) Update(template *models.ReportTemplate) error {
+	return r.db.Save(template).Error
+}
+
+func (r *reportTemplateRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&models.ReportTemplate{}, "id = ?", id).Error
+}
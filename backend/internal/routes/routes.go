@@ -20,6 +20,18 @@ type Router struct {
 	employeeHandler  *handler.EmployeeHandler
 	assetHandler     *handler.AssetHandler
 	inventoryHandler *handler.InventoryHandler
+	mappingHandler   *handler.AccountMappingHandler
+	periodHandler    *handler.PeriodHandler
+	fiscalYearHandler *handler.FiscalYearHandler
+	fundHandler      *handler.FundHandler
+	bankReconciliationHandler *handler.BankReconciliationHandler
+	reportTemplateHandler *handler.ReportTemplateHandler
+	recurringJournalHandler *handler.RecurringJournalHandler
+	approvalHandler *handler.ApprovalHandler
+	attachmentHandler *handler.AttachmentHandler
+	exchangeRateHandler *handler.ExchangeRateHandler
+	documentNumberHandler *handler.DocumentNumberHandler
+	openingBalanceHandler *handler.OpeningBalanceHandler
 }
 
 func NewRouter(
@@ -36,6 +48,18 @@ func NewRouter(
 	employeeHandler *handler.EmployeeHandler,
 	assetHandler *handler.AssetHandler,
 	inventoryHandler *handler.InventoryHandler,
+	mappingHandler *handler.AccountMappingHandler,
+	periodHandler *handler.PeriodHandler,
+	fiscalYearHandler *handler.FiscalYearHandler,
+	fundHandler *handler.FundHandler,
+	bankReconciliationHandler *handler.BankReconciliationHandler,
+	reportTemplateHandler *handler.ReportTemplateHandler,
+	recurringJournalHandler *handler.RecurringJournalHandler,
+	approvalHandler *handler.ApprovalHandler,
+	attachmentHandler *handler.AttachmentHandler,
+	exchangeRateHandler *handler.ExchangeRateHandler,
+	documentNumberHandler *handler.DocumentNumberHandler,
+	openingBalanceHandler *handler.OpeningBalanceHandler,
 ) *Router {
 	return &Router{
 		authHandler:      authHandler,
@@ -51,6 +75,18 @@ func NewRouter(
 		employeeHandler:  employeeHandler,
 		assetHandler:     assetHandler,
 		inventoryHandler: inventoryHandler,
+		mappingHandler:   mappingHandler,
+		periodHandler:    periodHandler,
+		fiscalYearHandler: fiscalYearHandler,
+		fundHandler:      fundHandler,
+		bankReconciliationHandler: bankReconciliationHandler,
+		reportTemplateHandler: reportTemplateHandler,
+		recurringJournalHandler: recurringJournalHandler,
+		approvalHandler: approvalHandler,
+		attachmentHandler: attachmentHandler,
+		exchangeRateHandler: exchangeRateHandler,
+		documentNumberHandler: documentNumberHandler,
+		openingBalanceHandler: openingBalanceHandler,
 	}
 }
 
@@ -147,22 +183,166 @@ func (r *Router) Setup(router *gin.Engine) {
 				accounts.DELETE("/:id", middleware.RequirePermission("accounts.delete"), r.accountHandler.Delete) // DIPERBAIKI
 			}
 
+			// Account mapping endpoints (accounts used by automatic journals)
+			mappings := protected.Group("/account-mappings")
+			mappings.Use(middleware.RequirePermission("accounts.view"))
+			{
+				mappings.GET("", r.mappingHandler.GetAll)
+				mappings.GET("/:id", r.mappingHandler.GetByID)
+
+				mappings.POST("", middleware.RequirePermission("accounts.update"), r.mappingHandler.Save)
+				mappings.DELETE("/:id", middleware.RequirePermission("accounts.update"), r.mappingHandler.Delete)
+			}
+
+			// Document number format endpoints
+			documentNumbers := protected.Group("/document-numbers")
+			documentNumbers.Use(middleware.RequirePermission("document_numbers.view"))
+			{
+				documentNumbers.GET("/formats", r.documentNumberHandler.GetFormats)
+				documentNumbers.GET("/formats/:id", r.documentNumberHandler.GetFormatByID)
+				documentNumbers.GET("/preview", r.documentNumberHandler.Preview)
+
+				documentNumbers.POST("/formats", middleware.RequirePermission("document_numbers.manage"), r.documentNumberHandler.SaveFormat)
+				documentNumbers.DELETE("/formats/:id", middleware.RequirePermission("document_numbers.manage"), r.documentNumberHandler.DeleteFormat)
+			}
+
 			// Journal Entry endpoints
 			journals := protected.Group("/journals")
 			journals.Use(middleware.RequirePermission("journals.view")) // DIPERBAIKI
 			{
-				journals.GET("", r.journalHandler.GetAll)
+				journals.GET("", r.journalHandler.Search)
+				journals.GET("/lines", r.journalHandler.SearchLines)
 				journals.GET("/status/:status", r.journalHandler.GetByStatus)
 				journals.GET("/:id", r.journalHandler.GetByID)
+				journals.GET("/:id/approvals", r.journalHandler.GetApprovals)
 
 				journals.POST("", middleware.RequirePermission("journals.create"), r.journalHandler.Create) // DIPERBAIKI
+				journals.POST("/import", middleware.RequirePermission("journals.create"), r.journalHandler.Import)
 				journals.PUT("/:id", middleware.RequirePermission("journals.update"), r.journalHandler.Update) // DIPERBAIKI
 				journals.DELETE("/:id", middleware.RequirePermission("journals.delete"), r.journalHandler.Delete) // DIPERBAIKI
 
 				journals.POST("/:id/submit", middleware.RequirePermission("journals.submit"), r.journalHandler.SubmitForReview) // DIPERBAIKI
 				journals.POST("/:id/review", middleware.RequirePermission("journals.review"), r.journalHandler.Review) // DIPERBAIKI
 				journals.POST("/:id/post", middleware.RequirePermission("journals.post"), r.journalHandler.Post)     // DIPERBAIKI
-				journals.POST("/:id/unpost", middleware.RequirePermission("journals.post"), r.journalHandler.Unpost) // DIPERBAIKI
+				journals.POST("/:id/reverse", middleware.RequirePermission("journals.reverse"), r.journalHandler.Reverse)
+
+				journals.GET("/:id/attachments", r.attachmentHandler.GetJournalAttachments)
+				journals.GET("/:id/attachments/:attachmentId/download", r.attachmentHandler.DownloadJournalAttachment)
+				journals.POST("/:id/attachments", middleware.RequirePermission("journals.attach"), r.attachmentHandler.UploadJournalAttachment)
+				journals.DELETE("/:id/attachments/:attachmentId", middleware.RequirePermission("journals.attach"), r.attachmentHandler.DeleteJournalAttachment)
+			}
+
+			// Approval chain endpoints
+			approvalChains := protected.Group("/approval-chains")
+			approvalChains.Use(middleware.RequirePermission("approval_chains.view"))
+			{
+				approvalChains.GET("", r.approvalHandler.GetChains)
+				approvalChains.GET("/:id", r.approvalHandler.GetChainByID)
+
+				approvalChains.POST("", middleware.RequirePermission("approval_chains.manage"), r.approvalHandler.CreateChain)
+				approvalChains.PUT("/:id", middleware.RequirePermission("approval_chains.manage"), r.approvalHandler.UpdateChain)
+				approvalChains.DELETE("/:id", middleware.RequirePermission("approval_chains.manage"), r.approvalHandler.DeleteChain)
+			}
+
+			// Recurring journal endpoints
+			recurringJournals := protected.Group("/recurring-journals")
+			recurringJournals.Use(middleware.RequirePermission("recurring_journals.view"))
+			{
+				recurringJournals.GET("", r.recurringJournalHandler.GetAll)
+				recurringJournals.GET("/:id", r.recurringJournalHandler.GetByID)
+				recurringJournals.GET("/:id/runs", r.recurringJournalHandler.GetRuns)
+
+				recurringJournals.POST("", middleware.RequirePermission("recurring_journals.manage"), r.recurringJournalHandler.Create)
+				recurringJournals.POST("/generate", middleware.RequirePermission("recurring_journals.manage"), r.recurringJournalHandler.Generate)
+				recurringJournals.PUT("/:id", middleware.RequirePermission("recurring_journals.manage"), r.recurringJournalHandler.Update)
+				recurringJournals.DELETE("/:id", middleware.RequirePermission("recurring_journals.manage"), r.recurringJournalHandler.Delete)
+				recurringJournals.POST("/:id/pause", middleware.RequirePermission("recurring_journals.manage"), r.recurringJournalHandler.Pause)
+				recurringJournals.POST("/:id/resume", middleware.RequirePermission("recurring_journals.manage"), r.recurringJournalHandler.Resume)
+				recurringJournals.POST("/:id/skip", middleware.RequirePermission("recurring_journals.manage"), r.recurringJournalHandler.Skip)
+			}
+
+			// Exchange rate endpoints
+			exchangeRates := protected.Group("/exchange-rates")
+			exchangeRates.Use(middleware.RequirePermission("exchange_rates.view"))
+			{
+				exchangeRates.GET("", r.exchangeRateHandler.GetAll)
+				exchangeRates.GET("/revaluations", r.exchangeRateHandler.GetRevaluations)
+
+				exchangeRates.POST("", middleware.RequirePermission("exchange_rates.manage"), r.exchangeRateHandler.Save)
+				exchangeRates.DELETE("/:id", middleware.RequirePermission("exchange_rates.manage"), r.exchangeRateHandler.Delete)
+				exchangeRates.POST("/revaluations", middleware.RequirePermission("exchange_rates.revalue"), r.exchangeRateHandler.Revalue)
+			}
+
+			// Opening balance endpoints
+			openingBalances := protected.Group("/opening-balances")
+			{
+				openingBalances.POST("/import", middleware.RequirePermission("opening_balances.import"), r.openingBalanceHandler.Import)
+			}
+
+			// Accounting period endpoints
+			periods := protected.Group("/periods")
+			periods.Use(middleware.RequirePermission("periods.view"))
+			{
+				periods.GET("", r.periodHandler.GetByBranch)
+
+				periods.PUT("", middleware.RequirePermission("periods.manage"), r.periodHandler.SetStatus)
+			}
+
+			// Fiscal year endpoints
+			fiscalYears := protected.Group("/fiscal-years")
+			fiscalYears.Use(middleware.RequirePermission("fiscal_years.view"))
+			{
+				fiscalYears.GET("", r.fiscalYearHandler.GetAll)
+				fiscalYears.GET("/current", r.fiscalYearHandler.GetCurrent)
+				fiscalYears.GET("/:id", r.fiscalYearHandler.GetByID)
+
+				fiscalYears.POST("", middleware.RequirePermission("fiscal_years.create"), r.fiscalYearHandler.Create)
+				fiscalYears.PUT("/:id/set-current", middleware.RequirePermission("fiscal_years.update"), r.fiscalYearHandler.SetCurrent)
+				fiscalYears.POST("/:id/close", middleware.RequirePermission("fiscal_years.close"), r.fiscalYearHandler.Close)
+				fiscalYears.POST("/:id/reopen", middleware.RequirePermission("fiscal_years.reopen"), r.fiscalYearHandler.Reopen)
+			}
+
+			// Fund endpoints
+			funds := protected.Group("/funds")
+			funds.Use(middleware.RequirePermission("funds.view"))
+			{
+				funds.GET("", r.fundHandler.GetAll)
+				funds.GET("/active", r.fundHandler.GetAllActive)
+				funds.GET("/:id", r.fundHandler.GetByID)
+
+				funds.POST("", middleware.RequirePermission("funds.create"), r.fundHandler.Create)
+				funds.PUT("/:id", middleware.RequirePermission("funds.update"), r.fundHandler.Update)
+				funds.DELETE("/:id", middleware.RequirePermission("funds.delete"), r.fundHandler.Delete)
+			}
+
+			// Program endpoints
+			programs := protected.Group("/programs")
+			programs.Use(middleware.RequirePermission("programs.view"))
+			{
+				programs.GET("", r.fundHandler.GetAllPrograms)
+				programs.GET("/active", r.fundHandler.GetAllActivePrograms)
+				programs.GET("/:id", r.fundHandler.GetProgramByID)
+
+				programs.POST("", middleware.RequirePermission("programs.create"), r.fundHandler.CreateProgram)
+				programs.PUT("/:id", middleware.RequirePermission("programs.update"), r.fundHandler.UpdateProgram)
+				programs.DELETE("/:id", middleware.RequirePermission("programs.delete"), r.fundHandler.DeleteProgram)
+			}
+
+			// Bank reconciliation endpoints
+			bankStatements := protected.Group("/bank-statements")
+			bankStatements.Use(middleware.RequirePermission("bank_reconciliations.view"))
+			{
+				bankStatements.GET("", r.bankReconciliationHandler.GetAll)
+				bankStatements.GET("/:id", r.bankReconciliationHandler.GetByID)
+				bankStatements.GET("/:id/unmatched", r.bankReconciliationHandler.GetUnmatched)
+				bankStatements.GET("/:id/report", r.bankReconciliationHandler.GetReport)
+
+				bankStatements.POST("/import", middleware.RequirePermission("bank_reconciliations.manage"), r.bankReconciliationHandler.Import)
+				bankStatements.POST("/:id/auto-match", middleware.RequirePermission("bank_reconciliations.manage"), r.bankReconciliationHandler.AutoMatch)
+				bankStatements.PUT("/lines/:line_id/match", middleware.RequirePermission("bank_reconciliations.manage"), r.bankReconciliationHandler.MatchLine)
+				bankStatements.DELETE("/lines/:line_id/match", middleware.RequirePermission("bank_reconciliations.manage"), r.bankReconciliationHandler.UnmatchLine)
+				bankStatements.POST("/:id/reconcile", middleware.RequirePermission("bank_reconciliations.manage"), r.bankReconciliationHandler.Reconcile)
+				bankStatements.DELETE("/:id", middleware.RequirePermission("bank_reconciliations.manage"), r.bankReconciliationHandler.Delete)
 			}
 
 			// Budget endpoints
@@ -187,6 +367,31 @@ func (r *Router) Setup(router *gin.Engine) {
 				reports.POST("/balance-sheet", r.reportHandler.GetBalanceSheet)
 				reports.POST("/income-statement", r.reportHandler.GetIncomeStatement)
 				reports.POST("/general-ledger", r.reportHandler.GetGeneralLedger)
+				reports.POST("/cash-flow", r.reportHandler.GetCashFlow)
+				reports.POST("/financial-position", r.reportHandler.GetFinancialPosition)
+				reports.POST("/comprehensive-income", r.reportHandler.GetComprehensiveIncome)
+				reports.POST("/changes-in-net-assets", r.reportHandler.GetChangesInNetAssets)
+				reports.POST("/fund-balance", r.reportHandler.GetFundBalance)
+				reports.POST("/program-expenses", r.reportHandler.GetProgramExpenses)
+				reports.POST("/inter-branch-balances", r.reportHandler.GetInterBranchBalances)
+				reports.POST("/consolidated/balance-sheet", r.reportHandler.GetConsolidatedBalanceSheet)
+				reports.POST("/consolidated/income-statement", r.reportHandler.GetConsolidatedIncomeStatement)
+				reports.POST("/comparative/income-statement", r.reportHandler.GetComparativeIncomeStatement)
+				reports.POST("/comparative/balance-sheet", r.reportHandler.GetComparativeBalanceSheet)
+				reports.POST("/comparative/trial-balance", r.reportHandler.GetComparativeTrialBalance)
+			}
+
+			// Report template endpoints
+			reportTemplates := protected.Group("/report-templates")
+			reportTemplates.Use(middleware.RequirePermission("report_templates.view"))
+			{
+				reportTemplates.GET("", r.reportTemplateHandler.GetAll)
+				reportTemplates.GET("/:id", r.reportTemplateHandler.GetByID)
+				reportTemplates.POST("/:id/run", r.reportTemplateHandler.Run)
+
+				reportTemplates.POST("", middleware.RequirePermission("report_templates.manage"), r.reportTemplateHandler.Create)
+				reportTemplates.PUT("/:id", middleware.RequirePermission("report_templates.manage"), r.reportTemplateHandler.Update)
+				reportTemplates.DELETE("/:id", middleware.RequirePermission("report_templates.manage"), r.reportTemplateHandler.Delete)
 			}
 
 			// Student endpoints
@@ -255,6 +460,7 @@ func (r *Router) Setup(router *gin.Engine) {
 
 				payrolls.POST("", middleware.RequirePermission("payrolls.create"), r.employeeHandler.CreatePayroll)       // DIPERBAIKI
 				payrolls.POST("/bulk", middleware.RequirePermission("payrolls.create"), r.employeeHandler.GenerateBulkPayroll) // DIPERBAIKI
+				payrolls.POST("/bulk/process", middleware.RequirePermission("payrolls.process"), r.employeeHandler.ProcessBulkPayroll)
 				payrolls.POST("/:id/process", middleware.RequirePermission("payrolls.process"), r.employeeHandler.ProcessPayroll) // DIPERBAIKI
 				payrolls.DELETE("/:id", middleware.RequirePermission("payrolls.delete"), r.employeeHandler.DeletePayroll) // DIPERBAIKI
 			}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/yayasan/erp-backend/internal/models"
+	"github.com/yayasan/erp-backend/internal/repository"
+)
+
+type AccountMappingService interface {
+	GetAll(branchID *uuid.UUID) ([]models.AccountMapping, error)
+	GetByID(id uuid.UUID) (*models.AccountMapping, error)
+	Save(req *models.SaveAccountMappingRequest) (*models.AccountMapping, error)
+	Delete(id uuid.UUID) error
+}
+
+type accountMappingService struct {
+	mappingRepo repository.AccountMappingRepository
+	accountRepo repository.AccountRepository
+	branchRepo  repository.BranchRepository
+}
+
+func NewAccountMappingService(
+	mappingRepo repository.AccountMappingRepository,
+	accountRepo repository.AccountRepository,
+	branchRepo repository.BranchRepository,
+) AccountMappingService {
+	return &accountMappingService{
+		mappingRepo: mappingRepo,
+		accountRepo: accountRepo,
+		branchRepo:  branchRepo,
+	}
+}
+
+func (s *accountMappingService) GetAll(branchID *uuid.UUID) ([]models.AccountMapping, error) {
+	return s.mappingRepo.GetAll(branchID)
+}
+
+func (s *accountMappingService) GetByID(id uuid.UUID) (*models.AccountMapping, error) {
+	return s.mappingRepo.GetByID(id)
+}
+
+func (s *accountMappingService) Save(req *models.SaveAccountMappingRequest) (*models.AccountMapping, error) {
+	// Validate branch if branch-specific
+	if req.BranchID != nil {
+		if _, err := s.branchRepo.GetByID(*req.BranchID); err != nil {
+			return nil, errors.New("branch not found")
+		}
+	}
+
+	// Validate account can receive postings
+	account, err := s.accountRepo.GetByID(req.AccountID)
+	if err != nil {
+		return nil, errors.New("account not found")
+	}
+	if !account.CanPostTransaction() {
+		return nil, errors.New("account " + account.Code + " cannot have transactions")
+	}
+
+	// Replace existing mapping for the same branch and key
+	mapping, err := s.mappingRepo.GetByBranchKey(req.BranchID, req.MappingKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if mapping != nil {
+		mapping.AccountID = req.AccountID
+		mapping.Description = req.Description
+		mapping.Account = models.Account{}
+		if err := s.mappingRepo.Update(mapping); err != nil {
+			return nil, err
+		}
+		return s.mappingRepo.GetByID(mapping.ID)
+	}
+
+	mapping = &models.AccountMapping{
+		BranchID:    req.BranchID,
+		MappingKey:  req.MappingKey,
+		AccountID:   req.AccountID,
+		Description: req.Description,
+	}
+
+	if err := s.mappingRepo.Create(mapping); err != nil {
+		return nil, err
+	}
+
+	return s.mappingRepo.GetByID(mapping.ID)
+}
+
+func (s *accountMappingService) Delete(id uuid.UUID) error {
+	if _, err := s.mappingRepo.GetByID(id); err != nil {
+		return errors.New("account mapping not found")
+	}
+	return s.mappingRepo.Delete(id)
+}
@@ -148,6 +148,11 @@ func (s *accountService) Create(req *models.CreateAccountRequest) (*models.Accou
 		}
 	}
 
+	currency := foreignCurrency(req.Currency)
+	if currency != "" && !config.GlobalConfig.App.EnableMultiCurrency {
+		return nil, errors.New("multi-currency is not enabled")
+	}
+
 	// Create account
 	account := &models.Account{
 		ParentID:      req.ParentID,
@@ -159,6 +164,8 @@ func (s *accountService) Create(req *models.CreateAccountRequest) (*models.Accou
 		NormalBalance: req.NormalBalance,
 		IsActive:      true,
 		Description:   req.Description,
+		CashFlowActivity: req.CashFlowActivity,
+		Currency:      currency,
 	}
 
 	if err := s.accountRepo.Create(account); err != nil {
@@ -183,6 +190,26 @@ func (s *accountService) Update(id uuid.UUID, req *models.UpdateAccountRequest)
 		account.IsActive = *req.IsActive
 	}
 
+	if req.CashFlowActivity != nil {
+		account.CashFlowActivity = *req.CashFlowActivity
+	}
+
+	// Balances already booked would mix currencies
+	if req.Currency != nil && foreignCurrency(*req.Currency) != account.Currency {
+		currency := foreignCurrency(*req.Currency)
+		if currency != "" && !config.GlobalConfig.App.EnableMultiCurrency {
+			return nil, errors.New("multi-currency is not enabled")
+		}
+		hasLines, err := s.accountRepo.HasJournalLines(account.ID)
+		if err != nil {
+			return nil, err
+		}
+		if hasLines {
+			return nil, errors.New("currency cannot be changed once the account has journal lines")
+		}
+		account.Currency = currency
+	}
+
 	if err := s.accountRepo.Update(account); err != nil {
 		return nil, err
 	}
@@ -0,0 +1,402 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yayasan/erp-backend/internal/models"
+	"github.com/yayasan/erp-backend/internal/repository"
+)
+
+// defaultApprovalStep names the single review step used when no chain matches a document
+const defaultApprovalStep = "Review"
+
+type ApprovalService interface {
+	GetChains(documentType string) ([]models.ApprovalChain, error)
+	GetChainByID(id uuid.UUID) (*models.ApprovalChain, error)
+	CreateChain(req *models.CreateApprovalChainRequest) (*models.ApprovalChain, error)
+	UpdateChain(id uuid.UUID, req *models.UpdateApprovalChainRequest) (*models.ApprovalChain, error)
+	DeleteChain(id uuid.UUID) error
+	PrepareSubmit(documentType string, documentID, branchID uuid.UUID, amount models.Money, userID uuid.UUID) (*models.ApprovalRequest, *models.ApprovalHistory, error)
+	PrepareAct(documentType string, documentID uuid.UUID, approve bool, notes string, userID uuid.UUID) (*models.ApprovalRequest, *models.ApprovalHistory, error)
+	GetRequests(documentType string, documentID uuid.UUID) ([]models.ApprovalRequest, error)
+}
+
+type approvalService struct {
+	approvalRepo repository.ApprovalRepository
+	userRepo     repository.UserRepository
+	roleRepo     repository.RoleRepository
+	branchRepo   repository.BranchRepository
+}
+
+func NewApprovalService(
+	approvalRepo repository.ApprovalRepository,
+	userRepo repository.UserRepository,
+	roleRepo repository.RoleRepository,
+	branchRepo repository.BranchRepository,
+) ApprovalService {
+	return &approvalService{
+		approvalRepo: approvalRepo,
+		userRepo:     userRepo,
+		roleRepo:     roleRepo,
+		branchRepo:   branchRepo,
+	}
+}
+
+func (s *approvalService) GetChains(documentType string) ([]models.ApprovalChain, error) {
+	return s.approvalRepo.GetChains(documentType)
+}
+
+func (s *approvalService) GetChainByID(id uuid.UUID) (*models.ApprovalChain, error) {
+	return s.approvalRepo.GetChainByID(id)
+}
+
+func (s *approvalService) CreateChain(req *models.CreateApprovalChainRequest) (*models.ApprovalChain, error) {
+	// Check if code exists
+	existing, _ := s.approvalRepo.GetChainByCode(req.Code)
+	if existing != nil {
+		return nil, errors.New("approval chain code already exists")
+	}
+
+	chain := &models.ApprovalChain{
+		Code:         req.Code,
+		Name:         req.Name,
+		DocumentType: req.DocumentType,
+		BranchID:     req.BranchID,
+		MinAmount:    req.MinAmount,
+		MaxAmount:    req.MaxAmount,
+		Description:  req.Description,
+		IsActive:     true,
+	}
+
+	steps, err := s.buildChainSteps(chain, req.Steps)
+	if err != nil {
+		return nil, err
+	}
+	chain.Steps = steps
+
+	if err := s.validateChain(chain); err != nil {
+		return nil, err
+	}
+
+	if err := s.approvalRepo.CreateChain(chain); err != nil {
+		return nil, err
+	}
+
+	return s.approvalRepo.GetChainByID(chain.ID)
+}
+
+func (s *approvalService) UpdateChain(id uuid.UUID, req *models.UpdateApprovalChainRequest) (*models.ApprovalChain, error) {
+	chain, err := s.approvalRepo.GetChainByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Steps of documents still in approval must not change under them
+	pending, err := s.approvalRepo.HasPendingRequests(id)
+	if err != nil {
+		return nil, err
+	}
+	if pending {
+		return nil, errors.New("approval chain has documents awaiting approval")
+	}
+
+	chain.Name = req.Name
+	chain.BranchID = req.BranchID
+	chain.MinAmount = req.MinAmount
+	chain.MaxAmount = req.MaxAmount
+	chain.Description = req.Description
+	if req.IsActive != nil {
+		chain.IsActive = *req.IsActive
+	}
+
+	steps, err := s.buildChainSteps(chain, req.Steps)
+	if err != nil {
+		return nil, err
+	}
+	chain.Steps = steps
+
+	if err := s.validateChain(chain); err != nil {
+		return nil, err
+	}
+
+	if err := s.approvalRepo.UpdateChain(chain); err != nil {
+		return nil, err
+	}
+
+	return s.approvalRepo.GetChainByID(id)
+}
+
+func (s *approvalService) DeleteChain(id uuid.UUID) error {
+	if _, err := s.approvalRepo.GetChainByID(id); err != nil {
+		return err
+	}
+
+	pending, err := s.approvalRepo.HasPendingRequests(id)
+	if err != nil {
+		return err
+	}
+	if pending {
+		return errors.New("approval chain has documents awaiting approval")
+	}
+
+	return s.approvalRepo.DeleteChain(id)
+}
+
+// PrepareSubmit builds the approval of a document using the chain for its branch and amount.
+// Nothing is saved: the document's repository saves the request and history together with the
+// document's status, replacing any approval still pending from an earlier submission.
+func (s *approvalService) PrepareSubmit(documentType string, documentID, branchID uuid.UUID, amount models.Money, userID uuid.UUID) (*models.ApprovalRequest, *models.ApprovalHistory, error) {
+	chain, err := s.selectChain(documentType, branchID, amount)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	request := &models.ApprovalRequest{
+		DocumentType: documentType,
+		DocumentID:   documentID,
+		BranchID:     branchID,
+		Amount:       amount,
+		CurrentStep:  1,
+		TotalSteps:   1,
+		Status:       models.ApprovalStatusPending,
+		RequestedBy:  userID,
+	}
+	if chain != nil {
+		request.ChainID = &chain.ID
+		request.TotalSteps = len(chain.Steps)
+	}
+
+	history := &models.ApprovalHistory{
+		DocumentType: documentType,
+		DocumentID:   documentID,
+		Action:       models.ApprovalActionSubmitted,
+		ActedBy:      userID,
+		ActedAt:      time.Now(),
+	}
+
+	request.Chain = chain
+	return request, history, nil
+}
+
+// PrepareAct approves or rejects the current step of a document's pending approval. The returned
+// request is approved once the last step is approved, and rejected as soon as any step is.
+// Nothing is saved: the document's repository records the step, whose number is in the
+// history's StepNo, together with the document's status.
+func (s *approvalService) PrepareAct(documentType string, documentID uuid.UUID, approve bool, notes string, userID uuid.UUID) (*models.ApprovalRequest, *models.ApprovalHistory, error) {
+	request, err := s.approvalRepo.GetPendingRequest(documentType, documentID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if request.RequestedBy == userID {
+		return nil, nil, errors.New("cannot approve your own submission")
+	}
+
+	// Each level must be a different person
+	for _, entry := range request.History {
+		if entry.ActedBy == userID && entry.Action == models.ApprovalActionApproved {
+			return nil, nil, errors.New("you already approved an earlier step")
+		}
+	}
+
+	step := request.Step(request.CurrentStep)
+	if err := s.ensureApprover(request, step, userID); err != nil {
+		return nil, nil, err
+	}
+
+	stepName := defaultApprovalStep
+	if step != nil {
+		stepName = step.Name
+	}
+
+	now := time.Now()
+	history := &models.ApprovalHistory{
+		DocumentType: documentType,
+		DocumentID:   documentID,
+		StepNo:       request.CurrentStep,
+		StepName:     stepName,
+		ActedBy:      userID,
+		ActedAt:      now,
+		Notes:        notes,
+	}
+
+	switch {
+	case !approve:
+		history.Action = models.ApprovalActionRejected
+		request.Status = models.ApprovalStatusRejected
+		request.CompletedAt = &now
+	case request.CurrentStep >= request.TotalSteps:
+		history.Action = models.ApprovalActionApproved
+		request.Status = models.ApprovalStatusApproved
+		request.CompletedAt = &now
+	default:
+		history.Action = models.ApprovalActionApproved
+		request.CurrentStep++
+	}
+
+	return request, history, nil
+}
+
+func (s *approvalService) GetRequests(documentType string, documentID uuid.UUID) ([]models.ApprovalRequest, error) {
+	return s.approvalRepo.GetRequests(documentType, documentID)
+}
+
+// selectChain picks the chain whose band contains the amount, preferring a chain for the
+// branch over one for every branch. Nil means the default single review step.
+func (s *approvalService) selectChain(documentType string, branchID uuid.UUID, amount models.Money) (*models.ApprovalChain, error) {
+	chains, err := s.approvalRepo.FindChains(documentType, branchID)
+	if err != nil {
+		return nil, err
+	}
+
+	var selected *models.ApprovalChain
+	for i := range chains {
+		chain := &chains[i]
+		if !chain.Matches(amount) || len(chain.Steps) == 0 {
+			continue
+		}
+		if selected == nil || (selected.BranchID == nil && chain.BranchID != nil) {
+			selected = chain
+		}
+	}
+	return selected, nil
+}
+
+// ensureApprover checks that the user may act on a step. Super admins may act on any step.
+func (s *approvalService) ensureApprover(request *models.ApprovalRequest, step *models.ApprovalChainStep, userID uuid.UUID) error {
+	if step == nil {
+		return nil
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+	if user.IsSuperAdmin {
+		return nil
+	}
+
+	notApprover := fmt.Errorf("you are not an approver for step %d (%s)", step.StepNo, step.Name)
+
+	if step.UserID != nil && *step.UserID != userID {
+		return notApprover
+	}
+
+	if step.RoleID != nil {
+		var branchID *uuid.UUID
+		switch step.Scope {
+		case models.ApproverScopeBranch:
+			branchID = step.BranchID
+		case models.ApproverScopeAnyBranch:
+		default:
+			branchID = &request.BranchID
+		}
+
+		hasRole, err := s.approvalRepo.UserHasRole(userID, *step.RoleID, branchID)
+		if err != nil {
+			return err
+		}
+		if !hasRole {
+			return notApprover
+		}
+	}
+
+	return nil
+}
+
+func (s *approvalService) buildChainSteps(chain *models.ApprovalChain, reqs []models.ApprovalChainStepReq) ([]models.ApprovalChainStep, error) {
+	steps := make([]models.ApprovalChainStep, len(reqs))
+	for i, req := range reqs {
+		if req.RoleID == nil && req.UserID == nil {
+			return nil, fmt.Errorf("step %d: a role or user is required", i+1)
+		}
+
+		scope := req.Scope
+		if scope == "" {
+			scope = models.ApproverScopeDocumentBranch
+		}
+
+		if req.RoleID != nil {
+			if _, err := s.roleRepo.GetByID(*req.RoleID); err != nil {
+				return nil, fmt.Errorf("step %d: role not found", i+1)
+			}
+		}
+		if req.UserID != nil {
+			if _, err := s.userRepo.GetByID(*req.UserID); err != nil {
+				return nil, fmt.Errorf("step %d: user not found", i+1)
+			}
+		}
+
+		var branchID *uuid.UUID
+		if scope == models.ApproverScopeBranch {
+			if req.BranchID == nil {
+				return nil, fmt.Errorf("step %d: branch is required for the branch scope", i+1)
+			}
+			if _, err := s.branchRepo.GetByID(*req.BranchID); err != nil {
+				return nil, fmt.Errorf("step %d: branch not found", i+1)
+			}
+			branchID = req.BranchID
+		}
+
+		steps[i] = models.ApprovalChainStep{
+			ChainID:  chain.ID,
+			StepNo:   i + 1,
+			Name:     req.Name,
+			RoleID:   req.RoleID,
+			UserID:   req.UserID,
+			Scope:    scope,
+			BranchID: branchID,
+		}
+	}
+	return steps, nil
+}
+
+// validateChain checks the amount band and that it does not overlap another active chain for
+// the same document type and branch, so the chain used for a document is never ambiguous
+func (s *approvalService) validateChain(chain *models.ApprovalChain) error {
+	if chain.MaxAmount != nil && *chain.MaxAmount <= chain.MinAmount {
+		return errors.New("max amount must be greater than min amount")
+	}
+
+	if chain.BranchID != nil {
+		if _, err := s.branchRepo.GetByID(*chain.BranchID); err != nil {
+			return errors.New("branch not found")
+		}
+	}
+
+	if !chain.IsActive {
+		return nil
+	}
+
+	chains, err := s.approvalRepo.GetChains(chain.DocumentType)
+	if err != nil {
+		return err
+	}
+
+	for _, other := range chains {
+		if other.ID == chain.ID || !other.IsActive || !sameBranch(other.BranchID, chain.BranchID) {
+			continue
+		}
+		if bandsOverlap(chain, &other) {
+			return fmt.Errorf("amount band overlaps approval chain %s", other.Code)
+		}
+	}
+
+	return nil
+}
+
+func sameBranch(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+func bandsOverlap(a, b *models.ApprovalChain) bool {
+	aBelowB := b.MaxAmount == nil || a.MinAmount < *b.MaxAmount
+	bBelowA := a.MaxAmount == nil || b.MinAmount < *a.MaxAmount
+	return aBelowB && bBelowA
+}
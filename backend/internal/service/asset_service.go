@@ -2,8 +2,7 @@ package service
 
 import (
 	"errors"
-	"math"
-	//"time"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/yayasan/erp-backend/internal/config"
@@ -39,12 +38,14 @@ type AssetService interface {
 type assetService struct {
 	assetRepo  repository.AssetRepository
 	branchRepo repository.BranchRepository
+	periodRepo repository.AccountingPeriodRepository
 }
 
-func NewAssetService(assetRepo repository.AssetRepository, branchRepo repository.BranchRepository) AssetService {
+func NewAssetService(assetRepo repository.AssetRepository, branchRepo repository.BranchRepository, periodRepo repository.AccountingPeriodRepository) AssetService {
 	return &assetService{
 		assetRepo:  assetRepo,
 		branchRepo: branchRepo,
+		periodRepo: periodRepo,
 	}
 }
 
@@ -170,18 +171,19 @@ func (s *assetService) CalculateDepreciation(assetID uuid.UUID, period string) (
 		return nil, errors.New("useful life not set") // DIPERBAIKI
 	}
 
-	var depreciationAmount float64
-
-	if asset.DepreciationMethod == models.DepreciationMethodStraightLine {
-		// Straight-line: (Cost - Salvage) / Useful Life / 12
-		depreciationAmount = (asset.PurchasePrice - asset.SalvageValue) / float64(asset.UsefulLife) / 12
-	} else if asset.DepreciationMethod == models.DepreciationMethodDecliningBalance {
-		// Declining balance: Book Value × (2 / Useful Life) / 12
-		rate := 2.0 / float64(asset.UsefulLife)
-		depreciationAmount = asset.BookValue * rate / 12
+	// Validate period is open
+	periodDate, err := time.Parse("2006-01", period)
+	if err != nil {
+		return nil, errors.New("invalid period format, use YYYY-MM")
+	}
+	if err := ensurePeriodOpen(s.periodRepo, asset.BranchID, periodDate); err != nil {
+		return nil, err
 	}
 
-	depreciationAmount = math.Round(depreciationAmount)
+	depreciationAmount, err := monthlyDepreciation(asset)
+	if err != nil {
+		return nil, err
+	}
 
 	newAccumulated := asset.AccumulatedDepreciation + depreciationAmount
 	newBookValue := asset.PurchasePrice - newAccumulated
@@ -210,6 +212,31 @@ func (s *assetService) CalculateDepreciation(assetID uuid.UUID, period string) (
 	return depreciation, nil
 }
 
+// monthlyDepreciation returns the depreciation of an asset for one month. It is charged in
+// whole rupiah and stops at the salvage value, so the last month only takes what is left.
+func monthlyDepreciation(asset *models.Asset) (models.Money, error) {
+	remaining := asset.BookValue - asset.SalvageValue
+	if remaining <= 0 {
+		return 0, errors.New("asset is fully depreciated")
+	}
+
+	var depreciationAmount models.Money
+	months := int64(asset.UsefulLife) * 12
+
+	if asset.DepreciationMethod == models.DepreciationMethodStraightLine {
+		// Straight-line: (Cost - Salvage) / Useful Life / 12
+		depreciationAmount = (asset.PurchasePrice - asset.SalvageValue).DivRound(months, models.Rupiah)
+	} else if asset.DepreciationMethod == models.DepreciationMethodDecliningBalance {
+		// Declining balance: Book Value × (2 / Useful Life) / 12
+		depreciationAmount = (asset.BookValue * 2).DivRound(months, models.Rupiah)
+	}
+
+	if depreciationAmount > remaining {
+		depreciationAmount = remaining
+	}
+	return depreciationAmount, nil
+}
+
 func (s *assetService) ProcessMonthlyDepreciation(period string) ([]models.AssetDepreciation, error) {
 	assets, err := s.assetRepo.GetByStatus(models.AssetStatusActive)
 	if err != nil {
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/yayasan/erp-backend/internal/models"
+)
+
+func TestMonthlyDepreciation(t *testing.T) {
+	tests := []struct {
+		name    string
+		asset   models.Asset
+		want    models.Money
+		wantErr bool
+	}{
+		{
+			name: "straight line",
+			asset: models.Asset{
+				DepreciationMethod: models.DepreciationMethodStraightLine,
+				UsefulLife:         1,
+				PurchasePrice:      12000000 * models.Rupiah,
+				BookValue:          12000000 * models.Rupiah,
+			},
+			want: 1000000 * models.Rupiah,
+		},
+		{
+			name: "straight line rounded to rupiah",
+			asset: models.Asset{
+				DepreciationMethod: models.DepreciationMethodStraightLine,
+				UsefulLife:         1,
+				PurchasePrice:      1000 * models.Rupiah,
+				BookValue:          1000 * models.Rupiah,
+			},
+			want: 83 * models.Rupiah,
+		},
+		{
+			name: "straight line capped at salvage value",
+			asset: models.Asset{
+				DepreciationMethod: models.DepreciationMethodStraightLine,
+				UsefulLife:         1,
+				PurchasePrice:      12000000 * models.Rupiah,
+				SalvageValue:       2000000 * models.Rupiah,
+				BookValue:          2500000 * models.Rupiah,
+			},
+			want: 500000 * models.Rupiah,
+		},
+		{
+			name: "declining balance",
+			asset: models.Asset{
+				DepreciationMethod: models.DepreciationMethodDecliningBalance,
+				UsefulLife:         2,
+				PurchasePrice:      12000000 * models.Rupiah,
+				BookValue:          12000000 * models.Rupiah,
+			},
+			want: 1000000 * models.Rupiah,
+		},
+		{
+			name: "declining balance capped at salvage value",
+			asset: models.Asset{
+				DepreciationMethod: models.DepreciationMethodDecliningBalance,
+				UsefulLife:         2,
+				PurchasePrice:      12000000 * models.Rupiah,
+				SalvageValue:       2000000 * models.Rupiah,
+				BookValue:          2100000 * models.Rupiah,
+			},
+			want: 100000 * models.Rupiah,
+		},
+		{
+			name: "fully depreciated",
+			asset: models.Asset{
+				DepreciationMethod: models.DepreciationMethodStraightLine,
+				UsefulLife:         1,
+				PurchasePrice:      12000000 * models.Rupiah,
+				SalvageValue:       2000000 * models.Rupiah,
+				BookValue:          2000000 * models.Rupiah,
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := monthlyDepreciation(&tt.asset)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("monthlyDepreciation() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("monthlyDepreciation() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
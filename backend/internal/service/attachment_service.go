@@ -0,0 +1,200 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yayasan/erp-backend/internal/config"
+	"github.com/yayasan/erp-backend/internal/models"
+	"github.com/yayasan/erp-backend/internal/repository"
+	"github.com/yayasan/erp-backend/internal/storage"
+)
+
+type AttachmentService interface {
+	GetJournalAttachments(journalID uuid.UUID) ([]models.Attachment, error)
+	UploadJournalAttachment(journalID uuid.UUID, fileName, contentType string, data []byte, userID uuid.UUID) (*models.Attachment, error)
+	OpenJournalAttachment(journalID, attachmentID uuid.UUID) (*models.Attachment, io.ReadCloser, error)
+	DeleteJournalAttachment(journalID, attachmentID uuid.UUID) error
+}
+
+type attachmentService struct {
+	attachmentRepo repository.AttachmentRepository
+	journalRepo    repository.JournalRepository
+	fileStorage    storage.FileStorage
+}
+
+func NewAttachmentService(
+	attachmentRepo repository.AttachmentRepository,
+	journalRepo repository.JournalRepository,
+	fileStorage storage.FileStorage,
+) AttachmentService {
+	return &attachmentService{
+		attachmentRepo: attachmentRepo,
+		journalRepo:    journalRepo,
+		fileStorage:    fileStorage,
+	}
+}
+
+func (s *attachmentService) GetJournalAttachments(journalID uuid.UUID) ([]models.Attachment, error) {
+	if _, err := s.journalRepo.GetByID(journalID); err != nil {
+		return nil, errors.New("journal not found")
+	}
+	return s.attachmentRepo.GetByEntity(models.AttachmentEntityJournal, journalID)
+}
+
+// UploadJournalAttachment stores a supporting document for a journal. Documents can still be
+// added after posting, since evidence often arrives late.
+func (s *attachmentService) UploadJournalAttachment(journalID uuid.UUID, fileName, contentType string, data []byte, userID uuid.UUID) (*models.Attachment, error) {
+	if _, err := s.journalRepo.GetByID(journalID); err != nil {
+		return nil, errors.New("journal not found")
+	}
+
+	fileName = filepath.Base(strings.ReplaceAll(fileName, "\\", "/"))
+	if fileName == "." || fileName == "/" || fileName == "" {
+		return nil, errors.New("file name is required")
+	}
+
+	uploadConfig := config.GlobalConfig.Upload
+	if len(data) == 0 {
+		return nil, errors.New("file is empty")
+	}
+	if int64(len(data)) > uploadConfig.MaxSize {
+		return nil, fmt.Errorf("file exceeds the maximum size of %d bytes", uploadConfig.MaxSize)
+	}
+
+	mimeType, err := attachmentMimeType(fileName, contentType, data, uploadConfig.AllowedTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	checksum := sha256.Sum256(data)
+	key := fmt.Sprintf("journals/%s/%s%s", journalID, uuid.New(), strings.ToLower(filepath.Ext(fileName)))
+
+	if err := s.fileStorage.Save(context.Background(), key, bytes.NewReader(data), int64(len(data)), mimeType); err != nil {
+		return nil, fmt.Errorf("failed to store file: %v", err)
+	}
+
+	attachment := &models.Attachment{
+		EntityType: models.AttachmentEntityJournal,
+		EntityID:   journalID,
+		FileName:   fileName,
+		FilePath:   key,
+		Storage:    s.fileStorage.Backend(),
+		FileSize:   int64(len(data)),
+		MimeType:   mimeType,
+		Checksum:   hex.EncodeToString(checksum[:]),
+		UploadedBy: userID,
+		UploadedAt: time.Now(),
+	}
+
+	if err := s.attachmentRepo.Create(attachment); err != nil {
+		s.fileStorage.Delete(context.Background(), key)
+		return nil, err
+	}
+
+	return attachment, nil
+}
+
+func (s *attachmentService) OpenJournalAttachment(journalID, attachmentID uuid.UUID) (*models.Attachment, io.ReadCloser, error) {
+	attachment, err := s.journalAttachment(journalID, attachmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if attachment.Storage != s.fileStorage.Backend() {
+		return nil, nil, fmt.Errorf("attachment is kept in %s storage, which is not configured", attachment.Storage)
+	}
+
+	content, err := s.fileStorage.Open(context.Background(), attachment.FilePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open file: %v", err)
+	}
+
+	return attachment, content, nil
+}
+
+// DeleteJournalAttachment removes the file of an attachment. The record is kept, soft deleted,
+// as a trace of what was removed. Attachments of posted journals are part of the audit trail
+// and cannot be deleted.
+func (s *attachmentService) DeleteJournalAttachment(journalID, attachmentID uuid.UUID) error {
+	journal, err := s.journalRepo.GetByID(journalID)
+	if err != nil {
+		return errors.New("journal not found")
+	}
+
+	if journal.IsPosted {
+		return errors.New("attachments of posted journals cannot be deleted")
+	}
+
+	attachment, err := s.journalAttachment(journalID, attachmentID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.attachmentRepo.Delete(attachment.ID); err != nil {
+		return err
+	}
+
+	if attachment.Storage == s.fileStorage.Backend() {
+		if err := s.fileStorage.Delete(context.Background(), attachment.FilePath); err != nil {
+			return fmt.Errorf("attachment deleted but its file could not be removed: %v", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *attachmentService) journalAttachment(journalID, attachmentID uuid.UUID) (*models.Attachment, error) {
+	attachment, err := s.attachmentRepo.GetByID(attachmentID)
+	if err != nil {
+		return nil, err
+	}
+	if attachment.EntityType != models.AttachmentEntityJournal || attachment.EntityID != journalID {
+		return nil, errors.New("attachment not found")
+	}
+	return attachment, nil
+}
+
+// attachmentMimeType resolves the type of an upload from the declared content type or the
+// file extension and checks it against the allowed types. Types the content can be sniffed
+// for, such as images and PDFs, must match the actual content.
+func attachmentMimeType(fileName, contentType string, data []byte, allowedTypes []string) (string, error) {
+	mimeType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || mimeType == "" || mimeType == "application/octet-stream" {
+		mimeType, _, _ = mime.ParseMediaType(mime.TypeByExtension(strings.ToLower(filepath.Ext(fileName))))
+	}
+	if mimeType == "" {
+		return "", errors.New("file type could not be determined")
+	}
+
+	allowed := false
+	for _, allowedType := range allowedTypes {
+		if strings.EqualFold(strings.TrimSpace(allowedType), mimeType) {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return "", fmt.Errorf("file type %s is not allowed", mimeType)
+	}
+
+	if strings.HasPrefix(mimeType, "image/") || mimeType == "application/pdf" {
+		sniffed, _, _ := mime.ParseMediaType(http.DetectContentType(data))
+		if sniffed != mimeType {
+			return "", fmt.Errorf("file content does not match type %s", mimeType)
+		}
+	}
+
+	return mimeType, nil
+}
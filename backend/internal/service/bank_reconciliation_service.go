@@ -0,0 +1,433 @@
+package service
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yayasan/erp-backend/internal/models"
+	"github.com/yayasan/erp-backend/internal/repository"
+)
+
+// defaultMatchDateWindow is the number of days a statement line may differ from its journal date
+const defaultMatchDateWindow = 3
+
+type BankReconciliationService interface {
+	GetAll(accountID *uuid.UUID) ([]models.BankStatement, error)
+	GetByID(id uuid.UUID) (*models.BankStatement, error)
+	Import(req *models.ImportBankStatementRequest, fileName string, data []byte, userID uuid.UUID) (*models.BankStatement, error)
+	AutoMatch(id uuid.UUID, dateWindowDays int) (*models.BankStatement, error)
+	GetUnmatched(id uuid.UUID) (*models.UnmatchedItemsResponse, error)
+	MatchLine(lineID uuid.UUID, req *models.MatchStatementLineRequest, userID uuid.UUID) (*models.BankStatementLine, error)
+	UnmatchLine(lineID uuid.UUID) (*models.BankStatementLine, error)
+	Reconcile(id uuid.UUID, userID uuid.UUID) (*models.BankStatement, error)
+	GetReport(id uuid.UUID) (*models.BankReconciliationReport, error)
+	Delete(id uuid.UUID) error
+}
+
+type bankReconciliationService struct {
+	statementRepo repository.BankStatementRepository
+	accountRepo   repository.AccountRepository
+	branchRepo    repository.BranchRepository
+}
+
+func NewBankReconciliationService(
+	statementRepo repository.BankStatementRepository,
+	accountRepo repository.AccountRepository,
+	branchRepo repository.BranchRepository,
+) BankReconciliationService {
+	return &bankReconciliationService{
+		statementRepo: statementRepo,
+		accountRepo:   accountRepo,
+		branchRepo:    branchRepo,
+	}
+}
+
+func (s *bankReconciliationService) GetAll(accountID *uuid.UUID) ([]models.BankStatement, error) {
+	return s.statementRepo.GetAll(accountID)
+}
+
+func (s *bankReconciliationService) GetByID(id uuid.UUID) (*models.BankStatement, error) {
+	return s.statementRepo.GetByID(id)
+}
+
+// Import parses a bank statement file for a cash/bank account and auto-matches its lines
+func (s *bankReconciliationService) Import(req *models.ImportBankStatementRequest, fileName string, data []byte, userID uuid.UUID) (*models.BankStatement, error) {
+	account, err := s.accountRepo.GetByID(req.AccountID)
+	if err != nil {
+		return nil, errors.New("account not found")
+	}
+	if account.GetCashFlowActivity() != models.CashFlowActivityCash {
+		return nil, errors.New("account " + account.Code + " is not a cash or bank account")
+	}
+
+	if _, err := s.branchRepo.GetByID(req.BranchID); err != nil {
+		return nil, errors.New("branch not found")
+	}
+
+	parsed, err := parseBankStatement(req.Format, data)
+	if err != nil {
+		return nil, err
+	}
+
+	statement := &models.BankStatement{
+		AccountID:   req.AccountID,
+		BranchID:    req.BranchID,
+		Format:      req.Format,
+		FileName:    fileName,
+		PeriodStart: parsed.lines[0].TransactionDate,
+		PeriodEnd:   parsed.lines[0].TransactionDate,
+		Status:      models.BankStatementStatusOpen,
+		ImportedBy:  userID,
+		Lines:       parsed.lines,
+	}
+
+	var movement models.Money
+	for _, line := range parsed.lines {
+		if line.TransactionDate.Before(statement.PeriodStart) {
+			statement.PeriodStart = line.TransactionDate
+		}
+		if line.TransactionDate.After(statement.PeriodEnd) {
+			statement.PeriodEnd = line.TransactionDate
+		}
+		movement += line.Amount
+	}
+
+	// Balances missing from the file are derived from the transactions
+	if parsed.openingBalance != nil {
+		statement.OpeningBalance = *parsed.openingBalance
+	}
+	if parsed.closingBalance != nil {
+		statement.ClosingBalance = *parsed.closingBalance
+	} else {
+		statement.ClosingBalance = statement.OpeningBalance + movement
+	}
+
+	if err := s.statementRepo.Create(statement); err != nil {
+		return nil, err
+	}
+
+	return s.AutoMatch(statement.ID, req.DateWindowDays)
+}
+
+// AutoMatch matches unmatched statement lines to unmatched ledger lines with the same amount
+// within the date window. A reference found in the statement line decides between several
+// candidates; lines that stay ambiguous are left for manual matching.
+func (s *bankReconciliationService) AutoMatch(id uuid.UUID, dateWindowDays int) (*models.BankStatement, error) {
+	statement, err := s.statementRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if statement.Status == models.BankStatementStatusReconciled {
+		return nil, errors.New("bank statement is already reconciled")
+	}
+
+	if dateWindowDays <= 0 {
+		dateWindowDays = defaultMatchDateWindow
+	}
+
+	startDate := statement.PeriodStart.AddDate(0, 0, -dateWindowDays)
+	endDate := statement.PeriodEnd.AddDate(0, 0, dateWindowDays)
+	items, err := s.statementRepo.GetLedgerItems(repository.LedgerItemFilter{
+		AccountID:     statement.AccountID,
+		BranchID:      statement.BranchID,
+		StartDate:     &startDate,
+		EndDate:       &endDate,
+		UnmatchedOnly: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	used := make(map[uuid.UUID]bool)
+	var matches []models.BankStatementLine
+	now := time.Now()
+
+	for _, line := range statement.Lines {
+		if line.IsMatched() {
+			continue
+		}
+
+		var candidates, referenced []models.LedgerItem
+		for _, item := range items {
+			if used[item.JournalLineID] || item.Amount != line.Amount {
+				continue
+			}
+			if daysBetween(line.TransactionDate, item.JournalDate) > dateWindowDays {
+				continue
+			}
+			candidates = append(candidates, item)
+			if referenceMatches(&line, &item) {
+				referenced = append(referenced, item)
+			}
+		}
+
+		var match *models.LedgerItem
+		switch {
+		case len(referenced) == 1:
+			match = &referenced[0]
+		case len(referenced) == 0 && len(candidates) == 1:
+			match = &candidates[0]
+		default:
+			continue
+		}
+
+		used[match.JournalLineID] = true
+		journalLineID := match.JournalLineID
+		line.JournalLineID = &journalLineID
+		line.MatchStatus = models.MatchStatusAuto
+		line.MatchedAt = &now
+		matches = append(matches, line)
+	}
+
+	if len(matches) > 0 {
+		if err := s.statementRepo.SaveMatches(matches); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.statementRepo.GetByID(id)
+}
+
+// GetUnmatched lists the statement lines and ledger lines still to be matched manually
+func (s *bankReconciliationService) GetUnmatched(id uuid.UUID) (*models.UnmatchedItemsResponse, error) {
+	statement, err := s.statementRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.statementRepo.GetLedgerItems(repository.LedgerItemFilter{
+		AccountID:     statement.AccountID,
+		BranchID:      statement.BranchID,
+		EndDate:       &statement.PeriodEnd,
+		UnmatchedOnly: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.UnmatchedItemsResponse{
+		StatementLines: make([]models.BankStatementLine, 0),
+		JournalLines:   items,
+	}
+	for _, line := range statement.Lines {
+		if !line.IsMatched() {
+			response.StatementLines = append(response.StatementLines, line)
+		}
+	}
+
+	return response, nil
+}
+
+// MatchLine manually matches a statement line to a posted journal line of the same account and branch
+func (s *bankReconciliationService) MatchLine(lineID uuid.UUID, req *models.MatchStatementLineRequest, userID uuid.UUID) (*models.BankStatementLine, error) {
+	line, statement, err := s.getOpenLine(lineID)
+	if err != nil {
+		return nil, err
+	}
+
+	if line.IsMatched() {
+		return nil, errors.New("bank statement line is already matched")
+	}
+
+	items, err := s.statementRepo.GetLedgerItems(repository.LedgerItemFilter{
+		AccountID:     statement.AccountID,
+		BranchID:      statement.BranchID,
+		JournalLineID: &req.JournalLineID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, errors.New("journal line is not a posted line of this bank account and branch")
+	}
+
+	if items[0].Amount != line.Amount {
+		return nil, errors.New("journal line amount does not match the statement line")
+	}
+
+	matched, err := s.statementRepo.IsJournalLineMatched(req.JournalLineID)
+	if err != nil {
+		return nil, err
+	}
+	if matched {
+		return nil, errors.New("journal line is already matched to another statement line")
+	}
+
+	now := time.Now()
+	line.JournalLineID = &req.JournalLineID
+	line.MatchStatus = models.MatchStatusManual
+	line.MatchedAt = &now
+	line.MatchedBy = &userID
+
+	if err := s.statementRepo.UpdateLine(line); err != nil {
+		return nil, err
+	}
+
+	return line, nil
+}
+
+func (s *bankReconciliationService) UnmatchLine(lineID uuid.UUID) (*models.BankStatementLine, error) {
+	line, _, err := s.getOpenLine(lineID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !line.IsMatched() {
+		return nil, errors.New("bank statement line is not matched")
+	}
+
+	line.JournalLineID = nil
+	line.MatchStatus = models.MatchStatusUnmatched
+	line.MatchedAt = nil
+	line.MatchedBy = nil
+
+	if err := s.statementRepo.UpdateLine(line); err != nil {
+		return nil, err
+	}
+
+	return line, nil
+}
+
+// Reconcile locks a fully matched statement and the journal lines matched to it
+func (s *bankReconciliationService) Reconcile(id uuid.UUID, userID uuid.UUID) (*models.BankStatement, error) {
+	statement, err := s.statementRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if statement.Status == models.BankStatementStatusReconciled {
+		return nil, errors.New("bank statement is already reconciled")
+	}
+
+	unmatched := 0
+	for _, line := range statement.Lines {
+		if !line.IsMatched() {
+			unmatched++
+		}
+	}
+	if unmatched > 0 {
+		return nil, errors.New(strconv.Itoa(unmatched) + " statement lines are still unmatched; book them in the ledger and match them first")
+	}
+
+	if err := s.statementRepo.Reconcile(statement, userID); err != nil {
+		return nil, err
+	}
+
+	return s.statementRepo.GetByID(id)
+}
+
+// GetReport compares the statement closing balance with the ledger balance at the statement end,
+// listing deposits and cheques booked in the ledger but not yet cleared by the bank
+func (s *bankReconciliationService) GetReport(id uuid.UUID) (*models.BankReconciliationReport, error) {
+	statement, err := s.statementRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.statementRepo.GetLedgerItems(repository.LedgerItemFilter{
+		AccountID:     statement.AccountID,
+		BranchID:      statement.BranchID,
+		EndDate:       &statement.PeriodEnd,
+		UnmatchedOnly: true,
+		MatchedBefore: &statement.PeriodEnd,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ledgerBalance, err := s.statementRepo.GetLedgerBalance(statement.AccountID, statement.BranchID, statement.PeriodEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.BankReconciliationReport{
+		StatementID:         statement.ID,
+		AccountCode:         statement.Account.Code,
+		AccountName:         statement.Account.Name,
+		PeriodEnd:           statement.PeriodEnd,
+		BankBalance:         statement.ClosingBalance,
+		OutstandingDeposits: make([]models.LedgerItem, 0),
+		OutstandingCheques:  make([]models.LedgerItem, 0),
+		UnmatchedBankItems:  make([]models.BankStatementLine, 0),
+		LedgerBalance:       ledgerBalance,
+	}
+
+	for _, item := range items {
+		if item.Amount >= 0 {
+			report.OutstandingDeposits = append(report.OutstandingDeposits, item)
+			report.TotalDeposits += item.Amount
+		} else {
+			report.OutstandingCheques = append(report.OutstandingCheques, item)
+			report.TotalCheques -= item.Amount
+		}
+	}
+
+	report.AdjustedLedgerBalance = ledgerBalance
+	for _, line := range statement.Lines {
+		if !line.IsMatched() {
+			report.UnmatchedBankItems = append(report.UnmatchedBankItems, line)
+			report.AdjustedLedgerBalance += line.Amount
+		}
+	}
+
+	report.AdjustedBankBalance = report.BankBalance + report.TotalDeposits - report.TotalCheques
+	report.Difference = report.AdjustedBankBalance - report.AdjustedLedgerBalance
+	report.IsReconciled = report.Difference == 0
+
+	return report, nil
+}
+
+func (s *bankReconciliationService) Delete(id uuid.UUID) error {
+	if _, err := s.statementRepo.GetByID(id); err != nil {
+		return err
+	}
+	return s.statementRepo.Delete(id)
+}
+
+// getOpenLine loads a statement line that can still be matched or unmatched
+func (s *bankReconciliationService) getOpenLine(lineID uuid.UUID) (*models.BankStatementLine, *models.BankStatement, error) {
+	line, err := s.statementRepo.GetLineByID(lineID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if line.IsReconciled {
+		return nil, nil, errors.New("bank statement line is reconciled")
+	}
+
+	statement, err := s.statementRepo.GetByID(line.StatementID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return line, statement, nil
+}
+
+// referenceMatches reports whether the statement line mentions the journal number, journal
+// reference or payment reference of a ledger item
+func referenceMatches(line *models.BankStatementLine, item *models.LedgerItem) bool {
+	text := strings.ToUpper(line.ReferenceNo + " " + line.Description)
+	for _, reference := range []string{item.JournalNumber, item.ReferenceNo, item.PaymentRefNo} {
+		reference = strings.ToUpper(strings.TrimSpace(reference))
+		// Very short references match too many descriptions to be meaningful
+		if len(reference) >= 4 && strings.Contains(text, reference) {
+			return true
+		}
+	}
+	return false
+}
+
+// daysBetween returns the absolute number of calendar days between two dates
+func daysBetween(a, b time.Time) int {
+	dayA := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	dayB := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	days := int(dayA.Sub(dayB).Hours() / 24)
+	if days < 0 {
+		return -days
+	}
+	return days
+}
@@ -0,0 +1,505 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/yayasan/erp-backend/internal/models"
+)
+
+// parsedStatement is the content of an imported bank statement file
+type parsedStatement struct {
+	openingBalance *models.Money
+	closingBalance *models.Money
+	lines          []models.BankStatementLine
+}
+
+// csvLayout lists the accepted header names (lowercase) of each column in a bank's CSV export.
+// Deposits and withdrawals come either from separate debit/credit columns or from one amount
+// column, signed by a direction column or a DB/CR suffix.
+type csvLayout struct {
+	date        []string
+	description []string
+	reference   []string
+	debit       []string // Withdrawals
+	credit      []string // Deposits
+	amount      []string
+	direction   []string
+	balance     []string
+	dateFormats []string
+}
+
+var csvLayouts = map[string]csvLayout{
+	models.BankStatementFormatBCA: {
+		date:        []string{"tanggal transaksi", "tanggal", "date"},
+		description: []string{"keterangan", "description"},
+		amount:      []string{"jumlah", "mutasi", "amount"},
+		direction:   []string{"db/cr", "jenis"},
+		balance:     []string{"saldo", "balance"},
+		dateFormats: []string{"02/01/2006", "02/01/06", "2006-01-02"},
+	},
+	models.BankStatementFormatMandiri: {
+		date:        []string{"date", "tanggal", "posting date", "tanggal transaksi"},
+		description: []string{"description", "keterangan", "remark", "remarks"},
+		reference:   []string{"reference no.", "reference no", "no. referensi", "ref no"},
+		debit:       []string{"debit", "debet"},
+		credit:      []string{"credit", "kredit"},
+		balance:     []string{"balance", "saldo"},
+		dateFormats: []string{"02/01/2006", "02/01/06", "2006-01-02", "02 Jan 2006", "02-Jan-2006"},
+	},
+	models.BankStatementFormatBNI: {
+		date:        []string{"post date", "tanggal transaksi", "tanggal", "date"},
+		description: []string{"description", "uraian transaksi", "keterangan"},
+		reference:   []string{"journal no.", "journal no", "no. jurnal", "reference"},
+		debit:       []string{"debit", "debet"},
+		credit:      []string{"credit", "kredit"},
+		amount:      []string{"amount", "nominal"},
+		direction:   []string{"db/cr", "d/k", "dc", "d/c"},
+		balance:     []string{"balance", "saldo"},
+		dateFormats: []string{"02/01/2006", "02-01-2006", "02-Jan-2006", "2006-01-02", "02/01/06"},
+	},
+	models.BankStatementFormatBRI: {
+		date:        []string{"tanggal", "tgl transaksi", "tanggal transaksi", "date"},
+		description: []string{"uraian", "uraian transaksi", "keterangan", "remark"},
+		reference:   []string{"no. referensi", "referensi", "teller"},
+		debit:       []string{"debet", "debit"},
+		credit:      []string{"kredit", "credit"},
+		balance:     []string{"saldo", "balance"},
+		dateFormats: []string{"02/01/06", "02/01/2006", "2006-01-02", "02-01-2006"},
+	},
+}
+
+// parseBankStatement parses a bank statement file in the given format
+func parseBankStatement(format string, data []byte) (*parsedStatement, error) {
+	var (
+		statement *parsedStatement
+		err       error
+	)
+
+	if format == models.BankStatementFormatMT940 {
+		statement, err = parseMT940(data)
+	} else {
+		layout, exists := csvLayouts[format]
+		if !exists {
+			return nil, errors.New("unsupported bank statement format")
+		}
+		statement, err = parseBankCSV(layout, data)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(statement.lines) == 0 {
+		return nil, errors.New("bank statement has no transactions")
+	}
+
+	for i := range statement.lines {
+		statement.lines[i].LineNo = i + 1
+		statement.lines[i].MatchStatus = models.MatchStatusUnmatched
+	}
+
+	return statement, nil
+}
+
+func parseBankCSV(layout csvLayout, data []byte) (*parsedStatement, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = detectDelimiter(data)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+
+	statement := &parsedStatement{}
+	var columns map[string]int
+	var lastBalance *models.Money
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.New("invalid CSV file: " + err.Error())
+		}
+
+		// Summary rows carry the statement balances
+		first := strings.ToLower(strings.TrimSpace(firstNonEmpty(record)))
+		if strings.HasPrefix(first, "saldo awal") || strings.HasPrefix(first, "opening balance") {
+			if amount, ok := lastAmount(record); ok {
+				statement.openingBalance = &amount
+			}
+			continue
+		}
+		if strings.HasPrefix(first, "saldo akhir") || strings.HasPrefix(first, "closing balance") {
+			if amount, ok := lastAmount(record); ok {
+				statement.closingBalance = &amount
+			}
+			continue
+		}
+
+		// Banks put account details above the column header row
+		if columns == nil {
+			columns = matchHeader(layout, record)
+			continue
+		}
+
+		date, err := parseStatementDate(cell(record, columns, "date"), layout.dateFormats)
+		if err != nil {
+			// Pending and footer rows have no transaction date
+			continue
+		}
+
+		amount, err := csvLineAmount(record, columns)
+		if err != nil {
+			return nil, errors.New("invalid amount on " + date.Format("2006-01-02") + ": " + err.Error())
+		}
+
+		line := models.BankStatementLine{
+			TransactionDate: date,
+			Description:     cell(record, columns, "description"),
+			ReferenceNo:     cell(record, columns, "reference"),
+			Amount:          amount,
+		}
+
+		if balanceText := cell(record, columns, "balance"); balanceText != "" {
+			if balance, err := parseStatementAmount(balanceText); err == nil {
+				if statement.openingBalance == nil && len(statement.lines) == 0 {
+					opening := balance - amount
+					statement.openingBalance = &opening
+				}
+				lastBalance = &balance
+			}
+		}
+
+		statement.lines = append(statement.lines, line)
+	}
+
+	if columns == nil {
+		return nil, errors.New("CSV header row not found for the selected bank format")
+	}
+
+	if statement.closingBalance == nil && lastBalance != nil {
+		statement.closingBalance = lastBalance
+	}
+
+	return statement, nil
+}
+
+// matchHeader maps column roles to indexes when the record is the layout's header row
+func matchHeader(layout csvLayout, record []string) map[string]int {
+	roles := map[string][]string{
+		"date":        layout.date,
+		"description": layout.description,
+		"reference":   layout.reference,
+		"debit":       layout.debit,
+		"credit":      layout.credit,
+		"amount":      layout.amount,
+		"direction":   layout.direction,
+		"balance":     layout.balance,
+	}
+
+	columns := make(map[string]int)
+	for i, value := range record {
+		name := strings.ToLower(strings.TrimSpace(value))
+		for role, names := range roles {
+			if _, exists := columns[role]; exists {
+				continue
+			}
+			for _, candidate := range names {
+				if name == candidate {
+					columns[role] = i
+					break
+				}
+			}
+		}
+	}
+
+	_, hasDate := columns["date"]
+	_, hasAmount := columns["amount"]
+	_, hasDebit := columns["debit"]
+	_, hasCredit := columns["credit"]
+	if !hasDate || !(hasAmount || (hasDebit && hasCredit)) {
+		return nil
+	}
+	return columns
+}
+
+// csvLineAmount returns the signed amount of a CSV row: deposits positive, withdrawals negative
+func csvLineAmount(record []string, columns map[string]int) (models.Money, error) {
+	if _, exists := columns["debit"]; exists {
+		debitText := cell(record, columns, "debit")
+		creditText := cell(record, columns, "credit")
+		if debitText != "" || creditText != "" {
+			var debit, credit models.Money
+			var err error
+			if debitText != "" {
+				if debit, err = parseStatementAmount(debitText); err != nil {
+					return 0, err
+				}
+			}
+			if creditText != "" {
+				if credit, err = parseStatementAmount(creditText); err != nil {
+					return 0, err
+				}
+			}
+			return credit - debit, nil
+		}
+	}
+
+	amountText := strings.ToUpper(cell(record, columns, "amount"))
+	if amountText == "" {
+		return 0, errors.New("amount is empty")
+	}
+
+	// BCA appends the direction to the amount, e.g. "1,500,000.00 DB"
+	direction := strings.ToUpper(cell(record, columns, "direction"))
+	if _, exists := columns["direction"]; !exists {
+		// KlikBCA exports put the direction in an unnamed column right after the amount
+		if next := columns["amount"] + 1; next < len(record) {
+			switch value := strings.ToUpper(strings.TrimSpace(record[next])); value {
+			case "DB", "CR":
+				direction = value
+			}
+		}
+	}
+	for _, suffix := range []string{"DB", "CR", "D", "K", "C"} {
+		if strings.HasSuffix(amountText, suffix) {
+			direction = suffix
+			amountText = strings.TrimSpace(strings.TrimSuffix(amountText, suffix))
+			break
+		}
+	}
+
+	amount, err := parseStatementAmount(amountText)
+	if err != nil {
+		return 0, err
+	}
+
+	switch direction {
+	case "DB", "D", "DEBIT", "DEBET":
+		if amount > 0 {
+			amount = -amount
+		}
+	case "CR", "K", "C", "CREDIT", "KREDIT":
+		if amount < 0 {
+			amount = -amount
+		}
+	}
+	return amount, nil
+}
+
+// mt940Transaction matches the :61: statement line field: value date, optional entry date,
+// debit/credit mark, optional funds code, amount and the remaining transaction details
+var mt940Transaction = regexp.MustCompile(`^(\d{6})(\d{4})?(RC|RD|C|D)[A-Z]?(\d+,\d*)(.*)$`)
+
+// mt940Balance matches the :60F:/:62F: balance fields
+var mt940Balance = regexp.MustCompile(`^([CD])(\d{6})[A-Z]{3}(\d+,\d*)`)
+
+func parseMT940(data []byte) (*parsedStatement, error) {
+	statement := &parsedStatement{}
+
+	// Collect tags, joining continuation lines to their field
+	var fields [][2]string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if strings.HasPrefix(text, ":") {
+			if end := strings.Index(text[1:], ":"); end > 0 {
+				fields = append(fields, [2]string{text[1 : end+1], text[end+2:]})
+				continue
+			}
+		}
+		if len(fields) > 0 && text != "-" && text != "" {
+			fields[len(fields)-1][1] += "\n" + text
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	var current *models.BankStatementLine
+	for _, field := range fields {
+		tag, value := field[0], field[1]
+
+		switch tag {
+		case "60F", "60M":
+			if statement.openingBalance == nil {
+				balance, err := parseMT940Balance(value)
+				if err != nil {
+					return nil, err
+				}
+				statement.openingBalance = &balance
+			}
+		case "62F", "62M":
+			balance, err := parseMT940Balance(value)
+			if err != nil {
+				return nil, err
+			}
+			statement.closingBalance = &balance
+		case "61":
+			match := mt940Transaction.FindStringSubmatch(strings.SplitN(value, "\n", 2)[0])
+			if match == nil {
+				return nil, errors.New("invalid MT940 transaction: " + value)
+			}
+
+			date, err := time.Parse("060102", match[1])
+			if err != nil {
+				return nil, errors.New("invalid MT940 value date: " + match[1])
+			}
+
+			amount, err := models.ParseMoney(strings.Replace(match[4], ",", ".", 1))
+			if err != nil {
+				return nil, errors.New("invalid MT940 amount: " + match[4])
+			}
+			// Debits and reversals of credits take money out of the account
+			if match[3] == "D" || match[3] == "RC" {
+				amount = -amount
+			}
+
+			// Transaction type (e.g. NTRF) followed by the customer reference and "//" bank reference
+			reference := match[5]
+			if len(reference) >= 4 {
+				reference = reference[4:]
+			}
+			if i := strings.Index(reference, "//"); i >= 0 {
+				reference = reference[:i]
+			}
+			if reference == "NONREF" {
+				reference = ""
+			}
+
+			statement.lines = append(statement.lines, models.BankStatementLine{
+				TransactionDate: date,
+				ReferenceNo:     strings.TrimSpace(reference),
+				Amount:          amount,
+			})
+			current = &statement.lines[len(statement.lines)-1]
+		case "86":
+			if current != nil {
+				current.Description = strings.TrimSpace(strings.ReplaceAll(value, "\n", " "))
+			}
+		}
+	}
+
+	return statement, nil
+}
+
+func parseMT940Balance(value string) (models.Money, error) {
+	match := mt940Balance.FindStringSubmatch(value)
+	if match == nil {
+		return 0, errors.New("invalid MT940 balance: " + value)
+	}
+
+	amount, err := models.ParseMoney(strings.Replace(match[3], ",", ".", 1))
+	if err != nil {
+		return 0, errors.New("invalid MT940 balance: " + value)
+	}
+	if match[1] == "D" {
+		amount = -amount
+	}
+	return amount, nil
+}
+
+// parseStatementAmount parses amounts in either Indonesian (1.500.000,00) or English (1,500,000.00)
+// notation. Parentheses and a leading minus mark negative amounts.
+func parseStatementAmount(text string) (models.Money, error) {
+	value := strings.TrimSpace(text)
+	value = strings.TrimPrefix(strings.TrimPrefix(value, "Rp"), "IDR")
+	value = strings.ReplaceAll(strings.TrimSpace(value), " ", "")
+
+	negative := false
+	if strings.HasPrefix(value, "(") && strings.HasSuffix(value, ")") {
+		negative = true
+		value = value[1 : len(value)-1]
+	}
+	if strings.HasPrefix(value, "-") {
+		negative = true
+		value = value[1:]
+	}
+
+	lastDot := strings.LastIndex(value, ".")
+	lastComma := strings.LastIndex(value, ",")
+	switch {
+	case lastDot >= 0 && lastComma >= 0:
+		// The separator that comes last is the decimal separator
+		if lastComma > lastDot {
+			value = strings.ReplaceAll(value, ".", "")
+			value = strings.Replace(value, ",", ".", 1)
+		} else {
+			value = strings.ReplaceAll(value, ",", "")
+		}
+	case lastComma >= 0:
+		if strings.Count(value, ",") == 1 && len(value)-lastComma-1 != 3 {
+			value = strings.Replace(value, ",", ".", 1)
+		} else {
+			value = strings.ReplaceAll(value, ",", "")
+		}
+	case lastDot >= 0:
+		if strings.Count(value, ".") > 1 || len(value)-lastDot-1 == 3 {
+			value = strings.ReplaceAll(value, ".", "")
+		}
+	}
+
+	amount, err := models.ParseMoney(value)
+	if err != nil {
+		return 0, errors.New("invalid amount " + text)
+	}
+	if negative {
+		amount = -amount
+	}
+	return amount, nil
+}
+
+func parseStatementDate(text string, formats []string) (time.Time, error) {
+	text = strings.TrimSpace(strings.TrimPrefix(text, "'"))
+	for _, format := range formats {
+		if date, err := time.Parse(format, text); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, errors.New("invalid date " + text)
+}
+
+// detectDelimiter picks semicolon or comma, whichever is more frequent in the first lines
+func detectDelimiter(data []byte) rune {
+	sample := data
+	if len(sample) > 4096 {
+		sample = sample[:4096]
+	}
+	if bytes.Count(sample, []byte(";")) > bytes.Count(sample, []byte(",")) {
+		return ';'
+	}
+	return ','
+}
+
+func cell(record []string, columns map[string]int, role string) string {
+	i, exists := columns[role]
+	if !exists || i >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[i])
+}
+
+func firstNonEmpty(record []string) string {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// lastAmount returns the last cell of a record that parses as an amount
+func lastAmount(record []string) (models.Money, bool) {
+	for i := len(record) - 1; i >= 0; i-- {
+		if amount, err := parseStatementAmount(record[i]); err == nil {
+			return amount, true
+		}
+	}
+	return 0, false
+}
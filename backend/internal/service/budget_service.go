@@ -212,7 +212,7 @@ func (s *budgetService) GetBudgetVsActual(req *models.BudgetVsActualRequest) (*m
 	}
 
 	lines := make([]models.BudgetVsActualLine, 0, len(budgets))
-	var totalBudget, totalActual float64
+	var totalBudget, totalActual models.Money
 
 	for _, budget := range budgets {
 		// Calculate actual spending
@@ -221,7 +221,7 @@ func (s *budgetService) GetBudgetVsActual(req *models.BudgetVsActualRequest) (*m
 		variance := budget.Amount - actual
 		variancePct := float64(0)
 		if budget.Amount > 0 {
-			variancePct = (variance / budget.Amount) * 100
+			variancePct = variance.Ratio(budget.Amount) * 100
 		}
 
 		lines = append(lines, models.BudgetVsActualLine{
@@ -240,7 +240,7 @@ func (s *budgetService) GetBudgetVsActual(req *models.BudgetVsActualRequest) (*m
 	totalVariance := totalBudget - totalActual
 	totalVariancePct := float64(0)
 	if totalBudget > 0 {
-		totalVariancePct = (totalVariance / totalBudget) * 100
+		totalVariancePct = totalVariance.Ratio(totalBudget) * 100
 	}
 
 	return &models.BudgetVsActualResponse{
@@ -258,8 +258,8 @@ func (s *budgetService) GetBudgetVsActual(req *models.BudgetVsActualRequest) (*m
 
 // Helper functions
 
-func (s *budgetService) calculateActualSpending(budget models.Budget) (float64, error) {
-	var totalDebit, totalCredit float64
+func (s *budgetService) calculateActualSpending(budget models.Budget) (models.Money, error) {
+	var totalDebit, totalCredit models.Money
 
 	query := s.db.Model(&models.JournalLine{}).
 		Select("COALESCE(SUM(debit), 0) as total_debit, COALESCE(SUM(credit), 0) as total_credit").
@@ -322,7 +322,7 @@ func (s *budgetService) toBudgetResponse(budget *models.Budget) *models.BudgetRe
 	resp.Actual = actual
 	resp.Variance = budget.Amount - actual
 	if budget.Amount > 0 {
-		resp.VariancePct = (resp.Variance / budget.Amount) * 100
+		resp.VariancePct = resp.Variance.Ratio(budget.Amount) * 100
 	}
 
 	return resp
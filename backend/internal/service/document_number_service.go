@@ -0,0 +1,116 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yayasan/erp-backend/internal/models"
+	"github.com/yayasan/erp-backend/internal/repository"
+)
+
+type DocumentNumberService interface {
+	GetFormats(branchID *uuid.UUID) ([]models.DocumentNumberFormat, error)
+	GetFormatByID(id uuid.UUID) (*models.DocumentNumberFormat, error)
+	SaveFormat(req *models.SaveDocumentNumberFormatRequest) (*models.DocumentNumberFormat, error)
+	DeleteFormat(id uuid.UUID) error
+	Preview(documentType, transactionType string, branchID uuid.UUID, date time.Time) (*models.DocumentNumberPreview, error)
+}
+
+type documentNumberService struct {
+	documentNumberRepo repository.DocumentNumberRepository
+	branchRepo         repository.BranchRepository
+}
+
+func NewDocumentNumberService(
+	documentNumberRepo repository.DocumentNumberRepository,
+	branchRepo repository.BranchRepository,
+) DocumentNumberService {
+	return &documentNumberService{
+		documentNumberRepo: documentNumberRepo,
+		branchRepo:         branchRepo,
+	}
+}
+
+func (s *documentNumberService) GetFormats(branchID *uuid.UUID) ([]models.DocumentNumberFormat, error) {
+	return s.documentNumberRepo.GetFormats(branchID)
+}
+
+func (s *documentNumberService) GetFormatByID(id uuid.UUID) (*models.DocumentNumberFormat, error) {
+	return s.documentNumberRepo.GetFormatByID(id)
+}
+
+// SaveFormat creates or replaces the number format of a document type for a branch, or for all
+// branches without a format of their own. Documents already numbered keep their numbers; the
+// next number continues after the highest existing number that fits the new format.
+func (s *documentNumberService) SaveFormat(req *models.SaveDocumentNumberFormatRequest) (*models.DocumentNumberFormat, error) {
+	// Validate branch if branch-specific
+	if req.BranchID != nil {
+		if _, err := s.branchRepo.GetByID(*req.BranchID); err != nil {
+			return nil, errors.New("branch not found")
+		}
+	}
+
+	format := &models.DocumentNumberFormat{
+		DocumentType: req.DocumentType,
+		BranchID:     req.BranchID,
+		Prefix:       req.Prefix,
+		Pattern:      req.Pattern,
+		Padding:      req.Padding,
+		ResetPeriod:  req.ResetPeriod,
+	}
+	if err := format.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Replace existing format for the same branch and document type
+	existing, err := s.documentNumberRepo.GetByBranchType(req.BranchID, req.DocumentType)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing != nil {
+		format.ID = existing.ID
+		format.CreatedAt = existing.CreatedAt
+		if err := s.documentNumberRepo.UpdateFormat(format); err != nil {
+			return nil, err
+		}
+		return s.documentNumberRepo.GetFormatByID(format.ID)
+	}
+
+	if err := s.documentNumberRepo.CreateFormat(format); err != nil {
+		return nil, err
+	}
+
+	return s.documentNumberRepo.GetFormatByID(format.ID)
+}
+
+// DeleteFormat removes a format, so its branch falls back to the format for all branches, or
+// that falls back to the built-in format
+func (s *documentNumberService) DeleteFormat(id uuid.UUID) error {
+	if _, err := s.documentNumberRepo.GetFormatByID(id); err != nil {
+		return err
+	}
+	return s.documentNumberRepo.DeleteFormat(id)
+}
+
+// Preview returns the number the next document of a type would get in a branch on a date. The
+// number is not reserved, so a document saved in between may take it.
+func (s *documentNumberService) Preview(documentType, transactionType string, branchID uuid.UUID, date time.Time) (*models.DocumentNumberPreview, error) {
+	if date.IsZero() {
+		date = time.Now()
+	}
+
+	number, format, err := s.documentNumberRepo.Preview(documentType, transactionType, branchID, date)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.DocumentNumberPreview{
+		DocumentType: documentType,
+		BranchID:     branchID,
+		Date:         date,
+		Format:       format,
+		NextNumber:   number,
+	}, nil
+}
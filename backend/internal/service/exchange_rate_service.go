@@ -0,0 +1,343 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yayasan/erp-backend/internal/config"
+	"github.com/yayasan/erp-backend/internal/models"
+	"github.com/yayasan/erp-backend/internal/repository"
+)
+
+type ExchangeRateService interface {
+	GetAll(currency string) ([]models.ExchangeRate, error)
+	Save(req *models.SaveExchangeRateRequest, userID uuid.UUID) (*models.ExchangeRate, error)
+	Delete(id uuid.UUID) error
+	GetRevaluations(branchID *uuid.UUID) ([]models.FXRevaluation, error)
+	Revalue(req *models.RunFXRevaluationRequest, userID *uuid.UUID) (*models.FXRevaluationResult, error)
+}
+
+type exchangeRateService struct {
+	exchangeRateRepo repository.ExchangeRateRepository
+	accountRepo      repository.AccountRepository
+	branchRepo       repository.BranchRepository
+	mappingRepo      repository.AccountMappingRepository
+	periodRepo       repository.AccountingPeriodRepository
+}
+
+func NewExchangeRateService(
+	exchangeRateRepo repository.ExchangeRateRepository,
+	accountRepo repository.AccountRepository,
+	branchRepo repository.BranchRepository,
+	mappingRepo repository.AccountMappingRepository,
+	periodRepo repository.AccountingPeriodRepository,
+) ExchangeRateService {
+	return &exchangeRateService{
+		exchangeRateRepo: exchangeRateRepo,
+		accountRepo:      accountRepo,
+		branchRepo:       branchRepo,
+		mappingRepo:      mappingRepo,
+		periodRepo:       periodRepo,
+	}
+}
+
+func (s *exchangeRateService) GetAll(currency string) ([]models.ExchangeRate, error) {
+	return s.exchangeRateRepo.GetAll(strings.ToUpper(currency))
+}
+
+// Save records the rate of a currency on a date, replacing an earlier rate for that date.
+// Journals already created keep the rate they were converted with.
+func (s *exchangeRateService) Save(req *models.SaveExchangeRateRequest, userID uuid.UUID) (*models.ExchangeRate, error) {
+	if !config.GlobalConfig.App.EnableMultiCurrency {
+		return nil, errors.New("multi-currency is not enabled")
+	}
+
+	currency := foreignCurrency(req.Currency)
+	if currency == "" {
+		return nil, errors.New("the default currency has no exchange rate")
+	}
+
+	rate := &models.ExchangeRate{
+		Currency:  currency,
+		RateDate:  dateOnly(req.RateDate),
+		Rate:      req.Rate,
+		CreatedBy: userID,
+	}
+	if err := s.exchangeRateRepo.Save(rate); err != nil {
+		return nil, err
+	}
+
+	return s.exchangeRateRepo.GetRate(rate.Currency, rate.RateDate)
+}
+
+func (s *exchangeRateService) Delete(id uuid.UUID) error {
+	if _, err := s.exchangeRateRepo.GetByID(id); err != nil {
+		return err
+	}
+	return s.exchangeRateRepo.Delete(id)
+}
+
+func (s *exchangeRateService) GetRevaluations(branchID *uuid.UUID) ([]models.FXRevaluation, error) {
+	return s.exchangeRateRepo.GetRevaluations(branchID)
+}
+
+// Revalue restates the foreign currency asset accounts of each branch at the rates of a month
+// end and posts the difference to the book balance as unrealized exchange gain or loss. Each
+// branch is revalued at most once per month end; a failed revaluation is retried on the next
+// run. The adjustment is not reversed, so the next month end only posts the further change.
+func (s *exchangeRateService) Revalue(req *models.RunFXRevaluationRequest, userID *uuid.UUID) (*models.FXRevaluationResult, error) {
+	if !config.GlobalConfig.App.EnableMultiCurrency {
+		return nil, errors.New("multi-currency is not enabled")
+	}
+
+	date := dateOnly(req.RevaluationDate)
+	if date.AddDate(0, 0, 1).Day() != 1 {
+		return nil, errors.New("revaluation date must be the last day of a month")
+	}
+	if date.After(time.Now()) {
+		return nil, errors.New("cannot revalue a future date")
+	}
+
+	if req.BranchID != nil {
+		if _, err := s.branchRepo.GetByID(*req.BranchID); err != nil {
+			return nil, errors.New("branch not found")
+		}
+	}
+
+	asOf := date.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	balances, err := s.exchangeRateRepo.GetForeignBalances(req.BranchID, asOf, config.GlobalConfig.App.DefaultCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.FXRevaluationResult{
+		RevaluationDate: date,
+		DryRun:          req.DryRun,
+		Lines:           []models.FXRevaluationLine{},
+		Revaluations:    []models.FXRevaluation{},
+	}
+
+	// Group balances per branch, keeping query order
+	var branchIDs []uuid.UUID
+	balancesByBranch := make(map[uuid.UUID][]models.ForeignCurrencyBalance)
+	for _, balance := range balances {
+		if _, exists := balancesByBranch[balance.BranchID]; !exists {
+			branchIDs = append(branchIDs, balance.BranchID)
+		}
+		balancesByBranch[balance.BranchID] = append(balancesByBranch[balance.BranchID], balance)
+	}
+
+	rates := make(map[string]*models.ExchangeRate)
+	for _, branchID := range branchIDs {
+		lines, err := s.revaluationLines(balancesByBranch[branchID], date, rates)
+		if req.DryRun {
+			if err != nil {
+				return nil, err
+			}
+			result.Lines = append(result.Lines, lines...)
+			continue
+		}
+
+		revaluation := &models.FXRevaluation{
+			BranchID:        branchID,
+			RevaluationDate: date,
+			Status:          models.FXRevaluationProcessing,
+			ProcessedBy:     userID,
+		}
+		claimed, claimErr := s.exchangeRateRepo.ClaimRevaluation(revaluation)
+		if claimErr != nil {
+			return nil, claimErr
+		}
+		if !claimed {
+			// Already revalued, or another run is still working on it
+			result.Revaluations = append(result.Revaluations, *revaluation)
+			continue
+		}
+
+		if err != nil {
+			revaluation.Status = models.FXRevaluationFailed
+			revaluation.Message = err.Error()
+			if err := s.exchangeRateRepo.SaveRevaluation(revaluation); err != nil {
+				return nil, err
+			}
+		} else {
+			result.Lines = append(result.Lines, lines...)
+			if err := s.postRevaluation(revaluation, lines, rates, userID); err != nil {
+				return nil, err
+			}
+		}
+		result.Revaluations = append(result.Revaluations, *revaluation)
+
+		switch revaluation.Status {
+		case models.FXRevaluationPosted:
+			result.Posted++
+		case models.FXRevaluationFailed:
+			result.Failed++
+		}
+	}
+
+	return result, nil
+}
+
+// revaluationLines restates the balances of one branch at the month-end rates. Rates are
+// looked up once per currency and must be dated in the month being revalued, so a missing
+// month-end rate is never replaced by an older one.
+func (s *exchangeRateService) revaluationLines(balances []models.ForeignCurrencyBalance, date time.Time, rates map[string]*models.ExchangeRate) ([]models.FXRevaluationLine, error) {
+	var lines []models.FXRevaluationLine
+	for _, balance := range balances {
+		rate, exists := rates[balance.Currency]
+		if !exists {
+			found, err := s.exchangeRateRepo.GetRate(balance.Currency, date)
+			if err != nil {
+				return nil, err
+			}
+			if found.RateDate.Before(beginningOfMonth(date)) {
+				return nil, fmt.Errorf("no %s exchange rate entered for %s", balance.Currency, date.Format("January 2006"))
+			}
+			rate = found
+			rates[balance.Currency] = rate
+		}
+
+		account, err := s.accountRepo.GetByID(balance.AccountID)
+		if err != nil {
+			return nil, err
+		}
+
+		revalued, err := balance.ForeignBalance.Mul(rate.Rate)
+		if err != nil {
+			return nil, fmt.Errorf("revaluing account %s: %w", account.Code, err)
+		}
+		lines = append(lines, models.FXRevaluationLine{
+			BranchID:        balance.BranchID,
+			AccountID:       balance.AccountID,
+			AccountCode:     account.Code,
+			AccountName:     account.Name,
+			Currency:        balance.Currency,
+			ForeignBalance:  balance.ForeignBalance,
+			Rate:            rate.Rate,
+			BookBalance:     balance.BookBalance,
+			RevaluedBalance: revalued,
+			Difference:      revalued - balance.BookBalance,
+		})
+	}
+	return lines, nil
+}
+
+// postRevaluation posts the journal of a claimed revaluation and saves the outcome on it. The
+// journal and the revaluation are saved together, so a posted journal is always linked to its
+// revaluation. Gains and losses are each booked in one line against the branch's
+// fx.unrealized_gain and fx.unrealized_loss accounts.
+func (s *exchangeRateService) postRevaluation(revaluation *models.FXRevaluation, lines []models.FXRevaluationLine, rates map[string]*models.ExchangeRate, userID *uuid.UUID) error {
+	fail := func(err error) error {
+		revaluation.Status = models.FXRevaluationFailed
+		revaluation.Message = err.Error()
+		revaluation.JournalID = nil
+		return s.exchangeRateRepo.SaveRevaluation(revaluation)
+	}
+
+	var journalLines []models.JournalLine
+	var gain, loss models.Money
+	description := "Revaluasi selisih kurs per " + revaluation.RevaluationDate.Format("02-01-2006")
+	for _, line := range lines {
+		if line.Difference == 0 {
+			continue
+		}
+
+		journalLine := models.JournalLine{
+			AccountID:    line.AccountID,
+			Description:  description + " (" + line.Currency + ")",
+			Currency:     line.Currency,
+			ExchangeRate: line.Rate,
+		}
+		if line.Difference > 0 {
+			journalLine.Debit = line.Difference
+			gain += line.Difference
+		} else {
+			journalLine.Credit = -line.Difference
+			loss -= line.Difference
+		}
+		journalLines = append(journalLines, journalLine)
+	}
+
+	revaluation.NetGain = gain - loss
+	revaluation.Message = ""
+	if len(journalLines) == 0 {
+		revaluation.Status = models.FXRevaluationUnchanged
+		return s.exchangeRateRepo.SaveRevaluation(revaluation)
+	}
+
+	if gain > 0 {
+		mapping, err := s.mappingRepo.Resolve(models.AccountMappingFXUnrealizedGain, revaluation.BranchID)
+		if err != nil {
+			return fail(err)
+		}
+		journalLines = append(journalLines, models.JournalLine{AccountID: mapping.AccountID, Description: description, Credit: gain})
+	}
+	if loss > 0 {
+		mapping, err := s.mappingRepo.Resolve(models.AccountMappingFXUnrealizedLoss, revaluation.BranchID)
+		if err != nil {
+			return fail(err)
+		}
+		journalLines = append(journalLines, models.JournalLine{AccountID: mapping.AccountID, Description: description, Debit: loss})
+	}
+
+	if err := ensurePeriodPostable(s.periodRepo, revaluation.BranchID, revaluation.RevaluationDate); err != nil {
+		return fail(err)
+	}
+
+	// Scheduled revaluations are booked as the user who entered the rate of the first line
+	createdBy := rates[lines[0].Currency].CreatedBy
+	if userID != nil {
+		createdBy = *userID
+	}
+
+	now := time.Now()
+	journal := &models.Journal{
+		BranchID:     revaluation.BranchID,
+		JournalDate:  revaluation.RevaluationDate,
+		Description:  description,
+		ReferenceNo:  "FXREV/" + revaluation.RevaluationDate.Format("2006-01"),
+		Type:         models.JournalTypeFXRevaluation,
+		Status:       models.JournalStatusPosted,
+		IsPosted:     true,
+		PostedAt:     &now,
+		PostedBy:     &createdBy,
+		CreatedBy:    createdBy,
+		JournalLines: journalLines,
+	}
+
+	revaluation.Status = models.FXRevaluationPosted
+	if err := s.exchangeRateRepo.SaveRevaluationJournal(revaluation, journal); err != nil {
+		return fail(err)
+	}
+	return nil
+}
+
+// RunFXRevaluationScheduler revalues foreign currency balances at the last month end, so each
+// month is revalued soon after it closes. Branches already revalued for that month end are left
+// alone, so checking again on later ticks only picks up the branches that failed.
+func RunFXRevaluationScheduler(ctx context.Context, exchangeRateService ExchangeRateService, interval time.Duration) {
+	runEvery(ctx, interval, func() {
+		monthEnd := beginningOfMonth(time.Now()).AddDate(0, 0, -1)
+		result, err := exchangeRateService.Revalue(&models.RunFXRevaluationRequest{RevaluationDate: monthEnd}, nil)
+		if err != nil {
+			log.Printf("FX revaluation failed: %v", err)
+		} else if result.Posted > 0 || result.Failed > 0 {
+			log.Printf("FX revaluation %s: %d posted, %d failed", monthEnd.Format("2006-01-02"), result.Posted, result.Failed)
+		}
+	})
+}
+
+// foreignCurrency normalizes a currency code, returning empty for the default currency
+func foreignCurrency(currency string) string {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == config.GlobalConfig.App.DefaultCurrency {
+		return ""
+	}
+	return currency
+}
@@ -0,0 +1,354 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yayasan/erp-backend/internal/config"
+	"github.com/yayasan/erp-backend/internal/models"
+	"github.com/yayasan/erp-backend/internal/repository"
+)
+
+type FiscalYearService interface {
+	GetAll() ([]models.FiscalYear, error)
+	GetByID(id uuid.UUID) (*models.FiscalYear, error)
+	GetCurrent() (*models.FiscalYear, error)
+	Create(req *models.CreateFiscalYearRequest) (*models.FiscalYear, error)
+	SetCurrent(id uuid.UUID) (*models.FiscalYear, error)
+	Close(id uuid.UUID, userID uuid.UUID) (*models.FiscalYear, error)
+	Reopen(id uuid.UUID, req *models.ReopenFiscalYearRequest, userID uuid.UUID) (*models.FiscalYear, error)
+}
+
+type fiscalYearService struct {
+	fiscalYearRepo repository.FiscalYearRepository
+	journalRepo    repository.JournalRepository
+	accountRepo    repository.AccountRepository
+	branchRepo     repository.BranchRepository
+}
+
+func NewFiscalYearService(
+	fiscalYearRepo repository.FiscalYearRepository,
+	journalRepo repository.JournalRepository,
+	accountRepo repository.AccountRepository,
+	branchRepo repository.BranchRepository,
+) FiscalYearService {
+	return &fiscalYearService{
+		fiscalYearRepo: fiscalYearRepo,
+		journalRepo:    journalRepo,
+		accountRepo:    accountRepo,
+		branchRepo:     branchRepo,
+	}
+}
+
+func (s *fiscalYearService) GetAll() ([]models.FiscalYear, error) {
+	return s.fiscalYearRepo.GetAll()
+}
+
+func (s *fiscalYearService) GetByID(id uuid.UUID) (*models.FiscalYear, error) {
+	return s.fiscalYearRepo.GetByID(id)
+}
+
+func (s *fiscalYearService) GetCurrent() (*models.FiscalYear, error) {
+	return s.fiscalYearRepo.GetCurrent()
+}
+
+func (s *fiscalYearService) Create(req *models.CreateFiscalYearRequest) (*models.FiscalYear, error) {
+	startDate := time.Date(req.StartDate.Year(), req.StartDate.Month(), req.StartDate.Day(), 0, 0, 0, 0, req.StartDate.Location())
+
+	// Fiscal years start on the first day of the configured month
+	startMonth := time.Month(config.GlobalConfig.App.FiscalYearStart)
+	if startMonth < time.January || startMonth > time.December {
+		startMonth = time.January
+	}
+	if startDate.Day() != 1 || startDate.Month() != startMonth {
+		return nil, errors.New("fiscal year must start on the first day of " + startMonth.String())
+	}
+
+	endDate := startDate.AddDate(1, 0, -1)
+	if req.EndDate != nil {
+		endDate = time.Date(req.EndDate.Year(), req.EndDate.Month(), req.EndDate.Day(), 0, 0, 0, 0, req.EndDate.Location())
+	}
+	if !endDate.After(startDate) {
+		return nil, errors.New("end date must be after start date")
+	}
+
+	overlap, err := s.fiscalYearRepo.HasOverlap(startDate, endDate, nil)
+	if err != nil {
+		return nil, err
+	}
+	if overlap {
+		return nil, errors.New("fiscal year overlaps an existing fiscal year")
+	}
+
+	fiscalYear := &models.FiscalYear{
+		Name:      req.Name,
+		StartDate: startDate,
+		EndDate:   endDate,
+		IsCurrent: req.IsCurrent,
+	}
+
+	if err := s.fiscalYearRepo.Create(fiscalYear); err != nil {
+		return nil, err
+	}
+
+	return s.fiscalYearRepo.GetByID(fiscalYear.ID)
+}
+
+// SetCurrent makes a fiscal year the only current one
+func (s *fiscalYearService) SetCurrent(id uuid.UUID) (*models.FiscalYear, error) {
+	fiscalYear, err := s.fiscalYearRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("fiscal year not found")
+	}
+
+	if fiscalYear.IsClosed {
+		return nil, errors.New("closed fiscal year cannot be set as current")
+	}
+
+	if err := s.fiscalYearRepo.SetCurrent(id); err != nil {
+		return nil, err
+	}
+
+	return s.fiscalYearRepo.GetByID(id)
+}
+
+// Close zeroes revenue and expense accounts into the current year surplus (R1) account,
+// moves the surplus to retained earnings (R) at the start of the next year and locks the year
+func (s *fiscalYearService) Close(id uuid.UUID, userID uuid.UUID) (*models.FiscalYear, error) {
+	fiscalYear, err := s.fiscalYearRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("fiscal year not found")
+	}
+
+	if fiscalYear.IsClosed {
+		return nil, errors.New("fiscal year is already closed")
+	}
+
+	// Years must be closed in order
+	fiscalYears, err := s.fiscalYearRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	for _, fy := range fiscalYears {
+		if fy.EndDate.Before(fiscalYear.StartDate) && !fy.IsClosed {
+			return nil, errors.New("previous fiscal year " + fy.Name + " must be closed first")
+		}
+	}
+
+	currentYearAccount, err := s.getRetainedEarningsAccount(models.AccountTypeRetainedCurr)
+	if err != nil {
+		return nil, err
+	}
+	retainedAccount, err := s.getRetainedEarningsAccount(models.AccountTypeRetained)
+	if err != nil {
+		return nil, err
+	}
+
+	yearEnd := fiscalYear.EndDate.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	movements, err := s.journalRepo.GetAccountMovements(nil, fiscalYear.StartDate, yearEnd, []string{
+		models.AccountCategoryRevenue,
+		models.AccountCategoryExpense,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Group movements per branch, keeping query order
+	var branchIDs []uuid.UUID
+	movementsByBranch := make(map[uuid.UUID][]models.AccountMovement)
+	for _, movement := range movements {
+		if _, exists := movementsByBranch[movement.BranchID]; !exists {
+			branchIDs = append(branchIDs, movement.BranchID)
+		}
+		movementsByBranch[movement.BranchID] = append(movementsByBranch[movement.BranchID], movement)
+	}
+
+	now := time.Now()
+	openingDate := fiscalYear.EndDate.AddDate(0, 0, 1)
+	var journals []*models.Journal
+
+	for _, branchID := range branchIDs {
+		closingLines, surplusByFund := buildClosingLines(movementsByBranch[branchID], currentYearAccount.ID)
+		if len(closingLines) == 0 {
+			continue
+		}
+
+		journals = append(journals, &models.Journal{
+			BranchID:     branchID,
+			JournalDate:  fiscalYear.EndDate,
+			Description:  "Jurnal penutup tahun buku " + fiscalYear.Name,
+			ReferenceNo:  closingReference(fiscalYear),
+			FiscalYearID: &fiscalYear.ID,
+			Status:       models.JournalStatusPosted,
+			IsPosted:     true,
+			PostedAt:     &now,
+			PostedBy:     &userID,
+			CreatedBy:    userID,
+			JournalLines: closingLines,
+		})
+
+		// Move the year's surplus from R1 to R in the opening period
+		var openingLines []models.JournalLine
+		for _, fund := range surplusByFund {
+			openingLines = append(openingLines,
+				closingLine(currentYearAccount.ID, fund.fundID, nil, -fund.amount, "Pemindahan surplus tahun berjalan"),
+				closingLine(retainedAccount.ID, fund.fundID, nil, fund.amount, "Pemindahan surplus tahun berjalan"),
+			)
+		}
+		if len(openingLines) == 0 {
+			continue
+		}
+
+		journals = append(journals, &models.Journal{
+			BranchID:     branchID,
+			JournalDate:  openingDate,
+			Description:  "Saldo awal: pemindahan surplus tahun buku " + fiscalYear.Name + " ke saldo laba",
+			ReferenceNo:  openingReference(fiscalYear),
+			FiscalYearID: &fiscalYear.ID,
+			Status:       models.JournalStatusPosted,
+			IsPosted:     true,
+			PostedAt:     &now,
+			PostedBy:     &userID,
+			CreatedBy:    userID,
+			JournalLines: openingLines,
+		})
+	}
+
+	fiscalYear.IsClosed = true
+	fiscalYear.ClosedAt = &now
+	fiscalYear.ClosedBy = &userID
+
+	if err := s.fiscalYearRepo.Close(fiscalYear, journals, openingDate.Format("2006-01")); err != nil {
+		return nil, err
+	}
+
+	return s.fiscalYearRepo.GetByID(fiscalYear.ID)
+}
+
+// Reopen reverses the closing and opening journals of a closed fiscal year and unlocks it
+func (s *fiscalYearService) Reopen(id uuid.UUID, req *models.ReopenFiscalYearRequest, userID uuid.UUID) (*models.FiscalYear, error) {
+	fiscalYear, err := s.fiscalYearRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("fiscal year not found")
+	}
+
+	if !fiscalYear.IsClosed {
+		return nil, errors.New("fiscal year is not closed")
+	}
+
+	// Years must be reopened in reverse order
+	fiscalYears, err := s.fiscalYearRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	for _, fy := range fiscalYears {
+		if fy.StartDate.After(fiscalYear.EndDate) && fy.IsClosed {
+			return nil, errors.New("later fiscal year " + fy.Name + " must be reopened first")
+		}
+	}
+
+	originals, err := s.journalRepo.GetByFiscalYear(fiscalYear.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reverse each journal on its own date so the closed year's figures are restored
+	reversals := make([]*models.Journal, len(originals))
+	for i := range originals {
+		reversals[i] = newReversalJournal(&originals[i], originals[i].JournalDate, req.Reason, userID)
+	}
+
+	if err := s.fiscalYearRepo.Reopen(fiscalYear, originals, reversals); err != nil {
+		return nil, err
+	}
+
+	return s.fiscalYearRepo.GetByID(fiscalYear.ID)
+}
+
+// closingReference is the reference number of a fiscal year's closing journals
+func closingReference(fiscalYear *models.FiscalYear) string {
+	return "CLOSE/" + fiscalYear.Name
+}
+
+// openingReference is the reference number of the opening journals that follow a fiscal year close
+func openingReference(fiscalYear *models.FiscalYear) string {
+	return "OPEN/" + fiscalYear.Name
+}
+
+// getRetainedEarningsAccount returns the first postable account of type R or R1
+func (s *fiscalYearService) getRetainedEarningsAccount(accountType string) (*models.Account, error) {
+	accounts, err := s.accountRepo.GetByType(accountType)
+	if err != nil {
+		return nil, err
+	}
+	for i := range accounts {
+		if accounts[i].IsDetail {
+			return &accounts[i], nil
+		}
+	}
+	return nil, errors.New("no detail account of type " + accountType + " configured for fiscal year close")
+}
+
+// fundSurplus is the year's surplus (credit positive) for one fund
+type fundSurplus struct {
+	fundID *uuid.UUID
+	amount models.Money
+}
+
+// buildClosingLines reverses every revenue and expense movement and books the
+// net surplus per fund to the current year surplus account
+func buildClosingLines(movements []models.AccountMovement, currentYearAccountID uuid.UUID) ([]models.JournalLine, []fundSurplus) {
+	var lines []models.JournalLine
+	var surpluses []fundSurplus
+	fundIndex := make(map[uuid.UUID]int)
+
+	for _, movement := range movements {
+		net := movement.Debit - movement.Credit
+		if net == 0 {
+			continue
+		}
+
+		// A debit balance is closed with a credit and vice versa
+		lines = append(lines, closingLine(movement.AccountID, movement.FundID, movement.ProgramID, -net, "Penutupan saldo akun"))
+
+		fundKey := uuid.Nil
+		if movement.FundID != nil {
+			fundKey = *movement.FundID
+		}
+		i, exists := fundIndex[fundKey]
+		if !exists {
+			i = len(surpluses)
+			fundIndex[fundKey] = i
+			surpluses = append(surpluses, fundSurplus{fundID: movement.FundID})
+		}
+		surpluses[i].amount -= net
+	}
+
+	var nonZero []fundSurplus
+	for _, surplus := range surpluses {
+		if surplus.amount == 0 {
+			continue
+		}
+		lines = append(lines, closingLine(currentYearAccountID, surplus.fundID, nil, surplus.amount, "Surplus (defisit) tahun berjalan"))
+		nonZero = append(nonZero, surplus)
+	}
+
+	return lines, nonZero
+}
+
+// closingLine creates a journal line from a signed amount: positive is credit, negative is debit
+func closingLine(accountID uuid.UUID, fundID, programID *uuid.UUID, amount models.Money, description string) models.JournalLine {
+	line := models.JournalLine{
+		AccountID:   accountID,
+		Description: description,
+		FundID:      fundID,
+		ProgramID:   programID,
+	}
+	if amount > 0 {
+		line.Credit = amount
+	} else {
+		line.Debit = -amount
+	}
+	return line
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/yayasan/erp-backend/internal/models"
+	"github.com/yayasan/erp-backend/internal/repository"
+)
+
+type FundService interface {
+	GetAll() ([]models.Fund, error)
+	GetAllActive() ([]models.Fund, error)
+	GetByID(id uuid.UUID) (*models.Fund, error)
+	Create(req *models.CreateFundRequest) (*models.Fund, error)
+	Update(id uuid.UUID, req *models.UpdateFundRequest) (*models.Fund, error)
+	Delete(id uuid.UUID) error
+}
+
+type fundService struct {
+	fundRepo repository.FundRepository
+}
+
+func NewFundService(fundRepo repository.FundRepository) FundService {
+	return &fundService{
+		fundRepo: fundRepo,
+	}
+}
+
+func (s *fundService) GetAll() ([]models.Fund, error) {
+	return s.fundRepo.GetAll()
+}
+
+func (s *fundService) GetAllActive() ([]models.Fund, error) {
+	return s.fundRepo.GetAllActive()
+}
+
+func (s *fundService) GetByID(id uuid.UUID) (*models.Fund, error) {
+	return s.fundRepo.GetByID(id)
+}
+
+func (s *fundService) Create(req *models.CreateFundRequest) (*models.Fund, error) {
+	// Check if code exists
+	existing, _ := s.fundRepo.GetByCode(req.Code)
+	if existing != nil {
+		return nil, errors.New("fund code already exists")
+	}
+
+	fund := &models.Fund{
+		Code:        req.Code,
+		Name:        req.Name,
+		Type:        req.Type,
+		Description: req.Description,
+		IsActive:    true,
+	}
+
+	if err := s.fundRepo.Create(fund); err != nil {
+		return nil, err
+	}
+
+	return fund, nil
+}
+
+func (s *fundService) Update(id uuid.UUID, req *models.UpdateFundRequest) (*models.Fund, error) {
+	fund, err := s.fundRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("fund not found")
+	}
+
+	fund.Name = req.Name
+	fund.Description = req.Description
+
+	if req.IsActive != nil {
+		fund.IsActive = *req.IsActive
+	}
+
+	if err := s.fundRepo.Update(fund); err != nil {
+		return nil, err
+	}
+
+	return fund, nil
+}
+
+// Delete removes an unused fund; funds with transactions must be deactivated instead
+func (s *fundService) Delete(id uuid.UUID) error {
+	if _, err := s.fundRepo.GetByID(id); err != nil {
+		return errors.New("fund not found")
+	}
+
+	used, err := s.fundRepo.IsUsed(id)
+	if err != nil {
+		return err
+	}
+	if used {
+		return errors.New("fund is already used, deactivate it instead")
+	}
+
+	return s.fundRepo.Delete(id)
+}
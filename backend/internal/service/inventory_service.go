@@ -178,32 +178,24 @@ func (s *inventoryService) createTransaction(req *models.CreateStockTransactionR
 		return nil, errors.New("item not found")
 	}
 
-	// Get branch
-	branch, err := s.branchRepo.GetByID(item.BranchID)
-	if err != nil {
-		return nil, err
-	}
-
-	// Generate transaction number
-	txNumber, err := s.inventoryRepo.GenerateTransactionNumber(txType, branch.Code, req.TransactionDate)
+	totalValue, err := req.UnitPrice.Mul(req.Quantity)
 	if err != nil {
 		return nil, err
 	}
 
 	// Create transaction
 	transaction := &models.StockTransaction{
-		TransactionNumber: txNumber,
-		ItemID:           req.ItemID,
-		BranchID:         item.BranchID,
-		TransactionType:  txType,
-		TransactionDate:  req.TransactionDate,
-		Quantity:         req.Quantity,
-		UnitPrice:        req.UnitPrice,
-		TotalValue:       req.Quantity * req.UnitPrice,
-		Supplier:         req.Supplier,
-		Customer:         req.Customer,
-		Reason:           req.Reason,
-		CreatedBy:        userID,
+		ItemID:          req.ItemID,
+		BranchID:        item.BranchID,
+		TransactionType: txType,
+		TransactionDate: req.TransactionDate,
+		Quantity:        req.Quantity,
+		UnitPrice:       req.UnitPrice,
+		TotalValue:      totalValue,
+		Supplier:        req.Supplier,
+		Customer:        req.Customer,
+		Reason:          req.Reason,
+		CreatedBy:       userID,
 	}
 
 	if err := s.inventoryRepo.CreateTransaction(transaction); err != nil {
@@ -218,21 +210,13 @@ func (s *inventoryService) GetTransactionHistory(itemID uuid.UUID) ([]models.Sto
 }
 
 func (s *inventoryService) CreateStockOpname(req *models.CreateStockOpnameRequest, userID uuid.UUID) (*models.StockOpname, error) {
-	// Get branch
-	branch, err := s.branchRepo.GetByID(req.BranchID)
-	if err != nil {
+	// Validate branch exists
+	if _, err := s.branchRepo.GetByID(req.BranchID); err != nil {
 		return nil, errors.New("branch not found")
 	}
 
-	// Generate opname number
-	opnameNumber, err := s.inventoryRepo.GenerateOpnameNumber(branch.Code, req.OpnameDate)
-	if err != nil {
-		return nil, err
-	}
-
-	// Create opname
+	// Create opname, numbered when it is saved
 	opname := &models.StockOpname{
-		OpnameNumber: opnameNumber,
 		BranchID:     req.BranchID,
 		OpnameDate:   req.OpnameDate,
 		Status:       models.OpnameStatusDraft,
@@ -247,7 +231,10 @@ func (s *inventoryService) CreateStockOpname(req *models.CreateStockOpnameReques
 		}
 
 		difference := itemReq.PhysicalStock - item.CurrentStock
-		differenceValue := difference * item.PurchasePrice
+		differenceValue, err := item.PurchasePrice.Mul(difference)
+		if err != nil {
+			return nil, err
+		}
 
 		opname.Items = append(opname.Items, models.StockOpnameItem{
 			ItemID:          itemReq.ItemID,
@@ -300,27 +287,19 @@ func (s *inventoryService) ProcessStockOpname(id uuid.UUID) error {
 			continue
 		}
 
-		branch, _ := s.branchRepo.GetByID(opname.BranchID)
-		txNumber, _ := s.inventoryRepo.GenerateTransactionNumber(
-			models.TransactionTypeOpname,
-			branch.Code,
-			opname.OpnameDate,
-		)
-
 		transaction := &models.StockTransaction{
-			TransactionNumber: txNumber,
-			ItemID:           item.ItemID,
-			BranchID:         opname.BranchID,
-			TransactionType:  models.TransactionTypeOpname,
-			TransactionDate:  opname.OpnameDate,
-			Quantity:         item.Difference,
-			UnitPrice:        item.UnitPrice,
-			TotalValue:       item.DifferenceValue,
-			Reason:           "Stock Opname: " + opname.OpnameNumber,
-			CreatedBy:        opname.PreparedBy,
-			ReferenceType:    "opname",
-			ReferenceID:      &opname.ID,
-			ReferenceNumber:  opname.OpnameNumber,
+			ItemID:          item.ItemID,
+			BranchID:        opname.BranchID,
+			TransactionType: models.TransactionTypeOpname,
+			TransactionDate: opname.OpnameDate,
+			Quantity:        item.Difference,
+			UnitPrice:       item.UnitPrice,
+			TotalValue:      item.DifferenceValue,
+			Reason:          "Stock Opname: " + opname.OpnameNumber,
+			CreatedBy:       opname.PreparedBy,
+			ReferenceType:   "opname",
+			ReferenceID:     &opname.ID,
+			ReferenceNumber: opname.OpnameNumber,
 		}
 
 		if err := s.inventoryRepo.CreateTransaction(transaction); err != nil {
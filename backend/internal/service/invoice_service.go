@@ -85,24 +85,11 @@ func (s *invoiceService) Create(req *CreateInvoiceRequest) (*models.Invoice, err
 		return nil, errors.New("student not found")
 	}
 
-	// Get branch
-	branch, err := s.branchRepo.GetByID(student.BranchID)
-	if err != nil {
-		return nil, err
-	}
-
-	// Generate invoice number
-	invoiceNumber, err := s.invoiceRepo.GenerateInvoiceNumber(branch.Code, req.InvoiceDate)
-	if err != nil {
-		return nil, err
-	}
-
 	// Create invoice
 	invoice := &models.Invoice{
 		StudentID:      req.StudentID,
 		BranchID:       student.BranchID,
 		AcademicYearID: req.AcademicYearID,
-		InvoiceNumber:  invoiceNumber,
 		InvoiceDate:    req.InvoiceDate,
 		DueDate:        req.DueDate,
 		Description:    req.Description,
@@ -182,9 +169,9 @@ type CreateInvoiceRequest struct {
 
 // InvoiceItemRequest for invoice item
 type InvoiceItemRequest struct {
-	FeeStructureID *uuid.UUID `json:"fee_structure_id"`
-	Description    string     `json:"description" binding:"required"`
-	Quantity       int        `json:"quantity" binding:"required,min=1"`
-	UnitPrice      float64    `json:"unit_price" binding:"required,gt=0"`
-	AccountID      *uuid.UUID `json:"account_id"`
+	FeeStructureID *uuid.UUID   `json:"fee_structure_id"`
+	Description    string       `json:"description" binding:"required"`
+	Quantity       int          `json:"quantity" binding:"required,min=1"`
+	UnitPrice      models.Money `json:"unit_price" binding:"required,gt=0"`
+	AccountID      *uuid.UUID   `json:"account_id"`
 }
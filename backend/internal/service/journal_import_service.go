@@ -0,0 +1,611 @@
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xuri/excelize/v2"
+	"github.com/yayasan/erp-backend/internal/models"
+	"github.com/yayasan/erp-backend/internal/repository"
+)
+
+// maxJournalImportRows limits the number of data rows in one import file
+const maxJournalImportRows = 5000
+
+// journalImportColumns lists the accepted header names of each column in a journal import file.
+// Headers are compared in lowercase with underscores and dashes read as spaces.
+var journalImportColumns = map[string][]string{
+	"journal_key":      {"journal key", "key", "journal", "no jurnal", "kode jurnal"},
+	"branch_code":      {"branch code", "branch", "kode cabang", "cabang"},
+	"date":             {"date", "journal date", "tanggal"},
+	"description":      {"description", "journal description", "keterangan"},
+	"reference":        {"reference", "reference no", "referensi", "no bukti"},
+	"account_code":     {"account code", "account", "kode akun", "akun"},
+	"line_description": {"line description", "memo", "keterangan baris"},
+	"debit":            {"debit", "debet"},
+	"credit":           {"credit", "kredit"},
+	"fund_code":        {"fund code", "fund", "kode dana", "dana"},
+	"program_code":     {"program code", "program", "kode program"},
+	"donor_code":       {"donor code", "donor", "kode donatur", "donatur"},
+}
+
+var journalImportDateFormats = []string{"2006-01-02", "02/01/2006", "2/1/2006", "02-01-2006", "02/01/06"}
+
+// journalImportRecord is a row of the import file with its line number in the file
+type journalImportRecord struct {
+	row   int
+	cells []string
+}
+
+// journalImportGroup collects the rows sharing a journal key
+type journalImportGroup struct {
+	result    *models.JournalImportJournal
+	branch    *models.Branch
+	date      time.Time
+	reference string
+	lines     []models.CreateJournalLineReq
+	firstRow  int
+	invalid   bool
+}
+
+// Import validates the journals in a CSV or XLSX file and creates the valid ones as drafts.
+// Rows are grouped into journals by their journal key. In all_or_nothing mode nothing is
+// imported unless every row is valid; in valid_only mode invalid journals are skipped.
+func (s *journalService) Import(req *models.ImportJournalsRequest, fileName string, data []byte, userID uuid.UUID) (*models.JournalImportResult, error) {
+	mode := req.Mode
+	if mode == "" {
+		mode = models.JournalImportAllOrNothing
+	}
+
+	var defaultBranch *models.Branch
+	if req.BranchID != nil {
+		branch, err := s.branchRepo.GetByID(*req.BranchID)
+		if err != nil {
+			return nil, errors.New("branch not found")
+		}
+		if !branch.IsActive {
+			return nil, errors.New("branch " + branch.Code + " is inactive")
+		}
+		defaultBranch = branch
+	}
+
+	records, rawValues, err := readJournalImportFile(fileName, data)
+	if err != nil {
+		return nil, err
+	}
+
+	// The first non-empty row is the header
+	for len(records) > 0 && strings.TrimSpace(firstNonEmpty(records[0].cells)) == "" {
+		records = records[1:]
+	}
+	if len(records) == 0 {
+		return nil, errors.New("import file is empty")
+	}
+
+	columns, err := journalImportHeader(records[0].cells)
+	if err != nil {
+		return nil, err
+	}
+	records = records[1:]
+
+	result := &models.JournalImportResult{
+		Mode:     mode,
+		DryRun:   req.DryRun,
+		Errors:   []models.JournalImportError{},
+		Journals: []models.JournalImportJournal{},
+	}
+
+	lookup := newJournalImportLookup(s.branchRepo, s.accountRepo, s.fundRepo, s.programRepo, s.donorRepo)
+	var groups []*journalImportGroup
+	groupByKey := make(map[string]*journalImportGroup)
+
+	for _, record := range records {
+		if strings.TrimSpace(firstNonEmpty(record.cells)) == "" {
+			continue
+		}
+
+		result.TotalRows++
+		if result.TotalRows > maxJournalImportRows {
+			return nil, fmt.Errorf("import file has more than %d rows", maxJournalImportRows)
+		}
+
+		key := cell(record.cells, columns, "journal_key")
+		rowError := func(column, message string) {
+			result.Errors = append(result.Errors, models.JournalImportError{
+				Row:        record.row,
+				JournalKey: key,
+				Column:     column,
+				Message:    message,
+			})
+		}
+
+		if key == "" {
+			rowError("journal_key", "journal key is required")
+			continue
+		}
+
+		group, exists := groupByKey[key]
+		if !exists {
+			group = &journalImportGroup{
+				result:   &models.JournalImportJournal{JournalKey: key, Rows: []int{}},
+				firstRow: record.row,
+			}
+			groupByKey[key] = group
+			groups = append(groups, group)
+		}
+		group.result.Rows = append(group.result.Rows, record.row)
+
+		errorCount := len(result.Errors)
+		s.importJournalRow(group, record, columns, rawValues, defaultBranch, lookup, rowError)
+		if len(result.Errors) > errorCount {
+			group.invalid = true
+		}
+	}
+
+	if result.TotalRows == 0 {
+		return nil, errors.New("import file has no rows")
+	}
+
+	// Journal level checks, the same as when creating a journal
+	for _, group := range groups {
+		if group.invalid {
+			continue
+		}
+
+		if err := s.validateImportedJournal(group); err != nil {
+			result.Errors = append(result.Errors, models.JournalImportError{
+				Row:        group.firstRow,
+				JournalKey: group.result.JournalKey,
+				Message:    err.Error(),
+			})
+			group.invalid = true
+		}
+	}
+
+	var valid []*journalImportGroup
+	for _, group := range groups {
+		group.result.Status = models.JournalImportStatusValid
+		if group.invalid {
+			group.result.Status = models.JournalImportStatusInvalid
+		} else {
+			valid = append(valid, group)
+		}
+	}
+	result.TotalJournals = len(groups)
+	result.ValidJournals = len(valid)
+
+	canImport := !req.DryRun && len(valid) > 0
+	if mode == models.JournalImportAllOrNothing && len(result.Errors) > 0 {
+		canImport = false
+	}
+
+	if canImport {
+		if err := s.createImportedJournals(valid, userID); err != nil {
+			return nil, err
+		}
+		result.ImportedJournals = len(valid)
+	}
+
+	for _, group := range groups {
+		result.Journals = append(result.Journals, *group.result)
+	}
+
+	return result, nil
+}
+
+// importJournalRow resolves the codes and amounts of a row and adds it to its journal as a line.
+// Problems are reported through rowError; a row with problems is not added.
+func (s *journalService) importJournalRow(group *journalImportGroup, record journalImportRecord, columns map[string]int, rawValues bool, defaultBranch *models.Branch, lookup *journalImportLookup, rowError func(column, message string)) {
+	valid := true
+	fail := func(column, message string) {
+		rowError(column, message)
+		valid = false
+	}
+
+	// Branch and date belong to the journal, so every row of a journal must agree on them
+	branch := defaultBranch
+	if code := cell(record.cells, columns, "branch_code"); code != "" {
+		found, err := lookup.branch(code)
+		if err != nil {
+			fail("branch_code", err.Error())
+		}
+		branch = found
+	} else if branch == nil {
+		fail("branch_code", "branch code is required when no branch is selected")
+	}
+
+	if branch != nil {
+		if group.branch == nil {
+			group.branch = branch
+			group.result.BranchID = &branch.ID
+		} else if group.branch.ID != branch.ID {
+			fail("branch_code", fmt.Sprintf("branch differs from row %d of the journal", group.firstRow))
+		}
+	}
+
+	date, err := parseJournalImportDate(cell(record.cells, columns, "date"), rawValues)
+	if err != nil {
+		fail("date", err.Error())
+	} else if group.result.JournalDate == nil {
+		group.date = date
+		group.result.JournalDate = &date
+	} else if !group.date.Equal(date) {
+		fail("date", fmt.Sprintf("date differs from row %d of the journal", group.firstRow))
+	}
+
+	if group.result.Description == "" {
+		group.result.Description = cell(record.cells, columns, "description")
+	}
+	if group.reference == "" {
+		group.reference = cell(record.cells, columns, "reference")
+	}
+
+	line := models.CreateJournalLineReq{
+		Description: cell(record.cells, columns, "line_description"),
+	}
+
+	accountCode := cell(record.cells, columns, "account_code")
+	if accountCode == "" {
+		fail("account_code", "account code is required")
+	} else if account, err := lookup.account(accountCode); err != nil {
+		fail("account_code", err.Error())
+	} else {
+		line.AccountID = account.ID
+	}
+
+	if code := cell(record.cells, columns, "fund_code"); code != "" {
+		if fund, err := lookup.fund(code); err != nil {
+			fail("fund_code", err.Error())
+		} else {
+			line.FundID = &fund.ID
+		}
+	}
+
+	if code := cell(record.cells, columns, "program_code"); code != "" {
+		if program, err := lookup.program(code); err != nil {
+			fail("program_code", err.Error())
+		} else {
+			line.ProgramID = &program.ID
+		}
+	}
+
+	if code := cell(record.cells, columns, "donor_code"); code != "" {
+		if donor, err := lookup.donor(code); err != nil {
+			fail("donor_code", err.Error())
+		} else {
+			line.DonorID = &donor.ID
+		}
+	}
+
+	for _, column := range []string{"debit", "credit"} {
+		amount, err := parseJournalImportAmount(cell(record.cells, columns, column), rawValues)
+		if err != nil {
+			fail(column, err.Error())
+			continue
+		}
+		if amount < 0 {
+			fail(column, column+" cannot be negative")
+			continue
+		}
+		if column == "debit" {
+			line.Debit = amount
+		} else {
+			line.Credit = amount
+		}
+	}
+
+	if !valid {
+		return
+	}
+
+	if err := validateJournalLine(s.accountRepo, s.fundRepo, s.programRepo, s.donorRepo, len(group.lines)+1, line); err != nil {
+		rowError("", err.Error())
+		return
+	}
+
+	group.lines = append(group.lines, line)
+	group.result.TotalDebit += line.Debit
+	group.result.TotalCredit += line.Credit
+}
+
+// validateImportedJournal runs the journal level checks of Create on an imported journal
+func (s *journalService) validateImportedJournal(group *journalImportGroup) error {
+	if group.result.Description == "" {
+		return errors.New("description is required")
+	}
+
+	if len(group.lines) < 2 {
+		return errors.New("journal must have at least 2 lines")
+	}
+
+	if err := ensurePeriodPostable(s.periodRepo, group.branch.ID, group.date); err != nil {
+		return err
+	}
+
+	if err := s.validateLineBranches(models.JournalTypeGeneral, group.branch.ID, group.date, group.lines); err != nil {
+		return err
+	}
+
+	if group.result.TotalDebit != group.result.TotalCredit {
+		return fmt.Errorf("journal is not balanced: debit %s != credit %s", group.result.TotalDebit, group.result.TotalCredit)
+	}
+
+	return nil
+}
+
+// createImportedJournals saves the valid journals as drafts in one transaction
+func (s *journalService) createImportedJournals(groups []*journalImportGroup, userID uuid.UUID) error {
+	journals := make([]*models.Journal, len(groups))
+
+	for i, group := range groups {
+		referenceNo := group.reference
+		if referenceNo == "" {
+			referenceNo = group.result.JournalKey
+		}
+
+		journal := &models.Journal{
+			BranchID:    group.branch.ID,
+			JournalDate: group.date,
+			Description: group.result.Description,
+			ReferenceNo: referenceNo,
+			Type:        models.JournalTypeGeneral,
+			Status:      models.JournalStatusDraft,
+			TotalDebit:  group.result.TotalDebit,
+			TotalCredit: group.result.TotalCredit,
+			CreatedBy:   userID,
+		}
+
+		journal.JournalLines = make([]models.JournalLine, len(group.lines))
+		for j, lineReq := range group.lines {
+			journal.JournalLines[j] = models.JournalLine{
+				AccountID:   lineReq.AccountID,
+				Description: lineReq.Description,
+				Debit:       lineReq.Debit,
+				Credit:      lineReq.Credit,
+				FundID:      lineReq.FundID,
+				ProgramID:   lineReq.ProgramID,
+				DonorID:     lineReq.DonorID,
+			}
+		}
+		journals[i] = journal
+	}
+
+	if err := s.journalRepo.CreateBatch(journals); err != nil {
+		return err
+	}
+
+	for i, group := range groups {
+		group.result.Status = models.JournalImportStatusImported
+		group.result.JournalID = &journals[i].ID
+		group.result.JournalNumber = journals[i].JournalNumber
+	}
+	return nil
+}
+
+// readJournalImportFile returns the rows of a CSV or XLSX file. For XLSX files the cell values
+// are returned raw, so dates are serial numbers and amounts are plain numbers.
+func readJournalImportFile(fileName string, data []byte) ([]journalImportRecord, bool, error) {
+	if strings.EqualFold(filepath.Ext(fileName), ".xlsx") || bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		workbook, err := excelize.OpenReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, false, errors.New("invalid XLSX file: " + err.Error())
+		}
+		defer workbook.Close()
+
+		sheets := workbook.GetSheetList()
+		if len(sheets) == 0 {
+			return nil, false, errors.New("XLSX file has no sheets")
+		}
+
+		rows, err := workbook.GetRows(sheets[0], excelize.Options{RawCellValue: true})
+		if err != nil {
+			return nil, false, errors.New("invalid XLSX file: " + err.Error())
+		}
+
+		records := make([]journalImportRecord, len(rows))
+		for i, row := range rows {
+			records[i] = journalImportRecord{row: i + 1, cells: row}
+		}
+		return records, true, nil
+	}
+
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	reader.Comma = detectDelimiter(data)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+
+	var records []journalImportRecord
+	for {
+		cells, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, false, errors.New("invalid CSV file: " + err.Error())
+		}
+		row, _ := reader.FieldPos(0)
+		records = append(records, journalImportRecord{row: row, cells: cells})
+	}
+	return records, false, nil
+}
+
+// journalImportHeader maps each known column to its index in the header row
+func journalImportHeader(header []string) (map[string]int, error) {
+	columns := importHeaderColumns(header, journalImportColumns)
+
+	for _, column := range []string{"journal_key", "date", "account_code"} {
+		if _, exists := columns[column]; !exists {
+			return nil, errors.New("import file has no " + column + " column")
+		}
+	}
+
+	_, hasDebit := columns["debit"]
+	_, hasCredit := columns["credit"]
+	if !hasDebit && !hasCredit {
+		return nil, errors.New("import file has no debit or credit column")
+	}
+
+	return columns, nil
+}
+
+// importHeaderColumns maps each column of an import file to its index in the header row, given
+// the accepted header names of each column
+func importHeaderColumns(header []string, columnAliases map[string][]string) map[string]int {
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		name = strings.NewReplacer("_", " ", "-", " ").Replace(name)
+
+		for column, aliases := range columnAliases {
+			if _, exists := columns[column]; exists {
+				continue
+			}
+			for _, alias := range aliases {
+				if name == alias {
+					columns[column] = i
+					break
+				}
+			}
+		}
+	}
+	return columns
+}
+
+func parseJournalImportDate(text string, rawValues bool) (time.Time, error) {
+	if text == "" {
+		return time.Time{}, errors.New("date is required")
+	}
+	if rawValues {
+		if serial, err := strconv.ParseFloat(text, 64); err == nil {
+			date, err := excelize.ExcelDateToTime(serial, false)
+			if err != nil {
+				return time.Time{}, errors.New("invalid date " + text)
+			}
+			return dateOnly(date), nil
+		}
+	}
+	return parseStatementDate(text, journalImportDateFormats)
+}
+
+func parseJournalImportAmount(text string, rawValues bool) (models.Money, error) {
+	if text == "" {
+		return 0, nil
+	}
+	if rawValues {
+		if amount, err := models.ParseMoney(text); err == nil {
+			return amount, nil
+		}
+	}
+	return parseStatementAmount(text)
+}
+
+// journalImportLookup resolves codes in an import file, remembering each code it has seen
+type journalImportLookup struct {
+	branchRepo  repository.BranchRepository
+	accountRepo repository.AccountRepository
+	fundRepo    repository.FundRepository
+	programRepo repository.ProgramRepository
+	donorRepo   repository.DonorRepository
+
+	branches map[string]*models.Branch
+	accounts map[string]*models.Account
+	funds    map[string]*models.Fund
+	programs map[string]*models.Program
+	donors   map[string]*models.Donor
+}
+
+func newJournalImportLookup(
+	branchRepo repository.BranchRepository,
+	accountRepo repository.AccountRepository,
+	fundRepo repository.FundRepository,
+	programRepo repository.ProgramRepository,
+	donorRepo repository.DonorRepository,
+) *journalImportLookup {
+	return &journalImportLookup{
+		branchRepo:  branchRepo,
+		accountRepo: accountRepo,
+		fundRepo:    fundRepo,
+		programRepo: programRepo,
+		donorRepo:   donorRepo,
+		branches:    make(map[string]*models.Branch),
+		accounts:    make(map[string]*models.Account),
+		funds:       make(map[string]*models.Fund),
+		programs:    make(map[string]*models.Program),
+		donors:      make(map[string]*models.Donor),
+	}
+}
+
+func (l *journalImportLookup) branch(code string) (*models.Branch, error) {
+	branch, exists := l.branches[code]
+	if !exists {
+		branch, _ = l.branchRepo.GetByCode(code)
+		l.branches[code] = branch
+	}
+	if branch == nil {
+		return nil, errors.New("branch " + code + " not found")
+	}
+	if !branch.IsActive {
+		return nil, errors.New("branch " + code + " is inactive")
+	}
+	return branch, nil
+}
+
+func (l *journalImportLookup) account(code string) (*models.Account, error) {
+	account, exists := l.accounts[code]
+	if !exists {
+		account, _ = l.accountRepo.GetByCode(code)
+		l.accounts[code] = account
+	}
+	if account == nil {
+		return nil, errors.New("account " + code + " not found")
+	}
+	return account, nil
+}
+
+func (l *journalImportLookup) fund(code string) (*models.Fund, error) {
+	fund, exists := l.funds[code]
+	if !exists {
+		fund, _ = l.fundRepo.GetByCode(code)
+		l.funds[code] = fund
+	}
+	if fund == nil {
+		return nil, errors.New("fund " + code + " not found")
+	}
+	return fund, nil
+}
+
+func (l *journalImportLookup) program(code string) (*models.Program, error) {
+	program, exists := l.programs[code]
+	if !exists {
+		program, _ = l.programRepo.GetByCode(code)
+		l.programs[code] = program
+	}
+	if program == nil {
+		return nil, errors.New("program " + code + " not found")
+	}
+	return program, nil
+}
+
+func (l *journalImportLookup) donor(code string) (*models.Donor, error) {
+	donor, exists := l.donors[code]
+	if !exists {
+		donor, _ = l.donorRepo.GetByCode(code)
+		l.donors[code] = donor
+	}
+	if donor == nil {
+		return nil, errors.New("donor " + code + " not found")
+	}
+	if !donor.IsActive {
+		return nil, errors.New("donor " + code + " is inactive")
+	}
+	return donor, nil
+}
@@ -2,6 +2,8 @@ package service
 
 import (
 	"errors"
+	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,49 +13,73 @@ import (
 )
 
 type JournalService interface {
-	GetAll(params *models.PaginationParams) (*models.JournalListResponse, error)
+	Search(params *models.JournalSearchParams) (*models.JournalListResponse, error)
+	SearchLines(params *models.JournalSearchParams) (*models.JournalLineListResponse, error)
+	SearchAll(params *models.JournalSearchParams) ([]models.JournalResponse, error)
+	SearchAllLines(params *models.JournalSearchParams) ([]models.JournalLineSearchResult, error)
 	GetByID(id uuid.UUID) (*models.Journal, error)
 	GetByStatus(status string, params *models.PaginationParams) (*models.JournalListResponse, error)
 	Create(req *models.CreateJournalRequest, userID uuid.UUID) (*models.Journal, error)
+	Prepare(req *models.CreateJournalRequest, userID uuid.UUID) (*models.Journal, error)
 	Update(id uuid.UUID, req *models.UpdateJournalRequest, userID uuid.UUID) (*models.Journal, error)
 	Delete(id uuid.UUID, userID uuid.UUID) error
 	SubmitForReview(id uuid.UUID, userID uuid.UUID) (*models.Journal, error)
 	Review(id uuid.UUID, req *models.ReviewJournalRequest, userID uuid.UUID) (*models.Journal, error)
 	Post(id uuid.UUID, req *models.PostJournalRequest, userID uuid.UUID) (*models.Journal, error)
-	Unpost(id uuid.UUID, userID uuid.UUID) (*models.Journal, error)
+	Reverse(id uuid.UUID, req *models.ReverseJournalRequest, userID uuid.UUID) (*models.Journal, error)
+	GetApprovals(id uuid.UUID) ([]models.ApprovalRequest, error)
+	Import(req *models.ImportJournalsRequest, fileName string, data []byte, userID uuid.UUID) (*models.JournalImportResult, error)
 }
 
 type journalService struct {
-	journalRepo repository.JournalRepository
-	accountRepo repository.AccountRepository
-	branchRepo  repository.BranchRepository
+	journalRepo      repository.JournalRepository
+	accountRepo      repository.AccountRepository
+	branchRepo       repository.BranchRepository
+	periodRepo       repository.AccountingPeriodRepository
+	fundRepo         repository.FundRepository
+	programRepo      repository.ProgramRepository
+	donorRepo        repository.DonorRepository
+	mappingRepo      repository.AccountMappingRepository
+	exchangeRateRepo repository.ExchangeRateRepository
+	approvalService  ApprovalService
 }
 
 func NewJournalService(
 	journalRepo repository.JournalRepository,
 	accountRepo repository.AccountRepository,
 	branchRepo repository.BranchRepository,
+	periodRepo repository.AccountingPeriodRepository,
+	fundRepo repository.FundRepository,
+	programRepo repository.ProgramRepository,
+	donorRepo repository.DonorRepository,
+	mappingRepo repository.AccountMappingRepository,
+	exchangeRateRepo repository.ExchangeRateRepository,
+	approvalService ApprovalService,
 ) JournalService {
 	return &journalService{
-		journalRepo: journalRepo,
-		accountRepo: accountRepo,
-		branchRepo:  branchRepo,
+		journalRepo:      journalRepo,
+		accountRepo:      accountRepo,
+		branchRepo:       branchRepo,
+		periodRepo:       periodRepo,
+		fundRepo:         fundRepo,
+		programRepo:      programRepo,
+		donorRepo:        donorRepo,
+		mappingRepo:      mappingRepo,
+		exchangeRateRepo: exchangeRateRepo,
+		approvalService:  approvalService,
 	}
 }
 
-func (s *journalService) GetAll(params *models.PaginationParams) (*models.JournalListResponse, error) {
-	// Set defaults
-	if params.Page < 1 {
-		params.Page = 1
-	}
-	if params.PageSize < 1 {
-		params.PageSize = config.GlobalConfig.App.DefaultPageSize
-	}
-	if params.PageSize > config.GlobalConfig.App.MaxPageSize {
-		params.PageSize = config.GlobalConfig.App.MaxPageSize
+// maxJournalExportRows caps the rows of a journal search export
+const maxJournalExportRows = 10000
+
+func (s *journalService) Search(params *models.JournalSearchParams) (*models.JournalListResponse, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
 	}
+	setSearchPage(params)
 
-	journals, total, err := s.journalRepo.GetAll(params)
+	journals, total, err := s.journalRepo.Search(params)
 	if err != nil {
 		return nil, err
 	}
@@ -64,20 +90,117 @@ func (s *journalService) GetAll(params *models.PaginationParams) (*models.Journa
 		journalResponses[i] = *journal.ToJournalResponse()
 	}
 
-	totalPages := int(total) / params.PageSize
-	if int(total)%params.PageSize > 0 {
-		totalPages++
-	}
-
 	return &models.JournalListResponse{
 		Journals:   journalResponses,
 		Total:      total,
 		Page:       params.Page,
 		PageSize:   params.PageSize,
-		TotalPages: totalPages,
+		TotalPages: searchTotalPages(total, params.PageSize),
 	}, nil
 }
 
+func (s *journalService) SearchLines(params *models.JournalSearchParams) (*models.JournalLineListResponse, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	setSearchPage(params)
+
+	lines, total, err := s.journalRepo.SearchLines(params)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]models.JournalLineSearchResult, len(lines))
+	for i := range lines {
+		results[i] = *lines[i].ToJournalLineSearchResult()
+	}
+
+	return &models.JournalLineListResponse{
+		Lines:      results,
+		Total:      total,
+		Page:       params.Page,
+		PageSize:   params.PageSize,
+		TotalPages: searchTotalPages(total, params.PageSize),
+	}, nil
+}
+
+// SearchAll returns every journal found by a search, for exporting
+func (s *journalService) SearchAll(params *models.JournalSearchParams) ([]models.JournalResponse, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	total, err := s.journalRepo.CountSearch(params)
+	if err != nil {
+		return nil, err
+	}
+	if total > maxJournalExportRows {
+		return nil, fmt.Errorf("the search found %d journals, narrow it to at most %d to export", total, maxJournalExportRows)
+	}
+
+	// The page still caps the load in case rows were added since the count
+	params.Page, params.PageSize = 1, maxJournalExportRows
+	journals, _, err := s.journalRepo.Search(params)
+	if err != nil {
+		return nil, err
+	}
+
+	journalResponses := make([]models.JournalResponse, len(journals))
+	for i, journal := range journals {
+		journalResponses[i] = *journal.ToJournalResponse()
+	}
+	return journalResponses, nil
+}
+
+// SearchAllLines returns every journal line found by a search, for exporting
+func (s *journalService) SearchAllLines(params *models.JournalSearchParams) ([]models.JournalLineSearchResult, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	total, err := s.journalRepo.CountSearchLines(params)
+	if err != nil {
+		return nil, err
+	}
+	if total > maxJournalExportRows {
+		return nil, fmt.Errorf("the search found %d journal lines, narrow it to at most %d to export", total, maxJournalExportRows)
+	}
+
+	// The page still caps the load in case rows were added since the count
+	params.Page, params.PageSize = 1, maxJournalExportRows
+	lines, _, err := s.journalRepo.SearchLines(params)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]models.JournalLineSearchResult, len(lines))
+	for i := range lines {
+		results[i] = *lines[i].ToJournalLineSearchResult()
+	}
+	return results, nil
+}
+
+// setSearchPage sets the page defaults of a search
+func setSearchPage(params *models.JournalSearchParams) {
+	if params.Page < 1 {
+		params.Page = 1
+	}
+	if params.PageSize < 1 {
+		params.PageSize = config.GlobalConfig.App.DefaultPageSize
+	}
+	if params.PageSize > config.GlobalConfig.App.MaxPageSize {
+		params.PageSize = config.GlobalConfig.App.MaxPageSize
+	}
+}
+
+func searchTotalPages(total int64, pageSize int) int {
+	totalPages := int(total) / pageSize
+	if int(total)%pageSize > 0 {
+		totalPages++
+	}
+	return totalPages
+}
+
 func (s *journalService) GetByID(id uuid.UUID) (*models.Journal, error) {
 	return s.journalRepo.GetByID(id)
 }
@@ -115,19 +238,52 @@ func (s *journalService) GetByStatus(status string, params *models.PaginationPar
 }
 
 func (s *journalService) Create(req *models.CreateJournalRequest, userID uuid.UUID) (*models.Journal, error) {
-	// Validate branch exists
-	branch, err := s.branchRepo.GetByID(req.BranchID)
+	journal, err := s.Prepare(req, userID)
 	if err != nil {
+		return nil, err
+	}
+
+	if err := s.journalRepo.Create(journal); err != nil {
+		return nil, err
+	}
+
+	return s.journalRepo.GetByID(journal.ID)
+}
+
+// Prepare runs the checks of Create and builds the draft journal without saving it, for callers
+// that save it together with records of their own
+func (s *journalService) Prepare(req *models.CreateJournalRequest, userID uuid.UUID) (*models.Journal, error) {
+	// Validate branch exists
+	if _, err := s.branchRepo.GetByID(req.BranchID); err != nil {
 		return nil, errors.New("branch not found")
 	}
 
+	// Validate period is not closed
+	if err := ensurePeriodPostable(s.periodRepo, req.BranchID, req.JournalDate); err != nil {
+		return nil, err
+	}
+
+	// Convert foreign currency lines at the rate of the journal date
+	if err := s.convertForeignLines(req.JournalLines, req.JournalDate); err != nil {
+		return nil, err
+	}
+
 	// Validate journal lines
 	if err := s.validateJournalLines(req.JournalLines); err != nil {
 		return nil, err
 	}
 
+	journalType := req.Type
+	if journalType == "" {
+		journalType = models.JournalTypeGeneral
+	}
+
+	if err := s.validateLineBranches(journalType, req.BranchID, req.JournalDate, req.JournalLines); err != nil {
+		return nil, err
+	}
+
 	// Check balance
-	var totalDebit, totalCredit float64
+	var totalDebit, totalCredit models.Money
 	for _, line := range req.JournalLines {
 		totalDebit += line.Debit
 		totalCredit += line.Credit
@@ -137,23 +293,17 @@ func (s *journalService) Create(req *models.CreateJournalRequest, userID uuid.UU
 		return nil, errors.New("journal is not balanced: debit != credit")
 	}
 
-	// Generate journal number
-	journalNumber, err := s.journalRepo.GenerateJournalNumber(branch.Code, req.JournalDate)
-	if err != nil {
-		return nil, err
-	}
-
-	// Create journal
+	// Create journal, numbered when it is saved
 	journal := &models.Journal{
-		BranchID:      req.BranchID,
-		JournalNumber: journalNumber,
-		JournalDate:   req.JournalDate,
-		Description:   req.Description,
-		ReferenceNo:   req.ReferenceNo,
-		Status:        models.JournalStatusDraft,
-		TotalDebit:    totalDebit,
-		TotalCredit:   totalCredit,
-		CreatedBy:     userID,
+		BranchID:    req.BranchID,
+		JournalDate: req.JournalDate,
+		Description: req.Description,
+		ReferenceNo: req.ReferenceNo,
+		Type:        journalType,
+		Status:      models.JournalStatusDraft,
+		TotalDebit:  totalDebit,
+		TotalCredit: totalCredit,
+		CreatedBy:   userID,
 	}
 
 	// Add journal lines
@@ -167,14 +317,15 @@ func (s *journalService) Create(req *models.CreateJournalRequest, userID uuid.UU
 			FundID:      lineReq.FundID,
 			ProgramID:   lineReq.ProgramID,
 			DonorID:     lineReq.DonorID,
+			BranchID:    lineReq.BranchID,
+			CounterpartBranchID: lineReq.CounterpartBranchID,
+			Currency:      lineReq.Currency,
+			ForeignAmount: lineReq.ForeignAmount,
+			ExchangeRate:  lineReq.ExchangeRate,
 		}
 	}
 
-	if err := s.journalRepo.Create(journal); err != nil {
-		return nil, err
-	}
-
-	return s.journalRepo.GetByID(journal.ID)
+	return journal, nil
 }
 
 func (s *journalService) Update(id uuid.UUID, req *models.UpdateJournalRequest, userID uuid.UUID) (*models.Journal, error) {
@@ -193,13 +344,27 @@ func (s *journalService) Update(id uuid.UUID, req *models.UpdateJournalRequest,
 		return nil, errors.New("only creator can update this journal")
 	}
 
+	// Validate period is not closed
+	if err := ensurePeriodPostable(s.periodRepo, journal.BranchID, req.JournalDate); err != nil {
+		return nil, err
+	}
+
+	// Convert foreign currency lines at the rate of the journal date
+	if err := s.convertForeignLines(req.JournalLines, req.JournalDate); err != nil {
+		return nil, err
+	}
+
 	// Validate lines
 	if err := s.validateJournalLines(req.JournalLines); err != nil {
 		return nil, err
 	}
 
+	if err := s.validateLineBranches(journal.Type, journal.BranchID, req.JournalDate, req.JournalLines); err != nil {
+		return nil, err
+	}
+
 	// Check balance
-	var totalDebit, totalCredit float64
+	var totalDebit, totalCredit models.Money
 	for _, line := range req.JournalLines {
 		totalDebit += line.Debit
 		totalCredit += line.Credit
@@ -228,6 +393,11 @@ func (s *journalService) Update(id uuid.UUID, req *models.UpdateJournalRequest,
 			FundID:      lineReq.FundID,
 			ProgramID:   lineReq.ProgramID,
 			DonorID:     lineReq.DonorID,
+			BranchID:    lineReq.BranchID,
+			CounterpartBranchID: lineReq.CounterpartBranchID,
+			Currency:      lineReq.Currency,
+			ForeignAmount: lineReq.ForeignAmount,
+			ExchangeRate:  lineReq.ExchangeRate,
 		}
 	}
 
@@ -278,10 +448,13 @@ func (s *journalService) SubmitForReview(id uuid.UUID, userID uuid.UUID) (*model
 		return nil, errors.New("journal must be balanced before submission")
 	}
 
-	// Update status
-	journal.Status = models.JournalStatusReview
-	
-	if err := s.journalRepo.Update(journal); err != nil {
+	// Start the approval chain for the journal's branch and amount, saved with the status
+	request, history, err := s.approvalService.PrepareSubmit(models.ApprovalDocumentJournal, journal.ID, journal.BranchID, journal.TotalDebit, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.journalRepo.SubmitForReview(journal, request, history); err != nil {
 		return nil, err
 	}
 
@@ -304,28 +477,63 @@ func (s *journalService) Review(id uuid.UUID, req *models.ReviewJournalRequest,
 		return nil, errors.New("cannot review your own journal")
 	}
 
-	now := time.Now()
+	// Journals submitted before approval chains existed have no pending approval yet
+	if err := s.ensureApprovalStarted(journal); err != nil {
+		return nil, err
+	}
+
+	// Record the step; the journal stays in review until the last step is approved
+	approval, history, err := s.approvalService.PrepareAct(models.ApprovalDocumentJournal, journal.ID, req.Action == "approve", req.Notes, userID)
+	if err != nil {
+		return nil, err
+	}
 
-	if req.Action == "approve" {
-		// Approve
+	switch approval.Status {
+	case models.ApprovalStatusApproved:
 		journal.Status = models.JournalStatusApproved
 		journal.ApprovedBy = &userID
-		journal.ApprovedAt = &now
-	} else {
-		// Reject
+		journal.ApprovedAt = approval.CompletedAt
+	case models.ApprovalStatusRejected:
 		journal.Status = models.JournalStatusRejected
 		journal.RejectedBy = &userID
-		journal.RejectedAt = &now
+		journal.RejectedAt = approval.CompletedAt
 		journal.RejectReason = req.Notes
 	}
 
-	if err := s.journalRepo.Update(journal); err != nil {
+	if err := s.journalRepo.RecordReview(journal, approval, history); err != nil {
 		return nil, err
 	}
 
 	return s.journalRepo.GetByID(journal.ID)
 }
 
+// ensureApprovalStarted starts the approval of a journal in review that has none pending
+func (s *journalService) ensureApprovalStarted(journal *models.Journal) error {
+	requests, err := s.approvalService.GetRequests(models.ApprovalDocumentJournal, journal.ID)
+	if err != nil {
+		return err
+	}
+	for _, request := range requests {
+		if request.Status == models.ApprovalStatusPending {
+			return nil
+		}
+	}
+
+	request, history, err := s.approvalService.PrepareSubmit(models.ApprovalDocumentJournal, journal.ID, journal.BranchID, journal.TotalDebit, journal.CreatedBy)
+	if err != nil {
+		return err
+	}
+	return s.journalRepo.SubmitForReview(journal, request, history)
+}
+
+// GetApprovals returns the approval submissions of a journal with their history
+func (s *journalService) GetApprovals(id uuid.UUID) ([]models.ApprovalRequest, error) {
+	if _, err := s.journalRepo.GetByID(id); err != nil {
+		return nil, errors.New("journal not found")
+	}
+	return s.approvalService.GetRequests(models.ApprovalDocumentJournal, id)
+}
+
 func (s *journalService) Post(id uuid.UUID, req *models.PostJournalRequest, userID uuid.UUID) (*models.Journal, error) {
 	journal, err := s.journalRepo.GetByID(id)
 	if err != nil {
@@ -337,6 +545,11 @@ func (s *journalService) Post(id uuid.UUID, req *models.PostJournalRequest, user
 		return nil, errors.New("journal cannot be posted")
 	}
 
+	// Validate period is not closed
+	if err := ensurePeriodPostable(s.periodRepo, journal.BranchID, journal.JournalDate); err != nil {
+		return nil, err
+	}
+
 	// Post journal
 	now := time.Now()
 	journal.IsPosted = true
@@ -344,39 +557,307 @@ func (s *journalService) Post(id uuid.UUID, req *models.PostJournalRequest, user
 	journal.PostedBy = &userID
 	journal.Status = models.JournalStatusPosted
 
-	if err := s.journalRepo.Update(journal); err != nil {
-		return nil, err
+	if journal.IsInterBranch() {
+		branchJournals, err := s.splitInterBranchJournal(journal)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := s.journalRepo.PostInterBranch(journal, branchJournals); err != nil {
+			return nil, err
+		}
+
+		return s.journalRepo.GetByID(journal.ID)
 	}
 
-	// TODO: Update account balances (implement in next iteration)
+	if err := s.journalRepo.Post(journal); err != nil {
+		return nil, err
+	}
 
 	return s.journalRepo.GetByID(journal.ID)
 }
 
-func (s *journalService) Unpost(id uuid.UUID, userID uuid.UUID) (*models.Journal, error) {
-	journal, err := s.journalRepo.GetByID(id)
+// splitInterBranchJournal moves the lines of other branches into one posted journal per branch
+// and settles each branch against the journal's branch through due to/due from lines, so the
+// books of every branch balance on their own. Lines are netted per branch and fund.
+func (s *journalService) splitInterBranchJournal(journal *models.Journal) ([]models.Journal, error) {
+	type dueKey struct {
+		branchID uuid.UUID
+		fundID   uuid.UUID
+	}
+
+	var ownLines []models.JournalLine
+	var branchIDs []uuid.UUID
+	branchLines := make(map[uuid.UUID][]models.JournalLine)
+	var keys []dueKey
+	netAmounts := make(map[dueKey]models.Money)
+
+	for _, line := range journal.JournalLines {
+		branchID := journal.LineBranchID(&line)
+		if branchID == journal.BranchID {
+			ownLines = append(ownLines, line)
+			continue
+		}
+
+		if _, exists := branchLines[branchID]; !exists {
+			branchIDs = append(branchIDs, branchID)
+		}
+		branchLines[branchID] = append(branchLines[branchID], line)
+
+		key := dueKey{branchID: branchID}
+		if line.FundID != nil {
+			key.fundID = *line.FundID
+		}
+		if _, exists := netAmounts[key]; !exists {
+			keys = append(keys, key)
+		}
+		netAmounts[key] += line.Debit - line.Credit
+	}
+
+	if len(branchIDs) == 0 {
+		return nil, errors.New("inter-branch journal has no lines in other branches")
+	}
+
+	sourceBranchID := journal.BranchID
+	branchJournals := make([]models.Journal, 0, len(branchIDs))
+
+	for _, branchID := range branchIDs {
+		branch, err := s.branchRepo.GetByID(branchID)
+		if err != nil {
+			return nil, errors.New("branch not found")
+		}
+
+		if err := ensurePeriodPostable(s.periodRepo, branchID, journal.JournalDate); err != nil {
+			return nil, err
+		}
+
+		lines := branchLines[branchID]
+		for _, key := range keys {
+			if key.branchID != branchID {
+				continue
+			}
+
+			amount := netAmounts[key]
+			if amount == 0 {
+				continue
+			}
+
+			sourceLine, branchLine, err := s.interBranchDueLines(sourceBranchID, branchID, amount)
+			if err != nil {
+				return nil, err
+			}
+
+			description := "Antar cabang " + journal.JournalNumber + ": " + branch.Name
+			sourceLine.Description = description
+			sourceLine.FundID = nullableFund(key.fundID)
+			ownLines = append(ownLines, *sourceLine)
+
+			branchLine.Description = "Antar cabang " + journal.JournalNumber + ": " + journal.Branch.Name
+			branchLine.FundID = nullableFund(key.fundID)
+			lines = append(lines, *branchLine)
+		}
+
+		lineBranchID := branchID
+		for i := range lines {
+			lines[i].BranchID = &lineBranchID
+		}
+
+		branchJournals = append(branchJournals, models.Journal{
+			BranchID:        branchID,
+			JournalDate:     journal.JournalDate,
+			Description:     journal.Description,
+			ReferenceNo:     journal.JournalNumber,
+			Type:            models.JournalTypeInterBranch,
+			Status:          models.JournalStatusPosted,
+			IsPosted:        true,
+			PostedAt:        journal.PostedAt,
+			PostedBy:        journal.PostedBy,
+			CreatedBy:       journal.CreatedBy,
+			ApprovedBy:      journal.ApprovedBy,
+			ApprovedAt:      journal.ApprovedAt,
+			SourceJournalID: &journal.ID,
+			JournalLines:    lines,
+		})
+	}
+
+	journal.JournalLines = ownLines
+	return branchJournals, nil
+}
+
+// interBranchDueLines builds the due from/due to pair for a net debit amount booked in a branch
+// on behalf of the source branch. A positive amount is owed by the branch to the source branch.
+func (s *journalService) interBranchDueLines(sourceBranchID, branchID uuid.UUID, amount models.Money) (*models.JournalLine, *models.JournalLine, error) {
+	dueFromKey, dueToKey := models.AccountMappingInterBranchDueFrom, models.AccountMappingInterBranchDueTo
+	if amount < 0 {
+		dueFromKey, dueToKey = dueToKey, dueFromKey
+		amount = -amount
+	}
+
+	// The source branch books the first key as a debit, the branch books the second as a credit
+	sourceMapping, err := s.mappingRepo.Resolve(dueFromKey, sourceBranchID)
+	if err != nil {
+		return nil, nil, err
+	}
+	branchMapping, err := s.mappingRepo.Resolve(dueToKey, branchID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sourceBranch, counterpartBranch := sourceBranchID, branchID
+	sourceLine := &models.JournalLine{
+		AccountID:           sourceMapping.AccountID,
+		CounterpartBranchID: &counterpartBranch,
+	}
+	branchLine := &models.JournalLine{
+		AccountID:           branchMapping.AccountID,
+		CounterpartBranchID: &sourceBranch,
+	}
+
+	if dueFromKey == models.AccountMappingInterBranchDueFrom {
+		sourceLine.Debit = amount
+		branchLine.Credit = amount
+	} else {
+		sourceLine.Credit = amount
+		branchLine.Debit = amount
+	}
+
+	return sourceLine, branchLine, nil
+}
+
+// nullableFund returns nil for an untagged fund
+func nullableFund(id uuid.UUID) *uuid.UUID {
+	if id == uuid.Nil {
+		return nil
+	}
+	return &id
+}
+
+// Reverse creates a posted mirror journal for a posted journal; the original stays unchanged
+func (s *journalService) Reverse(id uuid.UUID, req *models.ReverseJournalRequest, userID uuid.UUID) (*models.Journal, error) {
+	original, err := s.journalRepo.GetByID(id)
 	if err != nil {
 		return nil, errors.New("journal not found")
 	}
 
-	// Must be posted
-	if !journal.IsPosted {
-		return nil, errors.New("journal is not posted")
+	if !original.IsPosted {
+		return nil, errors.New("only posted journals can be reversed")
 	}
 
-	// Unpost journal
-	journal.IsPosted = false
-	journal.PostedAt = nil
-	journal.PostedBy = nil
-	journal.Status = models.JournalStatusApproved
+	if original.ReversedByJournalID != nil {
+		return nil, errors.New("journal is already reversed")
+	}
 
-	if err := s.journalRepo.Update(journal); err != nil {
+	if original.ReversalOfID != nil {
+		return nil, errors.New("reversing journal cannot be reversed")
+	}
+
+	if original.SourceJournalID != nil {
+		return nil, errors.New("journal was generated by an inter-branch journal, reverse the inter-branch journal instead")
+	}
+
+	// Lines cleared on a reconciled bank statement are locked
+	for _, line := range original.JournalLines {
+		if line.IsReconciled {
+			return nil, errors.New("journal has reconciled bank lines and cannot be reversed")
+		}
+	}
+	for _, branchJournal := range original.BranchJournals {
+		for _, line := range branchJournal.JournalLines {
+			if line.IsReconciled {
+				return nil, errors.New("journal " + branchJournal.JournalNumber + " has reconciled bank lines and cannot be reversed")
+			}
+		}
+	}
+
+	reversalDate := req.ReversalDate
+	if reversalDate.IsZero() {
+		reversalDate = time.Now()
+	}
+
+	if reversalDate.Before(original.JournalDate) {
+		return nil, errors.New("reversal date cannot be before the original journal date")
+	}
+
+	// Reversal must be dated in a period that is not closed
+	if err := ensurePeriodPostable(s.periodRepo, original.BranchID, reversalDate); err != nil {
 		return nil, err
 	}
 
-	// TODO: Reverse account balances (implement in next iteration)
+	reversal := newReversalJournal(original, reversalDate, req.Reason, userID)
 
-	return s.journalRepo.GetByID(journal.ID)
+	// An inter-branch journal is reversed together with the journals it generated
+	if len(original.BranchJournals) > 0 {
+		originals := []*models.Journal{original}
+		reversals := []*models.Journal{reversal}
+
+		for i := range original.BranchJournals {
+			branchJournal := &original.BranchJournals[i]
+			if err := ensurePeriodPostable(s.periodRepo, branchJournal.BranchID, reversalDate); err != nil {
+				return nil, err
+			}
+
+			originals = append(originals, branchJournal)
+			reversals = append(reversals, newReversalJournal(branchJournal, reversalDate, req.Reason, userID))
+		}
+
+		if err := s.journalRepo.CreateReversals(originals, reversals); err != nil {
+			return nil, err
+		}
+
+		return s.journalRepo.GetByID(reversal.ID)
+	}
+
+	if err := s.journalRepo.CreateReversal(original, reversal); err != nil {
+		return nil, err
+	}
+
+	return s.journalRepo.GetByID(reversal.ID)
+}
+
+// newReversalJournal builds a posted mirror of a journal with debit and credit swapped. It is
+// numbered when it is saved.
+func newReversalJournal(original *models.Journal, reversalDate time.Time, reason string, userID uuid.UUID) *models.Journal {
+	lines := make([]models.JournalLine, len(original.JournalLines))
+	for i, line := range original.JournalLines {
+		lines[i] = models.JournalLine{
+			AccountID:   line.AccountID,
+			Description: line.Description,
+			Debit:       line.Credit,
+			Credit:      line.Debit,
+			FundID:      line.FundID,
+			ProgramID:   line.ProgramID,
+			DonorID:     line.DonorID,
+			ProjectID:   line.ProjectID,
+			BranchID:    line.BranchID,
+			CounterpartBranchID: line.CounterpartBranchID,
+			Currency:      line.Currency,
+			ForeignAmount: -line.ForeignAmount,
+			ExchangeRate:  line.ExchangeRate,
+		}
+	}
+
+	// Reversing an opening journal takes out opening balances, not period movement
+	journalType := models.JournalTypeGeneral
+	if original.Type == models.JournalTypeOpening {
+		journalType = models.JournalTypeOpening
+	}
+
+	now := time.Now()
+	return &models.Journal{
+		BranchID:      original.BranchID,
+		JournalDate:   reversalDate,
+		Type:          journalType,
+		Description:   "Pembalik " + original.JournalNumber + ": " + original.Description,
+		ReferenceNo:   original.JournalNumber,
+		Status:        models.JournalStatusPosted,
+		IsPosted:      true,
+		PostedAt:      &now,
+		PostedBy:      &userID,
+		CreatedBy:     userID,
+		ReversalOfID:  &original.ID,
+		ReverseReason: reason,
+		JournalLines:  lines,
+	}
 }
 
 func (s *journalService) validateJournalLines(lines []models.CreateJournalLineReq) error {
@@ -385,24 +866,176 @@ func (s *journalService) validateJournalLines(lines []models.CreateJournalLineRe
 	}
 
 	for i, line := range lines {
-		// Validate account exists and can post
-		account, err := s.accountRepo.GetByID(line.AccountID)
+		if err := validateJournalLine(s.accountRepo, s.fundRepo, s.programRepo, s.donorRepo, i+1, line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateJournalLine checks a single journal line; lineNo is only used in messages. Journals
+// created through the API, imported journals and opening balances all go through it.
+func validateJournalLine(
+	accountRepo repository.AccountRepository,
+	fundRepo repository.FundRepository,
+	programRepo repository.ProgramRepository,
+	donorRepo repository.DonorRepository,
+	lineNo int,
+	line models.CreateJournalLineReq,
+) error {
+	// Validate account exists and can post
+	account, err := accountRepo.GetByID(line.AccountID)
+	if err != nil {
+		return errors.New("account not found on line " + strconv.Itoa(lineNo))
+	}
+
+	if !account.CanPostTransaction() {
+		return errors.New("account " + account.Code + " cannot have transactions")
+	}
+
+	// Lines on a foreign currency account carry their amount in that currency
+	if account.Currency != "" && line.Currency != account.Currency {
+		return errors.New("account " + account.Code + " is kept in " + account.Currency + ", line " + strconv.Itoa(lineNo) + " must have a " + account.Currency + " amount")
+	}
+
+	// Only active funds, programs and donors can be tagged
+	if line.FundID != nil {
+		fund, err := fundRepo.GetByID(*line.FundID)
 		if err != nil {
-			return errors.New("account not found on line " + string(rune(i+1)))
+			return err
+		}
+		if !fund.IsActive {
+			return errors.New("fund " + fund.Code + " is inactive")
+		}
+	}
+
+	if line.ProgramID != nil {
+		program, err := programRepo.GetByID(*line.ProgramID)
+		if err != nil {
+			return err
+		}
+		if !program.IsActive {
+			return errors.New("program " + program.Code + " is inactive")
+		}
+	}
+
+	if line.DonorID != nil {
+		donor, err := donorRepo.GetByID(*line.DonorID)
+		if err != nil {
+			return err
+		}
+		if !donor.IsActive {
+			return errors.New("donor " + donor.Code + " is inactive")
+		}
+	}
+
+	// Either debit or credit, not both
+	if line.Debit > 0 && line.Credit > 0 {
+		return errors.New("line cannot have both debit and credit")
+	}
+
+	if line.Debit == 0 && line.Credit == 0 {
+		return errors.New("line must have either debit or credit")
+	}
+
+	return nil
+}
+
+// convertForeignLines fills in the exchange rate and the default currency amount of foreign
+// currency lines. Without a rate, the latest rate on or before the journal date is used. A
+// debit or credit that is already given is kept as the amount actually booked, such as the
+// amount a bank converted.
+func (s *journalService) convertForeignLines(lines []models.CreateJournalLineReq, journalDate time.Time) error {
+	for i := range lines {
+		line := &lines[i]
+		line.Currency = foreignCurrency(line.Currency)
+		if line.Currency == "" {
+			line.ForeignAmount = 0
+			line.ExchangeRate = 0
+			continue
+		}
+
+		lineNo := strconv.Itoa(i + 1)
+		if !config.GlobalConfig.App.EnableMultiCurrency {
+			return errors.New("multi-currency is not enabled, line " + lineNo + " must be in " + config.GlobalConfig.App.DefaultCurrency)
 		}
 
-		if !account.CanPostTransaction() {
-			return errors.New("account " + account.Code + " cannot have transactions")
+		if line.ForeignAmount == 0 {
+			return errors.New("line " + lineNo + " must have a " + line.Currency + " amount")
 		}
 
-		// Either debit or credit, not both
-		if line.Debit > 0 && line.Credit > 0 {
-			return errors.New("line cannot have both debit and credit")
+		if line.ExchangeRate == 0 {
+			rate, err := s.exchangeRateRepo.GetRate(line.Currency, journalDate)
+			if err != nil {
+				return err
+			}
+			line.ExchangeRate = rate.Rate
 		}
 
 		if line.Debit == 0 && line.Credit == 0 {
-			return errors.New("line must have either debit or credit")
+			amount, err := line.ForeignAmount.Abs().Mul(line.ExchangeRate)
+			if err != nil {
+				return errors.New("line " + lineNo + ": " + err.Error())
+			}
+			if line.ForeignAmount > 0 {
+				line.Debit = amount
+			} else {
+				line.Credit = amount
+			}
+		} else if (line.Debit > 0) != (line.ForeignAmount > 0) {
+			return errors.New("line " + lineNo + ": " + line.Currency + " amount must be positive for a debit and negative for a credit")
+		}
+	}
+
+	return nil
+}
+
+// validateLineBranches checks the branch and counterpart branch set on journal lines. Only
+// inter-branch journals may book lines in other branches, and they must have at least one.
+func (s *journalService) validateLineBranches(journalType string, branchID uuid.UUID, journalDate time.Time, lines []models.CreateJournalLineReq) error {
+	checked := map[uuid.UUID]bool{branchID: true}
+	hasOtherBranch := false
+
+	for _, line := range lines {
+		lineBranchID := branchID
+		if line.BranchID != nil {
+			lineBranchID = *line.BranchID
+		}
+
+		if lineBranchID != branchID {
+			if journalType != models.JournalTypeInterBranch {
+				return errors.New("only inter-branch journals can have lines in other branches")
+			}
+			hasOtherBranch = true
+
+			if !checked[lineBranchID] {
+				branch, err := s.branchRepo.GetByID(lineBranchID)
+				if err != nil {
+					return errors.New("branch not found")
+				}
+				if !branch.IsActive {
+					return errors.New("branch " + branch.Code + " is inactive")
+				}
+				if err := ensurePeriodPostable(s.periodRepo, lineBranchID, journalDate); err != nil {
+					return err
+				}
+				checked[lineBranchID] = true
+			}
 		}
+
+		if line.CounterpartBranchID != nil {
+			if *line.CounterpartBranchID == lineBranchID {
+				return errors.New("counterpart branch must differ from the branch of the line")
+			}
+			if _, err := s.branchRepo.GetByID(*line.CounterpartBranchID); err != nil {
+				return errors.New("counterpart branch not found")
+			}
+		}
+	}
+
+	if journalType == models.JournalTypeInterBranch && !hasOtherBranch {
+		return errors.New("inter-branch journal must have lines in another branch")
 	}
 
 	return nil
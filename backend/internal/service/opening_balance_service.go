@@ -0,0 +1,498 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yayasan/erp-backend/internal/models"
+	"github.com/yayasan/erp-backend/internal/repository"
+)
+
+type OpeningBalanceService interface {
+	Import(req *models.ImportOpeningBalancesRequest, fileName string, data []byte, userID uuid.UUID) (*models.OpeningBalanceImportResult, error)
+}
+
+type openingBalanceService struct {
+	openingBalanceRepo repository.OpeningBalanceRepository
+	branchRepo         repository.BranchRepository
+	accountRepo        repository.AccountRepository
+	fundRepo           repository.FundRepository
+	programRepo        repository.ProgramRepository
+	donorRepo          repository.DonorRepository
+	studentRepo        repository.StudentRepository
+	periodRepo         repository.AccountingPeriodRepository
+}
+
+func NewOpeningBalanceService(
+	openingBalanceRepo repository.OpeningBalanceRepository,
+	branchRepo repository.BranchRepository,
+	accountRepo repository.AccountRepository,
+	fundRepo repository.FundRepository,
+	programRepo repository.ProgramRepository,
+	donorRepo repository.DonorRepository,
+	studentRepo repository.StudentRepository,
+	periodRepo repository.AccountingPeriodRepository,
+) OpeningBalanceService {
+	return &openingBalanceService{
+		openingBalanceRepo: openingBalanceRepo,
+		branchRepo:         branchRepo,
+		accountRepo:        accountRepo,
+		fundRepo:           fundRepo,
+		programRepo:        programRepo,
+		donorRepo:          donorRepo,
+		studentRepo:        studentRepo,
+		periodRepo:         periodRepo,
+	}
+}
+
+// openingBalanceColumns lists the accepted header names of each column in an opening balance
+// file, compared like the journal import headers
+var openingBalanceColumns = map[string][]string{
+	"branch_code":  {"branch code", "branch", "kode cabang", "cabang"},
+	"account_code": {"account code", "account", "kode akun", "akun"},
+	"description":  {"description", "keterangan"},
+	"debit":        {"debit", "debet"},
+	"credit":       {"credit", "kredit"},
+	"fund_code":    {"fund code", "fund", "kode dana", "dana"},
+	"program_code": {"program code", "program", "kode program"},
+	"donor_code":   {"donor code", "donor", "kode donatur", "donatur"},
+	"student_code": {"student code", "student", "registration number", "nis", "no induk", "kode siswa", "siswa"},
+}
+
+// openingBalanceGroup collects the rows of one branch
+type openingBalanceGroup struct {
+	result   *models.OpeningBalanceBranch
+	branch   *models.Branch
+	lines    []models.JournalLine
+	invoices []*models.Invoice
+	firstRow int
+	invalid  bool
+}
+
+// Import validates the opening balances in a CSV or XLSX file and posts one opening journal per
+// branch, dated the cut-over date. Rows with a student code also become an opening invoice of
+// the student, so the receivable is settled through the usual payments. Nothing is imported
+// unless every row is valid and every branch balances.
+func (s *openingBalanceService) Import(req *models.ImportOpeningBalancesRequest, fileName string, data []byte, userID uuid.UUID) (*models.OpeningBalanceImportResult, error) {
+	cutoverDate := dateOnly(req.CutoverDate)
+
+	var defaultBranch *models.Branch
+	if req.BranchID != nil {
+		branch, err := s.branchRepo.GetByID(*req.BranchID)
+		if err != nil {
+			return nil, errors.New("branch not found")
+		}
+		if !branch.IsActive {
+			return nil, errors.New("branch " + branch.Code + " is inactive")
+		}
+		defaultBranch = branch
+	}
+
+	records, rawValues, err := readJournalImportFile(fileName, data)
+	if err != nil {
+		return nil, err
+	}
+
+	// The first non-empty row is the header
+	for len(records) > 0 && strings.TrimSpace(firstNonEmpty(records[0].cells)) == "" {
+		records = records[1:]
+	}
+	if len(records) == 0 {
+		return nil, errors.New("import file is empty")
+	}
+
+	columns := importHeaderColumns(records[0].cells, openingBalanceColumns)
+	if _, exists := columns["account_code"]; !exists {
+		return nil, errors.New("import file has no account_code column")
+	}
+	_, hasDebit := columns["debit"]
+	_, hasCredit := columns["credit"]
+	if !hasDebit && !hasCredit {
+		return nil, errors.New("import file has no debit or credit column")
+	}
+	records = records[1:]
+
+	result := &models.OpeningBalanceImportResult{
+		CutoverDate: cutoverDate,
+		DryRun:      req.DryRun,
+		Errors:      []models.JournalImportError{},
+		Branches:    []models.OpeningBalanceBranch{},
+	}
+
+	importer := &openingBalanceImporter{
+		service:     s,
+		cutoverDate: cutoverDate,
+		columns:     columns,
+		rawValues:   rawValues,
+		lookup:      newJournalImportLookup(s.branchRepo, s.accountRepo, s.fundRepo, s.programRepo, s.donorRepo),
+		students:    make(map[string]*models.Student),
+	}
+
+	var groups []*openingBalanceGroup
+	groupByBranch := make(map[uuid.UUID]*openingBalanceGroup)
+
+	for _, record := range records {
+		if strings.TrimSpace(firstNonEmpty(record.cells)) == "" {
+			continue
+		}
+
+		result.TotalRows++
+		if result.TotalRows > maxJournalImportRows {
+			return nil, fmt.Errorf("import file has more than %d rows", maxJournalImportRows)
+		}
+
+		rowError := func(column, message string) {
+			result.Errors = append(result.Errors, models.JournalImportError{
+				Row:     record.row,
+				Column:  column,
+				Message: message,
+			})
+		}
+
+		branch := defaultBranch
+		if code := cell(record.cells, columns, "branch_code"); code != "" {
+			found, err := importer.lookup.branch(code)
+			if err != nil {
+				rowError("branch_code", err.Error())
+				continue
+			}
+			branch = found
+		} else if branch == nil {
+			rowError("branch_code", "branch code is required when no branch is selected")
+			continue
+		}
+
+		group, exists := groupByBranch[branch.ID]
+		if !exists {
+			group = &openingBalanceGroup{
+				result: &models.OpeningBalanceBranch{
+					BranchID:   branch.ID,
+					BranchCode: branch.Code,
+					BranchName: branch.Name,
+					Rows:       []int{},
+				},
+				branch:   branch,
+				firstRow: record.row,
+			}
+			groupByBranch[branch.ID] = group
+			groups = append(groups, group)
+		}
+		group.result.Rows = append(group.result.Rows, record.row)
+
+		errorCount := len(result.Errors)
+		importer.addRow(group, record, rowError)
+		if len(result.Errors) > errorCount {
+			group.invalid = true
+		}
+	}
+
+	if result.TotalRows == 0 {
+		return nil, errors.New("import file has no rows")
+	}
+
+	// Branch level checks
+	for _, group := range groups {
+		if group.invalid {
+			continue
+		}
+
+		if err := s.validateOpeningBalances(group, cutoverDate); err != nil {
+			result.Errors = append(result.Errors, models.JournalImportError{
+				Row:     group.firstRow,
+				Message: "branch " + group.branch.Code + ": " + err.Error(),
+			})
+			group.invalid = true
+		}
+	}
+
+	for _, group := range groups {
+		group.result.Status = models.OpeningBalanceStatusValid
+		if group.invalid {
+			group.result.Status = models.OpeningBalanceStatusInvalid
+		}
+	}
+
+	if !req.DryRun && len(result.Errors) == 0 {
+		if err := s.createOpeningBalances(groups, cutoverDate, userID); err != nil {
+			return nil, err
+		}
+		result.Imported = true
+	}
+
+	for _, group := range groups {
+		result.Branches = append(result.Branches, *group.result)
+	}
+
+	return result, nil
+}
+
+// validateOpeningBalances checks that a branch balances, can post on the cut-over date and has
+// no opening balances yet
+func (s *openingBalanceService) validateOpeningBalances(group *openingBalanceGroup, cutoverDate time.Time) error {
+	if group.result.TotalDebit != group.result.TotalCredit {
+		return fmt.Errorf("opening balances are not balanced: debit %s != credit %s", group.result.TotalDebit, group.result.TotalCredit)
+	}
+
+	if err := ensurePeriodPostable(s.periodRepo, group.branch.ID, cutoverDate); err != nil {
+		return err
+	}
+
+	existing, err := s.openingBalanceRepo.GetOpeningJournal(group.branch.ID)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return errors.New("opening balances were already loaded in journal " + existing.JournalNumber + ", reverse it first")
+	}
+
+	return nil
+}
+
+// createOpeningBalances posts the opening journal of each branch and saves the opening invoices
+func (s *openingBalanceService) createOpeningBalances(groups []*openingBalanceGroup, cutoverDate time.Time, userID uuid.UUID) error {
+	now := time.Now()
+	journals := make([]*models.Journal, len(groups))
+	var invoices []*models.Invoice
+
+	for i, group := range groups {
+		journals[i] = &models.Journal{
+			BranchID:     group.branch.ID,
+			JournalDate:  cutoverDate,
+			Description:  "Saldo awal per " + cutoverDate.Format("2006-01-02"),
+			ReferenceNo:  "OPENING/" + cutoverDate.Format("20060102"),
+			Type:         models.JournalTypeOpening,
+			Status:       models.JournalStatusPosted,
+			IsPosted:     true,
+			PostedAt:     &now,
+			PostedBy:     &userID,
+			CreatedBy:    userID,
+			JournalLines: group.lines,
+		}
+		invoices = append(invoices, group.invoices...)
+	}
+
+	if err := s.openingBalanceRepo.Create(journals, invoices); err != nil {
+		return err
+	}
+
+	for i, group := range groups {
+		group.result.Status = models.OpeningBalanceStatusImported
+		group.result.JournalID = &journals[i].ID
+		group.result.JournalNumber = journals[i].JournalNumber
+	}
+	return nil
+}
+
+// openingBalanceImporter reads the rows of an opening balance file
+type openingBalanceImporter struct {
+	service      *openingBalanceService
+	cutoverDate  time.Time
+	columns      map[string]int
+	rawValues    bool
+	lookup       *journalImportLookup
+	students     map[string]*models.Student
+	academicYear *models.AcademicYear // Current academic year, for students without one
+}
+
+// addRow resolves the codes and amounts of a row and adds it to its branch as a journal line,
+// and as an opening invoice when it names a student. Problems are reported through rowError; a
+// row with problems is not added.
+func (imp *openingBalanceImporter) addRow(group *openingBalanceGroup, record journalImportRecord, rowError func(column, message string)) {
+	valid := true
+	fail := func(column, message string) {
+		rowError(column, message)
+		valid = false
+	}
+	value := func(column string) string {
+		return cell(record.cells, imp.columns, column)
+	}
+
+	line := models.JournalLine{
+		Description: value("description"),
+	}
+
+	var account *models.Account
+	if code := value("account_code"); code == "" {
+		fail("account_code", "account code is required")
+	} else if found, err := imp.lookup.account(code); err != nil {
+		fail("account_code", err.Error())
+	} else if err := validateOpeningBalanceAccount(found); err != nil {
+		fail("account_code", err.Error())
+	} else {
+		account = found
+		line.AccountID = found.ID
+	}
+
+	if code := value("fund_code"); code != "" {
+		if fund, err := imp.lookup.fund(code); err != nil {
+			fail("fund_code", err.Error())
+		} else {
+			line.FundID = &fund.ID
+		}
+	}
+
+	if code := value("program_code"); code != "" {
+		if program, err := imp.lookup.program(code); err != nil {
+			fail("program_code", err.Error())
+		} else {
+			line.ProgramID = &program.ID
+		}
+	}
+
+	if code := value("donor_code"); code != "" {
+		if donor, err := imp.lookup.donor(code); err != nil {
+			fail("donor_code", err.Error())
+		} else {
+			line.DonorID = &donor.ID
+		}
+	}
+
+	for _, column := range []string{"debit", "credit"} {
+		amount, err := parseJournalImportAmount(value(column), imp.rawValues)
+		if err != nil {
+			fail(column, err.Error())
+			continue
+		}
+		if amount < 0 {
+			fail(column, column+" cannot be negative")
+			continue
+		}
+		if column == "debit" {
+			line.Debit = amount
+		} else {
+			line.Credit = amount
+		}
+	}
+	if valid && line.Debit == 0 && line.Credit == 0 {
+		fail("", "either debit or credit is required")
+	}
+	if line.Debit > 0 && line.Credit > 0 {
+		fail("", "a row cannot have both debit and credit")
+	}
+
+	var invoice *models.Invoice
+	if code := value("student_code"); code != "" {
+		student, err := imp.student(code)
+		if err != nil {
+			fail("student_code", err.Error())
+		} else if account != nil {
+			invoice, err = imp.studentInvoice(student, group.branch, account, &line)
+			if err != nil {
+				fail("student_code", err.Error())
+			}
+		}
+	}
+
+	if !valid {
+		return
+	}
+
+	// The same line checks as journals created through the API
+	s := imp.service
+	if err := validateJournalLine(s.accountRepo, s.fundRepo, s.programRepo, s.donorRepo, len(group.lines)+1, models.CreateJournalLineReq{
+		AccountID:   line.AccountID,
+		Description: line.Description,
+		Debit:       line.Debit,
+		Credit:      line.Credit,
+		FundID:      line.FundID,
+		ProgramID:   line.ProgramID,
+		DonorID:     line.DonorID,
+	}); err != nil {
+		rowError("", err.Error())
+		return
+	}
+
+	group.lines = append(group.lines, line)
+	group.result.TotalDebit += line.Debit
+	group.result.TotalCredit += line.Credit
+	if invoice != nil {
+		group.invoices = append(group.invoices, invoice)
+		group.result.StudentReceivables++
+	}
+}
+
+// studentInvoice builds the opening invoice of a student receivable row. The invoice item
+// carries the receivable account, so payments of the invoice credit it.
+func (imp *openingBalanceImporter) studentInvoice(student *models.Student, branch *models.Branch, account *models.Account, line *models.JournalLine) (*models.Invoice, error) {
+	if student.BranchID != branch.ID {
+		return nil, errors.New("student " + student.RegistrationNumber + " is not in branch " + branch.Code)
+	}
+	if account.Category != models.AccountCategoryAsset {
+		return nil, errors.New("student balances must be on a receivable asset account")
+	}
+	if line.Credit > 0 {
+		return nil, errors.New("student balances must be debits")
+	}
+
+	academicYearID := student.AcademicYearID
+	if academicYearID == nil {
+		if imp.academicYear == nil {
+			academicYear, err := imp.service.openingBalanceRepo.GetCurrentAcademicYear()
+			if err != nil {
+				return nil, err
+			}
+			if academicYear == nil {
+				return nil, errors.New("student " + student.RegistrationNumber + " has no academic year and no academic year is current")
+			}
+			imp.academicYear = academicYear
+		}
+		academicYearID = &imp.academicYear.ID
+	}
+
+	if line.Description == "" {
+		line.Description = "Saldo awal piutang " + student.FullName
+	}
+
+	return &models.Invoice{
+		StudentID:      student.ID,
+		BranchID:       branch.ID,
+		AcademicYearID: *academicYearID,
+		InvoiceDate:    imp.cutoverDate,
+		DueDate:        imp.cutoverDate,
+		Description:    line.Description,
+		Items: []models.InvoiceItem{
+			{
+				Description: line.Description,
+				Quantity:    1,
+				UnitPrice:   line.Debit,
+				AccountID:   &account.ID,
+			},
+		},
+	}, nil
+}
+
+func (imp *openingBalanceImporter) student(code string) (*models.Student, error) {
+	student, exists := imp.students[code]
+	if !exists {
+		student, _ = imp.service.studentRepo.GetByRegistrationNumber(code)
+		imp.students[code] = student
+	}
+	if student == nil {
+		return nil, errors.New("student " + code + " not found")
+	}
+	return student, nil
+}
+
+// validateOpeningBalanceAccount checks that an account can take an opening balance. Income and
+// expense of earlier years belong in the accumulated surplus, so only balance sheet accounts are
+// accepted.
+func validateOpeningBalanceAccount(account *models.Account) error {
+	if !account.CanPostTransaction() {
+		return errors.New("account " + account.Code + " cannot be used in transactions")
+	}
+
+	switch account.Category {
+	case models.AccountCategoryAsset, models.AccountCategoryLiability, models.AccountCategoryEquity:
+	default:
+		return errors.New("account " + account.Code + " is not a balance sheet account; load earlier income and expenses into the accumulated surplus")
+	}
+
+	if account.Currency != "" {
+		return errors.New("account " + account.Code + " is kept in " + account.Currency + "; foreign currency opening balances are not supported")
+	}
+
+	return nil
+}
@@ -24,6 +24,9 @@ type paymentService struct {
 	invoiceRepo repository.InvoiceRepository
 	branchRepo  repository.BranchRepository
 	studentRepo repository.StudentRepository
+	journalRepo repository.JournalRepository
+	mappingRepo repository.AccountMappingRepository
+	periodRepo  repository.AccountingPeriodRepository
 }
 
 func NewPaymentService(
@@ -31,12 +34,18 @@ func NewPaymentService(
 	invoiceRepo repository.InvoiceRepository,
 	branchRepo repository.BranchRepository,
 	studentRepo repository.StudentRepository,
+	journalRepo repository.JournalRepository,
+	mappingRepo repository.AccountMappingRepository,
+	periodRepo repository.AccountingPeriodRepository,
 ) PaymentService {
 	return &paymentService{
 		paymentRepo: paymentRepo,
 		invoiceRepo: invoiceRepo,
 		branchRepo:  branchRepo,
 		studentRepo: studentRepo,
+		journalRepo: journalRepo,
+		mappingRepo: mappingRepo,
+		periodRepo:  periodRepo,
 	}
 }
 
@@ -98,15 +107,8 @@ func (s *paymentService) Create(req *CreatePaymentRequest, userID uuid.UUID) (*m
 		return nil, errors.New("payment amount exceeds remaining balance")
 	}
 
-	// Get branch
-	branch, err := s.branchRepo.GetByID(invoice.BranchID)
-	if err != nil {
-		return nil, err
-	}
-
-	// Generate payment number
-	paymentNumber, err := s.paymentRepo.GeneratePaymentNumber(branch.Code, req.PaymentDate)
-	if err != nil {
+	// Validate period is open
+	if err := ensurePeriodOpen(s.periodRepo, invoice.BranchID, req.PaymentDate); err != nil {
 		return nil, err
 	}
 
@@ -115,7 +117,6 @@ func (s *paymentService) Create(req *CreatePaymentRequest, userID uuid.UUID) (*m
 		InvoiceID:     req.InvoiceID,
 		StudentID:     invoice.StudentID,
 		BranchID:      invoice.BranchID,
-		PaymentNumber: paymentNumber,
 		PaymentDate:   req.PaymentDate,
 		Amount:        req.Amount,
 		PaymentMethod: req.PaymentMethod,
@@ -142,20 +143,142 @@ func (s *paymentService) Post(id uuid.UUID, userID uuid.UUID) (*models.Payment,
 		return nil, errors.New("payment is already posted")
 	}
 
-	// TODO: Create journal entry for payment
-	// This will be implemented when journal integration is needed
+	// Validate period is open
+	if err := ensurePeriodOpen(s.periodRepo, payment.BranchID, payment.PaymentDate); err != nil {
+		return nil, err
+	}
+
+	// Build journal entry for payment
+	journal, err := s.buildPaymentJournal(payment, userID)
+	if err != nil {
+		return nil, err
+	}
 
 	now := time.Now()
 	payment.IsPosted = true
 	payment.PostedAt = &now
+	journal.PostedAt = &now
 
-	if err := s.paymentRepo.Update(payment); err != nil {
+	if err := s.paymentRepo.PostWithJournal(payment, journal); err != nil {
 		return nil, err
 	}
 
 	return s.paymentRepo.GetByID(payment.ID)
 }
 
+// buildPaymentJournal creates a balanced, posted journal for a payment:
+// debit cash/bank by payment method, credit revenue accounts of the invoice items
+func (s *paymentService) buildPaymentJournal(payment *models.Payment, userID uuid.UUID) (*models.Journal, error) {
+	cashMapping, err := s.mappingRepo.Resolve(models.PaymentMethodMappingKey(payment.PaymentMethod), payment.BranchID)
+	if err != nil {
+		return nil, err
+	}
+
+	creditLines, err := s.buildPaymentCreditLines(payment)
+	if err != nil {
+		return nil, err
+	}
+
+	description := "Penerimaan pembayaran " + payment.PaymentNumber
+	if payment.Student.FullName != "" {
+		description += " - " + payment.Student.FullName
+	}
+
+	lines := []models.JournalLine{
+		{
+			AccountID:   cashMapping.AccountID,
+			Description: description,
+			Debit:       payment.Amount,
+		},
+	}
+	lines = append(lines, creditLines...)
+
+	return &models.Journal{
+		BranchID:     payment.BranchID,
+		JournalDate:  payment.PaymentDate,
+		Description:  description,
+		ReferenceNo:  payment.PaymentNumber,
+		Status:       models.JournalStatusPosted,
+		IsPosted:     true,
+		PostedBy:     &userID,
+		CreatedBy:    userID,
+		JournalLines: lines,
+	}, nil
+}
+
+// buildPaymentCreditLines splits the payment amount over the invoice item accounts
+// proportionally; items without an account fall back to the student receivable mapping
+func (s *paymentService) buildPaymentCreditLines(payment *models.Payment) ([]models.JournalLine, error) {
+	invoice := payment.Invoice
+
+	// Group invoice amounts per credit account, keeping item order
+	var accountIDs []uuid.UUID
+	amounts := make(map[uuid.UUID]models.Money)
+	var unmappedAmount models.Money
+
+	for _, item := range invoice.Items {
+		var accountID *uuid.UUID
+		if item.AccountID != nil {
+			accountID = item.AccountID
+		} else if item.FeeStructure != nil {
+			accountID = &item.FeeStructure.AccountID
+		}
+
+		if accountID == nil {
+			unmappedAmount += item.Amount
+			continue
+		}
+
+		if _, exists := amounts[*accountID]; !exists {
+			accountIDs = append(accountIDs, *accountID)
+		}
+		amounts[*accountID] += item.Amount
+	}
+
+	if unmappedAmount > 0 || len(accountIDs) == 0 {
+		receivable, err := s.mappingRepo.Resolve(models.AccountMappingStudentReceivable, payment.BranchID)
+		if err != nil {
+			return nil, err
+		}
+		if _, exists := amounts[receivable.AccountID]; !exists {
+			accountIDs = append(accountIDs, receivable.AccountID)
+		}
+		amounts[receivable.AccountID] += unmappedAmount
+	}
+
+	var itemsTotal models.Money
+	for _, amount := range amounts {
+		itemsTotal += amount
+	}
+
+	description := "Pembayaran invoice " + invoice.InvoiceNumber
+	lines := make([]models.JournalLine, 0, len(accountIDs))
+	remaining := payment.Amount
+
+	for i, accountID := range accountIDs {
+		credit := remaining
+		if i < len(accountIDs)-1 {
+			credit = 0
+			if itemsTotal > 0 {
+				credit = payment.Amount.Prorate(amounts[accountID], itemsTotal)
+			}
+			remaining -= credit
+		}
+
+		if credit == 0 {
+			continue
+		}
+
+		lines = append(lines, models.JournalLine{
+			AccountID:   accountID,
+			Description: description,
+			Credit:      credit,
+		})
+	}
+
+	return lines, nil
+}
+
 func (s *paymentService) Delete(id uuid.UUID) error {
 	payment, err := s.paymentRepo.GetByID(id)
 	if err != nil {
@@ -171,10 +294,10 @@ func (s *paymentService) Delete(id uuid.UUID) error {
 
 // CreatePaymentRequest for creating payment
 type CreatePaymentRequest struct {
-	InvoiceID     uuid.UUID `json:"invoice_id" binding:"required"`
-	PaymentDate   time.Time `json:"payment_date" binding:"required"`
-	Amount        float64   `json:"amount" binding:"required,gt=0"`
-	PaymentMethod string    `json:"payment_method" binding:"required"`
-	ReferenceNo   string    `json:"reference_no"`
-	Notes         string    `json:"notes"`
+	InvoiceID     uuid.UUID    `json:"invoice_id" binding:"required"`
+	PaymentDate   time.Time    `json:"payment_date" binding:"required"`
+	Amount        models.Money `json:"amount" binding:"required,gt=0"`
+	PaymentMethod string       `json:"payment_method" binding:"required"`
+	ReferenceNo   string       `json:"reference_no"`
+	Notes         string       `json:"notes"`
 }
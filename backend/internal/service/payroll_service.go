@@ -2,7 +2,6 @@ package service
 
 import (
 	"errors"
-	"math"
 	"time"
 
 	"github.com/google/uuid"
@@ -17,9 +16,10 @@ type PayrollService interface {
 	GetByPeriod(period string) ([]models.Payroll, error)
 	GetByEmployee(employeeID uuid.UUID) ([]models.Payroll, error)
 	Create(req *models.CreatePayrollRequest) (*models.Payroll, error)
-	ProcessPayroll(payrollID uuid.UUID) (*models.Payroll, error)
+	ProcessPayroll(payrollID uuid.UUID, userID uuid.UUID) (*models.Payroll, error)
 	GenerateBulkPayroll(branchID uuid.UUID, period string, paymentDate time.Time) ([]models.Payroll, error)
-	CalculateTax(grossSalary float64, maritalStatus string, dependents int) float64
+	ProcessBulkPayroll(branchID uuid.UUID, period string, userID uuid.UUID) ([]models.Payroll, error)
+	CalculateTax(grossSalary models.Money, maritalStatus string, dependents int) (models.Money, error)
 	Delete(id uuid.UUID) error
 }
 
@@ -27,17 +27,26 @@ type payrollService struct {
 	payrollRepo  repository.PayrollRepository
 	employeeRepo repository.EmployeeRepository
 	branchRepo   repository.BranchRepository
+	journalRepo  repository.JournalRepository
+	mappingRepo  repository.AccountMappingRepository
+	periodRepo   repository.AccountingPeriodRepository
 }
 
 func NewPayrollService(
 	payrollRepo repository.PayrollRepository,
 	employeeRepo repository.EmployeeRepository,
 	branchRepo repository.BranchRepository,
+	journalRepo repository.JournalRepository,
+	mappingRepo repository.AccountMappingRepository,
+	periodRepo repository.AccountingPeriodRepository,
 ) PayrollService {
 	return &payrollService{
 		payrollRepo:  payrollRepo,
 		employeeRepo: employeeRepo,
 		branchRepo:   branchRepo,
+		journalRepo:  journalRepo,
+		mappingRepo:  mappingRepo,
+		periodRepo:   periodRepo,
 	}
 }
 
@@ -96,23 +105,14 @@ func (s *payrollService) Create(req *models.CreatePayrollRequest) (*models.Payro
 		return nil, errors.New("employee is not active")
 	}
 
-	// Get branch
-	branch, err := s.branchRepo.GetByID(employee.BranchID)
-	if err != nil {
-		return nil, err
-	}
-
-	// Generate payroll number
-	payrollNumber, err := s.payrollRepo.GeneratePayrollNumber(branch.Code, req.PaymentDate)
-	if err != nil {
+	// Validate period is open
+	if err := ensurePeriodOpen(s.periodRepo, employee.BranchID, req.PaymentDate); err != nil {
 		return nil, err
 	}
 
 	// Calculate gross salary
 	grossSalary := req.BaseSalary
-	allowances := 0.0
-	overtime := 0.0
-	bonus := 0.0
+	var allowances, overtime, bonus models.Money
 
 	for _, comp := range req.Components {
 		// You would fetch component details here
@@ -130,10 +130,16 @@ func (s *payrollService) Create(req *models.CreatePayrollRequest) (*models.Payro
 	if employee.MaritalStatus == models.MaritalStatusMarried {
 		dependents = 1 // Simplified
 	}
-	taxPPh21 := s.CalculateTax(grossSalary, employee.MaritalStatus, dependents)
+	taxPPh21, err := s.CalculateTax(grossSalary, employee.MaritalStatus, dependents)
+	if err != nil {
+		return nil, err
+	}
 
 	// Calculate BPJS (simplified - 4% of base salary)
-	bpjs := req.BaseSalary * 0.04
+	bpjs, err := req.BaseSalary.Percent(4)
+	if err != nil {
+		return nil, err
+	}
 
 	// Total deductions
 	totalDeductions := taxPPh21 + bpjs
@@ -145,7 +151,6 @@ func (s *payrollService) Create(req *models.CreatePayrollRequest) (*models.Payro
 	payroll := &models.Payroll{
 		EmployeeID:      req.EmployeeID,
 		BranchID:        employee.BranchID,
-		PayrollNumber:   payrollNumber,
 		Period:          req.Period,
 		PaymentDate:     req.PaymentDate,
 		BaseSalary:      req.BaseSalary,
@@ -171,7 +176,7 @@ func (s *payrollService) Create(req *models.CreatePayrollRequest) (*models.Payro
 	return s.payrollRepo.GetByID(payroll.ID)
 }
 
-func (s *payrollService) ProcessPayroll(payrollID uuid.UUID) (*models.Payroll, error) {
+func (s *payrollService) ProcessPayroll(payrollID uuid.UUID, userID uuid.UUID) (*models.Payroll, error) {
 	payroll, err := s.payrollRepo.GetByID(payrollID)
 	if err != nil {
 		return nil, errors.New("payroll not found")
@@ -181,21 +186,203 @@ func (s *payrollService) ProcessPayroll(payrollID uuid.UUID) (*models.Payroll, e
 		return nil, errors.New("payroll already paid")
 	}
 
-	// Mark as paid
-	now := time.Now()
-	payroll.PaymentStatus = models.PayrollStatusPaid
-	payroll.PaidAt = &now
+	if payroll.IsPosted {
+		return nil, errors.New("payroll already posted")
+	}
+
+	// Validate period is open
+	if err := ensurePeriodOpen(s.periodRepo, payroll.BranchID, payroll.PaymentDate); err != nil {
+		return nil, err
+	}
+
+	// Build payroll expense journal
+	description := "Gaji " + payroll.Employee.FullName + " periode " + payroll.Period
+	journal, err := s.buildPayrollJournal([]models.Payroll{*payroll}, &payroll.Branch, description, payroll.PayrollNumber, userID)
+	if err != nil {
+		return nil, err
+	}
 
-	// TODO: Create journal entry for payroll expense
-	// This would be integrated with the journal system
+	// Mark as paid and posted
+	now := time.Now()
+	journal.PostedAt = &now
 
-	if err := s.payrollRepo.Update(payroll); err != nil {
+	if err := s.payrollRepo.ProcessWithJournal([]uuid.UUID{payroll.ID}, journal, now); err != nil {
 		return nil, err
 	}
 
 	return s.payrollRepo.GetByID(payroll.ID)
 }
 
+// ProcessBulkPayroll pays all pending payrolls of a branch for a period with one consolidated journal
+func (s *payrollService) ProcessBulkPayroll(branchID uuid.UUID, period string, userID uuid.UUID) ([]models.Payroll, error) {
+	branch, err := s.branchRepo.GetByID(branchID)
+	if err != nil {
+		return nil, errors.New("branch not found")
+	}
+
+	payrolls, err := s.payrollRepo.GetPendingByBranchPeriod(branchID, period)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(payrolls) == 0 {
+		return nil, errors.New("no pending payroll for this period")
+	}
+
+	// Validate period is open
+	for _, payroll := range payrolls {
+		if err := ensurePeriodOpen(s.periodRepo, branchID, payroll.PaymentDate); err != nil {
+			return nil, err
+		}
+	}
+
+	description := "Gaji karyawan " + branch.Name + " periode " + period
+	journal, err := s.buildPayrollJournal(payrolls, branch, description, "PAYROLL/"+branch.Code+"/"+period, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	journal.PostedAt = &now
+
+	payrollIDs := make([]uuid.UUID, len(payrolls))
+	for i := range payrolls {
+		payrollIDs[i] = payrolls[i].ID
+	}
+
+	if err := s.payrollRepo.ProcessWithJournal(payrollIDs, journal, now); err != nil {
+		return nil, err
+	}
+
+	processed := make([]models.Payroll, 0, len(payrollIDs))
+	for _, id := range payrollIDs {
+		payroll, err := s.payrollRepo.GetByID(id)
+		if err != nil {
+			return nil, err
+		}
+		processed = append(processed, *payroll)
+	}
+
+	return processed, nil
+}
+
+// buildPayrollJournal creates a balanced, posted journal for one or more payrolls of a branch:
+// debit salary expense (per department), credit net salary cash/bank and the deduction payables.
+// The journal is dated on the payment date, so the payrolls must all be paid on the same date.
+func (s *payrollService) buildPayrollJournal(payrolls []models.Payroll, branch *models.Branch, description, referenceNo string, userID uuid.UUID) (*models.Journal, error) {
+	paymentDate := payrolls[0].PaymentDate
+	for _, payroll := range payrolls[1:] {
+		if !dateOnly(payroll.PaymentDate).Equal(dateOnly(paymentDate)) {
+			return nil, errors.New("payrolls " + payrolls[0].PayrollNumber + " and " + payroll.PayrollNumber +
+				" have different payment dates, give them the same payment date or process them one by one")
+		}
+	}
+
+	var lines []models.JournalLine
+
+	for _, payroll := range payrolls {
+		expenseAccountID, err := s.resolveSalaryExpenseAccount(&payroll)
+		if err != nil {
+			return nil, err
+		}
+		lines = addPayrollJournalLine(lines, expenseAccountID, "Beban gaji", payroll.GrossSalary, 0)
+
+		paymentMethod := payroll.PaymentMethod
+		if paymentMethod == "" {
+			paymentMethod = models.PaymentMethodTransfer
+		}
+
+		credits := []struct {
+			key         string
+			description string
+			amount      models.Money
+		}{
+			{models.PayrollPaymentMappingKey(paymentMethod), "Pembayaran gaji bersih", payroll.NetSalary},
+			{models.AccountMappingPayrollPPh21Payable, "Utang PPh 21", payroll.TaxPPh21},
+			{models.AccountMappingPayrollBPJSPayable, "Utang BPJS", payroll.BPJS},
+			{models.AccountMappingPayrollLoan, "Potongan pinjaman karyawan", payroll.Loan},
+			{models.AccountMappingPayrollOtherPayable, "Potongan lain-lain", payroll.OtherDeductions},
+		}
+
+		for _, credit := range credits {
+			if credit.amount == 0 {
+				continue
+			}
+			mapping, err := s.mappingRepo.Resolve(credit.key, payroll.BranchID)
+			if err != nil {
+				return nil, err
+			}
+			lines = addPayrollJournalLine(lines, mapping.AccountID, credit.description, 0, credit.amount)
+		}
+	}
+
+	// Validate journal is balanced
+	var totalDebit, totalCredit models.Money
+	for _, line := range lines {
+		totalDebit += line.Debit
+		totalCredit += line.Credit
+	}
+	if totalDebit != totalCredit {
+		return nil, errors.New("payroll journal is not balanced: gross salary does not match net salary plus deductions")
+	}
+
+	return &models.Journal{
+		BranchID:     branch.ID,
+		JournalDate:  paymentDate,
+		Description:  description,
+		ReferenceNo:  referenceNo,
+		Status:       models.JournalStatusPosted,
+		IsPosted:     true,
+		PostedBy:     &userID,
+		CreatedBy:    userID,
+		JournalLines: lines,
+	}, nil
+}
+
+// resolveSalaryExpenseAccount returns the department salary expense account,
+// falling back to the branch salary expense account when the department has no mapping
+func (s *payrollService) resolveSalaryExpenseAccount(payroll *models.Payroll) (uuid.UUID, error) {
+	if payroll.Employee.Department != "" {
+		key := models.DepartmentSalaryExpenseMappingKey(payroll.Employee.Department)
+		mapping, err := s.mappingRepo.GetByBranchKey(&payroll.BranchID, key)
+		if err != nil {
+			return uuid.Nil, err
+		}
+		if mapping == nil {
+			if mapping, err = s.mappingRepo.GetByBranchKey(nil, key); err != nil {
+				return uuid.Nil, err
+			}
+		}
+		if mapping != nil {
+			return mapping.AccountID, nil
+		}
+	}
+
+	mapping, err := s.mappingRepo.Resolve(models.AccountMappingPayrollSalaryExpense, payroll.BranchID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return mapping.AccountID, nil
+}
+
+// addPayrollJournalLine merges an amount into the line for the same account and side
+func addPayrollJournalLine(lines []models.JournalLine, accountID uuid.UUID, description string, debit, credit models.Money) []models.JournalLine {
+	for i := range lines {
+		if lines[i].AccountID == accountID && (lines[i].Debit > 0) == (debit > 0) {
+			lines[i].Debit += debit
+			lines[i].Credit += credit
+			return lines
+		}
+	}
+
+	return append(lines, models.JournalLine{
+		AccountID:   accountID,
+		Description: description,
+		Debit:       debit,
+		Credit:      credit,
+	})
+}
+
 func (s *payrollService) GenerateBulkPayroll(branchID uuid.UUID, period string, paymentDate time.Time) ([]models.Payroll, error) {
 	// Get all active employees in branch
 	employees, err := s.employeeRepo.GetByBranch(branchID)
@@ -246,7 +433,7 @@ func (s *payrollService) GenerateBulkPayroll(branchID uuid.UUID, period string,
 	return payrolls, nil
 }
 
-func (s *payrollService) CalculateTax(grossSalary float64, maritalStatus string, dependents int) float64 {
+func (s *payrollService) CalculateTax(grossSalary models.Money, maritalStatus string, dependents int) (models.Money, error) {
 	// PPh 21 Calculation (Indonesia Tax 2024 - Simplified)
 	// PTKP (Penghasilan Tidak Kena Pajak) based on marital status
 	
@@ -254,23 +441,23 @@ func (s *payrollService) CalculateTax(grossSalary float64, maritalStatus string,
 	annualGross := grossSalary * 12
 	
 	// Calculate PTKP
-	ptkp := 54000000.0 // Base PTKP for single (TK/0)
+	ptkp := 54000000 * models.Rupiah // Base PTKP for single (TK/0)
 	
 	if maritalStatus == models.MaritalStatusMarried {
-		ptkp = 58500000.0 // Married base (K/0)
+		ptkp = 58500000 * models.Rupiah // Married base (K/0)
 		
 		// Add PTKP for dependents (max 3)
 		if dependents > 3 {
 			dependents = 3
 		}
-		ptkp += float64(dependents) * 4500000.0
+		ptkp += models.Money(dependents) * 4500000 * models.Rupiah
 	}
 	
-	// Taxable income
-	taxableIncome := annualGross - ptkp
+	// Taxable income, rounded down to whole thousands of rupiah
+	taxableIncome := (annualGross - ptkp).Floor(1000 * models.Rupiah)
 	
 	if taxableIncome <= 0 {
-		return 0
+		return 0, nil
 	}
 	
 	// Progressive tax rates (2024)
@@ -279,26 +466,37 @@ func (s *payrollService) CalculateTax(grossSalary float64, maritalStatus string,
 	// 250-500jt: 25%
 	// 500-5M: 30%
 	// >5M: 35%
-	
-	var annualTax float64
-	
-	if taxableIncome <= 60000000 {
-		annualTax = taxableIncome * 0.05
-	} else if taxableIncome <= 250000000 {
-		annualTax = 60000000*0.05 + (taxableIncome-60000000)*0.15
-	} else if taxableIncome <= 500000000 {
-		annualTax = 60000000*0.05 + 190000000*0.15 + (taxableIncome-250000000)*0.25
-	} else if taxableIncome <= 5000000000 {
-		annualTax = 60000000*0.05 + 190000000*0.15 + 250000000*0.25 + (taxableIncome-500000000)*0.30
-	} else {
-		annualTax = 60000000*0.05 + 190000000*0.15 + 250000000*0.25 + 4500000000*0.30 + (taxableIncome-5000000000)*0.35
+	brackets := []struct {
+		upTo models.Money // Zero for the top bracket
+		rate float64
+	}{
+		{60000000 * models.Rupiah, 5},
+		{250000000 * models.Rupiah, 15},
+		{500000000 * models.Rupiah, 25},
+		{5000000000 * models.Rupiah, 30},
+		{0, 35},
 	}
 	
-	// Monthly tax
-	monthlyTax := annualTax / 12
+	var annualTax, lower models.Money
+	for _, bracket := range brackets {
+		upper := taxableIncome
+		if bracket.upTo > 0 && bracket.upTo < upper {
+			upper = bracket.upTo
+		}
+		tax, err := (upper - lower).Percent(bracket.rate)
+		if err != nil {
+			return 0, err
+		}
+		annualTax += tax
+		
+		if bracket.upTo == 0 || taxableIncome <= bracket.upTo {
+			break
+		}
+		lower = bracket.upTo
+	}
 	
-	// Round to nearest rupiah
-	return math.Round(monthlyTax)
+	// Monthly tax, rounded to the nearest rupiah
+	return annualTax.DivRound(12, models.Rupiah), nil
 }
 
 func (s *payrollService) Delete(id uuid.UUID) error {
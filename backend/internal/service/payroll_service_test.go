@@ -0,0 +1,43 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/yayasan/erp-backend/internal/models"
+)
+
+func TestCalculateTax(t *testing.T) {
+	tests := []struct {
+		name          string
+		grossSalary   models.Money
+		maritalStatus string
+		dependents    int
+		want          models.Money
+	}{
+		{name: "below PTKP", grossSalary: 4000000 * models.Rupiah, want: 0},
+		{name: "at PTKP", grossSalary: 4500000 * models.Rupiah, want: 0},
+		{name: "first bracket", grossSalary: 5000000 * models.Rupiah, want: 25000 * models.Rupiah},
+		{name: "taxable income floored to thousands", grossSalary: 5000050*models.Rupiah + 50, want: 25000 * models.Rupiah},
+		{name: "half rupiah rounded up", grossSalary: 4500250 * models.Rupiah, want: 13 * models.Rupiah},
+		{name: "second bracket", grossSalary: 10000000 * models.Rupiah, want: 325000 * models.Rupiah},
+		{name: "fourth bracket", grossSalary: 50000000 * models.Rupiah, want: 8983333 * models.Rupiah},
+		{name: "top bracket", grossSalary: 500000000 * models.Rupiah, want: 147925000 * models.Rupiah},
+		{name: "married", grossSalary: 10000000 * models.Rupiah, maritalStatus: models.MaritalStatusMarried, want: 268750 * models.Rupiah},
+		{name: "married with three dependents", grossSalary: 10000000 * models.Rupiah, maritalStatus: models.MaritalStatusMarried, dependents: 3, want: 200000 * models.Rupiah},
+		{name: "dependents capped at three", grossSalary: 10000000 * models.Rupiah, maritalStatus: models.MaritalStatusMarried, dependents: 5, want: 200000 * models.Rupiah},
+		{name: "dependents ignored when single", grossSalary: 10000000 * models.Rupiah, dependents: 2, want: 325000 * models.Rupiah},
+	}
+
+	s := &payrollService{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.CalculateTax(tt.grossSalary, tt.maritalStatus, tt.dependents)
+			if err != nil {
+				t.Fatalf("CalculateTax() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("CalculateTax() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
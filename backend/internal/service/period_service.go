@@ -0,0 +1,107 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yayasan/erp-backend/internal/models"
+	"github.com/yayasan/erp-backend/internal/repository"
+)
+
+type PeriodService interface {
+	GetByBranch(branchID uuid.UUID, year string) ([]models.AccountingPeriod, error)
+	SetStatus(req *models.SetPeriodStatusRequest, userID uuid.UUID) (*models.AccountingPeriod, error)
+}
+
+type periodService struct {
+	periodRepo repository.AccountingPeriodRepository
+	branchRepo repository.BranchRepository
+}
+
+func NewPeriodService(
+	periodRepo repository.AccountingPeriodRepository,
+	branchRepo repository.BranchRepository,
+) PeriodService {
+	return &periodService{
+		periodRepo: periodRepo,
+		branchRepo: branchRepo,
+	}
+}
+
+func (s *periodService) GetByBranch(branchID uuid.UUID, year string) ([]models.AccountingPeriod, error) {
+	return s.periodRepo.GetByBranch(branchID, year)
+}
+
+func (s *periodService) SetStatus(req *models.SetPeriodStatusRequest, userID uuid.UUID) (*models.AccountingPeriod, error) {
+	if _, err := s.branchRepo.GetByID(req.BranchID); err != nil {
+		return nil, errors.New("branch not found")
+	}
+
+	periodStart, err := time.Parse("2006-01", req.Period)
+	if err != nil {
+		return nil, errors.New("invalid period format, use YYYY-MM")
+	}
+
+	// Periods of a closed fiscal year stay closed
+	closed, err := s.periodRepo.IsInClosedFiscalYear(periodStart)
+	if err != nil {
+		return nil, err
+	}
+	if closed && req.Status != models.PeriodStatusClosed {
+		return nil, errors.New("period belongs to a closed fiscal year")
+	}
+
+	period, err := s.periodRepo.GetByBranchPeriod(req.BranchID, req.Period)
+	if err != nil {
+		return nil, err
+	}
+	if period == nil {
+		period = &models.AccountingPeriod{
+			BranchID: req.BranchID,
+			Period:   req.Period,
+		}
+	}
+
+	period.Status = req.Status
+	period.Notes = req.Notes
+	if req.Status == models.PeriodStatusOpen {
+		period.ClosedAt = nil
+		period.ClosedBy = nil
+	} else {
+		now := time.Now()
+		period.ClosedAt = &now
+		period.ClosedBy = &userID
+	}
+
+	if err := s.periodRepo.Save(period); err != nil {
+		return nil, err
+	}
+
+	return period, nil
+}
+
+// ensurePeriodOpen rejects operational transactions (payments, payroll, depreciation)
+// dated in a soft-closed or closed period
+func ensurePeriodOpen(periodRepo repository.AccountingPeriodRepository, branchID uuid.UUID, date time.Time) error {
+	status, err := periodRepo.GetStatus(branchID, date)
+	if err != nil {
+		return err
+	}
+	if status != models.PeriodStatusOpen {
+		return errors.New("accounting period " + date.Format("2006-01") + " is " + status)
+	}
+	return nil
+}
+
+// ensurePeriodPostable rejects journals dated in a closed period; soft-closed periods accept adjustments
+func ensurePeriodPostable(periodRepo repository.AccountingPeriodRepository, branchID uuid.UUID, date time.Time) error {
+	status, err := periodRepo.GetStatus(branchID, date)
+	if err != nil {
+		return err
+	}
+	if status == models.PeriodStatusClosed {
+		return errors.New("accounting period " + date.Format("2006-01") + " is closed")
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/yayasan/erp-backend/internal/models"
+	"github.com/yayasan/erp-backend/internal/repository"
+)
+
+type ProgramService interface {
+	GetAll() ([]models.Program, error)
+	GetAllActive() ([]models.Program, error)
+	GetByID(id uuid.UUID) (*models.Program, error)
+	Create(req *models.CreateProgramRequest) (*models.Program, error)
+	Update(id uuid.UUID, req *models.UpdateProgramRequest) (*models.Program, error)
+	Delete(id uuid.UUID) error
+}
+
+type programService struct {
+	programRepo repository.ProgramRepository
+}
+
+func NewProgramService(programRepo repository.ProgramRepository) ProgramService {
+	return &programService{
+		programRepo: programRepo,
+	}
+}
+
+func (s *programService) GetAll() ([]models.Program, error) {
+	return s.programRepo.GetAll()
+}
+
+func (s *programService) GetAllActive() ([]models.Program, error) {
+	return s.programRepo.GetAllActive()
+}
+
+func (s *programService) GetByID(id uuid.UUID) (*models.Program, error) {
+	return s.programRepo.GetByID(id)
+}
+
+func (s *programService) Create(req *models.CreateProgramRequest) (*models.Program, error) {
+	// Check if code exists
+	existing, _ := s.programRepo.GetByCode(req.Code)
+	if existing != nil {
+		return nil, errors.New("program code already exists")
+	}
+
+	if req.StartDate != nil && req.EndDate != nil && req.EndDate.Before(*req.StartDate) {
+		return nil, errors.New("end date must be after start date")
+	}
+
+	program := &models.Program{
+		Code:        req.Code,
+		Name:        req.Name,
+		Description: req.Description,
+		StartDate:   req.StartDate,
+		EndDate:     req.EndDate,
+		IsActive:    true,
+	}
+
+	if err := s.programRepo.Create(program); err != nil {
+		return nil, err
+	}
+
+	return program, nil
+}
+
+func (s *programService) Update(id uuid.UUID, req *models.UpdateProgramRequest) (*models.Program, error) {
+	program, err := s.programRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("program not found")
+	}
+
+	if req.StartDate != nil && req.EndDate != nil && req.EndDate.Before(*req.StartDate) {
+		return nil, errors.New("end date must be after start date")
+	}
+
+	program.Name = req.Name
+	program.Description = req.Description
+	program.StartDate = req.StartDate
+	program.EndDate = req.EndDate
+
+	if req.IsActive != nil {
+		program.IsActive = *req.IsActive
+	}
+
+	if err := s.programRepo.Update(program); err != nil {
+		return nil, err
+	}
+
+	return program, nil
+}
+
+// Delete removes an unused program; programs with transactions must be deactivated instead
+func (s *programService) Delete(id uuid.UUID) error {
+	if _, err := s.programRepo.GetByID(id); err != nil {
+		return errors.New("program not found")
+	}
+
+	used, err := s.programRepo.IsUsed(id)
+	if err != nil {
+		return err
+	}
+	if used {
+		return errors.New("program is already used, deactivate it instead")
+	}
+
+	return s.programRepo.Delete(id)
+}
@@ -0,0 +1,526 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yayasan/erp-backend/internal/config"
+	"github.com/yayasan/erp-backend/internal/models"
+	"github.com/yayasan/erp-backend/internal/repository"
+)
+
+type RecurringJournalService interface {
+	GetAll(status string) ([]models.RecurringJournal, error)
+	GetByID(id uuid.UUID) (*models.RecurringJournal, error)
+	GetRuns(id uuid.UUID) ([]models.RecurringJournalRun, error)
+	Create(req *models.CreateRecurringJournalRequest, userID uuid.UUID) (*models.RecurringJournal, error)
+	Update(id uuid.UUID, req *models.UpdateRecurringJournalRequest) (*models.RecurringJournal, error)
+	Delete(id uuid.UUID) error
+	Pause(id uuid.UUID) (*models.RecurringJournal, error)
+	Resume(id uuid.UUID, userID uuid.UUID) (*models.RecurringJournal, error)
+	Skip(id uuid.UUID, req *models.SkipRecurringJournalRequest, userID uuid.UUID) (*models.RecurringJournalRun, error)
+	GenerateDue(asOfDate time.Time, userID *uuid.UUID) (*models.RecurringGenerationResult, error)
+}
+
+type recurringJournalService struct {
+	recurringRepo  repository.RecurringJournalRepository
+	accountRepo    repository.AccountRepository
+	branchRepo     repository.BranchRepository
+	journalService JournalService
+}
+
+func NewRecurringJournalService(
+	recurringRepo repository.RecurringJournalRepository,
+	accountRepo repository.AccountRepository,
+	branchRepo repository.BranchRepository,
+	journalService JournalService,
+) RecurringJournalService {
+	return &recurringJournalService{
+		recurringRepo:  recurringRepo,
+		accountRepo:    accountRepo,
+		branchRepo:     branchRepo,
+		journalService: journalService,
+	}
+}
+
+func (s *recurringJournalService) GetAll(status string) ([]models.RecurringJournal, error) {
+	return s.recurringRepo.GetAll(status)
+}
+
+func (s *recurringJournalService) GetByID(id uuid.UUID) (*models.RecurringJournal, error) {
+	return s.recurringRepo.GetByID(id)
+}
+
+func (s *recurringJournalService) GetRuns(id uuid.UUID) ([]models.RecurringJournalRun, error) {
+	if _, err := s.recurringRepo.GetByID(id); err != nil {
+		return nil, err
+	}
+	return s.recurringRepo.GetRuns(id)
+}
+
+func (s *recurringJournalService) Create(req *models.CreateRecurringJournalRequest, userID uuid.UUID) (*models.RecurringJournal, error) {
+	if _, err := s.branchRepo.GetByID(req.BranchID); err != nil {
+		return nil, errors.New("branch not found")
+	}
+
+	startDate := dateOnly(req.StartDate)
+	endDate, err := recurringEndDate(startDate, req.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.validateRecurringLines(req.JournalLines); err != nil {
+		return nil, err
+	}
+
+	journalType := req.Type
+	if journalType == "" {
+		journalType = models.JournalTypeGeneral
+	}
+
+	interval := req.Interval
+	if interval == 0 {
+		interval = 1
+	}
+
+	recurring := &models.RecurringJournal{
+		BranchID:    req.BranchID,
+		Name:        req.Name,
+		Description: req.Description,
+		ReferenceNo: req.ReferenceNo,
+		Type:        journalType,
+		Frequency:   req.Frequency,
+		Interval:    interval,
+		StartDate:   startDate,
+		EndDate:     endDate,
+		NextRunDate: &startDate,
+		AutoSubmit:  req.AutoSubmit,
+		Status:      models.RecurringStatusActive,
+		CreatedBy:   userID,
+		Lines:       recurringLines(req.JournalLines),
+	}
+
+	if err := s.recurringRepo.Create(recurring); err != nil {
+		return nil, err
+	}
+
+	return s.recurringRepo.GetByID(recurring.ID)
+}
+
+func (s *recurringJournalService) Update(id uuid.UUID, req *models.UpdateRecurringJournalRequest) (*models.RecurringJournal, error) {
+	recurring, err := s.recurringRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	endDate, err := recurringEndDate(recurring.StartDate, req.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.validateRecurringLines(req.JournalLines); err != nil {
+		return nil, err
+	}
+
+	recurring.Name = req.Name
+	recurring.Description = req.Description
+	recurring.ReferenceNo = req.ReferenceNo
+	recurring.EndDate = endDate
+	recurring.AutoSubmit = req.AutoSubmit
+	recurring.Lines = recurringLines(req.JournalLines)
+
+	// A changed end date can finish the schedule or reopen a completed one
+	next := recurringDueDate(recurring, recurring.Occurrence)
+	if recurringFinished(recurring, next) {
+		recurring.NextRunDate = nil
+		recurring.Status = models.RecurringStatusCompleted
+	} else {
+		recurring.NextRunDate = &next
+		if recurring.Status == models.RecurringStatusCompleted {
+			recurring.Status = models.RecurringStatusActive
+		}
+	}
+
+	if err := s.recurringRepo.Update(recurring); err != nil {
+		return nil, err
+	}
+
+	return s.recurringRepo.GetByID(id)
+}
+
+// Delete removes the template. Journals already generated from it are kept.
+func (s *recurringJournalService) Delete(id uuid.UUID) error {
+	if _, err := s.recurringRepo.GetByID(id); err != nil {
+		return err
+	}
+	return s.recurringRepo.Delete(id)
+}
+
+func (s *recurringJournalService) Pause(id uuid.UUID) (*models.RecurringJournal, error) {
+	recurring, err := s.recurringRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if recurring.Status != models.RecurringStatusActive {
+		return nil, errors.New("only active recurring journals can be paused")
+	}
+
+	recurring.Status = models.RecurringStatusPaused
+	if err := s.recurringRepo.UpdateSchedule(recurring); err != nil {
+		return nil, err
+	}
+
+	return recurring, nil
+}
+
+// Resume reactivates a paused template. Due dates that passed while it was paused are recorded
+// as skipped rather than generated late.
+func (s *recurringJournalService) Resume(id uuid.UUID, userID uuid.UUID) (*models.RecurringJournal, error) {
+	recurring, err := s.recurringRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if recurring.Status != models.RecurringStatusPaused {
+		return nil, errors.New("only paused recurring journals can be resumed")
+	}
+
+	recurring.Status = models.RecurringStatusActive
+	today := dateOnly(time.Now())
+	for recurring.NextRunDate != nil && recurring.NextRunDate.Before(today) {
+		run := &models.RecurringJournalRun{
+			RecurringJournalID: recurring.ID,
+			ScheduledDate:      *recurring.NextRunDate,
+			Status:             models.RecurringRunSkipped,
+			Message:            "Skipped while paused",
+			ProcessedBy:        &userID,
+		}
+		if _, err := s.recurringRepo.ClaimRun(run); err != nil {
+			return nil, err
+		}
+		advanceRecurring(recurring)
+	}
+
+	if err := s.recurringRepo.UpdateSchedule(recurring); err != nil {
+		return nil, err
+	}
+
+	return recurring, nil
+}
+
+// Skip marks a due date so no journal is generated for it. Without a date, the next due date
+// is skipped.
+func (s *recurringJournalService) Skip(id uuid.UUID, req *models.SkipRecurringJournalRequest, userID uuid.UUID) (*models.RecurringJournalRun, error) {
+	recurring, err := s.recurringRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if recurring.NextRunDate == nil {
+		return nil, errors.New("recurring journal has no remaining due dates")
+	}
+
+	// The date must be one of the remaining due dates
+	occurrence := recurring.Occurrence
+	if req.ScheduledDate != nil {
+		target := req.ScheduledDate.Format("2006-01-02")
+		for {
+			due := recurringDueDate(recurring, occurrence)
+			if recurringFinished(recurring, due) || due.Format("2006-01-02") > target {
+				return nil, fmt.Errorf("%s is not a due date of this recurring journal", target)
+			}
+			if due.Format("2006-01-02") == target {
+				break
+			}
+			occurrence++
+		}
+	}
+	scheduledDate := recurringDueDate(recurring, occurrence)
+
+	message := req.Reason
+	if message == "" {
+		message = "Skipped manually"
+	}
+
+	run := &models.RecurringJournalRun{
+		RecurringJournalID: recurring.ID,
+		ScheduledDate:      scheduledDate,
+		Status:             models.RecurringRunSkipped,
+		Message:            message,
+		ProcessedBy:        &userID,
+	}
+	claimed, err := s.recurringRepo.ClaimRun(run)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, fmt.Errorf("due date %s is already %s", scheduledDate.Format("2006-01-02"), run.Status)
+	}
+
+	if occurrence == recurring.Occurrence {
+		advanceRecurring(recurring)
+		if err := s.recurringRepo.UpdateSchedule(recurring); err != nil {
+			return nil, err
+		}
+	}
+
+	return run, nil
+}
+
+// GenerateDue creates the journals of all active templates due on or before the given date.
+// Each due date is claimed before its journal is created, so running several generators at
+// once, or retrying after a failure, never produces a journal twice.
+func (s *recurringJournalService) GenerateDue(asOfDate time.Time, userID *uuid.UUID) (*models.RecurringGenerationResult, error) {
+	asOfDate = dateOnly(asOfDate)
+	before := asOfDate.AddDate(0, 0, 1)
+
+	due, err := s.recurringRepo.GetDue(before)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.RecurringGenerationResult{
+		AsOfDate: asOfDate,
+		Runs:     []models.RecurringJournalRun{},
+	}
+
+	for i := range due {
+		recurring := &due[i]
+		start := recurring.Occurrence
+
+		for recurring.NextRunDate != nil && recurring.NextRunDate.Before(before) {
+			run := &models.RecurringJournalRun{
+				RecurringJournalID: recurring.ID,
+				ScheduledDate:      *recurring.NextRunDate,
+				Status:             models.RecurringRunProcessing,
+				ProcessedBy:        userID,
+			}
+			claimed, err := s.recurringRepo.ClaimRun(run)
+			if err != nil {
+				return nil, err
+			}
+
+			if !claimed {
+				// Another generator is still working on this date
+				if run.Status == models.RecurringRunProcessing {
+					break
+				}
+				advanceRecurring(recurring)
+				continue
+			}
+
+			if err := s.generateRun(recurring, run); err != nil {
+				return nil, err
+			}
+			result.Runs = append(result.Runs, *run)
+
+			if run.Status == models.RecurringRunFailed {
+				result.Failed++
+				break
+			}
+			result.Generated++
+			advanceRecurring(recurring)
+		}
+
+		if recurring.Occurrence != start {
+			if err := s.recurringRepo.UpdateSchedule(recurring); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// generateRun creates the draft journal for a claimed run and saves the outcome on it. The
+// journal and the run are saved together, so a failure leaves neither.
+func (s *recurringJournalService) generateRun(recurring *models.RecurringJournal, run *models.RecurringJournalRun) error {
+	lines := make([]models.CreateJournalLineReq, len(recurring.Lines))
+	for i, line := range recurring.Lines {
+		lines[i] = models.CreateJournalLineReq{
+			AccountID:           line.AccountID,
+			Description:         line.Description,
+			Debit:               line.Debit,
+			Credit:              line.Credit,
+			FundID:              line.FundID,
+			ProgramID:           line.ProgramID,
+			DonorID:             line.DonorID,
+			BranchID:            line.BranchID,
+			CounterpartBranchID: line.CounterpartBranchID,
+		}
+	}
+
+	journal, err := s.journalService.Prepare(&models.CreateJournalRequest{
+		BranchID:     recurring.BranchID,
+		JournalDate:  run.ScheduledDate,
+		Description:  recurring.Description,
+		ReferenceNo:  recurring.ReferenceNo,
+		Type:         recurring.Type,
+		JournalLines: lines,
+	}, recurring.CreatedBy)
+	if err != nil {
+		run.Status = models.RecurringRunFailed
+		run.Message = err.Error()
+		return s.recurringRepo.SaveRun(run)
+	}
+
+	run.Status = models.RecurringRunGenerated
+	run.Message = ""
+	if err := s.recurringRepo.SaveRunJournal(run, journal); err != nil {
+		// Release the due date for the next run
+		run.Status = models.RecurringRunFailed
+		run.Message = err.Error()
+		run.JournalID = nil
+		return s.recurringRepo.SaveRun(run)
+	}
+
+	if recurring.AutoSubmit {
+		if _, err := s.journalService.SubmitForReview(journal.ID, recurring.CreatedBy); err != nil {
+			run.Message = "journal created as draft, submit failed: " + err.Error()
+			return s.recurringRepo.SaveRun(run)
+		}
+	}
+	return nil
+}
+
+func (s *recurringJournalService) validateRecurringLines(lines []models.CreateJournalLineReq) error {
+	if len(lines) < 2 {
+		return errors.New("recurring journal must have at least 2 lines")
+	}
+
+	var totalDebit, totalCredit models.Money
+	for i, line := range lines {
+		account, err := s.accountRepo.GetByID(line.AccountID)
+		if err != nil {
+			return fmt.Errorf("account not found on line %d", i+1)
+		}
+
+		if !account.CanPostTransaction() {
+			return errors.New("account " + account.Code + " cannot have transactions")
+		}
+
+		// Rates change between due dates, so templates are kept in the default currency
+		if foreignCurrency(line.Currency) != "" || account.Currency != "" {
+			return errors.New("recurring journals can only have lines in " + config.GlobalConfig.App.DefaultCurrency)
+		}
+
+		if line.Debit > 0 && line.Credit > 0 {
+			return errors.New("line cannot have both debit and credit")
+		}
+
+		if line.Debit == 0 && line.Credit == 0 {
+			return errors.New("line must have either debit or credit")
+		}
+
+		totalDebit += line.Debit
+		totalCredit += line.Credit
+	}
+
+	if totalDebit != totalCredit {
+		return errors.New("recurring journal is not balanced: debit != credit")
+	}
+
+	return nil
+}
+
+// RunRecurringJournalScheduler generates the recurring journals that have fallen due, catching
+// up on any runs missed while the server was down
+func RunRecurringJournalScheduler(ctx context.Context, recurringService RecurringJournalService, interval time.Duration) {
+	runEvery(ctx, interval, func() {
+		result, err := recurringService.GenerateDue(time.Now(), nil)
+		if err != nil {
+			log.Printf("Recurring journal generation failed: %v", err)
+		} else if result.Generated > 0 || result.Failed > 0 {
+			log.Printf("Recurring journals: %d generated, %d failed", result.Generated, result.Failed)
+		}
+	})
+}
+
+// runEvery runs a background job at once and then every interval until the context is cancelled
+func runEvery(ctx context.Context, interval time.Duration, job func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		job()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func recurringLines(lines []models.CreateJournalLineReq) []models.RecurringJournalLine {
+	result := make([]models.RecurringJournalLine, len(lines))
+	for i, line := range lines {
+		result[i] = models.RecurringJournalLine{
+			LineNo:              i + 1,
+			AccountID:           line.AccountID,
+			Description:         line.Description,
+			Debit:               line.Debit,
+			Credit:              line.Credit,
+			FundID:              line.FundID,
+			ProgramID:           line.ProgramID,
+			DonorID:             line.DonorID,
+			BranchID:            line.BranchID,
+			CounterpartBranchID: line.CounterpartBranchID,
+		}
+	}
+	return result
+}
+
+func recurringEndDate(startDate time.Time, endDate *time.Time) (*time.Time, error) {
+	if endDate == nil {
+		return nil, nil
+	}
+	end := dateOnly(*endDate)
+	if end.Before(startDate) {
+		return nil, errors.New("end date must be on or after start date")
+	}
+	return &end, nil
+}
+
+// advanceRecurring moves the schedule past its next due date, completing it after the end date
+func advanceRecurring(recurring *models.RecurringJournal) {
+	recurring.Occurrence++
+	next := recurringDueDate(recurring, recurring.Occurrence)
+	if recurringFinished(recurring, next) {
+		recurring.NextRunDate = nil
+		recurring.Status = models.RecurringStatusCompleted
+		return
+	}
+	recurring.NextRunDate = &next
+}
+
+// recurringDueDate returns the due date of the n-th occurrence, counting from zero. Dates are
+// computed from the start date so month-end schedules do not drift.
+func recurringDueDate(recurring *models.RecurringJournal, n int) time.Time {
+	step := n * recurring.Interval
+	switch recurring.Frequency {
+	case models.RecurringFrequencyDaily:
+		return recurring.StartDate.AddDate(0, 0, step)
+	case models.RecurringFrequencyWeekly:
+		return recurring.StartDate.AddDate(0, 0, 7*step)
+	case models.RecurringFrequencyQuarterly:
+		return addMonths(recurring.StartDate, 3*step)
+	case models.RecurringFrequencyYearly:
+		return addMonths(recurring.StartDate, 12*step)
+	}
+	return addMonths(recurring.StartDate, step)
+}
+
+func recurringFinished(recurring *models.RecurringJournal, dueDate time.Time) bool {
+	return recurring.EndDate != nil && dueDate.After(*recurring.EndDate)
+}
+
+// dateOnly drops the time of day
+func dateOnly(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+}
@@ -0,0 +1,903 @@
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/google/uuid"
+	"github.com/xuri/excelize/v2"
+	"github.com/yayasan/erp-backend/internal/models"
+	"github.com/yayasan/erp-backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+type ReportExportService interface {
+	ExportTrialBalance(req *models.TrialBalanceRequest, report *models.TrialBalanceResponse, format string, userID uuid.UUID) (*models.ExportedReport, error)
+	ExportBalanceSheet(req *models.BalanceSheetRequest, report *models.BalanceSheetResponse, format string, userID uuid.UUID) (*models.ExportedReport, error)
+	ExportIncomeStatement(req *models.IncomeStatementRequest, report *models.IncomeStatementResponse, format string, userID uuid.UUID) (*models.ExportedReport, error)
+	ExportGeneralLedger(req *models.GeneralLedgerRequest, report *models.GeneralLedgerResponse, format string, userID uuid.UUID) (*models.ExportedReport, error)
+	ExportBudgetVsActual(req *models.BudgetVsActualRequest, report *models.BudgetVsActualResponse, format string, userID uuid.UUID) (*models.ExportedReport, error)
+	ExportJournals(params *models.JournalSearchParams, journals []models.JournalResponse, format string, userID uuid.UUID) (*models.ExportedReport, error)
+	ExportJournalLines(params *models.JournalSearchParams, lines []models.JournalLineSearchResult, format string, userID uuid.UUID) (*models.ExportedReport, error)
+}
+
+type reportExportService struct {
+	db          *gorm.DB
+	userRepo    repository.UserRepository
+	branchRepo  repository.BranchRepository
+	accountRepo repository.AccountRepository
+	fundRepo    repository.FundRepository
+	programRepo repository.ProgramRepository
+}
+
+func NewReportExportService(
+	db *gorm.DB,
+	userRepo repository.UserRepository,
+	branchRepo repository.BranchRepository,
+	accountRepo repository.AccountRepository,
+	fundRepo repository.FundRepository,
+	programRepo repository.ProgramRepository,
+) ReportExportService {
+	return &reportExportService{
+		db:          db,
+		userRepo:    userRepo,
+		branchRepo:  branchRepo,
+		accountRepo: accountRepo,
+		fundRepo:    fundRepo,
+		programRepo: programRepo,
+	}
+}
+
+const exportDateFormat = "2006-01-02"
+
+func (s *reportExportService) ExportTrialBalance(req *models.TrialBalanceRequest, report *models.TrialBalanceResponse, format string, userID uuid.UUID) (*models.ExportedReport, error) {
+	doc := &models.ReportDocument{
+		Title:    "Trial Balance",
+		FileName: "trial-balance-" + report.AsOfDate.Format(exportDateFormat),
+		Parameters: []models.ReportParameter{
+			{Label: "As of", Value: report.AsOfDate.Format(exportDateFormat)},
+			{Label: "Branch", Value: s.branchLabel(req.BranchID)},
+			{Label: "Fund", Value: s.fundLabel(req.FundID)},
+			{Label: "Program", Value: s.programLabel(req.ProgramID)},
+		},
+		Columns: []models.ReportColumn{
+			{Title: "Account Code", Width: 1.2},
+			{Title: "Account Name", Width: 3},
+			{Title: "Category", Width: 1.2},
+			{Title: "Debit", Numeric: true, Width: 1.6},
+			{Title: "Credit", Numeric: true, Width: 1.6},
+		},
+	}
+
+	for _, line := range report.Lines {
+		doc.Rows = append(doc.Rows, models.ReportRow{
+			Cells: []interface{}{line.AccountCode, line.AccountName, line.Category, line.Debit, line.Credit},
+		})
+	}
+
+	doc.Rows = append(doc.Rows, models.ReportRow{
+		Cells: []interface{}{"", "Total", "", report.Summary.TotalDebit, report.Summary.TotalCredit},
+		Bold:  true,
+	})
+	if !report.Summary.IsBalanced {
+		doc.Rows = append(doc.Rows, models.ReportRow{
+			Cells: []interface{}{"", "Difference", "", report.Summary.Difference, ""},
+			Bold:  true,
+		})
+	}
+
+	return s.export(doc, format, userID)
+}
+
+func (s *reportExportService) ExportBalanceSheet(req *models.BalanceSheetRequest, report *models.BalanceSheetResponse, format string, userID uuid.UUID) (*models.ExportedReport, error) {
+	doc := &models.ReportDocument{
+		Title:    "Balance Sheet",
+		FileName: "balance-sheet-" + report.AsOfDate.Format(exportDateFormat),
+		Parameters: []models.ReportParameter{
+			{Label: "As of", Value: report.AsOfDate.Format(exportDateFormat)},
+			{Label: "Branch", Value: s.branchLabel(req.BranchID)},
+			{Label: "Fund", Value: s.fundLabel(req.FundID)},
+		},
+		Columns: amountColumns(),
+	}
+
+	addBalanceSheetSection(doc, "Assets", report.Assets)
+	addBalanceSheetSection(doc, "Liabilities", report.Liabilities)
+	addBalanceSheetSection(doc, "Equity", report.Equity)
+	doc.Rows = append(doc.Rows, models.ReportRow{
+		Cells: []interface{}{"", "Total Liabilities and Equity", report.TotalLiabilities + report.TotalEquity},
+		Bold:  true,
+	})
+
+	return s.export(doc, format, userID)
+}
+
+func (s *reportExportService) ExportIncomeStatement(req *models.IncomeStatementRequest, report *models.IncomeStatementResponse, format string, userID uuid.UUID) (*models.ExportedReport, error) {
+	doc := &models.ReportDocument{
+		Title:    "Income Statement",
+		FileName: "income-statement-" + report.StartDate.Format(exportDateFormat) + "-" + report.EndDate.Format(exportDateFormat),
+		Parameters: []models.ReportParameter{
+			{Label: "Period", Value: periodLabel(report.StartDate, report.EndDate)},
+			{Label: "Branch", Value: s.branchLabel(req.BranchID)},
+			{Label: "Fund", Value: s.fundLabel(req.FundID)},
+		},
+		Columns: amountColumns(),
+	}
+
+	addIncomeStatementSection(doc, "Revenue", report.Revenue)
+	addIncomeStatementSection(doc, "Expenses", report.Expenses)
+	doc.Rows = append(doc.Rows, models.ReportRow{
+		Cells: []interface{}{"", "Net Income", report.NetIncome},
+		Bold:  true,
+	})
+
+	return s.export(doc, format, userID)
+}
+
+func (s *reportExportService) ExportGeneralLedger(req *models.GeneralLedgerRequest, report *models.GeneralLedgerResponse, format string, userID uuid.UUID) (*models.ExportedReport, error) {
+	doc := &models.ReportDocument{
+		Title:    "General Ledger",
+		FileName: "general-ledger-" + report.Account.Code + "-" + report.StartDate.Format(exportDateFormat) + "-" + report.EndDate.Format(exportDateFormat),
+		Parameters: []models.ReportParameter{
+			{Label: "Account", Value: report.Account.Code + " - " + report.Account.Name},
+			{Label: "Period", Value: periodLabel(report.StartDate, report.EndDate)},
+			{Label: "Branch", Value: s.branchLabel(req.BranchID)},
+		},
+		Columns: []models.ReportColumn{
+			{Title: "Date", Width: 1.1},
+			{Title: "Journal No", Width: 1.6},
+			{Title: "Description", Width: 3.2},
+			{Title: "Debit", Numeric: true, Width: 1.5},
+			{Title: "Credit", Numeric: true, Width: 1.5},
+			{Title: "Balance", Numeric: true, Width: 1.6},
+		},
+	}
+
+	doc.Rows = append(doc.Rows, models.ReportRow{
+		Cells: []interface{}{report.StartDate.Format(exportDateFormat), "", "Opening Balance", "", "", report.OpeningBalance},
+		Bold:  true,
+	})
+	for _, line := range report.Transactions {
+		doc.Rows = append(doc.Rows, models.ReportRow{
+			Cells: []interface{}{line.Date.Format(exportDateFormat), line.JournalNumber, line.Description, line.Debit, line.Credit, line.Balance},
+		})
+	}
+	doc.Rows = append(doc.Rows,
+		models.ReportRow{
+			Cells: []interface{}{"", "", "Total", report.TotalDebit, report.TotalCredit, ""},
+			Bold:  true,
+		},
+		models.ReportRow{
+			Cells: []interface{}{report.EndDate.Format(exportDateFormat), "", "Closing Balance", "", "", report.ClosingBalance},
+			Bold:  true,
+		},
+	)
+
+	return s.export(doc, format, userID)
+}
+
+func (s *reportExportService) ExportBudgetVsActual(req *models.BudgetVsActualRequest, report *models.BudgetVsActualResponse, format string, userID uuid.UUID) (*models.ExportedReport, error) {
+	period := report.Period
+	if period == "" {
+		period = "Full year"
+	}
+
+	account := "All accounts"
+	if req.AccountID != nil {
+		account = req.AccountID.String()
+		if found, err := s.accountRepo.GetByID(*req.AccountID); err == nil {
+			account = found.Code + " - " + found.Name
+		}
+	}
+
+	fileName := "budget-vs-actual-" + strings.ReplaceAll(strings.ToLower(report.FiscalYear), " ", "-")
+	if report.Period != "" {
+		fileName += "-" + report.Period
+	}
+
+	doc := &models.ReportDocument{
+		Title:    "Budget vs Actual",
+		FileName: fileName,
+		Parameters: []models.ReportParameter{
+			{Label: "Fiscal Year", Value: report.FiscalYear},
+			{Label: "Period", Value: period},
+			{Label: "Branch", Value: s.branchLabel(req.BranchID)},
+			{Label: "Account", Value: account},
+		},
+		Columns: []models.ReportColumn{
+			{Title: "Account Code", Width: 1.2},
+			{Title: "Account Name", Width: 3},
+			{Title: "Budget", Numeric: true, Width: 1.5},
+			{Title: "Actual", Numeric: true, Width: 1.5},
+			{Title: "Variance", Numeric: true, Width: 1.5},
+			{Title: "Variance %", Numeric: true, Width: 1},
+		},
+	}
+
+	for _, line := range report.Lines {
+		doc.Rows = append(doc.Rows, models.ReportRow{
+			Cells: []interface{}{line.AccountCode, line.AccountName, line.Budget, line.Actual, line.Variance, line.VariancePct},
+		})
+	}
+	doc.Rows = append(doc.Rows, models.ReportRow{
+		Cells: []interface{}{"", "Total", report.Summary.TotalBudget, report.Summary.TotalActual, report.Summary.TotalVariance, report.Summary.TotalVariancePct},
+		Bold:  true,
+	})
+
+	return s.export(doc, format, userID)
+}
+
+func (s *reportExportService) ExportJournals(params *models.JournalSearchParams, journals []models.JournalResponse, format string, userID uuid.UUID) (*models.ExportedReport, error) {
+	doc := &models.ReportDocument{
+		Title:      "Journals",
+		FileName:   "journals-" + time.Now().Format(exportDateFormat),
+		Parameters: s.journalSearchParameters(params),
+		Columns: []models.ReportColumn{
+			{Title: "Date", Width: 1.1},
+			{Title: "Journal No", Width: 1.6},
+			{Title: "Reference", Width: 1.3},
+			{Title: "Description", Width: 3},
+			{Title: "Status", Width: 1},
+			{Title: "Debit", Numeric: true, Width: 1.5},
+			{Title: "Credit", Numeric: true, Width: 1.5},
+		},
+	}
+
+	var totalDebit, totalCredit models.Money
+	for _, journal := range journals {
+		doc.Rows = append(doc.Rows, models.ReportRow{
+			Cells: []interface{}{journal.JournalDate.Format(exportDateFormat), journal.JournalNumber, journal.ReferenceNo, journal.Description, journal.StatusName, journal.TotalDebit, journal.TotalCredit},
+		})
+		totalDebit += journal.TotalDebit
+		totalCredit += journal.TotalCredit
+	}
+	doc.Rows = append(doc.Rows, models.ReportRow{
+		Cells: []interface{}{"", "", "", "Total", "", totalDebit, totalCredit},
+		Bold:  true,
+	})
+
+	return s.export(doc, format, userID)
+}
+
+func (s *reportExportService) ExportJournalLines(params *models.JournalSearchParams, lines []models.JournalLineSearchResult, format string, userID uuid.UUID) (*models.ExportedReport, error) {
+	doc := &models.ReportDocument{
+		Title:      "Journal Lines",
+		FileName:   "journal-lines-" + time.Now().Format(exportDateFormat),
+		Parameters: s.journalSearchParameters(params),
+		Columns: []models.ReportColumn{
+			{Title: "Date", Width: 1.1},
+			{Title: "Journal No", Width: 1.6},
+			{Title: "Reference", Width: 1.2},
+			{Title: "Account Code", Width: 1.1},
+			{Title: "Account Name", Width: 2},
+			{Title: "Description", Width: 2.4},
+			{Title: "Debit", Numeric: true, Width: 1.4},
+			{Title: "Credit", Numeric: true, Width: 1.4},
+		},
+	}
+
+	var totalDebit, totalCredit models.Money
+	for _, line := range lines {
+		description := line.Description
+		if description == "" {
+			description = line.JournalDescription
+		}
+		doc.Rows = append(doc.Rows, models.ReportRow{
+			Cells: []interface{}{line.JournalDate.Format(exportDateFormat), line.JournalNumber, line.ReferenceNo, line.AccountCode, line.AccountName, description, line.Debit, line.Credit},
+		})
+		totalDebit += line.Debit
+		totalCredit += line.Credit
+	}
+	doc.Rows = append(doc.Rows, models.ReportRow{
+		Cells: []interface{}{"", "", "", "", "", "Total", totalDebit, totalCredit},
+		Bold:  true,
+	})
+
+	return s.export(doc, format, userID)
+}
+
+// export stamps the footer on a document and writes it in the requested format
+func (s *reportExportService) export(doc *models.ReportDocument, format string, userID uuid.UUID) (*models.ExportedReport, error) {
+	doc.GeneratedAt = time.Now()
+	doc.GeneratedBy = userID.String()
+	if user, err := s.userRepo.GetByID(userID); err == nil {
+		doc.GeneratedBy = user.FullName
+	}
+
+	var (
+		data        []byte
+		contentType string
+		err         error
+	)
+
+	switch format {
+	case models.ExportFormatCSV:
+		data, err = writeReportCSV(doc)
+		contentType = "text/csv"
+	case models.ExportFormatXLSX:
+		data, err = writeReportXLSX(doc, s.letterhead())
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case models.ExportFormatPDF:
+		data, err = writeReportPDF(doc, s.letterhead())
+		contentType = "application/pdf"
+	default:
+		return nil, errors.New("unsupported export format")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ExportedReport{
+		FileName:    doc.FileName + "." + format,
+		ContentType: contentType,
+		Data:        data,
+	}, nil
+}
+
+// letterhead returns the non-empty letterhead lines from settings, the yayasan name first
+func (s *reportExportService) letterhead() []string {
+	keys := []string{
+		models.SettingCompanyName,
+		models.SettingCompanyAddress,
+		models.SettingCompanyPhone,
+		models.SettingCompanyEmail,
+	}
+
+	var settings []models.Setting
+	if err := s.db.Where("setting_key IN ?", keys).Find(&settings).Error; err != nil {
+		return nil
+	}
+
+	values := make(map[string]string, len(settings))
+	for _, setting := range settings {
+		values[setting.SettingKey] = strings.TrimSpace(setting.SettingValue)
+	}
+
+	lines := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if values[key] != "" {
+			lines = append(lines, values[key])
+		}
+	}
+	return lines
+}
+
+func (s *reportExportService) branchLabel(id *uuid.UUID) string {
+	if id == nil {
+		return "All branches"
+	}
+	if branch, err := s.branchRepo.GetByID(*id); err == nil {
+		return branch.Code + " - " + branch.Name
+	}
+	return id.String()
+}
+
+func (s *reportExportService) fundLabel(id *uuid.UUID) string {
+	if id == nil {
+		return "All funds"
+	}
+	if fund, err := s.fundRepo.GetByID(*id); err == nil {
+		return fund.Code + " - " + fund.Name
+	}
+	return id.String()
+}
+
+func (s *reportExportService) programLabel(id *uuid.UUID) string {
+	if id == nil {
+		return "All programs"
+	}
+	if program, err := s.programRepo.GetByID(*id); err == nil {
+		return program.Code + " - " + program.Name
+	}
+	return id.String()
+}
+
+// journalSearchParameters describes the filters of a journal search
+func (s *reportExportService) journalSearchParameters(params *models.JournalSearchParams) []models.ReportParameter {
+	parameters := []models.ReportParameter{
+		{Label: "Branch", Value: s.branchLabel(params.BranchID)},
+	}
+
+	if params.StartDate != nil || params.EndDate != nil {
+		from, to := "", ""
+		if params.StartDate != nil {
+			from = params.StartDate.Format(exportDateFormat)
+		}
+		if params.EndDate != nil {
+			to = params.EndDate.Format(exportDateFormat)
+		}
+		parameters = append(parameters, models.ReportParameter{Label: "Period", Value: strings.TrimSpace(from + " to " + to)})
+	}
+	if params.Status != "" {
+		parameters = append(parameters, models.ReportParameter{Label: "Status", Value: params.Status})
+	}
+	if params.Type != "" {
+		parameters = append(parameters, models.ReportParameter{Label: "Type", Value: params.Type})
+	}
+	if params.AccountID != nil {
+		account := params.AccountID.String()
+		if found, err := s.accountRepo.GetByID(*params.AccountID); err == nil {
+			account = found.Code + " - " + found.Name
+		}
+		if params.IncludeSubAccounts {
+			account += " and sub-accounts"
+		}
+		parameters = append(parameters, models.ReportParameter{Label: "Account", Value: account})
+	}
+	if params.FundID != nil {
+		parameters = append(parameters, models.ReportParameter{Label: "Fund", Value: s.fundLabel(params.FundID)})
+	}
+	if params.ProgramID != nil {
+		parameters = append(parameters, models.ReportParameter{Label: "Program", Value: s.programLabel(params.ProgramID)})
+	}
+	if params.DonorID != nil {
+		donor := params.DonorID.String()
+		var found models.Donor
+		if err := s.db.Select("name").First(&found, "id = ?", *params.DonorID).Error; err == nil {
+			donor = found.Name
+		}
+		parameters = append(parameters, models.ReportParameter{Label: "Donor", Value: donor})
+	}
+	if params.CreatedBy != nil {
+		creator := params.CreatedBy.String()
+		if user, err := s.userRepo.GetByID(*params.CreatedBy); err == nil {
+			creator = user.FullName
+		}
+		parameters = append(parameters, models.ReportParameter{Label: "Created by", Value: creator})
+	}
+	if params.MinAmount != nil || params.MaxAmount != nil {
+		from, to := "", ""
+		if params.MinAmount != nil {
+			from = formatAmount(*params.MinAmount)
+		}
+		if params.MaxAmount != nil {
+			to = formatAmount(*params.MaxAmount)
+		}
+		parameters = append(parameters, models.ReportParameter{Label: "Amount", Value: strings.TrimSpace(from + " to " + to)})
+	}
+	if params.Search != "" {
+		parameters = append(parameters, models.ReportParameter{Label: "Search", Value: params.Search})
+	}
+
+	return parameters
+}
+
+// Document helpers
+
+func amountColumns() []models.ReportColumn {
+	return []models.ReportColumn{
+		{Title: "Account Code", Width: 1.2},
+		{Title: "Account Name", Width: 4},
+		{Title: "Amount", Numeric: true, Width: 1.8},
+	}
+}
+
+func addBalanceSheetSection(doc *models.ReportDocument, title string, section models.BalanceSheetSection) {
+	doc.Rows = append(doc.Rows, models.ReportRow{Cells: []interface{}{"", title, ""}, Bold: true})
+	for _, line := range section.Lines {
+		doc.Rows = append(doc.Rows, models.ReportRow{
+			Cells:  []interface{}{line.AccountCode, line.AccountName, line.Amount},
+			Indent: lineIndent(line.Level),
+			Bold:   line.IsHeader,
+		})
+	}
+	doc.Rows = append(doc.Rows, models.ReportRow{Cells: []interface{}{"", "Total " + title, section.Total}, Bold: true})
+}
+
+func addIncomeStatementSection(doc *models.ReportDocument, title string, section models.IncomeStatementSection) {
+	doc.Rows = append(doc.Rows, models.ReportRow{Cells: []interface{}{"", title, ""}, Bold: true})
+	for _, line := range section.Lines {
+		doc.Rows = append(doc.Rows, models.ReportRow{
+			Cells:  []interface{}{line.AccountCode, line.AccountName, line.Amount},
+			Indent: lineIndent(line.Level),
+			Bold:   line.IsHeader,
+		})
+	}
+	doc.Rows = append(doc.Rows, models.ReportRow{Cells: []interface{}{"", "Total " + title, section.Total}, Bold: true})
+}
+
+// lineIndent indents account lines below the top level
+func lineIndent(level int) int {
+	if level <= 1 {
+		return 0
+	}
+	return level - 1
+}
+
+func periodLabel(start, end time.Time) string {
+	return start.Format(exportDateFormat) + " to " + end.Format(exportDateFormat)
+}
+
+func generatedFooter(doc *models.ReportDocument) string {
+	return "Generated by " + doc.GeneratedBy + " at " + doc.GeneratedAt.Format("2006-01-02 15:04:05 MST")
+}
+
+// formatAmount formats an amount with thousand separators and negatives in parentheses
+func formatAmount(amount models.Money) string {
+	text := amount.Abs().String()
+	whole, fraction := text[:len(text)-3], text[len(text)-2:]
+
+	var grouped strings.Builder
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			grouped.WriteByte(',')
+		}
+		grouped.WriteRune(digit)
+	}
+
+	result := grouped.String() + "." + fraction
+	if amount < 0 {
+		return "(" + result + ")"
+	}
+	return result
+}
+
+// cellText returns the printed text of a document cell
+func cellText(cell interface{}) string {
+	switch value := cell.(type) {
+	case models.Money:
+		return formatAmount(value)
+	case float64:
+		if amount, err := models.NewMoney(value); err == nil {
+			return formatAmount(amount)
+		}
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case string:
+		return value
+	}
+	return ""
+}
+
+// Writers
+
+func writeReportCSV(doc *models.ReportDocument) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+
+	records := [][]string{{csvText(doc.Title)}}
+	for _, param := range doc.Parameters {
+		records = append(records, []string{csvText(param.Label), csvText(param.Value)})
+	}
+	records = append(records, []string{})
+
+	header := make([]string, len(doc.Columns))
+	for i, column := range doc.Columns {
+		header[i] = csvText(column.Title)
+	}
+	records = append(records, header)
+
+	for _, row := range doc.Rows {
+		record := make([]string, len(row.Cells))
+		for i, cell := range row.Cells {
+			// Plain numbers so the file can be re-imported
+			switch value := cell.(type) {
+			case models.Money:
+				record[i] = value.String()
+			case float64:
+				record[i] = strconv.FormatFloat(value, 'f', 2, 64)
+			default:
+				record[i] = csvText(cellText(cell))
+			}
+		}
+		records = append(records, record)
+	}
+
+	records = append(records, []string{}, []string{csvText(generatedFooter(doc))})
+
+	if err := writer.WriteAll(records); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// csvText prefixes text that a spreadsheet would run as a formula with ', such as a description
+// starting with =. Amounts are written as numbers and never escaped.
+func csvText(text string) string {
+	if text != "" && strings.ContainsRune("=+-@\t\r", rune(text[0])) {
+		return "'" + text
+	}
+	return text
+}
+
+func writeReportXLSX(doc *models.ReportDocument, letterhead []string) ([]byte, error) {
+	file := excelize.NewFile()
+	defer file.Close()
+
+	sheet := "Report"
+	if err := file.SetSheetName("Sheet1", sheet); err != nil {
+		return nil, err
+	}
+
+	amountFormat := "#,##0.00;(#,##0.00)"
+	titleStyle, err := file.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true, Size: 14}})
+	if err != nil {
+		return nil, err
+	}
+	boldStyle, err := file.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return nil, err
+	}
+	headerStyle, err := file.NewStyle(&excelize.Style{
+		Font:   &excelize.Font{Bold: true},
+		Fill:   excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"D9E1F2"}},
+		Border: []excelize.Border{{Type: "bottom", Color: "000000", Style: 1}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	amountStyle, err := file.NewStyle(&excelize.Style{CustomNumFmt: &amountFormat})
+	if err != nil {
+		return nil, err
+	}
+	boldAmountStyle, err := file.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}, CustomNumFmt: &amountFormat})
+	if err != nil {
+		return nil, err
+	}
+	footerStyle, err := file.NewStyle(&excelize.Style{Font: &excelize.Font{Italic: true, Size: 9}})
+	if err != nil {
+		return nil, err
+	}
+
+	// Indent styles are created on first use
+	indentStyles := make(map[[2]int]int)
+	indentStyle := func(indent int, bold bool) (int, error) {
+		key := [2]int{indent, 0}
+		if bold {
+			key[1] = 1
+		}
+		if style, ok := indentStyles[key]; ok {
+			return style, nil
+		}
+		style, err := file.NewStyle(&excelize.Style{
+			Font:      &excelize.Font{Bold: bold},
+			Alignment: &excelize.Alignment{Indent: indent},
+		})
+		indentStyles[key] = style
+		return style, err
+	}
+
+	row := 1
+	setRow := func(values []interface{}) error {
+		cell, err := excelize.CoordinatesToCellName(1, row)
+		if err != nil {
+			return err
+		}
+		return file.SetSheetRow(sheet, cell, &values)
+	}
+	styleRow := func(style int) error {
+		first, _ := excelize.CoordinatesToCellName(1, row)
+		last, _ := excelize.CoordinatesToCellName(len(doc.Columns), row)
+		return file.SetCellStyle(sheet, first, last, style)
+	}
+
+	for i, line := range letterhead {
+		if err := setRow([]interface{}{line}); err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			if err := file.SetCellStyle(sheet, "A1", "A1", boldStyle); err != nil {
+				return nil, err
+			}
+		}
+		row++
+	}
+	if len(letterhead) > 0 {
+		row++
+	}
+
+	if err := setRow([]interface{}{doc.Title}); err != nil {
+		return nil, err
+	}
+	titleCell, _ := excelize.CoordinatesToCellName(1, row)
+	if err := file.SetCellStyle(sheet, titleCell, titleCell, titleStyle); err != nil {
+		return nil, err
+	}
+	row++
+
+	for _, param := range doc.Parameters {
+		if err := setRow([]interface{}{param.Label, param.Value}); err != nil {
+			return nil, err
+		}
+		row++
+	}
+	row++
+
+	header := make([]interface{}, len(doc.Columns))
+	for i, column := range doc.Columns {
+		header[i] = column.Title
+	}
+	if err := setRow(header); err != nil {
+		return nil, err
+	}
+	if err := styleRow(headerStyle); err != nil {
+		return nil, err
+	}
+	row++
+
+	for _, reportRow := range doc.Rows {
+		// Spreadsheet cells hold amounts as numbers
+		values := make([]interface{}, len(reportRow.Cells))
+		for i, cell := range reportRow.Cells {
+			if amount, ok := cell.(models.Money); ok {
+				values[i] = amount.Float64()
+			} else {
+				values[i] = cell
+			}
+		}
+		if err := setRow(values); err != nil {
+			return nil, err
+		}
+		if reportRow.Bold {
+			if err := styleRow(boldStyle); err != nil {
+				return nil, err
+			}
+		}
+		for i, column := range doc.Columns {
+			cell, _ := excelize.CoordinatesToCellName(i+1, row)
+			style := -1
+			switch {
+			case column.Numeric && reportRow.Bold:
+				style = boldAmountStyle
+			case column.Numeric:
+				style = amountStyle
+			case i == 1 && reportRow.Indent > 0:
+				if style, err = indentStyle(reportRow.Indent, reportRow.Bold); err != nil {
+					return nil, err
+				}
+			}
+			if style >= 0 {
+				if err := file.SetCellStyle(sheet, cell, cell, style); err != nil {
+					return nil, err
+				}
+			}
+		}
+		row++
+	}
+
+	row++
+	if err := setRow([]interface{}{generatedFooter(doc)}); err != nil {
+		return nil, err
+	}
+	footerCell, _ := excelize.CoordinatesToCellName(1, row)
+	if err := file.SetCellStyle(sheet, footerCell, footerCell, footerStyle); err != nil {
+		return nil, err
+	}
+
+	for i, column := range doc.Columns {
+		name, err := excelize.ColumnNumberToName(i + 1)
+		if err != nil {
+			return nil, err
+		}
+		if err := file.SetColWidth(sheet, name, name, column.Width*12); err != nil {
+			return nil, err
+		}
+	}
+
+	buf, err := file.WriteToBuffer()
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeReportPDF(doc *models.ReportDocument, letterhead []string) ([]byte, error) {
+	orientation := "P"
+	if len(doc.Columns) > 5 {
+		orientation = "L"
+	}
+
+	pdf := fpdf.New(orientation, "mm", "A4", "")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.SetMargins(15, 15, 15)
+	pdf.SetAutoPageBreak(true, 18)
+	pdf.AliasNbPages("")
+
+	pageWidth, pageHeight := pdf.GetPageSize()
+	left, _, right, _ := pdf.GetMargins()
+
+	footer := generatedFooter(doc)
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-13)
+		pdf.SetFont("Helvetica", "I", 8)
+		pdf.CellFormat(0, 5, tr(footer), "", 0, "L", false, 0, "")
+		pdf.SetX(left)
+		pdf.CellFormat(0, 5, fmt.Sprintf("Page %d of {nb}", pdf.PageNo()), "", 0, "R", false, 0, "")
+	})
+	contentWidth := pageWidth - left - right
+
+	var totalWidth float64
+	for _, column := range doc.Columns {
+		totalWidth += column.Width
+	}
+	widths := make([]float64, len(doc.Columns))
+	for i, column := range doc.Columns {
+		widths[i] = contentWidth * column.Width / totalWidth
+	}
+
+	tableHeader := func() {
+		pdf.SetFont("Helvetica", "B", 9)
+		pdf.SetFillColor(217, 225, 242)
+		for i, column := range doc.Columns {
+			align := "L"
+			if column.Numeric {
+				align = "R"
+			}
+			pdf.CellFormat(widths[i], 7, tr(column.Title), "TB", 0, align, true, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+
+	pdf.AddPage()
+
+	for i, line := range letterhead {
+		if i == 0 {
+			pdf.SetFont("Helvetica", "B", 14)
+			pdf.CellFormat(0, 7, tr(line), "", 1, "C", false, 0, "")
+			continue
+		}
+		pdf.SetFont("Helvetica", "", 9)
+		pdf.CellFormat(0, 4.5, tr(line), "", 1, "C", false, 0, "")
+	}
+	if len(letterhead) > 0 {
+		y := pdf.GetY() + 2
+		pdf.Line(left, y, pageWidth-right, y)
+		pdf.SetY(y + 4)
+	}
+
+	pdf.SetFont("Helvetica", "B", 12)
+	pdf.CellFormat(0, 7, tr(doc.Title), "", 1, "C", false, 0, "")
+	pdf.Ln(2)
+
+	pdf.SetFont("Helvetica", "", 9)
+	for _, param := range doc.Parameters {
+		pdf.CellFormat(30, 5, tr(param.Label), "", 0, "L", false, 0, "")
+		pdf.CellFormat(0, 5, tr(": "+param.Value), "", 1, "L", false, 0, "")
+	}
+	pdf.Ln(3)
+
+	tableHeader()
+	for _, row := range doc.Rows {
+		if pdf.GetY()+6 > pageHeight-18 {
+			pdf.AddPage()
+			tableHeader()
+		}
+
+		style := ""
+		if row.Bold {
+			style = "B"
+		}
+		pdf.SetFont("Helvetica", style, 9)
+
+		for i, column := range doc.Columns {
+			var cell interface{}
+			if i < len(row.Cells) {
+				cell = row.Cells[i]
+			}
+			text := tr(cellText(cell))
+			align := "L"
+			if column.Numeric {
+				align = "R"
+			} else if i == 1 && row.Indent > 0 {
+				text = strings.Repeat("   ", row.Indent) + text
+			}
+			// Cut long text so the row stays on one line
+			for len(text) > 1 && pdf.GetStringWidth(text) > widths[i]-2 {
+				text = text[:len(text)-1]
+			}
+			pdf.CellFormat(widths[i], 6, text, "", 0, align, false, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
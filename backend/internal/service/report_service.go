@@ -2,6 +2,11 @@ package service
 
 import (
 	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,6 +20,19 @@ type ReportService interface {
 	GetBalanceSheet(req *models.BalanceSheetRequest) (*models.BalanceSheetResponse, error)
 	GetIncomeStatement(req *models.IncomeStatementRequest) (*models.IncomeStatementResponse, error)
 	GetGeneralLedger(req *models.GeneralLedgerRequest) (*models.GeneralLedgerResponse, error)
+	GetCashFlow(req *models.CashFlowRequest) (*models.CashFlowResponse, error)
+	GetFinancialPosition(req *models.FinancialPositionRequest) (*models.FinancialPositionResponse, error)
+	GetComprehensiveIncome(req *models.NetAssetsPeriodRequest) (*models.ComprehensiveIncomeResponse, error)
+	GetChangesInNetAssets(req *models.NetAssetsPeriodRequest) (*models.ChangesInNetAssetsResponse, error)
+	GetFundBalance(req *models.FundBalanceRequest) (*models.FundBalanceResponse, error)
+	GetProgramExpenses(req *models.ProgramExpenseRequest) (*models.ProgramExpenseResponse, error)
+	GetInterBranchBalances(req *models.InterBranchBalanceRequest) (*models.InterBranchBalanceResponse, error)
+	GetConsolidatedBalanceSheet(req *models.ConsolidatedBalanceSheetRequest) (*models.ConsolidatedBalanceSheetResponse, error)
+	GetConsolidatedIncomeStatement(req *models.ConsolidatedIncomeStatementRequest) (*models.ConsolidatedIncomeStatementResponse, error)
+	GetComparativeIncomeStatement(req *models.ComparativeIncomeStatementRequest) (*models.ComparativeIncomeStatementResponse, error)
+	GetComparativeBalanceSheet(req *models.ComparativeBalanceSheetRequest) (*models.ComparativeBalanceSheetResponse, error)
+	GetComparativeTrialBalance(req *models.ComparativeTrialBalanceRequest) (*models.ComparativeTrialBalanceResponse, error)
+	RunTemplate(template *models.ReportTemplate, req *models.RunReportTemplateRequest) (*models.ReportTemplateResult, error)
 }
 
 type reportService struct {
@@ -42,15 +60,25 @@ func (s *reportService) GetTrialBalance(req *models.TrialBalanceRequest) (*model
 		return nil, err
 	}
 
+	totals, err := s.sumAccountTotals(reportFilter{
+		PeriodStart: req.AsOfDate,
+		EndDate:     req.AsOfDate,
+		Cumulative:  true,
+		BranchID:    req.BranchID,
+		FundID:      req.FundID,
+		ProgramID:   req.ProgramID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	lines := make([]models.TrialBalanceLine, 0)
-	var totalDebit, totalCredit float64
+	var totalDebit, totalCredit models.Money
 
 	// Calculate balance for each account
-	for _, account := range accounts {
-		balance, err := s.calculateAccountBalance(account.ID, req.AsOfDate, req.BranchID, req.FundID, req.ProgramID)
-		if err != nil {
-			continue
-		}
+	for i := range accounts {
+		account := &accounts[i]
+		balance := accountBalance(account, totals[account.ID])
 
 		// Skip zero balances
 		if balance == 0 {
@@ -64,19 +92,7 @@ func (s *reportService) GetTrialBalance(req *models.TrialBalanceRequest) (*model
 		}
 
 		// Debit or credit based on normal balance
-		if account.GetNormalBalance() == models.NormalBalanceDebit {
-			if balance >= 0 {
-				line.Debit = balance
-			} else {
-				line.Credit = -balance
-			}
-		} else {
-			if balance >= 0 {
-				line.Credit = balance
-			} else {
-				line.Debit = -balance
-			}
-		}
+		line.Debit, line.Credit = trialBalanceSides(account, balance)
 
 		totalDebit += line.Debit
 		totalCredit += line.Credit
@@ -113,23 +129,45 @@ func (s *reportService) GetBalanceSheet(req *models.BalanceSheetRequest) (*model
 		return nil, err
 	}
 
+	revenue, err := s.accountRepo.GetByCategory(models.AccountCategoryRevenue)
+	if err != nil {
+		return nil, err
+	}
+
+	expenses, err := s.accountRepo.GetByCategory(models.AccountCategoryExpense)
+	if err != nil {
+		return nil, err
+	}
+
+	// Balances as of date, with current year movements for net income
+	totals, err := s.sumAccountTotals(reportFilter{
+		PeriodStart: time.Date(req.AsOfDate.Year(), 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:     req.AsOfDate,
+		Cumulative:  true,
+		BranchID:    req.BranchID,
+		FundID:      req.FundID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	response := &models.BalanceSheetResponse{
 		AsOfDate: req.AsOfDate,
 	}
 
 	// Assets section
-	response.Assets = s.buildBalanceSheetSection(assets, req.AsOfDate, req.BranchID, req.FundID)
+	response.Assets = buildBalanceSheetSection(assets, totals, req.IncludeHeaders)
 	response.TotalAssets = response.Assets.Total
 
 	// Liabilities section
-	response.Liabilities = s.buildBalanceSheetSection(liabilities, req.AsOfDate, req.BranchID, req.FundID)
+	response.Liabilities = buildBalanceSheetSection(liabilities, totals, req.IncludeHeaders)
 	response.TotalLiabilities = response.Liabilities.Total
 
 	// Equity section
-	response.Equity = s.buildBalanceSheetSection(equity, req.AsOfDate, req.BranchID, req.FundID)
+	response.Equity = buildBalanceSheetSection(equity, totals, req.IncludeHeaders)
 	
 	// Add net income to equity
-	netIncome, _ := s.calculateNetIncome(time.Date(req.AsOfDate.Year(), 1, 1, 0, 0, 0, 0, time.UTC), req.AsOfDate, req.BranchID, req.FundID)
+	netIncome := calculateNetIncome(revenue, expenses, totals)
 	response.Equity.Lines = append(response.Equity.Lines, models.BalanceSheetLine{
 		AccountCode: "NET_INCOME",
 		AccountName: "Net Income (Current Year)",
@@ -158,17 +196,27 @@ func (s *reportService) GetIncomeStatement(req *models.IncomeStatementRequest) (
 		return nil, err
 	}
 
+	totals, err := s.sumAccountTotals(reportFilter{
+		PeriodStart: req.StartDate,
+		EndDate:     req.EndDate,
+		BranchID:    req.BranchID,
+		FundID:      req.FundID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	response := &models.IncomeStatementResponse{
 		StartDate: req.StartDate,
 		EndDate:   req.EndDate,
 	}
 
 	// Revenue section
-	response.Revenue = s.buildIncomeStatementSection(revenue, req.StartDate, req.EndDate, req.BranchID, req.FundID)
+	response.Revenue = buildIncomeStatementSection(revenue, totals, req.IncludeHeaders)
 	response.TotalRevenue = response.Revenue.Total
 
 	// Expenses section
-	response.Expenses = s.buildIncomeStatementSection(expenses, req.StartDate, req.EndDate, req.BranchID, req.FundID)
+	response.Expenses = buildIncomeStatementSection(expenses, totals, req.IncludeHeaders)
 	response.TotalExpenses = response.Expenses.Total
 
 	// Net income
@@ -184,8 +232,18 @@ func (s *reportService) GetGeneralLedger(req *models.GeneralLedgerRequest) (*mod
 		return nil, errors.New("account not found")
 	}
 
-	// Calculate opening balance
-	openingBalance, _ := s.calculateAccountBalance(req.AccountID, req.StartDate.AddDate(0, 0, -1), req.BranchID, nil, nil)
+	// Calculate opening balance, including opening journals dated within the period
+	openingTotals, err := s.sumAccountTotals(reportFilter{
+		PeriodStart: req.StartDate,
+		EndDate:     req.EndDate,
+		Cumulative:  true,
+		BranchID:    req.BranchID,
+		AccountID:   &req.AccountID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	openingBalance := openingAmount(account, openingTotals[account.ID])
 
 	// Get transactions
 	var journalLines []models.JournalLine
@@ -194,6 +252,7 @@ func (s *reportService) GetGeneralLedger(req *models.GeneralLedgerRequest) (*mod
 		Where("journal_lines.account_id = ?", req.AccountID).
 		Where("journals.journal_date BETWEEN ? AND ?", req.StartDate, req.EndDate).
 		Where("journals.is_posted = ?", true).
+		Where("journals.type <> ?", models.JournalTypeOpening).
 		Order("journals.journal_date ASC, journals.created_at ASC")
 
 	if req.BranchID != nil {
@@ -207,7 +266,7 @@ func (s *reportService) GetGeneralLedger(req *models.GeneralLedgerRequest) (*mod
 	// Build response
 	transactions := make([]models.GeneralLedgerLine, 0, len(journalLines))
 	balance := openingBalance
-	var totalDebit, totalCredit float64
+	var totalDebit, totalCredit models.Money
 
 	for _, line := range journalLines {
 		// Calculate running balance
@@ -242,179 +301,2097 @@ func (s *reportService) GetGeneralLedger(req *models.GeneralLedgerRequest) (*mod
 	}, nil
 }
 
-// Helper functions
-
-func (s *reportService) calculateAccountBalance(
-	accountID uuid.UUID,
-	asOfDate time.Time,
-	branchID *uuid.UUID,
-	fundID *uuid.UUID,
-	programID *uuid.UUID,
-) (float64, error) {
-	var totalDebit, totalCredit float64
-
-	query := s.db.Model(&models.JournalLine{}).
-		Select("COALESCE(SUM(debit), 0) as total_debit, COALESCE(SUM(credit), 0) as total_credit").
-		Joins("JOIN journals ON journals.id = journal_lines.journal_id").
-		Where("journal_lines.account_id = ?", accountID).
-		Where("journals.journal_date <= ?", asOfDate).
-		Where("journals.is_posted = ?", true)
+// GetCashFlow builds the cash flow statement. The indirect method starts from net income and adds
+// the change of every other non-cash account; the direct method classifies the counter-accounts of
+// journals that touch cash. Both use each account's cash flow activity.
+func (s *reportService) GetCashFlow(req *models.CashFlowRequest) (*models.CashFlowResponse, error) {
+	method := req.Method
+	if method == "" {
+		method = models.CashFlowMethodIndirect
+	}
 
-	if branchID != nil {
-		query = query.Where("journals.branch_id = ?", *branchID)
+	accounts, err := s.accountRepo.GetActiveAccounts()
+	if err != nil {
+		return nil, err
 	}
-	if fundID != nil {
-		query = query.Where("journal_lines.fund_id = ?", *fundID)
+
+	// Without cash accounts there is no cash to explain, and bank accounts would show up as
+	// operating items
+	hasCash := false
+	for i := range accounts {
+		if accounts[i].GetCashFlowActivity() == models.CashFlowActivityCash {
+			hasCash = true
+			break
+		}
 	}
-	if programID != nil {
-		query = query.Where("journal_lines.program_id = ?", *programID)
+	if !hasCash {
+		return nil, errors.New("no account is set up as cash, set the cash flow activity of the cash and bank accounts to cash")
 	}
 
-	err := query.Row().Scan(&totalDebit, &totalCredit)
+	totals, err := s.sumAccountTotals(reportFilter{
+		PeriodStart: req.StartDate,
+		EndDate:     req.EndDate,
+		Cumulative:  true,
+		BranchID:    req.BranchID,
+		FundID:      req.FundID,
+	})
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
-	// Get account to determine normal balance
-	account, err := s.accountRepo.GetByID(accountID)
-	if err != nil {
-		return 0, err
+	// Cash effect per non-cash account: credits bring cash in, debits take it out
+	movements := make(map[uuid.UUID]models.Money)
+	if method == models.CashFlowMethodDirect {
+		movements, err = s.sumCashCounterparts(req)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		for id, total := range totals {
+			movements[id] = periodBalance(total)
+		}
 	}
 
-	// Calculate balance based on normal balance
-	if account.GetNormalBalance() == models.NormalBalanceDebit {
-		return totalDebit - totalCredit, nil
+	response := &models.CashFlowResponse{
+		StartDate: req.StartDate,
+		EndDate:   req.EndDate,
+		Method:    method,
+		Operating: models.CashFlowSection{Lines: make([]models.CashFlowLine, 0)},
+		Investing: models.CashFlowSection{Lines: make([]models.CashFlowLine, 0)},
+		Financing: models.CashFlowSection{Lines: make([]models.CashFlowLine, 0)},
+	}
+	sections := map[string]*models.CashFlowSection{
+		models.CashFlowActivityOperating: &response.Operating,
+		models.CashFlowActivityInvesting: &response.Investing,
+		models.CashFlowActivityFinancing: &response.Financing,
 	}
-	return totalCredit - totalDebit, nil
-}
-
-func (s *reportService) buildBalanceSheetSection(
-	accounts []models.Account,
-	asOfDate time.Time,
-	branchID *uuid.UUID,
-	fundID *uuid.UUID,
-) models.BalanceSheetSection {
-	lines := make([]models.BalanceSheetLine, 0)
-	var total float64
 
-	for _, account := range accounts {
+	var netIncome models.Money
+	for i := range accounts {
+		account := &accounts[i]
 		if !account.IsDetail {
 			continue
 		}
 
-		balance, err := s.calculateAccountBalance(account.ID, asOfDate, branchID, fundID, nil)
-		if err != nil || balance == 0 {
+		total := totals[account.ID]
+		activity := account.GetCashFlowActivity()
+		if activity == models.CashFlowActivityCash {
+			response.ClosingCash += total.Debit - total.Credit
+			response.OpeningCash += (total.Debit - total.PeriodDebit) - (total.Credit - total.PeriodCredit)
 			continue
 		}
 
-		lines = append(lines, models.BalanceSheetLine{
+		amount := movements[account.ID]
+
+		// Income, expenses and the retained surplus they close into make up net income
+		if method == models.CashFlowMethodIndirect && isNetIncomeAccount(account) {
+			netIncome += amount
+			continue
+		}
+
+		if amount == 0 {
+			continue
+		}
+
+		section, exists := sections[activity]
+		if !exists {
+			section = &response.Operating
+		}
+		section.Lines = append(section.Lines, models.CashFlowLine{
 			AccountCode: account.Code,
 			AccountName: account.Name,
-			Amount:      balance,
-			Level:       account.Level,
-			IsHeader:    false,
+			Amount:      amount,
 		})
+		section.Total += amount
+	}
 
-		total += balance
+	if method == models.CashFlowMethodIndirect {
+		response.Operating.Lines = append([]models.CashFlowLine{{
+			AccountCode: "NET_INCOME",
+			AccountName: "Net Income",
+			Amount:      netIncome,
+		}}, response.Operating.Lines...)
+		response.Operating.Total += netIncome
 	}
 
-	return models.BalanceSheetSection{
-		Lines: lines,
-		Total: total,
+	response.NetChange = response.Operating.Total + response.Investing.Total + response.Financing.Total
+	response.IsReconciled = response.OpeningCash+response.NetChange == response.ClosingCash
+
+	return response, nil
+}
+
+// GetFinancialPosition builds the ISAK 35 statement of financial position, splitting net assets by
+// the restriction of the fund each line is tagged with
+func (s *reportService) GetFinancialPosition(req *models.FinancialPositionRequest) (*models.FinancialPositionResponse, error) {
+	accounts, err := s.accountRepo.GetActiveAccounts()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.sumFundTotals(req.AsOfDate, req.AsOfDate, req.BranchID)
+	if err != nil {
+		return nil, err
+	}
+
+	accountsByID := make(map[uuid.UUID]*models.Account, len(accounts))
+	var assets, liabilities []models.Account
+	for i := range accounts {
+		accountsByID[accounts[i].ID] = &accounts[i]
+		switch accounts[i].Category {
+		case models.AccountCategoryAsset:
+			assets = append(assets, accounts[i])
+		case models.AccountCategoryLiability:
+			liabilities = append(liabilities, accounts[i])
+		}
+	}
+
+	totals := make(map[uuid.UUID]accountTotals)
+	response := &models.FinancialPositionResponse{
+		AsOfDate: req.AsOfDate,
+	}
+
+	for _, row := range rows {
+		total := totals[row.AccountID]
+		total.Debit += row.Debit
+		total.Credit += row.Credit
+		totals[row.AccountID] = total
+
+		if account, exists := accountsByID[row.AccountID]; exists && isNetAssetAccount(account) {
+			response.NetAssets.Add(row.restricted(), row.Credit-row.Debit)
+		}
 	}
+
+	response.Assets = buildBalanceSheetSection(assets, totals, false)
+	response.TotalAssets = response.Assets.Total
+	response.Liabilities = buildBalanceSheetSection(liabilities, totals, false)
+	response.TotalLiabilities = response.Liabilities.Total
+	response.IsBalanced = response.TotalAssets == response.TotalLiabilities+response.NetAssets.Total
+
+	return response, nil
 }
 
-func (s *reportService) buildIncomeStatementSection(
-	accounts []models.Account,
-	startDate, endDate time.Time,
-	branchID *uuid.UUID,
-	fundID *uuid.UUID,
-) models.IncomeStatementSection {
-	lines := make([]models.IncomeStatementLine, 0)
-	var total float64
+// GetComprehensiveIncome builds the ISAK 35 statement of comprehensive income by net asset class.
+// Expenses are presented without restrictions; those charged to restricted funds are released
+// from restriction through reclassification lines.
+func (s *reportService) GetComprehensiveIncome(req *models.NetAssetsPeriodRequest) (*models.ComprehensiveIncomeResponse, error) {
+	accounts, err := s.accountRepo.GetActiveAccounts()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.sumFundTotals(req.StartDate, req.EndDate, req.BranchID)
+	if err != nil {
+		return nil, err
+	}
+
+	revenue := make(map[uuid.UUID]*models.RestrictionAmounts)
+	expenses := make(map[uuid.UUID]*models.RestrictionAmounts)
+	releases := make(map[uuid.UUID]*models.RestrictionLine)
+	categories := make(map[uuid.UUID]string, len(accounts))
+	for i := range accounts {
+		categories[accounts[i].ID] = accounts[i].Category
+	}
+
+	for _, row := range rows {
+		switch categories[row.AccountID] {
+		case models.AccountCategoryRevenue:
+			if revenue[row.AccountID] == nil {
+				revenue[row.AccountID] = &models.RestrictionAmounts{}
+			}
+			revenue[row.AccountID].Add(row.restricted(), row.PeriodCredit-row.PeriodDebit)
+		case models.AccountCategoryExpense:
+			amount := row.PeriodDebit - row.PeriodCredit
+			if expenses[row.AccountID] == nil {
+				expenses[row.AccountID] = &models.RestrictionAmounts{}
+			}
+			expenses[row.AccountID].Add(false, amount)
+
+			if row.restricted() && amount != 0 {
+				release, exists := releases[*row.FundID]
+				if !exists {
+					release = &models.RestrictionLine{
+						AccountCode:        row.FundCode,
+						AccountName:        "Net assets released from restriction: " + row.FundName,
+						IsReclassification: true,
+					}
+					releases[*row.FundID] = release
+				}
+				release.WithoutRestrictions += amount
+				release.WithRestrictions -= amount
+			}
+		}
+	}
+
+	response := &models.ComprehensiveIncomeResponse{
+		StartDate: req.StartDate,
+		EndDate:   req.EndDate,
+		Revenue:   make([]models.RestrictionLine, 0),
+		Releases:  make([]models.RestrictionLine, 0),
+		Expenses:  make([]models.RestrictionLine, 0),
+	}
 
 	for _, account := range accounts {
-		if !account.IsDetail {
-			continue
+		if amounts, exists := revenue[account.ID]; exists && amounts.Total != 0 {
+			response.Revenue = append(response.Revenue, restrictionLine(&account, *amounts))
+			addRestrictionAmounts(&response.TotalRevenue, *amounts)
 		}
+		if amounts, exists := expenses[account.ID]; exists && amounts.Total != 0 {
+			response.Expenses = append(response.Expenses, restrictionLine(&account, *amounts))
+			addRestrictionAmounts(&response.TotalExpenses, *amounts)
+		}
+	}
 
-		// Calculate period balance
-		amount, err := s.calculatePeriodBalance(account.ID, startDate, endDate, branchID, fundID)
-		if err != nil || amount == 0 {
+	for _, release := range releases {
+		if release.WithoutRestrictions == 0 {
 			continue
 		}
+		response.Releases = append(response.Releases, *release)
+		addRestrictionAmounts(&response.TotalReleases, release.RestrictionAmounts)
+	}
+	sort.Slice(response.Releases, func(i, j int) bool {
+		return response.Releases[i].AccountCode < response.Releases[j].AccountCode
+	})
 
-		lines = append(lines, models.IncomeStatementLine{
-			AccountCode: account.Code,
-			AccountName: account.Name,
-			Amount:      amount,
-			Level:       account.Level,
-			IsHeader:    false,
-		})
+	response.Surplus = models.RestrictionAmounts{
+		WithoutRestrictions: response.TotalRevenue.WithoutRestrictions + response.TotalReleases.WithoutRestrictions - response.TotalExpenses.WithoutRestrictions,
+		WithRestrictions:    response.TotalRevenue.WithRestrictions + response.TotalReleases.WithRestrictions,
+		Total:               response.TotalRevenue.Total - response.TotalExpenses.Total,
+	}
 
-		total += amount
+	return response, nil
+}
+
+// GetChangesInNetAssets builds the ISAK 35 statement of changes in net assets per net asset class
+func (s *reportService) GetChangesInNetAssets(req *models.NetAssetsPeriodRequest) (*models.ChangesInNetAssetsResponse, error) {
+	accounts, err := s.accountRepo.GetActiveAccounts()
+	if err != nil {
+		return nil, err
 	}
 
-	return models.IncomeStatementSection{
-		Lines: lines,
-		Total: total,
+	rows, err := s.sumFundTotals(req.StartDate, req.EndDate, req.BranchID)
+	if err != nil {
+		return nil, err
+	}
+
+	categories := make(map[uuid.UUID]string, len(accounts))
+	for i := range accounts {
+		categories[accounts[i].ID] = accounts[i].Category
 	}
+
+	response := &models.ChangesInNetAssetsResponse{
+		StartDate: req.StartDate,
+		EndDate:   req.EndDate,
+	}
+
+	for _, row := range rows {
+		movement := row.PeriodCredit - row.PeriodDebit
+
+		switch categories[row.AccountID] {
+		case models.AccountCategoryRevenue:
+			response.Surplus.Add(row.restricted(), movement)
+		case models.AccountCategoryExpense:
+			response.Surplus.Add(row.restricted(), movement)
+			if row.restricted() {
+				// Restricted resources spent are released to net assets without restrictions
+				response.Releases.Add(false, -movement)
+				response.Releases.Add(true, movement)
+			}
+		case models.AccountCategoryEquity:
+			response.OtherChanges.Add(row.restricted(), movement)
+		default:
+			continue
+		}
+
+		response.Closing.Add(row.restricted(), row.Credit-row.Debit)
+	}
+
+	// Closing journals only move amounts within a net asset class, so the opening balance
+	// is the closing balance less this period's changes
+	response.Opening = models.RestrictionAmounts{
+		WithoutRestrictions: response.Closing.WithoutRestrictions - response.Surplus.WithoutRestrictions - response.Releases.WithoutRestrictions - response.OtherChanges.WithoutRestrictions,
+		WithRestrictions:    response.Closing.WithRestrictions - response.Surplus.WithRestrictions - response.Releases.WithRestrictions - response.OtherChanges.WithRestrictions,
+		Total:               response.Closing.Total - response.Surplus.Total - response.Releases.Total - response.OtherChanges.Total,
+	}
+
+	return response, nil
 }
 
-func (s *reportService) calculatePeriodBalance(
-	accountID uuid.UUID,
-	startDate, endDate time.Time,
-	branchID *uuid.UUID,
-	fundID *uuid.UUID,
-) (float64, error) {
-	var totalDebit, totalCredit float64
+// GetFundBalance reports the net asset activity of each fund over a date range
+func (s *reportService) GetFundBalance(req *models.FundBalanceRequest) (*models.FundBalanceResponse, error) {
+	accounts, err := s.accountRepo.GetActiveAccounts()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.sumFundTotals(req.StartDate, req.EndDate, req.BranchID)
+	if err != nil {
+		return nil, err
+	}
+
+	categories := make(map[uuid.UUID]string, len(accounts))
+	for i := range accounts {
+		categories[accounts[i].ID] = accounts[i].Category
+	}
+
+	var lines []*models.FundBalanceLine
+	lineIndex := make(map[uuid.UUID]*models.FundBalanceLine)
+
+	for _, row := range rows {
+		category := categories[row.AccountID]
+		if category != models.AccountCategoryRevenue && category != models.AccountCategoryExpense && category != models.AccountCategoryEquity {
+			continue
+		}
+
+		fundKey := uuid.Nil
+		if row.FundID != nil {
+			fundKey = *row.FundID
+		}
+		line, exists := lineIndex[fundKey]
+		if !exists {
+			line = &models.FundBalanceLine{
+				FundID:   row.FundID,
+				FundCode: row.FundCode,
+				FundName: row.FundName,
+				FundType: row.FundType,
+			}
+			if row.FundID == nil {
+				line.FundName = "Without fund"
+				line.FundType = models.FundTypeUnrestricted
+			}
+			lineIndex[fundKey] = line
+			lines = append(lines, line)
+		}
+
+		movement := row.PeriodCredit - row.PeriodDebit
+		switch category {
+		case models.AccountCategoryRevenue:
+			line.Revenue += movement
+		case models.AccountCategoryExpense:
+			line.Expenses -= movement
+		default:
+			line.OtherChanges += movement
+		}
+		line.ClosingBalance += row.Credit - row.Debit
+	}
+
+	sort.Slice(lines, func(i, j int) bool {
+		return lines[i].FundCode < lines[j].FundCode
+	})
+
+	response := &models.FundBalanceResponse{
+		StartDate: req.StartDate,
+		EndDate:   req.EndDate,
+		Lines:     make([]models.FundBalanceLine, 0, len(lines)),
+	}
+
+	for _, line := range lines {
+		// Closing journals stay within a fund, so the opening balance is the closing balance less this period's activity
+		line.OpeningBalance = line.ClosingBalance - line.Revenue + line.Expenses - line.OtherChanges
+
+		response.Lines = append(response.Lines, *line)
+		response.Total.OpeningBalance += line.OpeningBalance
+		response.Total.Revenue += line.Revenue
+		response.Total.Expenses += line.Expenses
+		response.Total.OtherChanges += line.OtherChanges
+		response.Total.ClosingBalance += line.ClosingBalance
+	}
+
+	return response, nil
+}
 
+// GetProgramExpenses reports expenses per program and account over a date range
+func (s *reportService) GetProgramExpenses(req *models.ProgramExpenseRequest) (*models.ProgramExpenseResponse, error) {
 	query := s.db.Model(&models.JournalLine{}).
-		Select("COALESCE(SUM(debit), 0) as total_debit, COALESCE(SUM(credit), 0) as total_credit").
+		Select(`journal_lines.program_id,
+			COALESCE(programs.code, '') as program_code,
+			COALESCE(programs.name, '') as program_name,
+			accounts.code as account_code,
+			accounts.name as account_name,
+			COALESCE(SUM(journal_lines.debit - journal_lines.credit), 0) as amount`).
 		Joins("JOIN journals ON journals.id = journal_lines.journal_id").
-		Where("journal_lines.account_id = ?", accountID).
-		Where("journals.journal_date BETWEEN ? AND ?", startDate, endDate).
-		Where("journals.is_posted = ?", true)
+		Joins("JOIN accounts ON accounts.id = journal_lines.account_id").
+		Joins("LEFT JOIN programs ON programs.id = journal_lines.program_id").
+		Where("journals.journal_date BETWEEN ? AND ?", req.StartDate, req.EndDate).
+		Where("journals.is_posted = ?", true).
+		Where("accounts.category = ?", models.AccountCategoryExpense).
+		Where("NOT " + closingJournalCondition).
+		Group("journal_lines.program_id, programs.code, programs.name, accounts.code, accounts.name").
+		Order("programs.code ASC, accounts.code ASC")
 
-	if branchID != nil {
-		query = query.Where("journals.branch_id = ?", *branchID)
+	if req.BranchID != nil {
+		query = query.Where("journals.branch_id = ?", *req.BranchID)
 	}
-	if fundID != nil {
-		query = query.Where("journal_lines.fund_id = ?", *fundID)
+	if req.FundID != nil {
+		query = query.Where("journal_lines.fund_id = ?", *req.FundID)
+	}
+	if req.ProgramID != nil {
+		query = query.Where("journal_lines.program_id = ?", *req.ProgramID)
+	}
+
+	var rows []struct {
+		ProgramID   *uuid.UUID
+		ProgramCode string
+		ProgramName string
+		AccountCode string
+		AccountName string
+		Amount      models.Money
+	}
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	response := &models.ProgramExpenseResponse{
+		StartDate: req.StartDate,
+		EndDate:   req.EndDate,
+		Programs:  make([]models.ProgramExpenseSection, 0),
+	}
+
+	// Rows are ordered by program, so each program's accounts are consecutive
+	var section *models.ProgramExpenseSection
+	for _, row := range rows {
+		if row.Amount == 0 {
+			continue
+		}
+
+		if section == nil || !sameProgram(section.ProgramID, row.ProgramID) {
+			name := row.ProgramName
+			if row.ProgramID == nil {
+				name = "Without program"
+			}
+			response.Programs = append(response.Programs, models.ProgramExpenseSection{
+				ProgramID:   row.ProgramID,
+				ProgramCode: row.ProgramCode,
+				ProgramName: name,
+				Lines:       make([]models.ProgramExpenseLine, 0),
+			})
+			section = &response.Programs[len(response.Programs)-1]
+		}
+
+		section.Lines = append(section.Lines, models.ProgramExpenseLine{
+			AccountCode: row.AccountCode,
+			AccountName: row.AccountName,
+			Amount:      row.Amount,
+		})
+		section.Total += row.Amount
+		response.TotalExpenses += row.Amount
+	}
+
+	return response, nil
+}
+
+// Helper functions
+
+// reportFilter selects the posted journal lines summed by sumAccountTotals
+type reportFilter struct {
+	PeriodStart time.Time // Period totals cover PeriodStart..EndDate
+	EndDate     time.Time
+	Cumulative  bool // Also sum journals before PeriodStart into the cumulative totals
+	BranchID    *uuid.UUID
+	FundID      *uuid.UUID
+	ProgramID   *uuid.UUID
+	AccountID   *uuid.UUID
+	BranchIDs   []uuid.UUID // Several branches, for consolidation
+	// Only lines on these accounts booked against another branch in BranchIDs, for eliminations
+	IntraGroupAccounts []uuid.UUID
+}
+
+// accountTotals holds posted debits and credits of one account
+type accountTotals struct {
+	AccountID    uuid.UUID
+	Debit        models.Money // Cumulative up to EndDate
+	Credit       models.Money
+	PeriodDebit  models.Money // PeriodStart..EndDate only
+	PeriodCredit models.Money
+}
+
+// GetComparativeIncomeStatement shows the income statement of the requested period next to the
+// periods it is compared with, optionally against budget
+func (s *reportService) GetComparativeIncomeStatement(req *models.ComparativeIncomeStatementRequest) (*models.ComparativeIncomeStatementResponse, error) {
+	if req.EndDate.Before(req.StartDate) {
+		return nil, errors.New("end date cannot be before start date")
+	}
+
+	revenue, err := s.accountRepo.GetByCategory(models.AccountCategoryRevenue)
+	if err != nil {
+		return nil, err
 	}
 
-	err := query.Row().Scan(&totalDebit, &totalCredit)
+	expenses, err := s.accountRepo.GetByCategory(models.AccountCategoryExpense)
 	if err != nil {
-		return 0, err
+		return nil, err
+	}
+
+	periods := comparisonPeriods(req.Mode, req.StartDate, req.EndDate)
+	columns := make([]map[uuid.UUID]accountTotals, len(periods))
+	for i, period := range periods {
+		columns[i], err = s.sumAccountTotals(reportFilter{
+			PeriodStart: period.StartDate,
+			EndDate:     period.EndDate,
+			BranchID:    req.BranchID,
+			FundID:      req.FundID,
+		})
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	return totalCredit - totalDebit, nil
+	var budgets []map[uuid.UUID]models.Money
+	if req.IncludeBudget {
+		if budgets, err = s.sumBudgets(periods, req.BranchID, req.FundID); err != nil {
+			return nil, err
+		}
+	}
+
+	response := &models.ComparativeIncomeStatementResponse{
+		Mode:    req.Mode,
+		Periods: periods,
+	}
+
+	response.Revenue = buildComparativeSection(revenue, columns, budgets, periodAmount, req.IncludeHeaders)
+	response.TotalRevenue = response.Revenue.Total
+
+	response.Expenses = buildComparativeSection(expenses, columns, budgets, periodAmount, req.IncludeHeaders)
+	response.TotalExpenses = response.Expenses.Total
+
+	response.NetIncome = newComparativeAmounts(len(periods), req.IncludeBudget)
+	addComparativeAmounts(&response.NetIncome, response.TotalRevenue, 1)
+	addComparativeAmounts(&response.NetIncome, response.TotalExpenses, -1)
+	finishComparativeAmounts(&response.NetIncome)
+
+	return response, nil
+}
+
+// GetComparativeBalanceSheet shows the balance sheet as of the requested date next to the dates
+// it is compared with
+func (s *reportService) GetComparativeBalanceSheet(req *models.ComparativeBalanceSheetRequest) (*models.ComparativeBalanceSheetResponse, error) {
+	assets, err := s.accountRepo.GetByCategory(models.AccountCategoryAsset)
+	if err != nil {
+		return nil, err
+	}
+
+	liabilities, err := s.accountRepo.GetByCategory(models.AccountCategoryLiability)
+	if err != nil {
+		return nil, err
+	}
+
+	equity, err := s.accountRepo.GetByCategory(models.AccountCategoryEquity)
+	if err != nil {
+		return nil, err
+	}
+
+	revenue, err := s.accountRepo.GetByCategory(models.AccountCategoryRevenue)
+	if err != nil {
+		return nil, err
+	}
+
+	expenses, err := s.accountRepo.GetByCategory(models.AccountCategoryExpense)
+	if err != nil {
+		return nil, err
+	}
+
+	periods := asOfPeriods(req.Mode, req.AsOfDate)
+	columns := make([]map[uuid.UUID]accountTotals, len(periods))
+	for i, period := range periods {
+		columns[i], err = s.sumAccountTotals(reportFilter{
+			PeriodStart: time.Date(period.EndDate.Year(), 1, 1, 0, 0, 0, 0, time.UTC),
+			EndDate:     period.EndDate,
+			Cumulative:  true,
+			BranchID:    req.BranchID,
+			FundID:      req.FundID,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	response := &models.ComparativeBalanceSheetResponse{
+		Mode:    req.Mode,
+		Periods: periods,
+	}
+
+	response.Assets = buildComparativeSection(assets, columns, nil, accountBalance, req.IncludeHeaders)
+	response.TotalAssets = response.Assets.Total
+
+	response.Liabilities = buildComparativeSection(liabilities, columns, nil, accountBalance, req.IncludeHeaders)
+	response.TotalLiabilities = response.Liabilities.Total
+
+	response.Equity = buildComparativeSection(equity, columns, nil, accountBalance, req.IncludeHeaders)
+
+	// Add net income to equity
+	netIncome := newComparativeAmounts(len(periods), false)
+	for i := range columns {
+		netIncome.Amounts[i] = calculateNetIncome(revenue, expenses, columns[i])
+	}
+	finishComparativeAmounts(&netIncome)
+
+	response.Equity.Lines = append(response.Equity.Lines, models.ComparativeLine{
+		AccountCode:        "NET_INCOME",
+		AccountName:        "Net Income (Current Year)",
+		ComparativeAmounts: netIncome,
+	})
+	addComparativeAmounts(&response.Equity.Total, netIncome, 1)
+	finishComparativeAmounts(&response.Equity.Total)
+	response.TotalEquity = response.Equity.Total
+
+	return response, nil
+}
+
+// GetComparativeTrialBalance shows the trial balance as of the requested date next to the dates
+// it is compared with
+func (s *reportService) GetComparativeTrialBalance(req *models.ComparativeTrialBalanceRequest) (*models.ComparativeTrialBalanceResponse, error) {
+	accounts, err := s.accountRepo.GetDetailAccounts()
+	if err != nil {
+		return nil, err
+	}
+
+	periods := asOfPeriods(req.Mode, req.AsOfDate)
+	columns := make([]map[uuid.UUID]accountTotals, len(periods))
+	for i, period := range periods {
+		columns[i], err = s.sumAccountTotals(reportFilter{
+			PeriodStart: period.EndDate,
+			EndDate:     period.EndDate,
+			Cumulative:  true,
+			BranchID:    req.BranchID,
+			FundID:      req.FundID,
+			ProgramID:   req.ProgramID,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	response := &models.ComparativeTrialBalanceResponse{
+		Mode:      req.Mode,
+		Periods:   periods,
+		Lines:     make([]models.ComparativeTrialBalanceLine, 0),
+		Summaries: make([]models.TrialBalanceSummary, len(periods)),
+	}
+
+	for i := range accounts {
+		account := &accounts[i]
+		line := models.ComparativeTrialBalanceLine{
+			ComparativeLine: models.ComparativeLine{
+				AccountCode:        account.Code,
+				AccountName:        account.Name,
+				Category:           account.Category,
+				Level:              account.Level,
+				ComparativeAmounts: newComparativeAmounts(len(periods), false),
+			},
+			Debits:  make([]models.Money, len(periods)),
+			Credits: make([]models.Money, len(periods)),
+		}
+
+		isZero := true
+		for c := range columns {
+			balance := accountBalance(account, columns[c][account.ID])
+			line.Amounts[c] = balance
+			line.Debits[c], line.Credits[c] = trialBalanceSides(account, balance)
+			if balance != 0 {
+				isZero = false
+			}
+		}
+
+		// Skip accounts without a balance in any period
+		if isZero {
+			continue
+		}
+
+		finishComparativeAmounts(&line.ComparativeAmounts)
+		response.Lines = append(response.Lines, line)
+
+		for c := range columns {
+			response.Summaries[c].TotalDebit += line.Debits[c]
+			response.Summaries[c].TotalCredit += line.Credits[c]
+		}
+	}
+
+	for c := range response.Summaries {
+		summary := &response.Summaries[c]
+		summary.Difference = summary.TotalDebit - summary.TotalCredit
+		summary.IsBalanced = summary.Difference == 0
+	}
+
+	return response, nil
+}
+
+// sumBudgets sums active budgets per account for each report period by their YYYY-MM period.
+// Budgets are monthly, so a period that covers part of a month gets the whole month's budget.
+func (s *reportService) sumBudgets(periods []models.ReportPeriod, branchID, fundID *uuid.UUID) ([]map[uuid.UUID]models.Money, error) {
+	type budgetTotal struct {
+		AccountID uuid.UUID
+		Period    string
+		Amount    models.Money
+	}
+
+	query := s.db.Model(&models.Budget{}).
+		Select("account_id, period, COALESCE(SUM(amount), 0) as amount").
+		Where("is_active = ?", true).
+		Where("period BETWEEN ? AND ?", periods[0].StartDate.Format("2006-01"), periods[len(periods)-1].EndDate.Format("2006-01")).
+		Group("account_id, period")
+
+	if branchID != nil {
+		query = query.Where("branch_id = ?", *branchID)
+	}
+	if fundID != nil {
+		query = query.Where("fund_id = ?", *fundID)
+	}
+
+	var rows []budgetTotal
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	budgets := make([]map[uuid.UUID]models.Money, len(periods))
+	for c := range periods {
+		budgets[c] = make(map[uuid.UUID]models.Money)
+	}
+
+	for _, row := range rows {
+		for c, period := range periods {
+			if row.Period >= period.StartDate.Format("2006-01") && row.Period <= period.EndDate.Format("2006-01") {
+				budgets[c][row.AccountID] += row.Amount
+			}
+		}
+	}
+
+	return budgets, nil
+}
+
+// buildComparativeSection builds a section with a column per report period; amount gives the
+// signed amount of an account from its totals. Budgets are optional.
+func buildComparativeSection(
+	accounts []models.Account,
+	columns []map[uuid.UUID]accountTotals,
+	budgets []map[uuid.UUID]models.Money,
+	amount func(account *models.Account, totals accountTotals) models.Money,
+	includeHeaders bool,
+) models.ComparativeSection {
+	withBudget := budgets != nil
+	amounts := make([]map[uuid.UUID]models.Money, len(columns))
+	rollups := make([]map[uuid.UUID]models.Money, len(columns))
+	budgetRollups := make([]map[uuid.UUID]models.Money, len(budgets))
+	for c := range columns {
+		amounts[c] = make(map[uuid.UUID]models.Money, len(accounts))
+		for i := range accounts {
+			amounts[c][accounts[i].ID] = amount(&accounts[i], columns[c][accounts[i].ID])
+		}
+		rollups[c] = rollupAmounts(accounts, amounts[c])
+		if withBudget {
+			budgetRollups[c] = rollupAmounts(accounts, budgets[c])
+		}
+	}
+
+	section := models.ComparativeSection{
+		Lines: make([]models.ComparativeLine, 0),
+		Total: newComparativeAmounts(len(columns), withBudget),
+	}
+
+	for _, account := range accounts {
+		values, budgetValues := amounts, budgets
+		if !account.IsDetail {
+			if !includeHeaders {
+				continue
+			}
+			values, budgetValues = rollups, budgetRollups
+		}
+
+		line := models.ComparativeLine{
+			AccountCode:        account.Code,
+			AccountName:        account.Name,
+			Level:              account.Level,
+			IsHeader:           !account.IsDetail,
+			ComparativeAmounts: newComparativeAmounts(len(columns), withBudget),
+		}
+
+		isZero := true
+		for c := range columns {
+			line.Amounts[c] = values[c][account.ID]
+			if withBudget {
+				line.Budgets[c] = budgetValues[c][account.ID]
+			}
+			if line.Amounts[c] != 0 || (withBudget && line.Budgets[c] != 0) {
+				isZero = false
+			}
+		}
+		if isZero {
+			continue
+		}
+
+		finishComparativeAmounts(&line.ComparativeAmounts)
+		section.Lines = append(section.Lines, line)
+
+		if account.IsDetail {
+			addComparativeAmounts(&section.Total, line.ComparativeAmounts, 1)
+		}
+	}
+
+	finishComparativeAmounts(&section.Total)
+	return section
+}
+
+// newComparativeAmounts allocates the columns of a comparative line
+func newComparativeAmounts(periods int, withBudget bool) models.ComparativeAmounts {
+	amounts := models.ComparativeAmounts{Amounts: make([]models.Money, periods)}
+	if withBudget {
+		amounts.Budgets = make([]models.Money, periods)
+	}
+	return amounts
+}
+
+// addComparativeAmounts adds amounts and budgets multiplied by sign to total
+func addComparativeAmounts(total *models.ComparativeAmounts, amounts models.ComparativeAmounts, sign models.Money) {
+	for i := range total.Amounts {
+		total.Amounts[i] += sign * amounts.Amounts[i]
+	}
+	for i := range total.Budgets {
+		total.Budgets[i] += sign * amounts.Budgets[i]
+	}
+}
+
+// finishComparativeAmounts sets the variances of each period against the period before it and
+// against its budget
+func finishComparativeAmounts(amounts *models.ComparativeAmounts) {
+	amounts.Variances = make([]models.Variance, 0, len(amounts.Amounts))
+	for i := 1; i < len(amounts.Amounts); i++ {
+		amounts.Variances = append(amounts.Variances, newVariance(amounts.Amounts[i], amounts.Amounts[i-1]))
+	}
+
+	if amounts.Budgets != nil {
+		amounts.BudgetVariances = make([]models.Variance, len(amounts.Budgets))
+		for i := range amounts.Budgets {
+			amounts.BudgetVariances[i] = newVariance(amounts.Amounts[i], amounts.Budgets[i])
+		}
+	}
+}
+
+// newVariance compares amount with base; the percentage is relative to the size of base
+func newVariance(amount, base models.Money) models.Variance {
+	variance := models.Variance{Amount: amount - base}
+	if base != 0 {
+		percent := math.Round(variance.Amount.Ratio(base.Abs())*10000) / 100
+		variance.Percent = &percent
+	}
+	return variance
+}
+
+// comparisonPeriods returns the report periods of a comparison mode, oldest first and ending
+// with the requested period. Periods of whole calendar months move by months, so month ends
+// stay month ends; other periods move by their length in days.
+func comparisonPeriods(mode string, start, end time.Time) []models.ReportPeriod {
+	switch mode {
+	case models.ComparisonMonthly:
+		periods := make([]models.ReportPeriod, 0, 12)
+		first := beginningOfMonth(end).AddDate(0, -11, 0)
+		for i := 0; i < 12; i++ {
+			monthStart := first.AddDate(0, i, 0)
+			monthEnd := endOfMonth(monthStart)
+			if i == 11 {
+				monthEnd = end
+			}
+			periods = append(periods, newReportPeriod(monthStart, monthEnd))
+		}
+		return periods
+
+	case models.ComparisonPriorYear:
+		priorStart, priorEnd := shiftPeriod(start, end, -12)
+		return []models.ReportPeriod{newReportPeriod(priorStart, priorEnd), newReportPeriod(start, end)}
+
+	default:
+		var priorStart, priorEnd time.Time
+		if months := wholeMonths(start, end); months > 0 {
+			priorStart, priorEnd = shiftPeriod(start, end, -months)
+		} else {
+			days := int(end.Sub(start).Hours()/24) + 1
+			priorEnd = start.AddDate(0, 0, -1)
+			priorStart = priorEnd.AddDate(0, 0, 1-days)
+		}
+		return []models.ReportPeriod{newReportPeriod(priorStart, priorEnd), newReportPeriod(start, end)}
+	}
+}
+
+// asOfPeriods returns the report dates of a comparison mode for point-in-time reports, where
+// the prior period of an as-of date is the same day of the month before
+func asOfPeriods(mode string, asOfDate time.Time) []models.ReportPeriod {
+	start := beginningOfMonth(asOfDate)
+	if mode == models.ComparisonPriorPeriod && wholeMonths(start, asOfDate) == 0 {
+		return []models.ReportPeriod{asOfPeriod(addMonths(asOfDate, -1)), asOfPeriod(asOfDate)}
+	}
+
+	periods := comparisonPeriods(mode, start, asOfDate)
+	for i := range periods {
+		periods[i] = asOfPeriod(periods[i].EndDate)
+	}
+	return periods
+}
+
+// asOfPeriod returns the report period of a point-in-time report
+func asOfPeriod(date time.Time) models.ReportPeriod {
+	return models.ReportPeriod{
+		Label:     date.Format("2006-01-02"),
+		StartDate: time.Date(date.Year(), 1, 1, 0, 0, 0, 0, date.Location()),
+		EndDate:   date,
+	}
+}
+
+// newReportPeriod labels a period by month when it covers whole months
+func newReportPeriod(start, end time.Time) models.ReportPeriod {
+	label := start.Format("2006-01-02") + " - " + end.Format("2006-01-02")
+	switch months := wholeMonths(start, end); {
+	case months == 1:
+		label = start.Format("2006-01")
+	case months > 1:
+		label = start.Format("2006-01") + " - " + end.Format("2006-01")
+	}
+	return models.ReportPeriod{Label: label, StartDate: start, EndDate: end}
+}
+
+// wholeMonths returns the number of calendar months a period covers, or zero when it does not
+// start on the first and end on the last day of a month
+func wholeMonths(start, end time.Time) int {
+	if start.Day() != 1 || end.Day() != endOfMonth(end).Day() || end.Before(start) {
+		return 0
+	}
+	return (end.Year()-start.Year())*12 + int(end.Month()-start.Month()) + 1
+}
+
+// shiftPeriod moves a period by a number of months, keeping month ends on month ends
+func shiftPeriod(start, end time.Time, months int) (time.Time, time.Time) {
+	if wholeMonths(start, end) > 0 {
+		return start.AddDate(0, months, 0), endOfMonth(beginningOfMonth(end).AddDate(0, months, 0))
+	}
+	return addMonths(start, months), addMonths(end, months)
+}
+
+// addMonths moves a date by a number of months, clamping the day to the end of the month
+func addMonths(date time.Time, months int) time.Time {
+	target := beginningOfMonth(date).AddDate(0, months, 0)
+	day := date.Day()
+	if last := endOfMonth(target).Day(); day > last {
+		day = last
+	}
+	return time.Date(target.Year(), target.Month(), day, date.Hour(), date.Minute(), date.Second(), date.Nanosecond(), date.Location())
+}
+
+// beginningOfMonth returns the first day of the month of a date
+func beginningOfMonth(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, date.Location())
+}
+
+// endOfMonth returns the last day of the month of a date
+func endOfMonth(date time.Time) time.Time {
+	return beginningOfMonth(date).AddDate(0, 1, -1)
+}
+
+// trialBalanceSides puts a balance on the debit or credit side based on the account's normal balance
+func trialBalanceSides(account *models.Account, balance models.Money) (models.Money, models.Money) {
+	if account.GetNormalBalance() == models.NormalBalanceDebit {
+		if balance >= 0 {
+			return balance, 0
+		}
+		return 0, -balance
+	}
+	if balance >= 0 {
+		return 0, balance
+	}
+	return -balance, 0
+}
+
+// RunTemplate runs a report template for a period. Account rows sum the matched detail accounts
+// following their normal balance; formula rows and columns are evaluated after the amounts they use.
+func (s *reportService) RunTemplate(template *models.ReportTemplate, req *models.RunReportTemplateRequest) (*models.ReportTemplateResult, error) {
+	if !template.IsActive {
+		return nil, errors.New("report template is inactive")
+	}
+	if req.EndDate.Before(req.StartDate) {
+		return nil, errors.New("end date cannot be before start date")
+	}
+	if err := validateTemplateLayout(template.Rows, template.Columns); err != nil {
+		return nil, err
+	}
+
+	accounts, err := s.accountRepo.GetDetailAccounts()
+	if err != nil {
+		return nil, err
+	}
+
+	// Accounts matched by each account row
+	rowAccounts := make([][]*models.Account, len(template.Rows))
+	for i, row := range template.Rows {
+		if row.Type != models.TemplateRowAccounts {
+			continue
+		}
+		for j := range accounts {
+			if templateRowMatches(row, &accounts[j]) {
+				rowAccounts[i] = append(rowAccounts[i], &accounts[j])
+			}
+		}
+	}
+
+	result := &models.ReportTemplateResult{
+		TemplateID:   template.ID,
+		TemplateCode: template.Code,
+		Name:         template.Name,
+		StartDate:    req.StartDate,
+		EndDate:      req.EndDate,
+		Columns:      make([]models.ReportTemplateResultColumn, len(template.Columns)),
+	}
+
+	// amounts[row][column]
+	amounts := make([][]models.Money, len(template.Rows))
+	for i := range amounts {
+		amounts[i] = make([]models.Money, len(template.Columns))
+	}
+
+	// Actual and budget columns
+	for c, column := range template.Columns {
+		result.Columns[c] = models.ReportTemplateResultColumn{
+			Code:  column.Code,
+			Label: column.Label,
+			Type:  column.Type,
+		}
+		if column.Type == models.TemplateColumnFormula {
+			continue
+		}
+
+		period := templateColumnPeriod(column.Period, req.StartDate, req.EndDate)
+		result.Columns[c].StartDate = &period.StartDate
+		result.Columns[c].EndDate = &period.EndDate
+
+		branchID, fundID := req.BranchID, req.FundID
+		if column.BranchID != nil {
+			branchID = column.BranchID
+		}
+		if column.FundID != nil {
+			fundID = column.FundID
+		}
+
+		if column.Type == models.TemplateColumnBudget {
+			budgets, err := s.sumBudgets([]models.ReportPeriod{period}, branchID, fundID)
+			if err != nil {
+				return nil, err
+			}
+			for r := range template.Rows {
+				for _, account := range rowAccounts[r] {
+					amounts[r][c] += budgets[0][account.ID]
+				}
+			}
+			continue
+		}
+
+		totals, err := s.sumAccountTotals(reportFilter{
+			PeriodStart: period.StartDate,
+			EndDate:     period.EndDate,
+			Cumulative:  true,
+			BranchID:    branchID,
+			FundID:      fundID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for r, row := range template.Rows {
+			for _, account := range rowAccounts[r] {
+				amounts[r][c] += templateAccountAmount(row.Basis, account, totals[account.ID])
+			}
+		}
+	}
+
+	for r, row := range template.Rows {
+		if row.Negate && row.Type == models.TemplateRowAccounts {
+			for c := range amounts[r] {
+				amounts[r][c] = -amounts[r][c]
+			}
+		}
+	}
+
+	// Formula rows, per actual and budget column
+	rowIndex := make(map[string]int, len(template.Rows))
+	rowFormulas := make(map[string]*formula)
+	rowValues := make(map[string]bool)
+	for r, row := range template.Rows {
+		if row.Code == "" {
+			continue
+		}
+		rowIndex[row.Code] = r
+		if row.Type == models.TemplateRowFormula {
+			rowFormulas[row.Code], _ = parseFormula(row.Formula)
+		} else {
+			rowValues[row.Code] = true
+		}
+	}
+
+	rowOrder, err := formulaOrder(rowFormulas, rowValues)
+	if err != nil {
+		return nil, err
+	}
+
+	for c, column := range template.Columns {
+		if column.Type == models.TemplateColumnFormula {
+			continue
+		}
+		lookup := func(code string) *big.Rat { return amounts[rowIndex[code]][c].Rat() }
+		for _, code := range rowOrder {
+			r := rowIndex[code]
+			amount, err := models.MoneyFromRat(rowFormulas[code].eval(lookup))
+			if err != nil {
+				return nil, fmt.Errorf("row %s: %v", code, err)
+			}
+			amounts[r][c] = amount
+			if template.Rows[r].Negate {
+				amounts[r][c] = -amounts[r][c]
+			}
+		}
+	}
+
+	// Formula columns, per row
+	columnIndex := make(map[string]int, len(template.Columns))
+	columnFormulas := make(map[string]*formula)
+	columnValues := make(map[string]bool)
+	for c, column := range template.Columns {
+		if column.Code == "" {
+			continue
+		}
+		columnIndex[column.Code] = c
+		if column.Type == models.TemplateColumnFormula {
+			columnFormulas[column.Code], _ = parseFormula(column.Formula)
+		} else {
+			columnValues[column.Code] = true
+		}
+	}
+
+	columnOrder, err := formulaOrder(columnFormulas, columnValues)
+	if err != nil {
+		return nil, err
+	}
+
+	for r := range template.Rows {
+		lookup := func(code string) *big.Rat { return amounts[r][columnIndex[code]].Rat() }
+		for _, code := range columnOrder {
+			amount, err := models.MoneyFromRat(columnFormulas[code].eval(lookup))
+			if err != nil {
+				return nil, fmt.Errorf("column %s: %v", code, err)
+			}
+			amounts[r][columnIndex[code]] = amount
+		}
+	}
+
+	result.Rows = make([]models.ReportTemplateResultRow, 0, len(template.Rows))
+	for r, row := range template.Rows {
+		if row.Hidden {
+			continue
+		}
+
+		line := models.ReportTemplateResultRow{
+			Code:   row.Code,
+			Label:  row.Label,
+			Type:   row.Type,
+			Bold:   row.Bold,
+			Indent: row.Indent,
+		}
+		if row.Type != models.TemplateRowHeading {
+			line.Amounts = make([]models.Money, len(amounts[r]))
+			for c := range amounts[r] {
+				line.Amounts[c] = amounts[r][c]
+			}
+		}
+		result.Rows = append(result.Rows, line)
+	}
+
+	return result, nil
+}
+
+// templateRowMatches reports whether an account falls in the code range and categories of a row.
+// A range bound also matches the codes starting with it, so 4 to 4 covers every 4xxx account.
+func templateRowMatches(row models.ReportTemplateRow, account *models.Account) bool {
+	if row.AccountFrom != "" && account.Code < row.AccountFrom {
+		return false
+	}
+	if row.AccountTo != "" && account.Code > row.AccountTo && !strings.HasPrefix(account.Code, row.AccountTo) {
+		return false
+	}
+	if len(row.Categories) == 0 {
+		return true
+	}
+	for _, category := range row.Categories {
+		if account.Category == category {
+			return true
+		}
+	}
+	return false
+}
+
+// templateAccountAmount returns the amount of an account for a row basis, following its normal
+// balance. Without a basis revenue and expense accounts use their movement and others their closing balance.
+func templateAccountAmount(basis string, account *models.Account, totals accountTotals) models.Money {
+	if basis == "" {
+		basis = models.TemplateBasisClosing
+		if account.Category == models.AccountCategoryRevenue || account.Category == models.AccountCategoryExpense {
+			basis = models.TemplateBasisMovement
+		}
+	}
+
+	switch basis {
+	case models.TemplateBasisMovement:
+		return periodAmount(account, totals)
+	case models.TemplateBasisOpening:
+		return openingAmount(account, totals)
+	}
+	return accountBalance(account, totals)
+}
+
+// templateColumnPeriod returns the period of a template column relative to the run period
+func templateColumnPeriod(period string, start, end time.Time) models.ReportPeriod {
+	switch period {
+	case models.TemplatePeriodPriorPeriod:
+		return comparisonPeriods(models.ComparisonPriorPeriod, start, end)[0]
+	case models.TemplatePeriodPriorYear:
+		return comparisonPeriods(models.ComparisonPriorYear, start, end)[0]
+	case models.TemplatePeriodYearToDate:
+		return newReportPeriod(time.Date(end.Year(), 1, 1, 0, 0, 0, 0, end.Location()), end)
+	}
+	return newReportPeriod(start, end)
+}
+
+// GetConsolidatedBalanceSheet shows the balance sheet of each selected branch next to the
+// consolidated total, eliminating due to/due from and transfer balances between them
+func (s *reportService) GetConsolidatedBalanceSheet(req *models.ConsolidatedBalanceSheetRequest) (*models.ConsolidatedBalanceSheetResponse, error) {
+	branches, err := s.consolidationBranches(req.BranchIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	assets, err := s.accountRepo.GetByCategory(models.AccountCategoryAsset)
+	if err != nil {
+		return nil, err
+	}
+
+	liabilities, err := s.accountRepo.GetByCategory(models.AccountCategoryLiability)
+	if err != nil {
+		return nil, err
+	}
+
+	equity, err := s.accountRepo.GetByCategory(models.AccountCategoryEquity)
+	if err != nil {
+		return nil, err
+	}
+
+	revenue, err := s.accountRepo.GetByCategory(models.AccountCategoryRevenue)
+	if err != nil {
+		return nil, err
+	}
+
+	expenses, err := s.accountRepo.GetByCategory(models.AccountCategoryExpense)
+	if err != nil {
+		return nil, err
+	}
+
+	columns, eliminated, interBranchAccounts, err := s.sumConsolidatedTotals(reportFilter{
+		PeriodStart: time.Date(req.AsOfDate.Year(), 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:     req.AsOfDate,
+		Cumulative:  true,
+	}, branches)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.ConsolidatedBalanceSheetResponse{
+		AsOfDate: req.AsOfDate,
+		Branches: consolidatedBranches(branches),
+	}
+
+	response.Assets = buildConsolidatedSection(assets, columns, eliminated, accountBalance, req.IncludeHeaders)
+	response.TotalAssets = response.Assets.Total
+
+	response.Liabilities = buildConsolidatedSection(liabilities, columns, eliminated, accountBalance, req.IncludeHeaders)
+	response.TotalLiabilities = response.Liabilities.Total
+
+	response.Equity = buildConsolidatedSection(equity, columns, eliminated, accountBalance, req.IncludeHeaders)
+
+	// Add net income to equity
+	netIncome := models.ConsolidatedAmounts{BranchAmounts: make([]models.Money, len(columns))}
+	for i := range columns {
+		netIncome.BranchAmounts[i] = calculateNetIncome(revenue, expenses, columns[i])
+	}
+	netIncome.Eliminations = -calculateNetIncome(revenue, expenses, eliminated)
+	finishConsolidatedAmounts(&netIncome)
+
+	response.Equity.Lines = append(response.Equity.Lines, models.ConsolidatedLine{
+		AccountCode:         "NET_INCOME",
+		AccountName:         "Net Income (Current Year)",
+		ConsolidatedAmounts: netIncome,
+	})
+	addConsolidatedAmounts(&response.Equity.Total, netIncome, 1)
+	response.TotalEquity = response.Equity.Total
+
+	response.IsBalanced = response.TotalAssets.Consolidated ==
+		response.TotalLiabilities.Consolidated+response.TotalEquity.Consolidated
+	response.UneliminatedBalance = uneliminatedBalance(interBranchAccounts, columns, eliminated, false)
+
+	return response, nil
+}
+
+// GetConsolidatedIncomeStatement shows the income statement of each selected branch next to the
+// consolidated total, eliminating transfers between them
+func (s *reportService) GetConsolidatedIncomeStatement(req *models.ConsolidatedIncomeStatementRequest) (*models.ConsolidatedIncomeStatementResponse, error) {
+	branches, err := s.consolidationBranches(req.BranchIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	revenue, err := s.accountRepo.GetByCategory(models.AccountCategoryRevenue)
+	if err != nil {
+		return nil, err
+	}
+
+	expenses, err := s.accountRepo.GetByCategory(models.AccountCategoryExpense)
+	if err != nil {
+		return nil, err
+	}
+
+	columns, eliminated, interBranchAccounts, err := s.sumConsolidatedTotals(reportFilter{
+		PeriodStart: req.StartDate,
+		EndDate:     req.EndDate,
+	}, branches)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.ConsolidatedIncomeStatementResponse{
+		StartDate: req.StartDate,
+		EndDate:   req.EndDate,
+		Branches:  consolidatedBranches(branches),
+	}
+
+	response.Revenue = buildConsolidatedSection(revenue, columns, eliminated, periodAmount, req.IncludeHeaders)
+	response.TotalRevenue = response.Revenue.Total
+
+	response.Expenses = buildConsolidatedSection(expenses, columns, eliminated, periodAmount, req.IncludeHeaders)
+	response.TotalExpenses = response.Expenses.Total
+
+	response.NetIncome = models.ConsolidatedAmounts{BranchAmounts: make([]models.Money, len(columns))}
+	addConsolidatedAmounts(&response.NetIncome, response.TotalRevenue, 1)
+	addConsolidatedAmounts(&response.NetIncome, response.TotalExpenses, -1)
+	response.UneliminatedBalance = uneliminatedBalance(interBranchAccounts, columns, eliminated, true)
+
+	return response, nil
+}
+
+// consolidationBranches loads the branches of a consolidated report ordered by code, defaulting
+// to all active branches
+func (s *reportService) consolidationBranches(branchIDs []uuid.UUID) ([]models.Branch, error) {
+	var branches []models.Branch
+	query := s.db.Order("code ASC")
+	if len(branchIDs) > 0 {
+		query = query.Where("id IN ?", branchIDs)
+	} else {
+		query = query.Where("is_active = ?", true)
+	}
+
+	if err := query.Find(&branches).Error; err != nil {
+		return nil, err
+	}
+
+	if len(branches) == 0 {
+		return nil, errors.New("no branches to consolidate")
+	}
+	if len(branchIDs) > 0 && len(branches) != len(uniqueIDs(branchIDs)) {
+		return nil, errors.New("branch not found")
+	}
+
+	return branches, nil
+}
+
+// sumConsolidatedTotals returns the account totals of each branch in branch order, the totals
+// of the intra-group lines to eliminate, and the inter-branch accounts
+func (s *reportService) sumConsolidatedTotals(filter reportFilter, branches []models.Branch) ([]map[uuid.UUID]accountTotals, map[uuid.UUID]accountTotals, []uuid.UUID, error) {
+	filter.BranchIDs = make([]uuid.UUID, len(branches))
+	for i := range branches {
+		filter.BranchIDs[i] = branches[i].ID
+	}
+
+	byBranch, err := s.sumBranchAccountTotals(filter)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	columns := make([]map[uuid.UUID]accountTotals, len(branches))
+	for i := range branches {
+		columns[i] = byBranch[branches[i].ID]
+	}
+
+	var interBranchAccounts []uuid.UUID
+	if err := s.db.Model(&models.AccountMapping{}).
+		Where("mapping_key IN ?", models.InterBranchMappingKeys()).
+		Distinct().
+		Pluck("account_id", &interBranchAccounts).Error; err != nil {
+		return nil, nil, nil, err
+	}
+
+	eliminated := make(map[uuid.UUID]accountTotals)
+	if len(interBranchAccounts) > 0 {
+		filter.IntraGroupAccounts = interBranchAccounts
+		if eliminated, err = s.sumAccountTotals(filter); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	return columns, eliminated, interBranchAccounts, nil
+}
+
+// buildConsolidatedSection builds a section with a column per branch; amount gives the signed
+// amount of an account from its totals
+func buildConsolidatedSection(
+	accounts []models.Account,
+	columns []map[uuid.UUID]accountTotals,
+	eliminated map[uuid.UUID]accountTotals,
+	amount func(account *models.Account, totals accountTotals) models.Money,
+	includeHeaders bool,
+) models.ConsolidatedSection {
+	branchAmounts := make([]map[uuid.UUID]models.Money, len(columns))
+	branchRollups := make([]map[uuid.UUID]models.Money, len(columns))
+	for c := range columns {
+		branchAmounts[c] = make(map[uuid.UUID]models.Money, len(accounts))
+		for i := range accounts {
+			branchAmounts[c][accounts[i].ID] = amount(&accounts[i], columns[c][accounts[i].ID])
+		}
+		branchRollups[c] = rollupAmounts(accounts, branchAmounts[c])
+	}
+
+	eliminations := make(map[uuid.UUID]models.Money, len(accounts))
+	for i := range accounts {
+		if totals, exists := eliminated[accounts[i].ID]; exists {
+			eliminations[accounts[i].ID] = -amount(&accounts[i], totals)
+		}
+	}
+	eliminationRollup := rollupAmounts(accounts, eliminations)
+
+	section := models.ConsolidatedSection{
+		Lines: make([]models.ConsolidatedLine, 0),
+		Total: models.ConsolidatedAmounts{BranchAmounts: make([]models.Money, len(columns))},
+	}
+
+	for _, account := range accounts {
+		amounts, rollup, elimination := branchAmounts, branchRollups, eliminations
+		if !account.IsDetail {
+			if !includeHeaders {
+				continue
+			}
+			amounts, elimination = rollup, eliminationRollup
+		}
+
+		line := models.ConsolidatedLine{
+			AccountCode: account.Code,
+			AccountName: account.Name,
+			Level:       account.Level,
+			IsHeader:    !account.IsDetail,
+			ConsolidatedAmounts: models.ConsolidatedAmounts{
+				BranchAmounts: make([]models.Money, len(columns)),
+				Eliminations:  elimination[account.ID],
+			},
+		}
+
+		isZero := line.Eliminations == 0
+		for c := range columns {
+			line.BranchAmounts[c] = amounts[c][account.ID]
+			if line.BranchAmounts[c] != 0 {
+				isZero = false
+			}
+		}
+		if isZero {
+			continue
+		}
+
+		finishConsolidatedAmounts(&line.ConsolidatedAmounts)
+		section.Lines = append(section.Lines, line)
+
+		if account.IsDetail {
+			addConsolidatedAmounts(&section.Total, line.ConsolidatedAmounts, 1)
+		}
+	}
+
+	return section
+}
+
+// finishConsolidatedAmounts sets the consolidated total from the branch amounts and eliminations
+func finishConsolidatedAmounts(amounts *models.ConsolidatedAmounts) {
+	amounts.Consolidated = amounts.Eliminations
+	for _, amount := range amounts.BranchAmounts {
+		amounts.Consolidated += amount
+	}
+}
+
+// addConsolidatedAmounts adds amounts multiplied by sign to total
+func addConsolidatedAmounts(total *models.ConsolidatedAmounts, amounts models.ConsolidatedAmounts, sign models.Money) {
+	for i := range total.BranchAmounts {
+		total.BranchAmounts[i] += sign * amounts.BranchAmounts[i]
+	}
+	total.Eliminations += sign * amounts.Eliminations
+	total.Consolidated += sign * amounts.Consolidated
+}
+
+// consolidatedBranches returns the branch columns of a consolidated report
+func consolidatedBranches(branches []models.Branch) []models.ConsolidatedBranch {
+	columns := make([]models.ConsolidatedBranch, len(branches))
+	for i := range branches {
+		columns[i] = models.ConsolidatedBranch{
+			BranchID:   branches[i].ID,
+			BranchCode: branches[i].Code,
+			BranchName: branches[i].Name,
+		}
+	}
+	return columns
+}
+
+// uneliminatedBalance returns the consolidated balance left on inter-branch accounts, within the
+// period or cumulative. Accounts are summed in absolute terms so a receivable and a payable that
+// were both left standing do not hide each other.
+func uneliminatedBalance(accountIDs []uuid.UUID, columns []map[uuid.UUID]accountTotals, eliminated map[uuid.UUID]accountTotals, period bool) models.Money {
+	net := func(totals accountTotals) models.Money {
+		if period {
+			return totals.PeriodDebit - totals.PeriodCredit
+		}
+		return totals.Debit - totals.Credit
+	}
+
+	var balance models.Money
+	for _, accountID := range accountIDs {
+		remaining := -net(eliminated[accountID])
+		for c := range columns {
+			remaining += net(columns[c][accountID])
+		}
+		balance += remaining.Abs()
+	}
+	return balance
+}
+
+// uniqueIDs removes duplicate IDs
+func uniqueIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	unique := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// interBranchPosition is the debit balance one branch holds against another
+type interBranchPosition struct {
+	BranchID              uuid.UUID
+	BranchCode            string
+	BranchName            string
+	CounterpartBranchID   uuid.UUID
+	CounterpartBranchCode string
+	CounterpartBranchName string
+	Balance               models.Money
+}
+
+// GetInterBranchBalances pairs the due to/due from positions branches hold against each other.
+// Each pair is listed once, from the branch with the lower code; it is reconciled when the two
+// positions offset.
+func (s *reportService) GetInterBranchBalances(req *models.InterBranchBalanceRequest) (*models.InterBranchBalanceResponse, error) {
+	var positions []interBranchPosition
+	err := s.db.Model(&models.JournalLine{}).
+		Select(`journals.branch_id, branches.code as branch_code, branches.name as branch_name,
+			journal_lines.counterpart_branch_id,
+			counterparts.code as counterpart_branch_code, counterparts.name as counterpart_branch_name,
+			COALESCE(SUM(journal_lines.debit - journal_lines.credit), 0) as balance`).
+		Joins("JOIN journals ON journals.id = journal_lines.journal_id").
+		Joins("JOIN branches ON branches.id = journals.branch_id").
+		Joins("JOIN branches counterparts ON counterparts.id = journal_lines.counterpart_branch_id").
+		Where("journal_lines.counterpart_branch_id IS NOT NULL").
+		Where("journals.is_posted = ? AND journals.journal_date <= ?", true, req.AsOfDate).
+		Group("journals.branch_id, branches.code, branches.name, journal_lines.counterpart_branch_id, counterparts.code, counterparts.name").
+		Scan(&positions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	type branchPair struct {
+		branchID      uuid.UUID
+		counterpartID uuid.UUID
+	}
+
+	var pairs []branchPair
+	lines := make(map[branchPair]*models.InterBranchBalanceLine)
+
+	for _, position := range positions {
+		// Orient each pair from the branch with the lower code
+		reversed := position.BranchCode > position.CounterpartBranchCode
+		pair := branchPair{branchID: position.BranchID, counterpartID: position.CounterpartBranchID}
+		if reversed {
+			pair = branchPair{branchID: position.CounterpartBranchID, counterpartID: position.BranchID}
+		}
+
+		line, exists := lines[pair]
+		if !exists {
+			line = &models.InterBranchBalanceLine{
+				BranchID:              position.BranchID,
+				BranchCode:            position.BranchCode,
+				BranchName:            position.BranchName,
+				CounterpartBranchID:   position.CounterpartBranchID,
+				CounterpartBranchCode: position.CounterpartBranchCode,
+				CounterpartBranchName: position.CounterpartBranchName,
+			}
+			if reversed {
+				line.BranchID, line.CounterpartBranchID = line.CounterpartBranchID, line.BranchID
+				line.BranchCode, line.CounterpartBranchCode = line.CounterpartBranchCode, line.BranchCode
+				line.BranchName, line.CounterpartBranchName = line.CounterpartBranchName, line.BranchName
+			}
+			lines[pair] = line
+			pairs = append(pairs, pair)
+		}
+
+		if reversed {
+			line.CounterpartPosition += position.Balance
+		} else {
+			line.BranchPosition += position.Balance
+		}
+	}
+
+	response := &models.InterBranchBalanceResponse{
+		AsOfDate: req.AsOfDate,
+		Lines:    make([]models.InterBranchBalanceLine, 0, len(pairs)),
+	}
+
+	for _, pair := range pairs {
+		line := lines[pair]
+		if req.BranchID != nil && line.BranchID != *req.BranchID && line.CounterpartBranchID != *req.BranchID {
+			continue
+		}
+
+		line.Difference = line.BranchPosition + line.CounterpartPosition
+		line.IsReconciled = line.Difference == 0
+
+		if req.UnreconciledOnly && line.IsReconciled {
+			continue
+		}
+
+		response.Lines = append(response.Lines, *line)
+		response.TotalDifference += line.Difference
+	}
+
+	sort.Slice(response.Lines, func(i, j int) bool {
+		if response.Lines[i].BranchCode != response.Lines[j].BranchCode {
+			return response.Lines[i].BranchCode < response.Lines[j].BranchCode
+		}
+		return response.Lines[i].CounterpartBranchCode < response.Lines[j].CounterpartBranchCode
+	})
+
+	response.IsReconciled = response.TotalDifference == 0
+	for _, line := range response.Lines {
+		if !line.IsReconciled {
+			response.IsReconciled = false
+		}
+	}
+
+	return response, nil
+}
+
+// sumAccountTotals sums posted journal lines per account in a single grouped query
+func (s *reportService) sumAccountTotals(filter reportFilter) (map[uuid.UUID]accountTotals, error) {
+	var rows []accountTotals
+	if err := s.accountTotalsQuery(filter, "journal_lines.account_id").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	totals := make(map[uuid.UUID]accountTotals, len(rows))
+	for _, row := range rows {
+		totals[row.AccountID] = row
+	}
+	return totals, nil
+}
+
+// branchAccountTotals holds the account totals of one branch
+type branchAccountTotals struct {
+	BranchID uuid.UUID
+	accountTotals
+}
+
+// sumBranchAccountTotals is sumAccountTotals grouped per branch as well
+func (s *reportService) sumBranchAccountTotals(filter reportFilter) (map[uuid.UUID]map[uuid.UUID]accountTotals, error) {
+	var rows []branchAccountTotals
+	if err := s.accountTotalsQuery(filter, "journals.branch_id, journal_lines.account_id").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	totals := make(map[uuid.UUID]map[uuid.UUID]accountTotals)
+	for _, row := range rows {
+		if totals[row.BranchID] == nil {
+			totals[row.BranchID] = make(map[uuid.UUID]accountTotals)
+		}
+		totals[row.BranchID][row.AccountID] = row.accountTotals
+	}
+	return totals, nil
+}
+
+// accountTotalsQuery builds the grouped query behind the account totals. Opening journals count
+// towards the opening balance, never the period movement, even when dated inside the period.
+// Fiscal year closing journals and their reversals are left out of the period movement too, so
+// income and expense accounts keep their movement after a year is closed.
+func (s *reportService) accountTotalsQuery(filter reportFilter, groupBy string) *gorm.DB {
+	periodJournal := "journals.journal_date >= @start AND journals.type <> @opening AND NOT " + closingJournalCondition
+	args := map[string]interface{}{"start": filter.PeriodStart, "opening": models.JournalTypeOpening}
+
+	query := s.db.Model(&models.JournalLine{}).
+		Select(groupBy+`,
+			COALESCE(SUM(debit), 0) as debit,
+			COALESCE(SUM(credit), 0) as credit,
+			COALESCE(SUM(CASE WHEN `+periodJournal+` THEN debit ELSE 0 END), 0) as period_debit,
+			COALESCE(SUM(CASE WHEN `+periodJournal+` THEN credit ELSE 0 END), 0) as period_credit`,
+			args).
+		Joins("JOIN journals ON journals.id = journal_lines.journal_id").
+		Where("journals.journal_date <= ?", filter.EndDate).
+		Where("journals.is_posted = ?", true).
+		Group(groupBy)
+
+	if !filter.Cumulative {
+		query = query.Where(periodJournal, args)
+	}
+	if filter.BranchID != nil {
+		query = query.Where("journals.branch_id = ?", *filter.BranchID)
+	}
+	if filter.FundID != nil {
+		query = query.Where("journal_lines.fund_id = ?", *filter.FundID)
+	}
+	if filter.ProgramID != nil {
+		query = query.Where("journal_lines.program_id = ?", *filter.ProgramID)
+	}
+	if filter.AccountID != nil {
+		query = query.Where("journal_lines.account_id = ?", *filter.AccountID)
+	}
+	if len(filter.BranchIDs) > 0 {
+		query = query.Where("journals.branch_id IN ?", filter.BranchIDs)
+	}
+	if filter.IntraGroupAccounts != nil {
+		query = query.Where("journal_lines.account_id IN ? AND journal_lines.counterpart_branch_id IN ?",
+			filter.IntraGroupAccounts, filter.BranchIDs)
+	}
+
+	return query
+}
+
+// sumCashCounterparts sums, per counter-account, the credit minus debit of non-cash lines in posted
+// journals that have at least one cash line
+func (s *reportService) sumCashCounterparts(req *models.CashFlowRequest) (map[uuid.UUID]models.Money, error) {
+	query := s.db.Model(&models.JournalLine{}).
+		Select("journal_lines.account_id, COALESCE(SUM(journal_lines.credit - journal_lines.debit), 0) as amount").
+		Joins("JOIN journals ON journals.id = journal_lines.journal_id").
+		Joins("JOIN accounts ON accounts.id = journal_lines.account_id").
+		Where("journals.journal_date BETWEEN ? AND ?", req.StartDate, req.EndDate).
+		Where("journals.is_posted = ?", true).
+		Where("journals.type <> ?", models.JournalTypeOpening).
+		Where("COALESCE(accounts.cash_flow_activity, '') <> ?", models.CashFlowActivityCash).
+		Where(`EXISTS (
+			SELECT 1 FROM journal_lines cash_lines
+			JOIN accounts cash_accounts ON cash_accounts.id = cash_lines.account_id
+			WHERE cash_lines.journal_id = journals.id
+				AND cash_lines.deleted_at IS NULL
+				AND cash_accounts.cash_flow_activity = ?
+		)`, models.CashFlowActivityCash).
+		Group("journal_lines.account_id")
+
+	if req.BranchID != nil {
+		query = query.Where("journals.branch_id = ?", *req.BranchID)
+	}
+	if req.FundID != nil {
+		query = query.Where("journal_lines.fund_id = ?", *req.FundID)
+	}
+
+	var rows []struct {
+		AccountID uuid.UUID
+		Amount    models.Money
+	}
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	amounts := make(map[uuid.UUID]models.Money, len(rows))
+	for _, row := range rows {
+		amounts[row.AccountID] = row.Amount
+	}
+	return amounts, nil
+}
+
+// isNetIncomeAccount reports whether an account's movement belongs to net income in the indirect
+// method: income and expense accounts, and the retained surplus accounts they are closed into
+func isNetIncomeAccount(account *models.Account) bool {
+	switch account.Category {
+	case models.AccountCategoryRevenue, models.AccountCategoryExpense:
+		return true
+	}
+	return account.Type == models.AccountTypeRetained || account.Type == models.AccountTypeRetainedCurr
+}
+
+// closingJournalCondition matches fiscal year closing journals and their reversals. Closing
+// journals are linked to their fiscal year and dated on its last day; the opening journals of the
+// close are linked too but dated the day after.
+const closingJournalCondition = `EXISTS (
+	SELECT 1 FROM journals closing_journals
+	JOIN fiscal_years ON fiscal_years.id = closing_journals.fiscal_year_id
+	WHERE closing_journals.id IN (journals.id, journals.reversal_of_id)
+		AND closing_journals.journal_date = fiscal_years.end_date)`
+
+// sameProgram compares optional program IDs
+func sameProgram(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// fundTotals holds posted debits and credits of one account within one fund
+type fundTotals struct {
+	AccountID    uuid.UUID
+	FundID       *uuid.UUID
+	FundCode     string
+	FundName     string
+	FundType     string
+	Debit        models.Money // Cumulative up to the end date
+	Credit       models.Money
+	PeriodDebit  models.Money // Within the period, excluding opening and fiscal year closing journals
+	PeriodCredit models.Money
+}
+
+// restricted reports whether the line belongs to net assets with donor restrictions
+func (t *fundTotals) restricted() bool {
+	return t.FundType == models.FundTypeRestricted
+}
+
+// sumFundTotals sums posted journal lines per account and fund in a single grouped query. Period
+// columns leave out opening journals, and fiscal year closing journals and their reversals, so
+// income and expense accounts keep their movement after a year is closed.
+func (s *reportService) sumFundTotals(periodStart, endDate time.Time, branchID *uuid.UUID) ([]fundTotals, error) {
+	closing := closingJournalCondition
+
+	query := s.db.Model(&models.JournalLine{}).
+		Select(`journal_lines.account_id, journal_lines.fund_id,
+			COALESCE(funds.code, '') as fund_code,
+			COALESCE(funds.name, '') as fund_name,
+			COALESCE(funds.type, '') as fund_type,
+			COALESCE(SUM(journal_lines.debit), 0) as debit,
+			COALESCE(SUM(journal_lines.credit), 0) as credit,
+			COALESCE(SUM(CASE WHEN journals.journal_date >= @start AND journals.type <> @opening AND NOT `+closing+` THEN journal_lines.debit ELSE 0 END), 0) as period_debit,
+			COALESCE(SUM(CASE WHEN journals.journal_date >= @start AND journals.type <> @opening AND NOT `+closing+` THEN journal_lines.credit ELSE 0 END), 0) as period_credit`,
+			map[string]interface{}{"start": periodStart, "opening": models.JournalTypeOpening}).
+		Joins("JOIN journals ON journals.id = journal_lines.journal_id").
+		Joins("LEFT JOIN funds ON funds.id = journal_lines.fund_id").
+		Where("journals.journal_date <= ?", endDate).
+		Where("journals.is_posted = ?", true).
+		Group("journal_lines.account_id, journal_lines.fund_id, funds.code, funds.name, funds.type")
+
+	if branchID != nil {
+		query = query.Where("journals.branch_id = ?", *branchID)
+	}
+
+	var rows []fundTotals
+	err := query.Scan(&rows).Error
+	return rows, err
+}
+
+// isNetAssetAccount reports whether an account's balance is part of net assets: equity, and the
+// income and expense accounts not yet closed into it
+func isNetAssetAccount(account *models.Account) bool {
+	switch account.Category {
+	case models.AccountCategoryEquity, models.AccountCategoryRevenue, models.AccountCategoryExpense:
+		return true
+	}
+	return false
+}
+
+// restrictionLine creates a statement line for an account
+func restrictionLine(account *models.Account, amounts models.RestrictionAmounts) models.RestrictionLine {
+	return models.RestrictionLine{
+		AccountCode:        account.Code,
+		AccountName:        account.Name,
+		RestrictionAmounts: amounts,
+	}
+}
+
+// addRestrictionAmounts adds amounts to a running total
+func addRestrictionAmounts(total *models.RestrictionAmounts, amounts models.RestrictionAmounts) {
+	total.WithoutRestrictions += amounts.WithoutRestrictions
+	total.WithRestrictions += amounts.WithRestrictions
+	total.Total += amounts.Total
+}
+
+// accountBalance returns the cumulative balance of an account by its normal balance
+func accountBalance(account *models.Account, totals accountTotals) models.Money {
+	if account.GetNormalBalance() == models.NormalBalanceDebit {
+		return totals.Debit - totals.Credit
+	}
+	return totals.Credit - totals.Debit
+}
+
+// periodBalance returns the credit minus debit movement of an account within the period
+func periodBalance(totals accountTotals) models.Money {
+	return totals.PeriodCredit - totals.PeriodDebit
+}
+
+// periodAmount returns the movement of an account within the period following its normal balance,
+// so both revenue and expenses are positive
+func periodAmount(account *models.Account, totals accountTotals) models.Money {
+	if account.GetNormalBalance() == models.NormalBalanceDebit {
+		return -periodBalance(totals)
+	}
+	return periodBalance(totals)
+}
+
+// openingAmount returns the balance of an account before the period movement, following its normal
+// balance
+func openingAmount(account *models.Account, totals accountTotals) models.Money {
+	return accountBalance(account, totals) - periodAmount(account, totals)
+}
+
+// rollupAmounts adds each detail account's amount to all of its ancestors through ParentID
+func rollupAmounts(accounts []models.Account, amounts map[uuid.UUID]models.Money) map[uuid.UUID]models.Money {
+	parents := make(map[uuid.UUID]*uuid.UUID, len(accounts))
+	for i := range accounts {
+		parents[accounts[i].ID] = accounts[i].ParentID
+	}
+
+	rollup := make(map[uuid.UUID]models.Money, len(accounts))
+	for i := range accounts {
+		if !accounts[i].IsDetail {
+			continue
+		}
+		amount := amounts[accounts[i].ID]
+		rollup[accounts[i].ID] += amount
+
+		// Walk up the tree; the depth guard stops malformed cyclic hierarchies
+		parentID := accounts[i].ParentID
+		for depth := 0; parentID != nil && depth < len(accounts); depth++ {
+			rollup[*parentID] += amount
+			parentID = parents[*parentID]
+		}
+	}
+	return rollup
+}
+
+func buildBalanceSheetSection(
+	accounts []models.Account,
+	totals map[uuid.UUID]accountTotals,
+	includeHeaders bool,
+) models.BalanceSheetSection {
+	amounts := make(map[uuid.UUID]models.Money, len(accounts))
+	for i := range accounts {
+		amounts[accounts[i].ID] = accountBalance(&accounts[i], totals[accounts[i].ID])
+	}
+	rollup := rollupAmounts(accounts, amounts)
+
+	lines := make([]models.BalanceSheetLine, 0)
+	var total models.Money
+
+	for _, account := range accounts {
+		if !account.IsDetail {
+			if includeHeaders && rollup[account.ID] != 0 {
+				lines = append(lines, models.BalanceSheetLine{
+					AccountCode: account.Code,
+					AccountName: account.Name,
+					Amount:      rollup[account.ID],
+					Level:       account.Level,
+					IsHeader:    true,
+				})
+			}
+			continue
+		}
+
+		balance := amounts[account.ID]
+		if balance == 0 {
+			continue
+		}
+
+		lines = append(lines, models.BalanceSheetLine{
+			AccountCode: account.Code,
+			AccountName: account.Name,
+			Amount:      balance,
+			Level:       account.Level,
+			IsHeader:    false,
+		})
+
+		total += balance
+	}
+
+	return models.BalanceSheetSection{
+		Lines: lines,
+		Total: total,
+	}
+}
+
+// buildIncomeStatementSection lists the period movement of each account as credit minus debit,
+// the sign the income statement has always used, so expenses come out negative. The comparative
+// and consolidated statements use periodAmount instead.
+func buildIncomeStatementSection(
+	accounts []models.Account,
+	totals map[uuid.UUID]accountTotals,
+	includeHeaders bool,
+) models.IncomeStatementSection {
+	amounts := make(map[uuid.UUID]models.Money, len(accounts))
+	for i := range accounts {
+		amounts[accounts[i].ID] = periodBalance(totals[accounts[i].ID])
+	}
+	rollup := rollupAmounts(accounts, amounts)
+
+	lines := make([]models.IncomeStatementLine, 0)
+	var total models.Money
+
+	for _, account := range accounts {
+		if !account.IsDetail {
+			if includeHeaders && rollup[account.ID] != 0 {
+				lines = append(lines, models.IncomeStatementLine{
+					AccountCode: account.Code,
+					AccountName: account.Name,
+					Amount:      rollup[account.ID],
+					Level:       account.Level,
+					IsHeader:    true,
+				})
+			}
+			continue
+		}
+
+		// Calculate period balance
+		amount := amounts[account.ID]
+		if amount == 0 {
+			continue
+		}
+
+		lines = append(lines, models.IncomeStatementLine{
+			AccountCode: account.Code,
+			AccountName: account.Name,
+			Amount:      amount,
+			Level:       account.Level,
+			IsHeader:    false,
+		})
+
+		total += amount
+	}
+
+	return models.IncomeStatementSection{
+		Lines: lines,
+		Total: total,
+	}
 }
 
-func (s *reportService) calculateNetIncome(
-	startDate, endDate time.Time,
-	branchID *uuid.UUID,
-	fundID *uuid.UUID,
-) (float64, error) {
-	// Get revenue
-	revenue, _ := s.accountRepo.GetByCategory(models.AccountCategoryRevenue)
-	var totalRevenue float64
+// calculateNetIncome totals the balance sheet's net income with the same signs as the income
+// statement
+func calculateNetIncome(
+	revenue, expenses []models.Account,
+	totals map[uuid.UUID]accountTotals,
+) models.Money {
+	var totalRevenue models.Money
 	for _, account := range revenue {
 		if account.IsDetail {
-			amount, _ := s.calculatePeriodBalance(account.ID, startDate, endDate, branchID, fundID)
-			totalRevenue += amount
+			totalRevenue += periodBalance(totals[account.ID])
 		}
 	}
 
-	// Get expenses
-	expenses, _ := s.accountRepo.GetByCategory(models.AccountCategoryExpense)
-	var totalExpenses float64
+	var totalExpenses models.Money
 	for _, account := range expenses {
 		if account.IsDetail {
-			amount, _ := s.calculatePeriodBalance(account.ID, startDate, endDate, branchID, fundID)
-			totalExpenses += amount
+			totalExpenses += periodBalance(totals[account.ID])
 		}
 	}
 
-	return totalRevenue - totalExpenses, nil
+	return totalRevenue - totalExpenses
 }
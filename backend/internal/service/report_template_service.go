@@ -0,0 +1,425 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/yayasan/erp-backend/internal/models"
+	"github.com/yayasan/erp-backend/internal/repository"
+)
+
+type ReportTemplateService interface {
+	GetAll(activeOnly bool) ([]models.ReportTemplate, error)
+	GetByID(id uuid.UUID) (*models.ReportTemplate, error)
+	Create(req *models.CreateReportTemplateRequest, userID uuid.UUID) (*models.ReportTemplate, error)
+	Update(id uuid.UUID, req *models.UpdateReportTemplateRequest) (*models.ReportTemplate, error)
+	Delete(id uuid.UUID) error
+}
+
+type reportTemplateService struct {
+	templateRepo repository.ReportTemplateRepository
+}
+
+func NewReportTemplateService(templateRepo repository.ReportTemplateRepository) ReportTemplateService {
+	return &reportTemplateService{
+		templateRepo: templateRepo,
+	}
+}
+
+func (s *reportTemplateService) GetAll(activeOnly bool) ([]models.ReportTemplate, error) {
+	return s.templateRepo.GetAll(activeOnly)
+}
+
+func (s *reportTemplateService) GetByID(id uuid.UUID) (*models.ReportTemplate, error) {
+	return s.templateRepo.GetByID(id)
+}
+
+func (s *reportTemplateService) Create(req *models.CreateReportTemplateRequest, userID uuid.UUID) (*models.ReportTemplate, error) {
+	// Check if code exists
+	existing, _ := s.templateRepo.GetByCode(req.Code)
+	if existing != nil {
+		return nil, errors.New("report template code already exists")
+	}
+
+	if err := validateTemplateLayout(req.Rows, req.Columns); err != nil {
+		return nil, err
+	}
+
+	reportType := req.ReportType
+	if reportType == "" {
+		reportType = "financial"
+	}
+
+	template := &models.ReportTemplate{
+		Code:        req.Code,
+		Name:        req.Name,
+		Module:      "finance",
+		ReportType:  reportType,
+		Description: req.Description,
+		Rows:        req.Rows,
+		Columns:     req.Columns,
+		IsActive:    true,
+		CreatedBy:   &userID,
+	}
+
+	if err := s.templateRepo.Create(template); err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+func (s *reportTemplateService) Update(id uuid.UUID, req *models.UpdateReportTemplateRequest) (*models.ReportTemplate, error) {
+	template, err := s.templateRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("report template not found")
+	}
+
+	if template.IsSystemReport {
+		return nil, errors.New("system report templates cannot be changed")
+	}
+
+	if err := validateTemplateLayout(req.Rows, req.Columns); err != nil {
+		return nil, err
+	}
+
+	template.Name = req.Name
+	template.Description = req.Description
+	template.Rows = req.Rows
+	template.Columns = req.Columns
+
+	if req.ReportType != "" {
+		template.ReportType = req.ReportType
+	}
+	if req.IsActive != nil {
+		template.IsActive = *req.IsActive
+	}
+
+	if err := s.templateRepo.Update(template); err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+func (s *reportTemplateService) Delete(id uuid.UUID) error {
+	template, err := s.templateRepo.GetByID(id)
+	if err != nil {
+		return errors.New("report template not found")
+	}
+
+	if template.IsSystemReport {
+		return errors.New("system report templates cannot be deleted")
+	}
+
+	return s.templateRepo.Delete(id)
+}
+
+// validateTemplateLayout checks row and column definitions, including formula references
+func validateTemplateLayout(rows []models.ReportTemplateRow, columns []models.ReportTemplateColumn) error {
+	rowFormulas := make(map[string]*formula)
+	rowValues := make(map[string]bool)
+	for i, row := range rows {
+		if row.Code != "" {
+			if !isFormulaCode(row.Code) {
+				return fmt.Errorf("row %d: code %s must start with a letter or _ and only have letters, digits, _ and .", i+1, row.Code)
+			}
+			if rowValues[row.Code] || rowFormulas[row.Code] != nil {
+				return fmt.Errorf("row %d: duplicate code %s", i+1, row.Code)
+			}
+		}
+
+		switch row.Type {
+		case models.TemplateRowAccounts:
+			if row.AccountFrom == "" && row.AccountTo == "" && len(row.Categories) == 0 {
+				return fmt.Errorf("row %d: an account range or category is required", i+1)
+			}
+			if row.AccountFrom != "" && row.AccountTo != "" && row.AccountFrom > row.AccountTo {
+				return fmt.Errorf("row %d: account range is reversed", i+1)
+			}
+			if row.Code != "" {
+				rowValues[row.Code] = true
+			}
+
+		case models.TemplateRowFormula:
+			if row.Code == "" {
+				return fmt.Errorf("row %d: formula rows need a code", i+1)
+			}
+			parsed, err := parseFormula(row.Formula)
+			if err != nil {
+				return fmt.Errorf("row %d: %v", i+1, err)
+			}
+			rowFormulas[row.Code] = parsed
+		}
+	}
+
+	if _, err := formulaOrder(rowFormulas, rowValues); err != nil {
+		return fmt.Errorf("rows: %v", err)
+	}
+
+	columnFormulas := make(map[string]*formula)
+	columnValues := make(map[string]bool)
+	for i, column := range columns {
+		if column.Code != "" {
+			if !isFormulaCode(column.Code) {
+				return fmt.Errorf("column %d: code %s must start with a letter or _ and only have letters, digits, _ and .", i+1, column.Code)
+			}
+			if columnValues[column.Code] || columnFormulas[column.Code] != nil {
+				return fmt.Errorf("column %d: duplicate code %s", i+1, column.Code)
+			}
+		}
+
+		if column.Type != models.TemplateColumnFormula {
+			if column.Code != "" {
+				columnValues[column.Code] = true
+			}
+			continue
+		}
+
+		if column.Code == "" {
+			return fmt.Errorf("column %d: formula columns need a code", i+1)
+		}
+		parsed, err := parseFormula(column.Formula)
+		if err != nil {
+			return fmt.Errorf("column %d: %v", i+1, err)
+		}
+		columnFormulas[column.Code] = parsed
+	}
+
+	if _, err := formulaOrder(columnFormulas, columnValues); err != nil {
+		return fmt.Errorf("columns: %v", err)
+	}
+
+	return nil
+}
+
+// formula is a parsed arithmetic expression over row or column codes
+type formula struct {
+	eval formulaEval
+	refs []string
+}
+
+// parseFormula parses +, -, *, / and parentheses over numbers and codes. Division by zero gives zero.
+func parseFormula(text string) (*formula, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, errors.New("formula is required")
+	}
+
+	p := &formulaParser{text: text}
+	eval, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	if p.pos < len(p.text) {
+		return nil, fmt.Errorf("unexpected %q in formula", p.text[p.pos:])
+	}
+
+	return &formula{eval: eval, refs: p.refs}, nil
+}
+
+// formulaEval evaluates a formula exactly; row and column amounts are looked up by code
+type formulaEval func(lookup func(code string) *big.Rat) *big.Rat
+
+type formulaParser struct {
+	text string
+	pos  int
+	refs []string
+}
+
+func (p *formulaParser) skipSpaces() {
+	for p.pos < len(p.text) && p.text[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+func (p *formulaParser) peek() byte {
+	p.skipSpaces()
+	if p.pos < len(p.text) {
+		return p.text[p.pos]
+	}
+	return 0
+}
+
+func (p *formulaParser) parseExpression() (formulaEval, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		op := p.peek()
+		if op != '+' && op != '-' {
+			return left, nil
+		}
+		p.pos++
+
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+
+		l := left
+		if op == '+' {
+			left = func(lookup func(string) *big.Rat) *big.Rat { return new(big.Rat).Add(l(lookup), right(lookup)) }
+		} else {
+			left = func(lookup func(string) *big.Rat) *big.Rat { return new(big.Rat).Sub(l(lookup), right(lookup)) }
+		}
+	}
+}
+
+func (p *formulaParser) parseTerm() (formulaEval, error) {
+	left, err := p.parseFactor()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		op := p.peek()
+		if op != '*' && op != '/' {
+			return left, nil
+		}
+		p.pos++
+
+		right, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+
+		l := left
+		if op == '*' {
+			left = func(lookup func(string) *big.Rat) *big.Rat { return new(big.Rat).Mul(l(lookup), right(lookup)) }
+		} else {
+			left = func(lookup func(string) *big.Rat) *big.Rat {
+				divisor := right(lookup)
+				if divisor.Sign() == 0 {
+					return new(big.Rat)
+				}
+				return new(big.Rat).Quo(l(lookup), divisor)
+			}
+		}
+	}
+}
+
+func (p *formulaParser) parseFactor() (formulaEval, error) {
+	switch c := p.peek(); {
+	case c == '-':
+		p.pos++
+		operand, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		return func(lookup func(string) *big.Rat) *big.Rat { return new(big.Rat).Neg(operand(lookup)) }, nil
+
+	case c == '(':
+		p.pos++
+		inner, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ')' {
+			return nil, errors.New("missing ) in formula")
+		}
+		p.pos++
+		return inner, nil
+
+	case c >= '0' && c <= '9' || c == '.':
+		start := p.pos
+		for p.pos < len(p.text) && (p.text[p.pos] >= '0' && p.text[p.pos] <= '9' || p.text[p.pos] == '.') {
+			p.pos++
+		}
+		value, ok := new(big.Rat).SetString(p.text[start:p.pos])
+		if !ok {
+			return nil, fmt.Errorf("invalid number %q in formula", p.text[start:p.pos])
+		}
+		return func(func(string) *big.Rat) *big.Rat { return value }, nil
+
+	case isFormulaCodeChar(rune(c)):
+		start := p.pos
+		for p.pos < len(p.text) && isFormulaCodeChar(rune(p.text[p.pos])) {
+			p.pos++
+		}
+		code := p.text[start:p.pos]
+		p.refs = append(p.refs, code)
+		return func(lookup func(string) *big.Rat) *big.Rat { return lookup(code) }, nil
+
+	case c == 0:
+		return nil, errors.New("formula ends unexpectedly")
+	}
+
+	return nil, fmt.Errorf("unexpected %q in formula", p.text[p.pos:])
+}
+
+func isFormulaCodeChar(r rune) bool {
+	return r == '_' || r == '.' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// isFormulaCode reports whether a row or column code reads back as a reference in a formula,
+// checking it byte by byte like the parser. A code starting with a digit or . would be read as a
+// number.
+func isFormulaCode(code string) bool {
+	for i := 0; i < len(code); i++ {
+		r := rune(code[i])
+		if !isFormulaCodeChar(r) || i == 0 && (r == '.' || unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return code != ""
+}
+
+// formulaOrder returns the formula codes ordered so every formula comes after the formulas it
+// references. values holds the codes that are not formulas.
+func formulaOrder(formulas map[string]*formula, values map[string]bool) ([]string, error) {
+	const (
+		visiting = 1
+		done     = 2
+	)
+
+	state := make(map[string]int, len(formulas))
+	order := make([]string, 0, len(formulas))
+
+	var visit func(code string) error
+	visit = func(code string) error {
+		switch state[code] {
+		case visiting:
+			return fmt.Errorf("formula %s has a circular reference", code)
+		case done:
+			return nil
+		}
+
+		state[code] = visiting
+		for _, ref := range formulas[code].refs {
+			if values[ref] {
+				continue
+			}
+			if formulas[ref] == nil {
+				return fmt.Errorf("formula %s refers to unknown code %s", code, ref)
+			}
+			if err := visit(ref); err != nil {
+				return err
+			}
+		}
+		state[code] = done
+		order = append(order, code)
+		return nil
+	}
+
+	// Visit in a fixed order so errors are stable
+	codes := make([]string, 0, len(formulas))
+	for code := range formulas {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	for _, code := range codes {
+		if err := visit(code); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
@@ -667,9 +667,13 @@ CREATE TABLE report_templates (
     description TEXT,
     query_template TEXT,
     parameters JSONB,
+    rows JSONB, -- row definitions: account ranges, categories, formulas
+    columns JSONB, -- column definitions: periods, branches, funds, budget, formulas
     is_system_report BOOLEAN DEFAULT false,
     is_active BOOLEAN DEFAULT true,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP,
     created_by UUID REFERENCES users(id)
 );
 
@@ -696,8 +700,13 @@ CREATE TABLE attachments (
     file_path VARCHAR(500) NOT NULL,
     file_size BIGINT,
     mime_type VARCHAR(100),
+    storage VARCHAR(20) NOT NULL DEFAULT 'local',
+    checksum VARCHAR(64),
     uploaded_by UUID REFERENCES users(id),
-    uploaded_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+    uploaded_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
 );
 
 -- ============================================
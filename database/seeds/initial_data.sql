@@ -55,6 +55,51 @@ VALUES
     (gen_random_uuid(), 'journals.delete', 'Delete Journal', 'Can delete journal entries', 'finance', NOW(), NOW()),
     (gen_random_uuid(), 'journals.approve', 'Approve Journal', 'Can approve journal entries', 'finance', NOW(), NOW()),
     (gen_random_uuid(), 'journals.post', 'Post Journal', 'Can post journal entries', 'finance', NOW(), NOW()),
+    (gen_random_uuid(), 'journals.reverse', 'Reverse Journal', 'Can reverse posted journal entries', 'finance', NOW(), NOW()),
+    (gen_random_uuid(), 'journals.attach', 'Manage Journal Attachments', 'Can upload and delete journal attachments', 'finance', NOW(), NOW()),
+    
+    -- Finance - Approval Chains
+    (gen_random_uuid(), 'approval_chains.view', 'View Approval Chains', 'Can view approval chains', 'finance', NOW(), NOW()),
+    (gen_random_uuid(), 'approval_chains.manage', 'Manage Approval Chains', 'Can create, update and delete approval chains', 'finance', NOW(), NOW()),
+    
+    -- Finance - Periods
+    (gen_random_uuid(), 'periods.view', 'View Periods', 'Can view accounting period status', 'finance', NOW(), NOW()),
+    (gen_random_uuid(), 'periods.manage', 'Manage Periods', 'Can open, soft-close and close accounting periods', 'finance', NOW(), NOW()),
+    (gen_random_uuid(), 'fiscal_years.view', 'View Fiscal Years', 'Can view fiscal years', 'finance', NOW(), NOW()),
+    (gen_random_uuid(), 'fiscal_years.create', 'Create Fiscal Year', 'Can create fiscal years', 'finance', NOW(), NOW()),
+    (gen_random_uuid(), 'fiscal_years.update', 'Update Fiscal Year', 'Can set the current fiscal year', 'finance', NOW(), NOW()),
+    (gen_random_uuid(), 'fiscal_years.close', 'Close Fiscal Year', 'Can close fiscal years', 'finance', NOW(), NOW()),
+    (gen_random_uuid(), 'fiscal_years.reopen', 'Reopen Fiscal Year', 'Can reopen closed fiscal years', 'finance', NOW(), NOW()),
+    
+    -- Finance - Funds & Programs
+    (gen_random_uuid(), 'funds.view', 'View Funds', 'Can view funds', 'finance', NOW(), NOW()),
+    (gen_random_uuid(), 'funds.create', 'Create Fund', 'Can create funds', 'finance', NOW(), NOW()),
+    (gen_random_uuid(), 'funds.update', 'Update Fund', 'Can update and deactivate funds', 'finance', NOW(), NOW()),
+    (gen_random_uuid(), 'funds.delete', 'Delete Fund', 'Can delete unused funds', 'finance', NOW(), NOW()),
+    (gen_random_uuid(), 'programs.view', 'View Programs', 'Can view programs', 'finance', NOW(), NOW()),
+    (gen_random_uuid(), 'programs.create', 'Create Program', 'Can create programs', 'finance', NOW(), NOW()),
+    (gen_random_uuid(), 'programs.update', 'Update Program', 'Can update and deactivate programs', 'finance', NOW(), NOW()),
+    (gen_random_uuid(), 'programs.delete', 'Delete Program', 'Can delete unused programs', 'finance', NOW(), NOW()),
+    
+    -- Finance - Bank Reconciliation
+    (gen_random_uuid(), 'bank_reconciliations.view', 'View Bank Reconciliations', 'Can view bank statements and reconciliation reports', 'finance', NOW(), NOW()),
+    (gen_random_uuid(), 'bank_reconciliations.manage', 'Manage Bank Reconciliations', 'Can import, match and reconcile bank statements', 'finance', NOW(), NOW()),
+    
+    -- Finance - Recurring Journals
+    (gen_random_uuid(), 'recurring_journals.view', 'View Recurring Journals', 'Can view recurring journals and their history', 'finance', NOW(), NOW()),
+    (gen_random_uuid(), 'recurring_journals.manage', 'Manage Recurring Journals', 'Can create, pause, skip and generate recurring journals', 'finance', NOW(), NOW()),
+    
+    -- Finance - Exchange Rates
+    (gen_random_uuid(), 'exchange_rates.view', 'View Exchange Rates', 'Can view exchange rates and FX revaluations', 'finance', NOW(), NOW()),
+    (gen_random_uuid(), 'exchange_rates.manage', 'Manage Exchange Rates', 'Can enter and delete exchange rates', 'finance', NOW(), NOW()),
+    (gen_random_uuid(), 'exchange_rates.revalue', 'Run FX Revaluation', 'Can post month-end FX revaluation journals', 'finance', NOW(), NOW()),
+    
+    -- Finance - Opening Balances
+    (gen_random_uuid(), 'opening_balances.import', 'Import Opening Balances', 'Can load and post opening balances of branches and students', 'finance', NOW(), NOW()),
+    
+    -- Document Numbering
+    (gen_random_uuid(), 'document_numbers.view', 'View Number Formats', 'Can view document number formats', 'settings', NOW(), NOW()),
+    (gen_random_uuid(), 'document_numbers.manage', 'Manage Number Formats', 'Can change document number formats', 'settings', NOW(), NOW()),
     
     -- Assets
     (gen_random_uuid(), 'assets.view', 'View Assets', 'Can view asset list', 'assets', NOW(), NOW()),
@@ -66,7 +111,9 @@ VALUES
     -- Reports
     (gen_random_uuid(), 'reports.finance', 'Financial Reports', 'Can view financial reports', 'reports', NOW(), NOW()),
     (gen_random_uuid(), 'reports.assets', 'Asset Reports', 'Can view asset reports', 'reports', NOW(), NOW()),
-    (gen_random_uuid(), 'reports.donor', 'Donor Reports', 'Can view donor reports', 'reports', NOW(), NOW());
+    (gen_random_uuid(), 'reports.donor', 'Donor Reports', 'Can view donor reports', 'reports', NOW(), NOW()),
+    (gen_random_uuid(), 'report_templates.view', 'View Report Templates', 'Can view and run report templates', 'reports', NOW(), NOW()),
+    (gen_random_uuid(), 'report_templates.manage', 'Manage Report Templates', 'Can create, update and delete report templates', 'reports', NOW(), NOW());
 
 -- ============================================================================
 -- 3. INSERT ROLES
@@ -231,6 +278,17 @@ BEGIN
     RAISE NOTICE 'Bendahara Umum user created: bendahara.umum / Admin123!';
 END $$;
 
+-- ============================================================================
+-- 7. INSERT COMPANY SETTINGS (report letterhead)
+-- ============================================================================
+
+INSERT INTO settings (id, setting_key, setting_value, setting_type, category, description, is_public, created_at, updated_at)
+VALUES
+    (gen_random_uuid(), 'company.name', 'Yayasan As-Salam Joglo', 'string', 'company', 'Yayasan name printed on report letterheads', true, NOW(), NOW()),
+    (gen_random_uuid(), 'company.address', 'Jl. Masjid As-Salam Joglo, Jakarta Barat, DKI Jakarta 11640', 'string', 'company', 'Address printed on report letterheads', true, NOW(), NOW()),
+    (gen_random_uuid(), 'company.phone', '021-12345678', 'string', 'company', 'Phone printed on report letterheads', true, NOW(), NOW()),
+    (gen_random_uuid(), 'company.email', 'sekretariat@assalamjoglo.or.id', 'string', 'company', 'Email printed on report letterheads', true, NOW(), NOW());
+
 COMMIT;
 
 -- ============================================================================